@@ -0,0 +1,94 @@
+// Command export writes the social graph of the database given by the DB_ environment
+// variables of the server as DOT, GraphML or JSON, as GET /admin/graph/export does.
+//
+//	go run ./cmd/export -o graph.graphml
+//	go run ./cmd/export -format dot -user 1 -depth 2 | dot -Tsvg > ego.svg
+//
+// The rows are written as they are read, so a graph larger than memory can be exported.
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql"
+
+	"problem1/configs"
+	"problem1/pkg/graphexport"
+	"problem1/repository"
+	"problem1/service"
+	"problem1/usecase"
+)
+
+func main() {
+	var (
+		format = flag.String("format", "", "dot, graphml or json, by the extension of -o or json when not given")
+		userId = flag.Int("user", 0, "user at the center of the ego network exported with -depth")
+		depth  = flag.Int("depth", 0, "hops of the ego network of -user, or 0 for the whole graph")
+		output = flag.String("o", "", "file to write to instead of the standard output")
+	)
+	flag.Parse()
+
+	if err := run(*format, *userId, *depth, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "export:", err)
+		os.Exit(1)
+	}
+}
+
+func run(format string, userId, depth int, output string) error {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(output), ".")
+	}
+	f, err := graphexport.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	if depth < 0 {
+		return fmt.Errorf("depth %d is invalid", depth)
+	}
+
+	conf := configs.Get()
+	db, err := sql.Open(conf.DB.Driver, conf.DB.DataSource)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	bw := bufio.NewWriter(w)
+
+	enc, err := graphexport.NewEncoder(bw, f)
+	if err != nil {
+		return err
+	}
+
+	blockPolicy, err := service.ParseBlockPolicy(conf.Block.Policy)
+	if err != nil {
+		return err
+	}
+	friendListRepository := repository.NewFriendListRepository(db)
+	friendListService := service.NewFriendListService(friendListRepository, repository.NewFriendRequestRepository(db), blockPolicy)
+	graphExportService := service.NewGraphExportService(friendListRepository, repository.NewGraphExportRepository(db))
+	graphExportUseCase := usecase.NewGraphExportUseCase(repository.NewTransaction(db), friendListService, graphExportService)
+
+	if err := graphExportUseCase.Export(context.Background(), enc, userId, depth); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// checkpoint records the last line of each input file whose batch was committed, so that
+// a run failed halfway resumes after it instead of from the top.
+type checkpoint struct {
+	path  string
+	Kind  string         `json:"kind"`
+	Lines map[string]int `json:"lines"`
+}
+
+// loadCheckpoint reads the checkpoint at path, or starts one when the file does not exist.
+// An empty path keeps the checkpoint in memory only.
+func loadCheckpoint(path, kind string) (*checkpoint, error) {
+	cp := &checkpoint{path: path, Kind: kind, Lines: map[string]int{}}
+	if path == "" {
+		return cp, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, cp); err != nil {
+		return nil, err
+	}
+	if cp.Kind != kind {
+		return nil, errors.New("checkpoint is of kind " + cp.Kind)
+	}
+	if cp.Lines == nil {
+		cp.Lines = map[string]int{}
+	}
+
+	return cp, nil
+}
+
+func (cp *checkpoint) line(file string) int {
+	return cp.Lines[file]
+}
+
+// set records line of file and writes the checkpoint. The file is replaced by a rename so
+// that a crash while writing leaves the previous checkpoint.
+func (cp *checkpoint) set(file string, line int) error {
+	cp.Lines[file] = line
+	if cp.path == "" {
+		return nil
+	}
+
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(cp.path), filepath.Base(cp.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), cp.path)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_checkpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "import.checkpoint")
+
+	cp, err := loadCheckpoint(path, kindUsers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, cp.line("users.csv"), "a checkpoint not written yet starts at the top")
+
+	if err := cp.set("users.csv", 500); err != nil {
+		t.Fatal(err)
+	}
+	if err := cp.set("users.csv", 1000); err != nil {
+		t.Fatal(err)
+	}
+
+	cp, err = loadCheckpoint(path, kindUsers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1000, cp.line("users.csv"))
+	assert.Equal(t, 0, cp.line("more_users.csv"))
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, entries, 1, "no temporary file is left")
+
+	_, err = loadCheckpoint(path, kindFriendLink)
+	assert.Error(t, err, "a checkpoint of another kind is not resumed")
+}
+
+func Test_checkpoint_inMemory(t *testing.T) {
+	cp, err := loadCheckpoint("", kindUsers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cp.set("users.csv", 10); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 10, cp.line("users.csv"))
+}
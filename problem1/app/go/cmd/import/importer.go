@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"problem1/model"
+	"problem1/repository"
+)
+
+var errUserNotExist = errors.New("user not exist")
+
+// rejection is a row left out of the import, written as one line of the rejected file.
+type rejection struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Raw    string `json:"raw,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// fileSummary counts the rows of one input file.
+type fileSummary struct {
+	File     string
+	Read     int
+	Imported int
+	Rejected int
+	// Resumed counts the rows skipped since the checkpoint had them committed.
+	Resumed int
+}
+
+// importer writes the rows of the input files in batches, one transaction each.
+type importer struct {
+	tx         repository.Transaction
+	ur         repository.UserRepository
+	flr        repository.FriendListRepository
+	kind       string
+	batchSize  int
+	dryRun     bool
+	checkpoint *checkpoint
+	// rejected receives each rejected row, and may be nil.
+	rejected *json.Encoder
+	// rejections keeps the first of the rejected rows for the summary.
+	rejections    []*rejection
+	maxRejections int
+}
+
+// importFile imports the rows of rr read from file. The rows up to the checkpoint of file are
+// skipped. The checkpoint moves past each committed batch, except in dry run, which validates
+// the rows and checks their users without writing anything.
+func (im *importer) importFile(ctx context.Context, file string, rr recordReader) (*fileSummary, error) {
+	summary := &fileSummary{File: file}
+	done := im.checkpoint.line(file)
+
+	batch := make([]*record, 0, im.batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := im.writeBatch(ctx, file, batch, summary); err != nil {
+			return err
+		}
+		batch = batch[:0]
+
+		return nil
+	}
+
+	lastLine := done
+	for {
+		rec, err := rr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return summary, err
+		}
+		if rec.Line <= done {
+			summary.Resumed++
+			continue
+		}
+
+		summary.Read++
+		lastLine = rec.Line
+		if rec.Err != nil {
+			if err := im.reject(file, rec, rec.Err, summary); err != nil {
+				return summary, err
+			}
+			continue
+		}
+
+		batch = append(batch, rec)
+		if len(batch) == im.batchSize {
+			if err := flush(); err != nil {
+				return summary, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return summary, err
+	}
+	// the rejected rows after the last batch are done as well
+	if !im.dryRun && done < lastLine {
+		if err := im.checkpoint.set(file, lastLine); err != nil {
+			return summary, err
+		}
+	}
+
+	return summary, nil
+}
+
+// writeBatch writes batch in one transaction. The links to users not in users are rejected.
+func (im *importer) writeBatch(ctx context.Context, file string, batch []*record, summary *fileSummary) error {
+	if im.dryRun && im.kind == kindUsers {
+		summary.Imported += len(batch)
+		return nil
+	}
+
+	var missing []*record
+	err := im.tx.DoInTx(ctx, func(ctx context.Context) error {
+		if im.kind == kindUsers {
+			users := make([]*model.User, len(batch))
+			for i, rec := range batch {
+				users[i] = rec.User
+			}
+
+			return im.ur.InsertUsers(ctx, users)
+		}
+
+		exist, err := im.usersExist(ctx, batch)
+		if err != nil {
+			return err
+		}
+
+		links := make([][2]int, 0, len(batch))
+		missing = missing[:0]
+		for _, rec := range batch {
+			if !exist[rec.Link[0]] || !exist[rec.Link[1]] {
+				missing = append(missing, rec)
+				continue
+			}
+			links = append(links, rec.Link)
+		}
+		if im.dryRun {
+			return nil
+		}
+
+		return im.flr.InsertUserLinks(ctx, im.kind, links)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, rec := range missing {
+		if err := im.reject(file, rec, errUserNotExist, summary); err != nil {
+			return err
+		}
+	}
+	summary.Imported += len(batch) - len(missing)
+	if im.dryRun {
+		return nil
+	}
+
+	return im.checkpoint.set(file, batch[len(batch)-1].Line)
+}
+
+func (im *importer) usersExist(ctx context.Context, batch []*record) (map[int]bool, error) {
+	userIds := make([]int, 0, len(batch)*2)
+	seen := make(map[int]bool, len(batch)*2)
+	for _, rec := range batch {
+		for _, userId := range rec.Link {
+			if !seen[userId] {
+				seen[userId] = true
+				userIds = append(userIds, userId)
+			}
+		}
+	}
+
+	userList, err := im.flr.GetUserListByUserIds(ctx, userIds)
+	if err != nil {
+		return nil, err
+	}
+
+	exist := make(map[int]bool, len(userIds))
+	if userList != nil {
+		for _, u := range userList.Friends {
+			exist[u.UserId] = true
+		}
+	}
+
+	return exist, nil
+}
+
+func (im *importer) reject(file string, rec *record, reason error, summary *fileSummary) error {
+	summary.Rejected++
+
+	r := &rejection{File: file, Line: rec.Line, Raw: rec.Raw, Reason: reason.Error()}
+	if len(im.rejections) < im.maxRejections {
+		im.rejections = append(im.rejections, r)
+	}
+	if im.rejected == nil {
+		return nil
+	}
+
+	return im.rejected.Encode(r)
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/mock/mock_repository"
+	"problem1/model"
+	"problem1/pkg/testutil"
+	"problem1/repository"
+)
+
+type importerTest struct {
+	mock     sqlmock.Sqlmock
+	ur       *mock_repository.MockUserRepository
+	flr      *mock_repository.MockFriendListRepository
+	rejected *bytes.Buffer
+	im       *importer
+}
+
+func newImporterTest(t *testing.T, kind string, dryRun bool, done int) *importerTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	db, mock := testutil.NewSQLMock(t)
+	ur := mock_repository.NewMockUserRepository(ctrl)
+	flr := mock_repository.NewMockFriendListRepository(ctrl)
+	cp, err := loadCheckpoint("", kind)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done > 0 {
+		cp.Lines["input.jsonl"] = done
+	}
+	rejected := &bytes.Buffer{}
+
+	return &importerTest{
+		mock:     mock,
+		ur:       ur,
+		flr:      flr,
+		rejected: rejected,
+		im: &importer{
+			tx:            repository.NewTransaction(db),
+			ur:            ur,
+			flr:           flr,
+			kind:          kind,
+			batchSize:     2,
+			dryRun:        dryRun,
+			checkpoint:    cp,
+			rejected:      json.NewEncoder(rejected),
+			maxRejections: 1,
+		},
+	}
+}
+
+func Test_importer_importFile(t *testing.T) {
+	users := strings.Join([]string{
+		`{"userId":1,"name":"hoge"}`,
+		`{"userId":2,"name":""}`,
+		`{"userId":3,"name":"fuga"}`,
+		`{"userId":4,"name":"bar"}`,
+	}, "\n")
+	links := strings.Join([]string{
+		`{"user1Id":1,"user2Id":3}`,
+		`{"user1Id":1,"user2Id":999}`,
+		`{"user1Id":3,"user2Id":4}`,
+	}, "\n")
+	friends := func(userIds ...int) *model.FriendList {
+		fl := &model.FriendList{}
+		for _, userId := range userIds {
+			fl.Friends = append(fl.Friends, &model.Friend{UserId: userId})
+		}
+		return fl
+	}
+
+	tests := []struct {
+		name         string
+		kind         string
+		input        string
+		dryRun       bool
+		done         int
+		expects      func(*importerTest)
+		want         *fileSummary
+		wantLine     int
+		wantRejected []*rejection
+		wantErr      bool
+	}{
+		{
+			name:  "ok: users in batches",
+			kind:  kindUsers,
+			input: users,
+			expects: func(it *importerTest) {
+				it.mock.ExpectBegin()
+				it.ur.EXPECT().InsertUsers(gomock.Any(), []*model.User{{UserId: 1, Name: "hoge"}, {UserId: 3, Name: "fuga"}}).Return(nil)
+				it.mock.ExpectCommit()
+				it.mock.ExpectBegin()
+				it.ur.EXPECT().InsertUsers(gomock.Any(), []*model.User{{UserId: 4, Name: "bar"}}).Return(nil)
+				it.mock.ExpectCommit()
+			},
+			want:     &fileSummary{File: "input.jsonl", Read: 4, Imported: 3, Rejected: 1},
+			wantLine: 4,
+			wantRejected: []*rejection{
+				{File: "input.jsonl", Line: 2, Raw: `{"userId":2,"name":""}`, Reason: "name is empty"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "ok: links to users not exist rejected",
+			kind:  kindFriendLink,
+			input: links,
+			expects: func(it *importerTest) {
+				it.mock.ExpectBegin()
+				it.flr.EXPECT().GetUserListByUserIds(gomock.Any(), []int{1, 3, 999}).Return(friends(1, 3), nil)
+				it.flr.EXPECT().InsertUserLinks(gomock.Any(), kindFriendLink, [][2]int{{1, 3}}).Return(nil)
+				it.mock.ExpectCommit()
+				it.mock.ExpectBegin()
+				it.flr.EXPECT().GetUserListByUserIds(gomock.Any(), []int{3, 4}).Return(friends(3, 4), nil)
+				it.flr.EXPECT().InsertUserLinks(gomock.Any(), kindFriendLink, [][2]int{{3, 4}}).Return(nil)
+				it.mock.ExpectCommit()
+			},
+			want:     &fileSummary{File: "input.jsonl", Read: 3, Imported: 2, Rejected: 1},
+			wantLine: 3,
+			wantRejected: []*rejection{
+				{File: "input.jsonl", Line: 2, Raw: `{"user1Id":1,"user2Id":999}`, Reason: "user not exist"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "ok: resumed after the checkpoint",
+			kind:  kindUsers,
+			input: users,
+			done:  3,
+			expects: func(it *importerTest) {
+				it.mock.ExpectBegin()
+				it.ur.EXPECT().InsertUsers(gomock.Any(), []*model.User{{UserId: 4, Name: "bar"}}).Return(nil)
+				it.mock.ExpectCommit()
+			},
+			want:         &fileSummary{File: "input.jsonl", Read: 1, Imported: 1, Resumed: 3},
+			wantLine:     4,
+			wantRejected: nil,
+			wantErr:      false,
+		},
+		{
+			name:   "ok: dry run writes nothing",
+			kind:   kindBlockList,
+			input:  links,
+			dryRun: true,
+			expects: func(it *importerTest) {
+				it.mock.ExpectBegin()
+				it.flr.EXPECT().GetUserListByUserIds(gomock.Any(), []int{1, 3, 999}).Return(friends(1, 3), nil)
+				it.mock.ExpectCommit()
+				it.mock.ExpectBegin()
+				it.flr.EXPECT().GetUserListByUserIds(gomock.Any(), []int{3, 4}).Return(friends(3, 4), nil)
+				it.mock.ExpectCommit()
+			},
+			want:     &fileSummary{File: "input.jsonl", Read: 3, Imported: 2, Rejected: 1},
+			wantLine: 0,
+			wantRejected: []*rejection{
+				{File: "input.jsonl", Line: 2, Raw: `{"user1Id":1,"user2Id":999}`, Reason: "user not exist"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "ng: checkpoint stays at the last committed batch",
+			kind:  kindUsers,
+			input: users,
+			expects: func(it *importerTest) {
+				it.mock.ExpectBegin()
+				it.ur.EXPECT().InsertUsers(gomock.Any(), gomock.Any()).Return(nil)
+				it.mock.ExpectCommit()
+				it.mock.ExpectBegin()
+				it.ur.EXPECT().InsertUsers(gomock.Any(), gomock.Any()).Return(testutil.ErrTest)
+				it.mock.ExpectRollback()
+			},
+			want:     &fileSummary{File: "input.jsonl", Read: 4, Imported: 2, Rejected: 1},
+			wantLine: 3,
+			wantRejected: []*rejection{
+				{File: "input.jsonl", Line: 2, Raw: `{"userId":2,"name":""}`, Reason: "name is empty"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := newImporterTest(t, tt.kind, tt.dryRun, tt.done)
+			tt.expects(it)
+
+			got, err := it.im.importFile(context.Background(), "input.jsonl", newJSONLReader(strings.NewReader(tt.input), tt.kind))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("importFile() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantLine, it.im.checkpoint.line("input.jsonl"))
+			assert.Equal(t, tt.wantRejected, it.im.rejections)
+
+			var rejected []*rejection
+			d := json.NewDecoder(it.rejected)
+			for d.More() {
+				r := &rejection{}
+				if err := d.Decode(r); err != nil {
+					t.Fatal(err)
+				}
+				rejected = append(rejected, r)
+			}
+			assert.Equal(t, tt.wantRejected, rejected)
+			if err := it.mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func Test_printSummary(t *testing.T) {
+	var b bytes.Buffer
+	printSummary(&b, []*fileSummary{
+		{File: "users.csv", Read: 3, Imported: 1, Rejected: 2},
+		{File: "more_users.csv", Read: 1, Imported: 1, Resumed: 5},
+	}, []*rejection{{File: "users.csv", Line: 2, Reason: "name is empty"}}, false)
+
+	assert.Equal(t, `users.csv: read 3, imported 1, rejected 2, resumed past 0
+more_users.csv: read 1, imported 1, rejected 0, resumed past 5
+total: read 4, imported 2, rejected 2, resumed past 5
+rejected users.csv:2: name is empty
+... and 1 more rejected
+`, b.String())
+}
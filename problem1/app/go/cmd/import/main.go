@@ -0,0 +1,165 @@
+// Command import loads users, friend links or blocks from CSV or JSONL files into the database
+// given by the DB_ environment variables of the server.
+//
+//	go run ./cmd/import -kind users users.csv
+//	go run ./cmd/import -kind friend_link -batch 1000 -checkpoint links.checkpoint links.jsonl
+//
+// A CSV file starts with a header naming the columns: user_id and name for users, user1_id and
+// user2_id for links. A JSONL file has one object per line shaped as the API does: userId and
+// name for users, user1Id and user2Id for links.
+//
+// Each batch is written in one transaction. The existing users take the new name and the
+// existing links are kept, so importing a file twice is harmless. The links are written as
+// they are: a block does not sever the friendship as POST /user_link does, and the links to
+// users not in users are rejected. Import the users first.
+//
+// With -checkpoint, the last committed line of each file is kept in the checkpoint file, and
+// a rerun after a failure resumes after it. The files are told apart by the path given.
+// -dry-run validates the rows and checks their users without writing anything.
+//
+// The rejected rows are counted in the summary and written with their reason to -rejected.
+// A server running with GRAPH_ENABLED or CACHE_ENABLED does not see the imported rows until
+// it restarts.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql"
+
+	"problem1/configs"
+	"problem1/repository"
+)
+
+const maxRejectionsShown = 20
+
+func main() {
+	var (
+		kind           = flag.String("kind", "", "what the files hold: users, friend_link or block_list")
+		format         = flag.String("format", "", "csv or jsonl, by the file extension when not given")
+		batchSize      = flag.Int("batch", 500, "rows written per transaction")
+		checkpointPath = flag.String("checkpoint", "", "file to resume from and to record the progress in")
+		dryRun         = flag.Bool("dry-run", false, "validate the rows without writing them")
+		rejectedPath   = flag.String("rejected", "", "file to write the rejected rows to as JSONL")
+	)
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: import -kind users|friend_link|block_list [flags] file...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(*kind, *format, *batchSize, *checkpointPath, *dryRun, *rejectedPath, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "import:", err)
+		os.Exit(1)
+	}
+}
+
+func run(kind, format string, batchSize int, checkpointPath string, dryRun bool, rejectedPath string, files []string) error {
+	if _, ok := columns[kind]; !ok {
+		return fmt.Errorf("kind %q is invalid", kind)
+	}
+	if batchSize < 1 {
+		return fmt.Errorf("batch %d is invalid", batchSize)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no file given")
+	}
+
+	cp, err := loadCheckpoint(checkpointPath, kind)
+	if err != nil {
+		return fmt.Errorf("load checkpoint: %w", err)
+	}
+
+	conf := configs.Get()
+	db, err := sql.Open(conf.DB.Driver, conf.DB.DataSource)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	im := &importer{
+		tx:            repository.NewTransaction(db),
+		ur:            repository.NewUserRepository(db),
+		flr:           repository.NewFriendListRepository(db),
+		kind:          kind,
+		batchSize:     batchSize,
+		dryRun:        dryRun,
+		checkpoint:    cp,
+		maxRejections: maxRejectionsShown,
+	}
+	if rejectedPath != "" {
+		f, err := os.Create(rejectedPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		im.rejected = json.NewEncoder(f)
+	}
+
+	var summaries []*fileSummary
+	defer func() {
+		printSummary(os.Stdout, summaries, im.rejections, dryRun)
+	}()
+	for _, file := range files {
+		summary, err := importFile(context.Background(), im, file, format)
+		if summary != nil {
+			summaries = append(summaries, summary)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+func importFile(ctx context.Context, im *importer, file, format string) (*fileSummary, error) {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(file), ".")
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rr, err := newRecordReader(f, format, im.kind)
+	if err != nil {
+		return nil, err
+	}
+
+	return im.importFile(ctx, file, rr)
+}
+
+func printSummary(w io.Writer, summaries []*fileSummary, rejections []*rejection, dryRun bool) {
+	imported := "imported"
+	if dryRun {
+		imported = "valid"
+	}
+
+	var total fileSummary
+	for _, s := range summaries {
+		fmt.Fprintf(w, "%s: read %d, %s %d, rejected %d, resumed past %d\n", s.File, s.Read, imported, s.Imported, s.Rejected, s.Resumed)
+		total.Read += s.Read
+		total.Imported += s.Imported
+		total.Rejected += s.Rejected
+		total.Resumed += s.Resumed
+	}
+	fmt.Fprintf(w, "total: read %d, %s %d, rejected %d, resumed past %d\n", total.Read, imported, total.Imported, total.Rejected, total.Resumed)
+
+	for _, r := range rejections {
+		fmt.Fprintf(w, "rejected %s:%d: %s\n", r.File, r.Line, r.Reason)
+	}
+	if len(rejections) < total.Rejected {
+		fmt.Fprintf(w, "... and %d more rejected\n", total.Rejected-len(rejections))
+	}
+}
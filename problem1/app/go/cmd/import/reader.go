@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"problem1/model"
+)
+
+const (
+	kindUsers      = "users"
+	kindFriendLink = "friend_link"
+	kindBlockList  = "block_list"
+
+	formatCSV   = "csv"
+	formatJSONL = "jsonl"
+
+	maxUserId   = 4294967295 // max unsigned int at mysql
+	maxNameLen  = 64         // varchar(64) of users.name
+	maxLineSize = 1 << 20
+)
+
+// record is one row of an input file. Err is set when the row can not be imported.
+type record struct {
+	Line int
+	Raw  string
+	User *model.User
+	Link [2]int
+	Err  error
+}
+
+// recordReader reads the rows of an input file in order. Read returns io.EOF after the last row.
+type recordReader interface {
+	Read() (*record, error)
+}
+
+func newRecordReader(r io.Reader, format, kind string) (recordReader, error) {
+	switch kind {
+	case kindUsers, kindFriendLink, kindBlockList:
+	default:
+		return nil, fmt.Errorf("kind %q is invalid", kind)
+	}
+
+	switch format {
+	case formatCSV:
+		return newCSVReader(r, kind)
+	case formatJSONL:
+		return newJSONLReader(r, kind), nil
+	default:
+		return nil, fmt.Errorf("format %q is invalid", format)
+	}
+}
+
+// columns are the header of the CSV files of each kind.
+var columns = map[string][]string{
+	kindUsers:      {"user_id", "name"},
+	kindFriendLink: {"user1_id", "user2_id"},
+	kindBlockList:  {"user1_id", "user2_id"},
+}
+
+// csvReader reads a CSV file with a header naming the columns of the kind in any order.
+type csvReader struct {
+	r     *csv.Reader
+	kind  string
+	index []int
+}
+
+func newCSVReader(r io.Reader, kind string) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	index := make([]int, len(columns[kind]))
+	for i, column := range columns[kind] {
+		index[i] = -1
+		for j, h := range header {
+			if strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")) == column {
+				index[i] = j
+			}
+		}
+		if index[i] < 0 {
+			return nil, fmt.Errorf("header has no %s", column)
+		}
+	}
+
+	return &csvReader{r: cr, kind: kind, index: index}, nil
+}
+
+func (r *csvReader) Read() (*record, error) {
+	fields, err := r.r.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return &record{Line: parseErr.StartLine, Err: parseErr.Err}, nil
+		}
+
+		return nil, err
+	}
+	line, _ := r.r.FieldPos(0)
+
+	rec := &record{Line: line, Raw: strings.Join(fields, ",")}
+	values := make([]string, len(r.index))
+	for i, j := range r.index {
+		if len(fields) <= j {
+			rec.Err = fmt.Errorf("%s is missing", columns[r.kind][i])
+			return rec, nil
+		}
+		values[i] = fields[j]
+	}
+
+	if r.kind == kindUsers {
+		userId, err := parseUserId(columns[r.kind][0], values[0])
+		if err != nil {
+			rec.Err = err
+			return rec, nil
+		}
+		rec.User = &model.User{UserId: userId, Name: values[1]}
+	} else {
+		for i := range rec.Link {
+			if rec.Link[i], err = parseUserId(columns[r.kind][i], values[i]); err != nil {
+				rec.Err = err
+				return rec, nil
+			}
+		}
+	}
+	rec.Err = validate(rec)
+
+	return rec, nil
+}
+
+func parseUserId(column, v string) (int, error) {
+	userId, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return 0, fmt.Errorf("%s is not integer", column)
+	}
+
+	return userId, nil
+}
+
+// jsonlReader reads a file of one JSON object per line, shaped as model.User for users
+// and as model.UserLinkForRequest for links, whose table is ignored. Blank lines are skipped.
+type jsonlReader struct {
+	s    *bufio.Scanner
+	kind string
+	line int
+}
+
+func newJSONLReader(r io.Reader, kind string) *jsonlReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	return &jsonlReader{s: s, kind: kind}
+}
+
+func (r *jsonlReader) Read() (*record, error) {
+	for r.s.Scan() {
+		r.line++
+		raw := strings.TrimSpace(r.s.Text())
+		if raw == "" {
+			continue
+		}
+
+		rec := &record{Line: r.line, Raw: raw}
+		if rec.Err = r.decode(rec, raw); rec.Err != nil {
+			return rec, nil
+		}
+		rec.Err = validate(rec)
+
+		return rec, nil
+	}
+	if err := r.s.Err(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", r.line+1, err)
+	}
+
+	return nil, io.EOF
+}
+
+// jsonlUser and jsonlLink tell a missing id from user 0.
+type jsonlUser struct {
+	UserId *int    `json:"userId"`
+	Name   *string `json:"name"`
+}
+
+type jsonlLink struct {
+	User1Id *int `json:"user1Id"`
+	User2Id *int `json:"user2Id"`
+}
+
+func (r *jsonlReader) decode(rec *record, raw string) error {
+	if r.kind == kindUsers {
+		var u jsonlUser
+		if err := json.Unmarshal([]byte(raw), &u); err != nil {
+			return errors.New("row is not a valid object")
+		}
+		if u.UserId == nil {
+			return errors.New("userId is missing")
+		}
+		if u.Name == nil {
+			return errors.New("name is missing")
+		}
+		rec.User = &model.User{UserId: *u.UserId, Name: *u.Name}
+
+		return nil
+	}
+
+	var l jsonlLink
+	if err := json.Unmarshal([]byte(raw), &l); err != nil {
+		return errors.New("row is not a valid object")
+	}
+	if l.User1Id == nil {
+		return errors.New("user1Id is missing")
+	}
+	if l.User2Id == nil {
+		return errors.New("user2Id is missing")
+	}
+	rec.Link = [2]int{*l.User1Id, *l.User2Id}
+
+	return nil
+}
+
+// validate checks a row with the rules of the API, so that the imported rows could have been posted.
+func validate(rec *record) error {
+	if rec.User != nil {
+		if rec.User.UserId < 0 || maxUserId < rec.User.UserId {
+			return errors.New("userId is invalid")
+		}
+		if rec.User.Name == "" {
+			return errors.New("name is empty")
+		}
+		if maxNameLen < utf8.RuneCountInString(rec.User.Name) {
+			return errors.New("name is too long")
+		}
+
+		return nil
+	}
+
+	if rec.Link[0] < 0 || maxUserId < rec.Link[0] || rec.Link[1] < 0 || maxUserId < rec.Link[1] {
+		return errors.New("userId is invalid")
+	}
+	if rec.Link[0] == rec.Link[1] {
+		return errors.New("user1Id is equal to user2Id")
+	}
+
+	return nil
+}
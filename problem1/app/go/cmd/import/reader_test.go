@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"problem1/model"
+)
+
+// readAll returns the rows of rr, with the reason of the rejected ones in place of the row.
+func readAll(t *testing.T, rr recordReader) []*record {
+	t.Helper()
+
+	var recs []*record
+	for {
+		rec, err := rr.Read()
+		if errors.Is(err, io.EOF) {
+			return recs
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		recs = append(recs, rec)
+	}
+}
+
+func Test_newRecordReader(t *testing.T) {
+	long := strings.Repeat("あ", maxNameLen+1)
+
+	tests := []struct {
+		name    string
+		input   string
+		format  string
+		kind    string
+		want    []*record
+		wantErr bool
+	}{
+		{
+			name:   "ok: csv users",
+			input:  "name,user_id\n藤井 太郎,0\n\"石川, 篤司\",1\n",
+			format: formatCSV,
+			kind:   kindUsers,
+			want: []*record{
+				{Line: 2, Raw: "藤井 太郎,0", User: &model.User{UserId: 0, Name: "藤井 太郎"}},
+				{Line: 3, Raw: "石川, 篤司,1", User: &model.User{UserId: 1, Name: "石川, 篤司"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "ok: csv users rejected",
+			input:  "user_id,name\nx,hoge\n-1,hoge\n2,\n3," + long + "\n4\n",
+			format: formatCSV,
+			kind:   kindUsers,
+			want: []*record{
+				{Line: 2, Raw: "x,hoge", Err: errors.New("user_id is not integer")},
+				{Line: 3, Raw: "-1,hoge", User: &model.User{UserId: -1, Name: "hoge"}, Err: errors.New("userId is invalid")},
+				{Line: 4, Raw: "2,", User: &model.User{UserId: 2}, Err: errors.New("name is empty")},
+				{Line: 5, Raw: "3," + long, User: &model.User{UserId: 3, Name: long}, Err: errors.New("name is too long")},
+				{Line: 6, Raw: "4", Err: errors.New("name is missing")},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "ok: csv links",
+			input:  "\ufeffuser1_id,user2_id\n1,16\n2,2\n3,4294967296\n",
+			format: formatCSV,
+			kind:   kindFriendLink,
+			want: []*record{
+				{Line: 2, Raw: "1,16", Link: [2]int{1, 16}},
+				{Line: 3, Raw: "2,2", Link: [2]int{2, 2}, Err: errors.New("user1Id is equal to user2Id")},
+				{Line: 4, Raw: "3,4294967296", Link: [2]int{3, 4294967296}, Err: errors.New("userId is invalid")},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "ng: csv header without the columns",
+			input:   "user_id,name\n1,hoge\n",
+			format:  formatCSV,
+			kind:    kindBlockList,
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:   "ok: jsonl users",
+			input:  "{\"userId\":0,\"name\":\"藤井 太郎\"}\n\n{\"name\":\"hoge\"}\n{\"userId\":\"1\",\"name\":\"hoge\"}\n",
+			format: formatJSONL,
+			kind:   kindUsers,
+			want: []*record{
+				{Line: 1, Raw: `{"userId":0,"name":"藤井 太郎"}`, User: &model.User{UserId: 0, Name: "藤井 太郎"}},
+				{Line: 3, Raw: `{"name":"hoge"}`, Err: errors.New("userId is missing")},
+				{Line: 4, Raw: `{"userId":"1","name":"hoge"}`, Err: errors.New("row is not a valid object")},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "ok: jsonl links",
+			input:  "{\"user1Id\":0,\"user2Id\":1,\"table\":\"block_list\"}\n{\"user1Id\":1}\n",
+			format: formatJSONL,
+			kind:   kindBlockList,
+			want: []*record{
+				{Line: 1, Raw: `{"user1Id":0,"user2Id":1,"table":"block_list"}`, Link: [2]int{0, 1}},
+				{Line: 2, Raw: `{"user1Id":1}`, Err: errors.New("user2Id is missing")},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "ng: format invalid",
+			input:   "",
+			format:  "xml",
+			kind:    kindUsers,
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "ng: kind invalid",
+			input:   "",
+			format:  formatJSONL,
+			kind:    "friend_request",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr, err := newRecordReader(strings.NewReader(tt.input), tt.format, tt.kind)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newRecordReader() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			assert.Equal(t, tt.want, readAll(t, rr))
+		})
+	}
+}
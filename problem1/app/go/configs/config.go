@@ -4,6 +4,7 @@ import (
 	"github.com/kelseyhightower/envconfig"
 	"log"
 	"sync"
+	"time"
 )
 
 var (
@@ -12,8 +13,16 @@ var (
 )
 
 type Config struct {
-	Server ServerConfig
-	DB     DBConfig
+	Server  ServerConfig
+	DB      DBConfig
+	Paging  PagingConfig
+	Path    PathConfig
+	Block   BlockConfig
+	Graph   GraphConfig
+	Cache   CacheConfig
+	Admin   AdminConfig
+	Legacy  LegacyConfig
+	OpenAPI OpenAPIConfig
 }
 
 type ServerConfig struct {
@@ -22,7 +31,82 @@ type ServerConfig struct {
 
 type DBConfig struct {
 	Driver     string `default:"mysql"`
-	DataSource string `default:"root:@(db:3306)/app"`
+	DataSource string `default:"root:@(db:3306)/app?parseTime=true"`
+}
+
+type PagingConfig struct {
+	// CursorSecret is the HMAC key of the paging cursors. It has no default since a key known
+	// from the source would let anyone forge cursors, and the server does not start without it.
+	CursorSecret string `split_words:"true"`
+	DefaultLimit int    `split_words:"true" default:"20"`
+	MaxLimit     int    `split_words:"true" default:"100"`
+	// RouteDefaultLimit and RouteMaxLimit override the limits above per route path,
+	// e.g. PAGING_ROUTE_MAX_LIMIT="/get_friend_list:50". The limits over the maximum of
+	// the spec are rejected by the OpenAPI validation before these apply.
+	RouteDefaultLimit map[string]int `split_words:"true"`
+	RouteMaxLimit     map[string]int `split_words:"true"`
+}
+
+// Limits returns the default and max limit of the route path.
+func (c PagingConfig) Limits(path string) (defaultLimit, maxLimit int) {
+	defaultLimit, maxLimit = c.DefaultLimit, c.MaxLimit
+	if v, ok := c.RouteDefaultLimit[path]; ok {
+		defaultLimit = v
+	}
+	if v, ok := c.RouteMaxLimit[path]; ok {
+		maxLimit = v
+	}
+	if defaultLimit > maxLimit {
+		defaultLimit = maxLimit
+	}
+
+	return defaultLimit, maxLimit
+}
+
+type PathConfig struct {
+	MaxDepth        int `split_words:"true" default:"6"`
+	MaxVisitedUsers int `split_words:"true" default:"10000"`
+}
+
+type BlockConfig struct {
+	// Policy is "mutual" to hide users from each other whoever blocked,
+	// or "one_way" to hide only the users one blocked.
+	Policy string `default:"mutual"`
+}
+
+type GraphConfig struct {
+	// Enabled serves the friend lists from friend_link and block_list loaded in memory at startup.
+	// Only one server may write the links while it is on, since each keeps its own copy.
+	Enabled bool `default:"false"`
+}
+
+type CacheConfig struct {
+	// Enabled caches the friend lists and the friends of friends lists in process.
+	Enabled bool          `default:"false"`
+	Size    int           `default:"10000"`
+	TTL     time.Duration `default:"30s"`
+	// LoadTimeout bounds computing a list missed by the cache.
+	LoadTimeout time.Duration `default:"10s"`
+}
+
+type AdminConfig struct {
+	// Token is the bearer token of the /admin routes, which are closed while it is empty.
+	Token string
+}
+
+type LegacyConfig struct {
+	// DeprecatedAt and SunsetAt are sent in the Deprecation and Sunset headers of the legacy
+	// routes aliasing the /v1 ones, as RFC 3339 times.
+	DeprecatedAt time.Time `split_words:"true" default:"2026-10-18T00:00:00Z"`
+	SunsetAt     time.Time `split_words:"true" default:"2027-04-18T00:00:00Z"`
+}
+
+type OpenAPIConfig struct {
+	// Spec is the path of the OpenAPI document the requests are validated against.
+	Spec string `default:"../../spec/openapi.yaml"`
+	// ValidateResponses also validates the responses and logs those drifting from Spec.
+	// It is meant for test and development, since every response body is buffered.
+	ValidateResponses bool `split_words:"true" default:"false"`
 }
 
 func Get() Config {
@@ -33,6 +117,30 @@ func Get() Config {
 		if err := envconfig.Process("db", &conf.DB); err != nil {
 			log.Fatal(err.Error())
 		}
+		if err := envconfig.Process("paging", &conf.Paging); err != nil {
+			log.Fatal(err.Error())
+		}
+		if err := envconfig.Process("path", &conf.Path); err != nil {
+			log.Fatal(err.Error())
+		}
+		if err := envconfig.Process("block", &conf.Block); err != nil {
+			log.Fatal(err.Error())
+		}
+		if err := envconfig.Process("graph", &conf.Graph); err != nil {
+			log.Fatal(err.Error())
+		}
+		if err := envconfig.Process("cache", &conf.Cache); err != nil {
+			log.Fatal(err.Error())
+		}
+		if err := envconfig.Process("admin", &conf.Admin); err != nil {
+			log.Fatal(err.Error())
+		}
+		if err := envconfig.Process("legacy", &conf.Legacy); err != nil {
+			log.Fatal(err.Error())
+		}
+		if err := envconfig.Process("openapi", &conf.OpenAPI); err != nil {
+			log.Fatal(err.Error())
+		}
 	})
 	return conf
 }
@@ -9,7 +9,9 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"problem1/model"
+	"problem1/pkg/cursor"
 	"problem1/pkg/httputil"
+	"problem1/service"
 	"problem1/usecase"
 )
 
@@ -17,100 +19,511 @@ import (
 
 type FriendListController interface {
 	PostUserLink(c echo.Context) error
+	PostUserLinks(c echo.Context) error
+	DeleteUserLink(c echo.Context) error
 	GetFriendListByUserId(c echo.Context) error
 	GetFriendListOfFriendsByUserId(c echo.Context) error
 	GetFriendListOfFriendsByUserIdWithPaging(c echo.Context) error
+	GetBlockListByUserId(c echo.Context) error
+	GetNeighbourhood(c echo.Context) error
+	GetFriends(c echo.Context) error
+	GetFriendsOfFriends(c echo.Context) error
+	GetBlocks(c echo.Context) error
 }
 
 type friendListController struct {
 	friendListUseCase usecase.FriendListUseCase
+	cursorCodec       *cursor.Codec
 }
 
-func NewFriendListController(flu usecase.FriendListUseCase) FriendListController {
+func NewFriendListController(flu usecase.FriendListUseCase, codec *cursor.Codec) FriendListController {
 	return &friendListController{
 		friendListUseCase: flu,
+		cursorCodec:       codec,
 	}
 }
 
 const (
 	maxUserId = 4294967295 // max unsigned int at mysql
+	// maxUserLinks bounds the links one POST /user_links may carry.
+	maxUserLinks = 1000
 )
 
-func (c *friendListController) PostUserLink(ctx echo.Context) error {
+// userIdFromQuery returns the user of the ID query parameter, or adds it to params when
+// it is invalid.
+func userIdFromQuery(ctx echo.Context, params *httputil.InvalidParams) int {
+	return parseUserId(params, "query", "ID", ctx.QueryParam("ID"))
+}
+
+// parseUserId returns the user id v of the parameter name, or adds it to params when it is
+// not an id of a user.
+func parseUserId(params *httputil.InvalidParams, in, name, v string) int {
+	if v == "" {
+		params.Add(in, name, name+" is required")
+		return 0
+	}
+	userId, err := strconv.Atoi(v)
+	if err != nil {
+		params.Add(in, name, name+" is not integer")
+		return 0
+	}
+	if userId < 0 || maxUserId < userId {
+		params.Add(in, name, name+" is invalid")
+		return 0
+	}
+
+	return userId
+}
+
+func contextInt(ctx echo.Context, key string) (int, error) {
+	v, ok := ctx.Get(key).(int)
+	if !ok {
+		return 0, httputil.NewHTTPError(errors.New(key+" not set"), http.StatusInternalServerError, httputil.ErrorCodeInternal, "")
+	}
+
+	return v, nil
+}
+
+// sortFromQuery returns the sort query parameter, or userId when it is not given.
+// It must be one of allowed, the orders the endpoint supports, or else it is added to params.
+func sortFromQuery(ctx echo.Context, params *httputil.InvalidParams, allowed ...model.FriendListSort) model.FriendListSort {
+	sort := model.FriendListSort(ctx.QueryParam("sort"))
+	if sort == "" {
+		return model.FriendListSortUserId
+	}
+	for _, s := range allowed {
+		if sort == s {
+			return sort
+		}
+	}
+	params.Add("query", "sort", "sort is invalid")
+
+	return ""
+}
+
+var (
+	friendListSorts = []model.FriendListSort{
+		model.FriendListSortUserId,
+		model.FriendListSortName,
+		model.FriendListSortNameDesc,
+		model.FriendListSortAddedAt,
+	}
+	// friendOfFriendSorts leaves out addedAt since a friend of friend has no single link to order by.
+	friendOfFriendSorts = []model.FriendListSort{
+		model.FriendListSortUserId,
+		model.FriendListSortName,
+		model.FriendListSortNameDesc,
+	}
+)
+
+// pageFromContext returns the limit and offset set by middleware.Paging, which has
+// already clamped them, so it fails only when the route lacks the middleware.
+func pageFromContext(ctx echo.Context) (limit, offset int, err error) {
+	if limit, err = contextInt(ctx, "limit"); err != nil {
+		return 0, 0, err
+	}
+	if offset, err = contextInt(ctx, "offset"); err != nil {
+		return 0, 0, err
+	}
+
+	return limit, offset, nil
+}
+
+// legacyPageFromContext is pageFromContext for the legacy routes, which return the whole
+// list unless limit or page is given. A limit of 0 asks for the whole list.
+func legacyPageFromContext(ctx echo.Context) (limit, offset int, err error) {
+	if ctx.QueryParam("limit") == "" && ctx.QueryParam("page") == "" {
+		return 0, 0, nil
+	}
+
+	return pageFromContext(ctx)
+}
+
+// setPagingLinkHeader links the first, previous, next and last pages. Pages read by
+// cursor only link the first and next page since they have no page number.
+func setPagingLinkHeader(ctx echo.Context, p *model.Paging, nextCursor string) {
+	if p == nil {
+		return
+	}
+
+	if p.Page == 0 {
+		links := []httputil.Link{{Rel: "first", Query: map[string]string{"cursor": ""}}}
+		if nextCursor != "" {
+			links = append(links, httputil.Link{Rel: "next", Query: map[string]string{"cursor": nextCursor}})
+		}
+		httputil.SetLinkHeader(ctx, links...)
+
+		return
+	}
+
+	page := func(n int) map[string]string {
+		return map[string]string{"page": strconv.Itoa(n)}
+	}
+	links := []httputil.Link{{Rel: "first", Query: page(1)}}
+	if p.Page > 1 {
+		links = append(links, httputil.Link{Rel: "prev", Query: page(p.Page - 1)})
+	}
+	if p.HasNext {
+		links = append(links, httputil.Link{Rel: "next", Query: page(p.Page + 1)})
+	}
+	links = append(links, httputil.Link{Rel: "last", Query: page(p.LastPage())})
+	httputil.SetLinkHeader(ctx, links...)
+}
+
+func bindUserLinkRequest(ctx echo.Context) (*model.UserLinkForRequest, error) {
 	var req model.UserLinkForRequest
 	if err := json.NewDecoder(ctx.Request().Body).Decode(&req); err != nil {
-		return httputil.NewHTTPError(errors.New("request invalid"), http.StatusBadRequest, "")
+		return nil, httputil.NewHTTPError(errors.New("request invalid"), http.StatusBadRequest, httputil.ErrorCodeInvalidRequest, "")
 	}
 
-	if req.User1Id < 0 || maxUserId < req.User1Id || req.User2Id < 0 || maxUserId < req.User2Id {
-		return httputil.NewHTTPError(errors.New("userId is invalid"), http.StatusBadRequest, "")
+	if params := validateUserLinkRequest(&req); len(params) > 0 {
+		code := httputil.ErrorCodeInvalidParameter
+		if len(params) == 1 && params[0].Name == "/table" {
+			code = httputil.ErrorCodeInvalidTable
+		}
+
+		return nil, params.Err(code)
+	}
+
+	return &req, nil
+}
+
+func validateUserLinkRequest(req *model.UserLinkForRequest) httputil.InvalidParams {
+	var params httputil.InvalidParams
+	if req.User1Id < 0 || maxUserId < req.User1Id {
+		params.Add("body", "/user1Id", "user1Id is invalid")
 	}
-	if req.User1Id == req.User2Id {
-		return httputil.NewHTTPError(errors.New("user1Id is equal to user2Id"), http.StatusBadRequest, "")
+	if req.User2Id < 0 || maxUserId < req.User2Id {
+		params.Add("body", "/user2Id", "user2Id is invalid")
+	}
+	if len(params) == 0 && req.User1Id == req.User2Id {
+		params.Add("body", "/user2Id", "user1Id is equal to user2Id")
 	}
 
 	switch req.Table {
 	case "friend_link", "block_list":
-		if err := c.friendListUseCase.PostUserLink(&req); err != nil {
+	default:
+		params.Add("body", "/table", "table not exist")
+	}
+
+	return params
+}
+
+func userLinksModeFromQuery(ctx echo.Context, params *httputil.InvalidParams) model.UserLinksMode {
+	switch mode := model.UserLinksMode(ctx.QueryParam("mode")); mode {
+	case "":
+		return model.UserLinksModeAllOrNothing
+	case model.UserLinksModeAllOrNothing, model.UserLinksModeBestEffort:
+		return mode
+	default:
+		params.Add("query", "mode", "mode is invalid")
+
+		return ""
+	}
+}
+
+func (c *friendListController) PostUserLink(ctx echo.Context) error {
+	req, err := bindUserLinkRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.friendListUseCase.PostUserLink(ctx.Request().Context(), req); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusCreated)
+}
+
+// PostUserLinks inserts the links of the body in one transaction and reports the result of
+// each of them. It responds 201 when all of them are applied, 207 when only some are in
+// best-effort mode, and 400 when none are.
+func (c *friendListController) PostUserLinks(ctx echo.Context) error {
+	var params httputil.InvalidParams
+	mode := userLinksModeFromQuery(ctx, &params)
+	if err := params.Err(httputil.ErrorCodeInvalidParameter); err != nil {
+		return err
+	}
+
+	var reqs []*model.UserLinkForRequest
+	if err := json.NewDecoder(ctx.Request().Body).Decode(&reqs); err != nil {
+		return httputil.NewHTTPError(errors.New("request invalid"), http.StatusBadRequest, httputil.ErrorCodeInvalidRequest, "")
+	}
+	if len(reqs) == 0 || maxUserLinks < len(reqs) {
+		return httputil.NewHTTPError(errors.New("number of links is invalid"), http.StatusBadRequest, httputil.ErrorCodeInvalidParameter, "")
+	}
+
+	report := &model.UserLinksReport{
+		Mode:    mode,
+		Results: make([]*model.UserLinkResult, len(reqs)),
+	}
+	var (
+		valid   []*model.UserLinkForRequest
+		indexes []int
+	)
+	for i, req := range reqs {
+		report.Results[i] = &model.UserLinkResult{Index: i}
+		if req == nil {
+			report.Results[i].Status = model.UserLinkStatusInvalid
+			report.Results[i].Message = "request invalid"
+			continue
+		}
+		if params := validateUserLinkRequest(req); len(params) > 0 {
+			report.Results[i].Status = model.UserLinkStatusInvalid
+			report.Results[i].Message = params.Message()
+			continue
+		}
+		valid = append(valid, req)
+		indexes = append(indexes, i)
+	}
+
+	if len(valid) < len(reqs) && mode == model.UserLinksModeAllOrNothing {
+		for _, i := range indexes {
+			report.Results[i].Status = model.UserLinkStatusSkipped
+		}
+
+		return ctx.JSON(http.StatusBadRequest, report)
+	}
+
+	if len(valid) > 0 {
+		statuses, err := c.friendListUseCase.PostUserLinks(ctx.Request().Context(), valid, mode)
+		if err != nil {
 			return err
 		}
+		for j, i := range indexes {
+			report.Results[i].Status = statuses[j]
+		}
+	}
 
-		return ctx.NoContent(http.StatusCreated)
+	applied := 0
+	for _, r := range report.Results {
+		if r.Status.Applied() {
+			applied++
+		}
+	}
+	switch applied {
+	case len(reqs):
+		return ctx.JSON(http.StatusCreated, report)
+	case 0:
+		return ctx.JSON(http.StatusBadRequest, report)
 	default:
-		return httputil.NewHTTPError(errors.New("table not exist"), http.StatusBadRequest, "")
+		return ctx.JSON(http.StatusMultiStatus, report)
 	}
 }
 
-func (c *friendListController) GetFriendListByUserId(ctx echo.Context) error {
-	userId, err := strconv.Atoi(ctx.QueryParam("ID"))
+func (c *friendListController) DeleteUserLink(ctx echo.Context) error {
+	req, err := bindUserLinkRequest(ctx)
 	if err != nil {
-		return httputil.NewHTTPError(err, http.StatusBadRequest, "userId is not integer or not exist in query parameter")
+		return err
 	}
-	if userId < 0 || maxUserId < userId {
-		return httputil.NewHTTPError(errors.New("userId is invalid"), http.StatusBadRequest, "")
+
+	if err := c.friendListUseCase.DeleteUserLink(ctx.Request().Context(), req); err != nil {
+		return err
 	}
-	ctx.Set("userId", userId)
 
-	friendList, err := c.friendListUseCase.GetFriendListByUserId(ctx)
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (c *friendListController) GetFriendListByUserId(ctx echo.Context) error {
+	var params httputil.InvalidParams
+	userId := userIdFromQuery(ctx, &params)
+
+	return c.getFriendList(ctx, userId, &params, legacyPageFromContext)
+}
+
+// GetFriends is GetFriendListByUserId with the user in the id path parameter.
+func (c *friendListController) GetFriends(ctx echo.Context) error {
+	var params httputil.InvalidParams
+	userId := userIdFromParam(ctx, &params, "id")
+
+	return c.getFriendList(ctx, userId, &params, pageFromContext)
+}
+
+// getFriendList adds the invalid parameters of its own to params, which has those of the
+// user, and reports all of them at once.
+func (c *friendListController) getFriendList(ctx echo.Context, userId int, params *httputil.InvalidParams, pageFrom func(echo.Context) (int, int, error)) error {
+	sort := sortFromQuery(ctx, params, friendListSorts...)
+	if err := params.Err(httputil.ErrorCodeInvalidParameter); err != nil {
+		return err
+	}
+
+	limit, offset, err := pageFrom(ctx)
 	if err != nil {
 		return err
 	}
 
+	friendList, err := c.friendListUseCase.GetFriendListByUserId(ctx.Request().Context(), userId, sort, limit, offset)
+	if err != nil {
+		return err
+	}
+
+	setPagingLinkHeader(ctx, friendList.Paging, "")
+
 	return ctx.JSON(http.StatusOK, friendList)
 }
 
 func (c *friendListController) GetFriendListOfFriendsByUserId(ctx echo.Context) error {
-	userId, err := strconv.Atoi(ctx.QueryParam("ID"))
-	if err != nil {
-		return httputil.NewHTTPError(err, http.StatusBadRequest, "userId is not integer or not exist in query parameter")
+	var params httputil.InvalidParams
+	userId := userIdFromQuery(ctx, &params)
+	sort := sortFromQuery(ctx, &params, friendOfFriendSorts...)
+	if err := params.Err(httputil.ErrorCodeInvalidParameter); err != nil {
+		return err
 	}
-	if userId < 0 || maxUserId < userId {
-		return httputil.NewHTTPError(errors.New("userId is invalid"), http.StatusBadRequest, "")
+
+	limit, offset, err := legacyPageFromContext(ctx)
+	if err != nil {
+		return err
 	}
-	ctx.Set("userId", userId)
 
-	friendList, err := c.friendListUseCase.GetFriendListOfFriendsByUserId(ctx)
+	friendList, err := c.friendListUseCase.GetFriendListOfFriendsByUserId(ctx.Request().Context(), userId, sort, limit, offset)
 	if err != nil {
 		return err
 	}
 
+	setPagingLinkHeader(ctx, friendList.Paging, "")
+
 	return ctx.JSON(http.StatusOK, friendList)
 }
 
 func (c *friendListController) GetFriendListOfFriendsByUserIdWithPaging(ctx echo.Context) error {
-	userId, err := strconv.Atoi(ctx.QueryParam("ID"))
-	if err != nil {
-		return httputil.NewHTTPError(err, http.StatusBadRequest, "userId is not integer or not exist in query parameter")
+	var params httputil.InvalidParams
+	userId := userIdFromQuery(ctx, &params)
+
+	return c.getFriendListOfFriendsWithPaging(ctx, userId, &params)
+}
+
+// GetFriendsOfFriends is GetFriendListOfFriendsByUserIdWithPaging with the user in the id path parameter.
+func (c *friendListController) GetFriendsOfFriends(ctx echo.Context) error {
+	var params httputil.InvalidParams
+	userId := userIdFromParam(ctx, &params, "id")
+
+	return c.getFriendListOfFriendsWithPaging(ctx, userId, &params)
+}
+
+// getFriendListOfFriendsWithPaging adds the invalid parameters of its own to params, which
+// has those of the user, and reports all of them at once.
+func (c *friendListController) getFriendListOfFriendsWithPaging(ctx echo.Context, userId int, params *httputil.InvalidParams) error {
+	sort := sortFromQuery(ctx, params, friendOfFriendSorts...)
+	cur, withCursor := ctx.Get("cursor").(cursor.Cursor)
+	// the cursor is the last user_id, so it only seeks in user_id order
+	if withCursor && sort != "" && sort != model.FriendListSortUserId {
+		params.Add("query", "sort", "sort is not supported with cursor")
 	}
-	if userId < 0 || maxUserId < userId {
-		return httputil.NewHTTPError(errors.New("userId is invalid"), http.StatusBadRequest, "")
+	if err := params.Err(httputil.ErrorCodeInvalidParameter); err != nil {
+		return err
 	}
-	ctx.Set("userId", userId)
 
-	friendList, err := c.friendListUseCase.GetFriendListOfFriendsByUserIdWithPaging(ctx)
+	var (
+		friendList *model.FriendList
+		err        error
+	)
+	if withCursor {
+		// a cursor issued for another user's list must not seek in this one
+		if cur.UserId != userId {
+			return httputil.NewHTTPError(errors.New("cursor is issued for another user"), http.StatusBadRequest, httputil.ErrorCodeInvalidCursor, "")
+		}
+
+		var limit int
+		if limit, err = contextInt(ctx, "limit"); err != nil {
+			return err
+		}
+
+		friendList, err = c.friendListUseCase.GetFriendListOfFriendsByUserIdWithCursor(ctx.Request().Context(), userId, cur.LastUserId, limit)
+	} else {
+		var limit, offset int
+		if limit, offset, err = pageFromContext(ctx); err != nil {
+			return err
+		}
+
+		friendList, err = c.friendListUseCase.GetFriendListOfFriendsByUserIdWithPaging(ctx.Request().Context(), userId, sort, limit, offset)
+	}
 	if err != nil {
 		return err
 	}
 
+	// the cursor seeks by user_id, so following it under another sort would skip or repeat rows
+	if sort == model.FriendListSortUserId && friendList.Paging != nil && friendList.Paging.HasNext && len(friendList.Friends) > 0 {
+		friendList.NextCursor = c.cursorCodec.Encode(cursor.Cursor{
+			UserId:     userId,
+			LastUserId: friendList.Friends[len(friendList.Friends)-1].UserId,
+		})
+	}
+	setPagingLinkHeader(ctx, friendList.Paging, friendList.NextCursor)
+
 	return ctx.JSON(http.StatusOK, friendList)
 }
+
+func (c *friendListController) GetBlockListByUserId(ctx echo.Context) error {
+	var params httputil.InvalidParams
+	userId := userIdFromQuery(ctx, &params)
+
+	return c.getBlockList(ctx, userId, &params, legacyPageFromContext)
+}
+
+// GetBlocks is GetBlockListByUserId with the user in the id path parameter.
+func (c *friendListController) GetBlocks(ctx echo.Context) error {
+	var params httputil.InvalidParams
+	userId := userIdFromParam(ctx, &params, "id")
+
+	return c.getBlockList(ctx, userId, &params, pageFromContext)
+}
+
+func (c *friendListController) getBlockList(ctx echo.Context, userId int, params *httputil.InvalidParams, pageFrom func(echo.Context) (int, int, error)) error {
+	if err := params.Err(httputil.ErrorCodeInvalidParameter); err != nil {
+		return err
+	}
+
+	limit, offset, err := pageFrom(ctx)
+	if err != nil {
+		return err
+	}
+
+	blockList, err := c.friendListUseCase.GetBlockListByUserId(ctx.Request().Context(), userId, limit, offset)
+	if err != nil {
+		return err
+	}
+
+	setPagingLinkHeader(ctx, blockList.Paging, "")
+
+	return ctx.JSON(http.StatusOK, blockList)
+}
+
+// GetNeighbourhood returns the users exactly depth hops away when exact is true, or up to depth hops away otherwise.
+func (c *friendListController) GetNeighbourhood(ctx echo.Context) error {
+	var params httputil.InvalidParams
+	userId := userIdFromQuery(ctx, &params)
+	depth := 2
+	if v := ctx.QueryParam("depth"); v != "" {
+		var err error
+		if depth, err = strconv.Atoi(v); err != nil || depth < 1 || service.MaxNeighbourhoodDepth < depth {
+			params.Add("query", "depth", "depth is invalid")
+		}
+	}
+	minDepth := 1
+	if v := ctx.QueryParam("exact"); v != "" {
+		exact, err := strconv.ParseBool(v)
+		if err != nil {
+			params.Add("query", "exact", "exact is not boolean")
+		}
+		if exact {
+			minDepth = depth
+		}
+	}
+	if err := params.Err(httputil.ErrorCodeInvalidParameter); err != nil {
+		return err
+	}
+
+	limit, offset, err := pageFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	neighbourList, err := c.friendListUseCase.GetNeighbourhood(ctx.Request().Context(), userId, minDepth, depth, limit, offset)
+	if err != nil {
+		return err
+	}
+
+	setPagingLinkHeader(ctx, neighbourList.Paging, "")
+
+	return ctx.JSON(http.StatusOK, neighbourList)
+}
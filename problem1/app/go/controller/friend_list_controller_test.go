@@ -1,6 +1,9 @@
 package controller
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
 	"net/http"
 	"testing"
 
@@ -8,10 +11,16 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 
+	"problem1/configs"
 	"problem1/mock/mock_usecase"
 	"problem1/model"
+	"problem1/pkg/cursor"
 	"problem1/pkg/httputil"
+	"problem1/pkg/httputil/middleware"
 	"problem1/pkg/testutil"
+	"problem1/repository"
+	"problem1/service"
+	"problem1/usecase"
 )
 
 type friendListControllerTest struct {
@@ -20,6 +29,12 @@ type friendListControllerTest struct {
 	echo *echo.Echo
 }
 
+// testCursorCodec and testPaging stand in for the ones newServer builds from the config.
+var (
+	testCursorCodec = cursor.NewCodec([]byte("secret"))
+	testPaging      = middleware.Paging(configs.PagingConfig{DefaultLimit: 20, MaxLimit: 100}, testCursorCodec)
+)
+
 func newFriendListControllerTest(t *testing.T) *friendListControllerTest {
 	t.Helper()
 
@@ -28,7 +43,7 @@ func newFriendListControllerTest(t *testing.T) *friendListControllerTest {
 
 	return &friendListControllerTest{
 		flu:  flu,
-		flc:  NewFriendListController(flu),
+		flc:  NewFriendListController(flu, testCursorCodec),
 		echo: echo.New(),
 	}
 }
@@ -51,7 +66,7 @@ func newFriendList() *model.FriendList {
 func Test_friendListController_PostUserLink(t *testing.T) {
 	testRequest := &model.UserLinkForRequest{
 		User1Id: testutil.UserIDForDebug,
-		User2Id: testutil.UserIDForDebug,
+		User2Id: 111111,
 		Table:   "friend_link",
 	}
 
@@ -65,14 +80,14 @@ func Test_friendListController_PostUserLink(t *testing.T) {
 		{
 			name: "ok",
 			expects: func(ct *friendListControllerTest) {
-				ct.flu.EXPECT().PostUserLink(testRequest).Return(nil)
+				ct.flu.EXPECT().PostUserLink(gomock.Any(), testRequest).Return(nil)
 			},
 			payload: &model.UserLinkForRequest{
 				User1Id: testutil.UserIDForDebug,
-				User2Id: testutil.UserIDForDebug,
+				User2Id: 111111,
 				Table:   "friend_link",
 			},
-			wantStatus: http.StatusOK,
+			wantStatus: http.StatusCreated,
 			wantErr:    false,
 		},
 		{
@@ -105,11 +120,11 @@ func Test_friendListController_PostUserLink(t *testing.T) {
 		{
 			name: "ng: error at PostUserLink()",
 			expects: func(ct *friendListControllerTest) {
-				ct.flu.EXPECT().PostUserLink(testRequest).Return(testutil.ErrTest)
+				ct.flu.EXPECT().PostUserLink(gomock.Any(), testRequest).Return(testutil.ErrTest)
 			},
 			payload: &model.UserLinkForRequest{
 				User1Id: testutil.UserIDForDebug,
-				User2Id: testutil.UserIDForDebug,
+				User2Id: 111111,
 				Table:   "friend_link",
 			},
 			wantStatus: http.StatusInternalServerError,
@@ -120,7 +135,7 @@ func Test_friendListController_PostUserLink(t *testing.T) {
 			expects: func(ct *friendListControllerTest) {},
 			payload: &model.UserLinkForRequest{
 				User1Id: testutil.UserIDForDebug,
-				User2Id: testutil.UserIDForDebug,
+				User2Id: 111111,
 				Table:   "invalid",
 			},
 			wantStatus: http.StatusBadRequest,
@@ -148,6 +163,260 @@ func Test_friendListController_PostUserLink(t *testing.T) {
 	}
 }
 
+func Test_friendListController_PostUserLinks(t *testing.T) {
+	friendLink := &model.UserLinkForRequest{User1Id: testutil.UserIDForDebug, User2Id: 111111, Table: "friend_link"}
+	blockList := &model.UserLinkForRequest{User1Id: testutil.UserIDForDebug, User2Id: 222222, Table: "block_list"}
+	invalid := &model.UserLinkForRequest{User1Id: testutil.UserIDForDebug, User2Id: testutil.UserIDForDebug, Table: "friend_link"}
+	result := func(index int, status model.UserLinkStatus, message string) *model.UserLinkResult {
+		return &model.UserLinkResult{Index: index, Status: status, Message: message}
+	}
+
+	tests := []struct {
+		name       string
+		expects    func(test *friendListControllerTest)
+		mode       string
+		payload    any
+		wantStatus int
+		want       *model.UserLinksReport
+		wantParams []*httputil.InvalidParam
+	}{
+		{
+			name: "ok: all applied",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().PostUserLinks(gomock.Any(), []*model.UserLinkForRequest{friendLink, blockList}, model.UserLinksModeAllOrNothing).
+					Return([]model.UserLinkStatus{model.UserLinkStatusCreated, model.UserLinkStatusExists}, nil)
+			},
+			mode:       "",
+			payload:    []*model.UserLinkForRequest{friendLink, blockList},
+			wantStatus: http.StatusCreated,
+			want: &model.UserLinksReport{
+				Mode:    model.UserLinksModeAllOrNothing,
+				Results: []*model.UserLinkResult{result(0, model.UserLinkStatusCreated, ""), result(1, model.UserLinkStatusExists, "")},
+			},
+		},
+		{
+			name:       "ng: all-or-nothing with an invalid link",
+			expects:    func(ct *friendListControllerTest) {},
+			mode:       "all-or-nothing",
+			payload:    []*model.UserLinkForRequest{friendLink, invalid},
+			wantStatus: http.StatusBadRequest,
+			want: &model.UserLinksReport{
+				Mode:    model.UserLinksModeAllOrNothing,
+				Results: []*model.UserLinkResult{result(0, model.UserLinkStatusSkipped, ""), result(1, model.UserLinkStatusInvalid, "user1Id is equal to user2Id")},
+			},
+		},
+		{
+			name: "ng: all-or-nothing with a user not exist",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().PostUserLinks(gomock.Any(), []*model.UserLinkForRequest{friendLink, blockList}, model.UserLinksModeAllOrNothing).
+					Return([]model.UserLinkStatus{model.UserLinkStatusSkipped, model.UserLinkStatusUserNotExist}, nil)
+			},
+			mode:       "all-or-nothing",
+			payload:    []*model.UserLinkForRequest{friendLink, blockList},
+			wantStatus: http.StatusBadRequest,
+			want: &model.UserLinksReport{
+				Mode:    model.UserLinksModeAllOrNothing,
+				Results: []*model.UserLinkResult{result(0, model.UserLinkStatusSkipped, ""), result(1, model.UserLinkStatusUserNotExist, "")},
+			},
+		},
+		{
+			name: "ok: best-effort partially applied",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().PostUserLinks(gomock.Any(), []*model.UserLinkForRequest{friendLink, blockList}, model.UserLinksModeBestEffort).
+					Return([]model.UserLinkStatus{model.UserLinkStatusCreated, model.UserLinkStatusUserNotExist}, nil)
+			},
+			mode:       "best-effort",
+			payload:    []*model.UserLinkForRequest{invalid, friendLink, blockList},
+			wantStatus: http.StatusMultiStatus,
+			want: &model.UserLinksReport{
+				Mode: model.UserLinksModeBestEffort,
+				Results: []*model.UserLinkResult{
+					result(0, model.UserLinkStatusInvalid, "user1Id is equal to user2Id"),
+					result(1, model.UserLinkStatusCreated, ""),
+					result(2, model.UserLinkStatusUserNotExist, ""),
+				},
+			},
+		},
+		{
+			name:       "ng: best-effort with no valid link",
+			expects:    func(ct *friendListControllerTest) {},
+			mode:       "best-effort",
+			payload:    []*model.UserLinkForRequest{invalid},
+			wantStatus: http.StatusBadRequest,
+			want: &model.UserLinksReport{
+				Mode:    model.UserLinksModeBestEffort,
+				Results: []*model.UserLinkResult{result(0, model.UserLinkStatusInvalid, "user1Id is equal to user2Id")},
+			},
+		},
+		{
+			name:       "ng: mode invalid",
+			expects:    func(ct *friendListControllerTest) {},
+			mode:       "invalid",
+			payload:    []*model.UserLinkForRequest{friendLink},
+			wantStatus: http.StatusBadRequest,
+			wantParams: []*httputil.InvalidParam{
+				{In: "query", Name: "mode", Reason: "mode is invalid"},
+			},
+		},
+		{
+			name:       "ng: error at Decode()",
+			expects:    func(ct *friendListControllerTest) {},
+			payload:    friendLink,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "ng: no link",
+			expects:    func(ct *friendListControllerTest) {},
+			payload:    []*model.UserLinkForRequest{},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "ng: too many links",
+			expects:    func(ct *friendListControllerTest) {},
+			payload:    make([]*model.UserLinkForRequest, maxUserLinks+1),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "ng: error at PostUserLinks()",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().PostUserLinks(gomock.Any(), []*model.UserLinkForRequest{friendLink}, model.UserLinksModeAllOrNothing).
+					Return(nil, testutil.ErrTest)
+			},
+			payload:    []*model.UserLinkForRequest{friendLink},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := newFriendListControllerTest(t)
+			tt.expects(ct)
+
+			rec, req := httputil.NewRequestAndRecorder("POST", "/user_links?mode="+tt.mode, testutil.I2Reader(t, tt.payload))
+			ct.echo.POST("/user_links", func(c echo.Context) error {
+				if err := ct.flc.PostUserLinks(c); err != nil {
+					return httputil.RespondError(c, err)
+				}
+
+				return nil
+			})
+			ct.echo.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.want != nil {
+				testutil.AssertResponseBody(t, tt.want, rec.Body)
+			}
+			if tt.wantParams != nil {
+				var got httputil.ErrorResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+				assert.Equal(t, tt.wantParams, got.Errors)
+			}
+		})
+	}
+}
+
+func Test_friendListController_DeleteUserLink(t *testing.T) {
+	testRequest := &model.UserLinkForRequest{
+		User1Id: testutil.UserIDForDebug,
+		User2Id: 111111,
+		Table:   "friend_link",
+	}
+
+	tests := []struct {
+		name       string
+		expects    func(test *friendListControllerTest)
+		payload    any
+		wantStatus int
+		wantErr    bool
+	}{
+		{
+			name: "ok",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().DeleteUserLink(gomock.Any(), testRequest).Return(nil)
+			},
+			payload:    testRequest,
+			wantStatus: http.StatusNoContent,
+			wantErr:    false,
+		},
+		{
+			name:       "ng: error at Decode()",
+			expects:    func(ct *friendListControllerTest) {},
+			payload:    "invalid",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:    "ng: user1Id not invalid",
+			expects: func(ct *friendListControllerTest) {},
+			payload: &model.UserLinkForRequest{
+				User1Id: -1,
+				User2Id: testutil.UserIDForDebug,
+			},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:    "ng: user1Id is equal to user2Id",
+			expects: func(ct *friendListControllerTest) {},
+			payload: &model.UserLinkForRequest{
+				User1Id: testutil.UserIDForDebug,
+				User2Id: testutil.UserIDForDebug,
+				Table:   "friend_link",
+			},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:    "ng: table not exist",
+			expects: func(ct *friendListControllerTest) {},
+			payload: &model.UserLinkForRequest{
+				User1Id: testutil.UserIDForDebug,
+				User2Id: 111111,
+				Table:   "invalid",
+			},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name: "ng: user link not exist",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().DeleteUserLink(gomock.Any(), testRequest).Return(httputil.NewHTTPError(testutil.ErrTest, http.StatusNotFound, httputil.ErrorCodeUserLinkNotFound, ""))
+			},
+			payload:    testRequest,
+			wantStatus: http.StatusNotFound,
+			wantErr:    true,
+		},
+		{
+			name: "ng: error at DeleteUserLink()",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().DeleteUserLink(gomock.Any(), testRequest).Return(testutil.ErrTest)
+			},
+			payload:    testRequest,
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := newFriendListControllerTest(t)
+			tt.expects(ct)
+
+			rec, req := httputil.NewRequestAndRecorder("DELETE", "/user_link", testutil.I2Reader(t, tt.payload))
+			ct.echo.DELETE("/user_link", func(c echo.Context) error {
+				if err := ct.flc.DeleteUserLink(c); err != nil {
+					return httputil.RespondError(c, err)
+				}
+
+				return nil
+			})
+			ct.echo.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
 func Test_friendListController_GetFriendListByUserId(t *testing.T) {
 	want := newFriendList()
 
@@ -158,17 +427,56 @@ func Test_friendListController_GetFriendListByUserId(t *testing.T) {
 		want       *model.FriendList
 		wantStatus int
 		wantErr    bool
+		wantParams []*httputil.InvalidParam
 	}{
 		{
 			name: "ok",
 			expects: func(ct *friendListControllerTest) {
-				ct.flu.EXPECT().GetFriendListByUserId(gomock.Any()).Return(want, nil)
+				ct.flu.EXPECT().GetFriendListByUserId(gomock.Any(), testutil.UserIDForDebug, model.FriendListSortUserId, 0, 0).Return(want, nil)
 			},
 			url:        "/get_friend_list?ID=123456789",
 			want:       want,
 			wantStatus: http.StatusOK,
 			wantErr:    false,
 		},
+		{
+			name: "ok: sort by name descending",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetFriendListByUserId(gomock.Any(), testutil.UserIDForDebug, model.FriendListSortNameDesc, 0, 0).Return(want, nil)
+			},
+			url:        "/get_friend_list?ID=123456789&sort=-name",
+			want:       want,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name: "ok: sort by addedAt",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetFriendListByUserId(gomock.Any(), testutil.UserIDForDebug, model.FriendListSortAddedAt, 0, 0).Return(want, nil)
+			},
+			url:        "/get_friend_list?ID=123456789&sort=addedAt",
+			want:       want,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name: "ok: paged with limit and page",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetFriendListByUserId(gomock.Any(), testutil.UserIDForDebug, model.FriendListSortUserId, 5, 5).Return(want, nil)
+			},
+			url:        "/get_friend_list?ID=123456789&limit=5&page=2",
+			want:       want,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:       "ng: sort invalid",
+			expects:    func(ct *friendListControllerTest) {},
+			url:        "/get_friend_list?ID=123456789&sort=name%3BDROP%20TABLE%20users",
+			want:       nil,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
 		{
 			name:       "ng: userId missing in query parameter",
 			expects:    func(ct *friendListControllerTest) {},
@@ -201,12 +509,24 @@ func Test_friendListController_GetFriendListByUserId(t *testing.T) {
 			wantStatus: http.StatusBadRequest,
 			wantErr:    true,
 		},
+		{
+			name:       "ng: every invalid parameter",
+			expects:    func(ct *friendListControllerTest) {},
+			url:        "/get_friend_list?sort=invalid",
+			want:       nil,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+			wantParams: []*httputil.InvalidParam{
+				{In: "query", Name: "ID", Reason: "ID is required"},
+				{In: "query", Name: "sort", Reason: "sort is invalid"},
+			},
+		},
 		{
 			name: "ng: error at GetFriendListByUserId()",
 			expects: func(ct *friendListControllerTest) {
-				ct.flu.EXPECT().GetFriendListByUserId(gomock.Any()).Return(nil, testutil.ErrTest)
+				ct.flu.EXPECT().GetFriendListByUserId(gomock.Any(), testutil.UserIDForDebug, model.FriendListSortUserId, 0, 0).Return(nil, testutil.ErrTest)
 			},
-			url:        "/get_friend_list?ID=0",
+			url:        "/get_friend_list?ID=123456789",
 			want:       nil,
 			wantStatus: http.StatusInternalServerError,
 			wantErr:    true,
@@ -225,13 +545,18 @@ func Test_friendListController_GetFriendListByUserId(t *testing.T) {
 				}
 
 				return nil
-			})
+			}, testPaging)
 			ct.echo.ServeHTTP(rec, req)
 
 			assert.Equal(t, tt.wantStatus, rec.Code)
 			if !tt.wantErr {
 				testutil.AssertResponseBody(t, want, rec.Body)
 			}
+			if tt.wantParams != nil {
+				var got httputil.ErrorResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+				assert.Equal(t, tt.wantParams, got.Errors)
+			}
 		})
 	}
 }
@@ -250,13 +575,41 @@ func Test_friendListController_GetFriendListOfFriendsByUserId(t *testing.T) {
 		{
 			name: "ok",
 			expects: func(ct *friendListControllerTest) {
-				ct.flu.EXPECT().GetFriendListOfFriendsByUserId(gomock.Any()).Return(want, nil)
+				ct.flu.EXPECT().GetFriendListOfFriendsByUserId(gomock.Any(), testutil.UserIDForDebug, model.FriendListSortUserId, 0, 0).Return(want, nil)
 			},
 			url:        "/get_friend_list?ID=123456789",
 			want:       want,
 			wantStatus: http.StatusOK,
 			wantErr:    false,
 		},
+		{
+			name: "ok: sort by name",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetFriendListOfFriendsByUserId(gomock.Any(), testutil.UserIDForDebug, model.FriendListSortName, 0, 0).Return(want, nil)
+			},
+			url:        "/get_friend_list?ID=123456789&sort=name",
+			want:       want,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name: "ok: paged with page",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetFriendListOfFriendsByUserId(gomock.Any(), testutil.UserIDForDebug, model.FriendListSortUserId, 20, 20).Return(want, nil)
+			},
+			url:        "/get_friend_list?ID=123456789&page=2",
+			want:       want,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:       "ng: sort by addedAt not supported",
+			expects:    func(ct *friendListControllerTest) {},
+			url:        "/get_friend_list?ID=123456789&sort=addedAt",
+			want:       nil,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
 		{
 			name:       "ng: userId missing in query parameter",
 			expects:    func(ct *friendListControllerTest) {},
@@ -292,9 +645,9 @@ func Test_friendListController_GetFriendListOfFriendsByUserId(t *testing.T) {
 		{
 			name: "ng: error at GetFriendListOfFriendsByUserId()",
 			expects: func(ct *friendListControllerTest) {
-				ct.flu.EXPECT().GetFriendListOfFriendsByUserId(gomock.Any()).Return(nil, testutil.ErrTest)
+				ct.flu.EXPECT().GetFriendListOfFriendsByUserId(gomock.Any(), testutil.UserIDForDebug, model.FriendListSortUserId, 0, 0).Return(nil, testutil.ErrTest)
 			},
-			url:        "/get_friend_list?ID=0",
+			url:        "/get_friend_list?ID=123456789",
 			want:       nil,
 			wantStatus: http.StatusInternalServerError,
 			wantErr:    true,
@@ -313,7 +666,7 @@ func Test_friendListController_GetFriendListOfFriendsByUserId(t *testing.T) {
 				}
 
 				return nil
-			})
+			}, testPaging)
 			ct.echo.ServeHTTP(rec, req)
 
 			assert.Equal(t, tt.wantStatus, rec.Code)
@@ -325,26 +678,109 @@ func Test_friendListController_GetFriendListOfFriendsByUserId(t *testing.T) {
 }
 
 func Test_friendListController_GetFriendListOfFriendsByUserIdWithPaging(t *testing.T) {
-	want := newFriendList()
+	encodeCursor := func(userId, lastUserId int) string {
+		return testCursorCodec.Encode(cursor.Cursor{UserId: userId, LastUserId: lastUserId})
+	}
+	nextCursor := encodeCursor(testutil.UserIDForDebug, 222222)
+	newPagedFriendList := func(p model.Paging) *model.FriendList {
+		return &model.FriendList{
+			Friends: newFriendList().Friends,
+			Paging:  &p,
+		}
+	}
 
 	tests := []struct {
 		name       string
 		expects    func(test *friendListControllerTest)
 		url        string
 		want       *model.FriendList
+		wantLink   string
 		wantStatus int
 		wantErr    bool
 	}{
 		{
 			name: "ok",
 			expects: func(ct *friendListControllerTest) {
-				ct.flu.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(gomock.Any()).Return(want, nil)
+				ct.flu.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(gomock.Any(), testutil.UserIDForDebug, model.FriendListSortUserId, 2, 0).Return(newPagedFriendList(model.Paging{Total: 3, Page: 1, Limit: 2, HasNext: true}), nil)
 			},
-			url:        "/get_friend_list?ID=123456789",
-			want:       want,
+			url: "/get_friend_list?ID=123456789&limit=2",
+			want: &model.FriendList{
+				Friends:    newFriendList().Friends,
+				Paging:     &model.Paging{Total: 3, Page: 1, Limit: 2, HasNext: true},
+				NextCursor: nextCursor,
+			},
+			wantLink: `</get_friend_list?ID=123456789&limit=2&page=1>; rel="first", ` +
+				`</get_friend_list?ID=123456789&limit=2&page=2>; rel="next", ` +
+				`</get_friend_list?ID=123456789&limit=2&page=2>; rel="last"`,
 			wantStatus: http.StatusOK,
 			wantErr:    false,
 		},
+		{
+			name: "ok: last page",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(gomock.Any(), testutil.UserIDForDebug, model.FriendListSortUserId, 2, 2).Return(newPagedFriendList(model.Paging{Total: 4, Page: 2, Limit: 2, HasNext: false}), nil)
+			},
+			url:  "/get_friend_list?ID=123456789&limit=2&page=2",
+			want: newPagedFriendList(model.Paging{Total: 4, Page: 2, Limit: 2, HasNext: false}),
+			wantLink: `</get_friend_list?ID=123456789&limit=2&page=1>; rel="first", ` +
+				`</get_friend_list?ID=123456789&limit=2&page=1>; rel="prev", ` +
+				`</get_friend_list?ID=123456789&limit=2&page=2>; rel="last"`,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name: "ok: no cursor when sorted by name",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(gomock.Any(), testutil.UserIDForDebug, model.FriendListSortName, 2, 0).Return(newPagedFriendList(model.Paging{Total: 3, Page: 1, Limit: 2, HasNext: true}), nil)
+			},
+			url:  "/get_friend_list?ID=123456789&limit=2&sort=name",
+			want: newPagedFriendList(model.Paging{Total: 3, Page: 1, Limit: 2, HasNext: true}),
+			wantLink: `</get_friend_list?ID=123456789&limit=2&page=1&sort=name>; rel="first", ` +
+				`</get_friend_list?ID=123456789&limit=2&page=2&sort=name>; rel="next", ` +
+				`</get_friend_list?ID=123456789&limit=2&page=2&sort=name>; rel="last"`,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name: "ok: cursor",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetFriendListOfFriendsByUserIdWithCursor(gomock.Any(), testutil.UserIDForDebug, 111111, 2).Return(newPagedFriendList(model.Paging{Total: 5, Limit: 2, HasNext: true}), nil)
+			},
+			url: "/get_friend_list?ID=123456789&limit=2&cursor=" + encodeCursor(testutil.UserIDForDebug, 111111),
+			want: &model.FriendList{
+				Friends:    newFriendList().Friends,
+				Paging:     &model.Paging{Total: 5, Limit: 2, HasNext: true},
+				NextCursor: nextCursor,
+			},
+			wantLink: `</get_friend_list?ID=123456789&limit=2>; rel="first", ` +
+				`</get_friend_list?ID=123456789&cursor=` + nextCursor + `&limit=2>; rel="next"`,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:       "ng: sort by name with cursor",
+			expects:    func(ct *friendListControllerTest) {},
+			url:        "/get_friend_list?ID=123456789&sort=name&cursor=" + encodeCursor(testutil.UserIDForDebug, 111111),
+			want:       nil,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "ng: cursor of another user",
+			expects:    func(ct *friendListControllerTest) {},
+			url:        "/get_friend_list?ID=123456789&cursor=" + encodeCursor(111111, 111111),
+			want:       nil,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "ng: cursor forged",
+			expects:    func(ct *friendListControllerTest) {},
+			url:        "/get_friend_list?ID=123456789&cursor=MTExMTEx.forged",
+			want:       nil,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
 		{
 			name:       "ng: userId missing in query parameter",
 			expects:    func(ct *friendListControllerTest) {},
@@ -380,9 +816,9 @@ func Test_friendListController_GetFriendListOfFriendsByUserIdWithPaging(t *testi
 		{
 			name: "ng: error at GetFriendListOfFriendsByUserId()",
 			expects: func(ct *friendListControllerTest) {
-				ct.flu.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(gomock.Any()).Return(nil, testutil.ErrTest)
+				ct.flu.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(gomock.Any(), testutil.UserIDForDebug, model.FriendListSortUserId, 20, 0).Return(nil, testutil.ErrTest)
 			},
-			url:        "/get_friend_list?ID=0",
+			url:        "/get_friend_list?ID=123456789",
 			want:       nil,
 			wantStatus: http.StatusInternalServerError,
 			wantErr:    true,
@@ -401,13 +837,396 @@ func Test_friendListController_GetFriendListOfFriendsByUserIdWithPaging(t *testi
 				}
 
 				return nil
-			})
+			}, testPaging)
+			ct.echo.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if !tt.wantErr {
+				testutil.AssertResponseBody(t, tt.want, rec.Body)
+				assert.Equal(t, tt.wantLink, rec.Header().Get("Link"))
+			}
+		})
+	}
+}
+
+func Test_friendListController_GetBlockListByUserId(t *testing.T) {
+	whole := &model.BlockList{BlockUsers: newFriendList().Friends}
+	paged := &model.BlockList{
+		BlockUsers: newFriendList().Friends[:1],
+		Paging:     &model.Paging{Total: 2, Page: 1, Limit: 1, HasNext: true},
+	}
+
+	tests := []struct {
+		name       string
+		expects    func(test *friendListControllerTest)
+		url        string
+		want       *model.BlockList
+		wantLink   string
+		wantStatus int
+		wantErr    bool
+	}{
+		{
+			name: "ok: whole list without limit and page",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetBlockListByUserId(gomock.Any(), testutil.UserIDForDebug, 0, 0).Return(whole, nil)
+			},
+			url:        "/get_block_list?ID=123456789",
+			want:       whole,
+			wantLink:   "",
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name: "ok: paged with limit",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetBlockListByUserId(gomock.Any(), testutil.UserIDForDebug, 1, 0).Return(paged, nil)
+			},
+			url:        "/get_block_list?ID=123456789&limit=1",
+			want:       paged,
+			wantLink:   `</get_block_list?ID=123456789&limit=1&page=1>; rel="first", </get_block_list?ID=123456789&limit=1&page=2>; rel="next", </get_block_list?ID=123456789&limit=1&page=2>; rel="last"`,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name: "ok: paged with page",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetBlockListByUserId(gomock.Any(), testutil.UserIDForDebug, 20, 0).Return(paged, nil)
+			},
+			url:        "/get_block_list?ID=123456789&page=1",
+			want:       paged,
+			wantLink:   `</get_block_list?ID=123456789&page=1>; rel="first", </get_block_list?ID=123456789&page=2>; rel="next", </get_block_list?ID=123456789&page=2>; rel="last"`,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:       "ng: userId not integer",
+			expects:    func(ct *friendListControllerTest) {},
+			url:        "/get_block_list?ID=invalid",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name: "ng: error at GetBlockListByUserId()",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetBlockListByUserId(gomock.Any(), testutil.UserIDForDebug, 0, 0).Return(nil, testutil.ErrTest)
+			},
+			url:        "/get_block_list?ID=123456789",
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := newFriendListControllerTest(t)
+			tt.expects(ct)
+
+			rec, req := httputil.NewRequestAndRecorder("GET", tt.url, nil)
+			ct.echo.GET("/get_block_list", func(c echo.Context) error {
+				if err := ct.flc.GetBlockListByUserId(c); err != nil {
+					return httputil.RespondError(c, err)
+				}
+
+				return nil
+			}, testPaging)
+			ct.echo.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if !tt.wantErr {
+				testutil.AssertResponseBody(t, tt.want, rec.Body)
+				assert.Equal(t, tt.wantLink, rec.Header().Get("Link"))
+			}
+		})
+	}
+}
+
+func Test_friendListController_v1(t *testing.T) {
+	friendList := &model.FriendList{
+		Friends: newFriendList().Friends,
+		Paging:  &model.Paging{Total: 2, Page: 1, Limit: 20, HasNext: false},
+	}
+	blockList := &model.BlockList{
+		BlockUsers: newFriendList().Friends,
+		Paging:     &model.Paging{Total: 2, Page: 1, Limit: 20, HasNext: false},
+	}
+
+	tests := []struct {
+		name       string
+		expects    func(test *friendListControllerTest)
+		path       string
+		handler    func(FriendListController) echo.HandlerFunc
+		url        string
+		want       any
+		wantLink   string
+		wantStatus int
+	}{
+		{
+			name: "ok: friends",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetFriendListByUserId(gomock.Any(), testutil.UserIDForDebug, model.FriendListSortName, 20, 0).Return(friendList, nil)
+			},
+			path:       "/v1/users/:id/friends",
+			handler:    func(flc FriendListController) echo.HandlerFunc { return flc.GetFriends },
+			url:        "/v1/users/123456789/friends?sort=name",
+			want:       friendList,
+			wantLink:   `</v1/users/123456789/friends?page=1&sort=name>; rel="first", </v1/users/123456789/friends?page=1&sort=name>; rel="last"`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "ok: friends of friends",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(gomock.Any(), testutil.UserIDForDebug, model.FriendListSortUserId, 20, 0).Return(friendList, nil)
+			},
+			path:       "/v1/users/:id/friends-of-friends",
+			handler:    func(flc FriendListController) echo.HandlerFunc { return flc.GetFriendsOfFriends },
+			url:        "/v1/users/123456789/friends-of-friends",
+			want:       friendList,
+			wantLink:   `</v1/users/123456789/friends-of-friends?page=1>; rel="first", </v1/users/123456789/friends-of-friends?page=1>; rel="last"`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "ok: blocks",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetBlockListByUserId(gomock.Any(), testutil.UserIDForDebug, 20, 0).Return(blockList, nil)
+			},
+			path:       "/v1/users/:id/blocks",
+			handler:    func(flc FriendListController) echo.HandlerFunc { return flc.GetBlocks },
+			url:        "/v1/users/123456789/blocks",
+			want:       blockList,
+			wantLink:   `</v1/users/123456789/blocks?page=1>; rel="first", </v1/users/123456789/blocks?page=1>; rel="last"`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "ng: userId not integer",
+			expects:    func(ct *friendListControllerTest) {},
+			path:       "/v1/users/:id/friends",
+			handler:    func(flc FriendListController) echo.HandlerFunc { return flc.GetFriends },
+			url:        "/v1/users/invalid/friends",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "ng: userId over mysql max limit",
+			expects:    func(ct *friendListControllerTest) {},
+			path:       "/v1/users/:id/blocks",
+			handler:    func(flc FriendListController) echo.HandlerFunc { return flc.GetBlocks },
+			url:        "/v1/users/4294967296/blocks",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "ng: user not exist",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(gomock.Any(), 111111, model.FriendListSortUserId, 20, 0).Return(nil, httputil.NewHTTPError(testutil.ErrTest, http.StatusBadRequest, httputil.ErrorCodeUserNotFound, "user not exist"))
+			},
+			path:       "/v1/users/:id/friends-of-friends",
+			handler:    func(flc FriendListController) echo.HandlerFunc { return flc.GetFriendsOfFriends },
+			url:        "/v1/users/111111/friends-of-friends",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := newFriendListControllerTest(t)
+			tt.expects(ct)
+
+			rec, req := httputil.NewRequestAndRecorder("GET", tt.url, nil)
+			handler := tt.handler(ct.flc)
+			ct.echo.GET(tt.path, func(c echo.Context) error {
+				if err := handler(c); err != nil {
+					return httputil.RespondError(c, err)
+				}
+
+				return nil
+			}, testPaging)
+			ct.echo.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.want != nil {
+				testutil.AssertResponseBody(t, tt.want, rec.Body)
+				assert.Equal(t, tt.wantLink, rec.Header().Get("Link"))
+			}
+		})
+	}
+}
+
+func Test_friendListController_GetNeighbourhood(t *testing.T) {
+	want := &model.NeighbourList{
+		Neighbours: []*model.Neighbour{
+			{UserId: 111111, Name: "hoge", Distance: 3},
+		},
+		Paging: &model.Paging{Total: 1, Page: 1, Limit: 20, HasNext: false},
+	}
+
+	tests := []struct {
+		name       string
+		expects    func(test *friendListControllerTest)
+		url        string
+		wantStatus int
+		wantErr    bool
+		wantParams []*httputil.InvalidParam
+	}{
+		{
+			name: "ok: default depth",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetNeighbourhood(gomock.Any(), testutil.UserIDForDebug, 1, 2, 20, 0).Return(want, nil)
+			},
+			url:        "/get_neighbourhood_list?ID=123456789",
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name: "ok: at most 3 hops",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetNeighbourhood(gomock.Any(), testutil.UserIDForDebug, 1, 3, 20, 0).Return(want, nil)
+			},
+			url:        "/get_neighbourhood_list?ID=123456789&depth=3&exact=false",
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name: "ok: exactly 3 hops",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetNeighbourhood(gomock.Any(), testutil.UserIDForDebug, 3, 3, 20, 0).Return(want, nil)
+			},
+			url:        "/get_neighbourhood_list?ID=123456789&depth=3&exact=true",
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:       "ng: depth over max",
+			expects:    func(ct *friendListControllerTest) {},
+			url:        "/get_neighbourhood_list?ID=123456789&depth=5",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "ng: depth zero",
+			expects:    func(ct *friendListControllerTest) {},
+			url:        "/get_neighbourhood_list?ID=123456789&depth=0",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "ng: exact not boolean",
+			expects:    func(ct *friendListControllerTest) {},
+			url:        "/get_neighbourhood_list?ID=123456789&exact=maybe",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "ng: every invalid parameter",
+			expects:    func(ct *friendListControllerTest) {},
+			url:        "/get_neighbourhood_list?ID=invalid&depth=5&exact=maybe",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+			wantParams: []*httputil.InvalidParam{
+				{In: "query", Name: "ID", Reason: "ID is not integer"},
+				{In: "query", Name: "depth", Reason: "depth is invalid"},
+				{In: "query", Name: "exact", Reason: "exact is not boolean"},
+			},
+		},
+		{
+			name: "ng: error at GetNeighbourhood()",
+			expects: func(ct *friendListControllerTest) {
+				ct.flu.EXPECT().GetNeighbourhood(gomock.Any(), testutil.UserIDForDebug, 1, 2, 20, 0).Return(nil, testutil.ErrTest)
+			},
+			url:        "/get_neighbourhood_list?ID=123456789",
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := newFriendListControllerTest(t)
+			tt.expects(ct)
+
+			rec, req := httputil.NewRequestAndRecorder("GET", tt.url, nil)
+			ct.echo.GET("/get_neighbourhood_list", func(c echo.Context) error {
+				if err := ct.flc.GetNeighbourhood(c); err != nil {
+					return httputil.RespondError(c, err)
+				}
+
+				return nil
+			}, testPaging)
 			ct.echo.ServeHTTP(rec, req)
 
 			assert.Equal(t, tt.wantStatus, rec.Code)
 			if !tt.wantErr {
 				testutil.AssertResponseBody(t, want, rec.Body)
 			}
+			if tt.wantParams != nil {
+				var got httputil.ErrorResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+				assert.Equal(t, tt.wantParams, got.Errors)
+			}
+		})
+	}
+}
+
+// racingFriendListRepository lets another request insert the link right after the check
+// of the link found none, as when two requests for the same link run at once.
+type racingFriendListRepository struct {
+	repository.FriendListRepository
+	insertErrs []error
+}
+
+func (r *racingFriendListRepository) CheckUserLink(ctx context.Context, user1Id, user2Id int, table string) error {
+	// outside the transaction carried by ctx, so the link is there before the request inserts it
+	if err := r.FriendListRepository.InsertUserLink(context.Background(), user1Id, user2Id, table); err != nil {
+		return err
+	}
+
+	return sql.ErrNoRows
+}
+
+func (r *racingFriendListRepository) InsertUserLink(ctx context.Context, user1Id, user2Id int, table string) error {
+	err := r.FriendListRepository.InsertUserLink(ctx, user1Id, user2Id, table)
+	r.insertErrs = append(r.insertErrs, err)
+
+	return err
+}
+
+func Test_friendListController_PostUserLink_Race(t *testing.T) {
+	for _, table := range []string{"friend_link", "block_list"} {
+		t.Run(table, func(t *testing.T) {
+			db := testutil.PrepareMySQL(t)
+			for _, userId := range []int{testutil.UserIDForDebug, 111111} {
+				testutil.ExecSQL(t, db, `INSERT INTO users (id, user_id, name) VALUES (0, ?, ?)`, userId, "hoge")
+			}
+
+			flr := &racingFriendListRepository{FriendListRepository: repository.NewFriendListRepository(db)}
+			fls := service.NewFriendListService(flr, repository.NewFriendRequestRepository(db), service.BlockPolicyMutual)
+			flc := NewFriendListController(usecase.NewFriendListUseCase(repository.NewTransaction(db), fls), testCursorCodec)
+
+			e := echo.New()
+			e.POST("/user_link", func(c echo.Context) error {
+				if err := flc.PostUserLink(c); err != nil {
+					return httputil.RespondError(c, err)
+				}
+
+				return nil
+			})
+
+			req := &model.UserLinkForRequest{
+				User1Id: testutil.UserIDForDebug,
+				User2Id: 111111,
+				Table:   table,
+			}
+			rec, httpReq := httputil.NewRequestAndRecorder("POST", "/user_link", testutil.I2Reader(t, req))
+			e.ServeHTTP(rec, httpReq)
+
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			if assert.Len(t, flr.insertErrs, 1) {
+				assert.ErrorIs(t, flr.insertErrs[0], repository.ErrUserLinkDuplicated)
+			}
+
+			var count int
+			q := "SELECT COUNT(*) FROM " + table + " WHERE user1_id = ? AND user2_id = ?"
+			if err := db.QueryRow(q, req.User1Id, req.User2Id).Scan(&count); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, 1, count)
 		})
 	}
 }
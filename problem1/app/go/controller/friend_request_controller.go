@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"problem1/model"
+	"problem1/pkg/httputil"
+	"problem1/usecase"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+type FriendRequestController interface {
+	PostFriendRequest(c echo.Context) error
+	GetIncomingFriendRequests(c echo.Context) error
+	GetOutgoingFriendRequests(c echo.Context) error
+	AcceptFriendRequest(c echo.Context) error
+	RejectFriendRequest(c echo.Context) error
+	CancelFriendRequest(c echo.Context) error
+}
+
+type friendRequestController struct {
+	friendRequestUseCase usecase.FriendRequestUseCase
+}
+
+func NewFriendRequestController(fru usecase.FriendRequestUseCase) FriendRequestController {
+	return &friendRequestController{
+		friendRequestUseCase: fru,
+	}
+}
+
+func (c *friendRequestController) PostFriendRequest(ctx echo.Context) error {
+	var req model.FriendRequestForRequest
+	if err := json.NewDecoder(ctx.Request().Body).Decode(&req); err != nil {
+		return httputil.NewHTTPError(errors.New("request invalid"), http.StatusBadRequest, httputil.ErrorCodeInvalidRequest, "")
+	}
+
+	var params httputil.InvalidParams
+	if req.FromUserId < 0 || maxUserId < req.FromUserId {
+		params.Add("body", "/fromUserId", "fromUserId is invalid")
+	}
+	if req.ToUserId < 0 || maxUserId < req.ToUserId {
+		params.Add("body", "/toUserId", "toUserId is invalid")
+	}
+	if len(params) == 0 && req.FromUserId == req.ToUserId {
+		params.Add("body", "/toUserId", "fromUserId is equal to toUserId")
+	}
+	if err := params.Err(httputil.ErrorCodeInvalidParameter); err != nil {
+		return err
+	}
+
+	friendRequest, err := c.friendRequestUseCase.SendFriendRequest(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, friendRequest)
+}
+
+func (c *friendRequestController) GetIncomingFriendRequests(ctx echo.Context) error {
+	var params httputil.InvalidParams
+	userId := userIdFromQuery(ctx, &params)
+	if err := params.Err(httputil.ErrorCodeInvalidParameter); err != nil {
+		return err
+	}
+
+	friendRequests, err := c.friendRequestUseCase.GetIncomingFriendRequests(ctx.Request().Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, friendRequests)
+}
+
+func (c *friendRequestController) GetOutgoingFriendRequests(ctx echo.Context) error {
+	var params httputil.InvalidParams
+	userId := userIdFromQuery(ctx, &params)
+	if err := params.Err(httputil.ErrorCodeInvalidParameter); err != nil {
+		return err
+	}
+
+	friendRequests, err := c.friendRequestUseCase.GetOutgoingFriendRequests(ctx.Request().Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, friendRequests)
+}
+
+func bindFriendRequestActionRequest(ctx echo.Context) (*model.FriendRequestActionForRequest, error) {
+	var req model.FriendRequestActionForRequest
+	if err := json.NewDecoder(ctx.Request().Body).Decode(&req); err != nil {
+		return nil, httputil.NewHTTPError(errors.New("request invalid"), http.StatusBadRequest, httputil.ErrorCodeInvalidRequest, "")
+	}
+
+	var params httputil.InvalidParams
+	if req.RequestId < 1 {
+		params.Add("body", "/requestId", "requestId is invalid")
+	}
+	if req.UserId < 0 || maxUserId < req.UserId {
+		params.Add("body", "/userId", "userId is invalid")
+	}
+	if err := params.Err(httputil.ErrorCodeInvalidParameter); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (c *friendRequestController) AcceptFriendRequest(ctx echo.Context) error {
+	req, err := bindFriendRequestActionRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.friendRequestUseCase.AcceptFriendRequest(ctx.Request().Context(), req); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (c *friendRequestController) RejectFriendRequest(ctx echo.Context) error {
+	req, err := bindFriendRequestActionRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.friendRequestUseCase.RejectFriendRequest(ctx.Request().Context(), req); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (c *friendRequestController) CancelFriendRequest(ctx echo.Context) error {
+	req, err := bindFriendRequestActionRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.friendRequestUseCase.CancelFriendRequest(ctx.Request().Context(), req); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,331 @@
+package controller
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/mock/mock_usecase"
+	"problem1/model"
+	"problem1/pkg/httputil"
+	"problem1/pkg/testutil"
+)
+
+type friendRequestControllerTest struct {
+	fru  *mock_usecase.MockFriendRequestUseCase
+	frc  FriendRequestController
+	echo *echo.Echo
+}
+
+func newFriendRequestControllerTest(t *testing.T) *friendRequestControllerTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	fru := mock_usecase.NewMockFriendRequestUseCase(ctrl)
+
+	return &friendRequestControllerTest{
+		fru:  fru,
+		frc:  NewFriendRequestController(fru),
+		echo: echo.New(),
+	}
+}
+
+func newFriendRequestList() *model.FriendRequestList {
+	return &model.FriendRequestList{
+		FriendRequests: []*model.FriendRequest{
+			{
+				RequestId:  1,
+				FromUserId: 111111,
+				ToUserId:   testutil.UserIDForDebug,
+				Status:     model.FriendRequestStatusPending,
+			},
+		},
+	}
+}
+
+func Test_friendRequestController_PostFriendRequest(t *testing.T) {
+	testRequest := &model.FriendRequestForRequest{
+		FromUserId: testutil.UserIDForDebug,
+		ToUserId:   111111,
+	}
+	want := &model.FriendRequest{
+		RequestId:  1,
+		FromUserId: testutil.UserIDForDebug,
+		ToUserId:   111111,
+		Status:     model.FriendRequestStatusPending,
+	}
+
+	tests := []struct {
+		name       string
+		expects    func(*friendRequestControllerTest)
+		payload    any
+		wantStatus int
+		wantErr    bool
+	}{
+		{
+			name: "ok",
+			expects: func(ct *friendRequestControllerTest) {
+				ct.fru.EXPECT().SendFriendRequest(gomock.Any(), testRequest).Return(want, nil)
+			},
+			payload:    testRequest,
+			wantStatus: http.StatusCreated,
+			wantErr:    false,
+		},
+		{
+			name:       "ng: error at Decode()",
+			expects:    func(ct *friendRequestControllerTest) {},
+			payload:    "invalid",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:    "ng: fromUserId invalid",
+			expects: func(ct *friendRequestControllerTest) {},
+			payload: &model.FriendRequestForRequest{
+				FromUserId: -1,
+				ToUserId:   111111,
+			},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:    "ng: fromUserId is equal to toUserId",
+			expects: func(ct *friendRequestControllerTest) {},
+			payload: &model.FriendRequestForRequest{
+				FromUserId: testutil.UserIDForDebug,
+				ToUserId:   testutil.UserIDForDebug,
+			},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name: "ng: blocked",
+			expects: func(ct *friendRequestControllerTest) {
+				ct.fru.EXPECT().SendFriendRequest(gomock.Any(), testRequest).Return(nil, httputil.NewHTTPError(testutil.ErrTest, http.StatusForbidden, httputil.ErrorCodeFriendRequestBlocked, ""))
+			},
+			payload:    testRequest,
+			wantStatus: http.StatusForbidden,
+			wantErr:    true,
+		},
+		{
+			name: "ng: error at SendFriendRequest()",
+			expects: func(ct *friendRequestControllerTest) {
+				ct.fru.EXPECT().SendFriendRequest(gomock.Any(), testRequest).Return(nil, testutil.ErrTest)
+			},
+			payload:    testRequest,
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := newFriendRequestControllerTest(t)
+			tt.expects(ct)
+
+			rec, req := httputil.NewRequestAndRecorder("POST", "/friend_request", testutil.I2Reader(t, tt.payload))
+			ct.echo.POST("/friend_request", func(c echo.Context) error {
+				if err := ct.frc.PostFriendRequest(c); err != nil {
+					return httputil.RespondError(c, err)
+				}
+
+				return nil
+			})
+			ct.echo.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if !tt.wantErr {
+				testutil.AssertResponseBody(t, want, rec.Body)
+			}
+		})
+	}
+}
+
+func Test_friendRequestController_GetIncomingFriendRequests(t *testing.T) {
+	want := newFriendRequestList()
+
+	tests := []struct {
+		name       string
+		expects    func(*friendRequestControllerTest)
+		url        string
+		wantStatus int
+		wantErr    bool
+	}{
+		{
+			name: "ok",
+			expects: func(ct *friendRequestControllerTest) {
+				ct.fru.EXPECT().GetIncomingFriendRequests(gomock.Any(), testutil.UserIDForDebug).Return(want, nil)
+			},
+			url:        "/friend_request/incoming?ID=123456789",
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:       "ng: userId not integer",
+			expects:    func(ct *friendRequestControllerTest) {},
+			url:        "/friend_request/incoming?ID=invalid",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name: "ng: error at GetIncomingFriendRequests()",
+			expects: func(ct *friendRequestControllerTest) {
+				ct.fru.EXPECT().GetIncomingFriendRequests(gomock.Any(), testutil.UserIDForDebug).Return(nil, testutil.ErrTest)
+			},
+			url:        "/friend_request/incoming?ID=123456789",
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := newFriendRequestControllerTest(t)
+			tt.expects(ct)
+
+			rec, req := httputil.NewRequestAndRecorder("GET", tt.url, nil)
+			ct.echo.GET("/friend_request/incoming", func(c echo.Context) error {
+				if err := ct.frc.GetIncomingFriendRequests(c); err != nil {
+					return httputil.RespondError(c, err)
+				}
+
+				return nil
+			})
+			ct.echo.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if !tt.wantErr {
+				testutil.AssertResponseBody(t, want, rec.Body)
+			}
+		})
+	}
+}
+
+func Test_friendRequestController_GetOutgoingFriendRequests(t *testing.T) {
+	want := newFriendRequestList()
+
+	ct := newFriendRequestControllerTest(t)
+	ct.fru.EXPECT().GetOutgoingFriendRequests(gomock.Any(), testutil.UserIDForDebug).Return(want, nil)
+
+	rec, req := httputil.NewRequestAndRecorder("GET", "/friend_request/outgoing?ID=123456789", nil)
+	ct.echo.GET("/friend_request/outgoing", func(c echo.Context) error {
+		if err := ct.frc.GetOutgoingFriendRequests(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	})
+	ct.echo.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	testutil.AssertResponseBody(t, want, rec.Body)
+}
+
+func Test_friendRequestController_Actions(t *testing.T) {
+	testRequest := &model.FriendRequestActionForRequest{
+		RequestId: 1,
+		UserId:    testutil.UserIDForDebug,
+	}
+
+	type action struct {
+		path    string
+		handler func(FriendRequestController) echo.HandlerFunc
+		expect  func(*mock_usecase.MockFriendRequestUseCase) *gomock.Call
+	}
+	actions := []action{
+		{
+			path:    "/friend_request/accept",
+			handler: func(c FriendRequestController) echo.HandlerFunc { return c.AcceptFriendRequest },
+			expect: func(m *mock_usecase.MockFriendRequestUseCase) *gomock.Call {
+				return m.EXPECT().AcceptFriendRequest(gomock.Any(), testRequest)
+			},
+		},
+		{
+			path:    "/friend_request/reject",
+			handler: func(c FriendRequestController) echo.HandlerFunc { return c.RejectFriendRequest },
+			expect: func(m *mock_usecase.MockFriendRequestUseCase) *gomock.Call {
+				return m.EXPECT().RejectFriendRequest(gomock.Any(), testRequest)
+			},
+		},
+		{
+			path:    "/friend_request/cancel",
+			handler: func(c FriendRequestController) echo.HandlerFunc { return c.CancelFriendRequest },
+			expect: func(m *mock_usecase.MockFriendRequestUseCase) *gomock.Call {
+				return m.EXPECT().CancelFriendRequest(gomock.Any(), testRequest)
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expects    func(*friendRequestControllerTest, action)
+		payload    any
+		wantStatus int
+	}{
+		{
+			name: "ok",
+			expects: func(ct *friendRequestControllerTest, a action) {
+				a.expect(ct.fru).Return(nil)
+			},
+			payload:    testRequest,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "ng: error at Decode()",
+			expects:    func(ct *friendRequestControllerTest, a action) {},
+			payload:    "invalid",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "ng: requestId invalid",
+			expects: func(ct *friendRequestControllerTest, a action) {},
+			payload: &model.FriendRequestActionForRequest{
+				RequestId: 0,
+				UserId:    testutil.UserIDForDebug,
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "ng: userId invalid",
+			expects: func(ct *friendRequestControllerTest, a action) {},
+			payload: &model.FriendRequestActionForRequest{
+				RequestId: 1,
+				UserId:    -1,
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "ng: request not exist",
+			expects: func(ct *friendRequestControllerTest, a action) {
+				a.expect(ct.fru).Return(httputil.NewHTTPError(testutil.ErrTest, http.StatusNotFound, httputil.ErrorCodeFriendRequestNotFound, ""))
+			},
+			payload:    testRequest,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, a := range actions {
+		for _, tt := range tests {
+			t.Run(a.path+" "+tt.name, func(t *testing.T) {
+				ct := newFriendRequestControllerTest(t)
+				tt.expects(ct, a)
+
+				rec, req := httputil.NewRequestAndRecorder("POST", a.path, testutil.I2Reader(t, tt.payload))
+				handler := a.handler(ct.frc)
+				ct.echo.POST(a.path, func(c echo.Context) error {
+					if err := handler(c); err != nil {
+						return httputil.RespondError(c, err)
+					}
+
+					return nil
+				})
+				ct.echo.ServeHTTP(rec, req)
+
+				assert.Equal(t, tt.wantStatus, rec.Code)
+			})
+		}
+	}
+}
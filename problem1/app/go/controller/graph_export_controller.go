@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"problem1/pkg/graphexport"
+	"problem1/pkg/httputil"
+	"problem1/service"
+	"problem1/usecase"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+type GraphExportController interface {
+	ExportGraph(c echo.Context) error
+}
+
+type graphExportController struct {
+	graphExportUseCase usecase.GraphExportUseCase
+}
+
+func NewGraphExportController(geu usecase.GraphExportUseCase) GraphExportController {
+	return &graphExportController{
+		graphExportUseCase: geu,
+	}
+}
+
+// ExportGraph streams the graph in the format query parameter. With the ID query parameter,
+// it streams the ego network of the user of depth hops, 1 when depth is omitted.
+func (c *graphExportController) ExportGraph(ctx echo.Context) error {
+	var params httputil.InvalidParams
+	format, err := graphexport.ParseFormat(ctx.QueryParam("format"))
+	if err != nil {
+		params.Add("query", "format", "format is invalid")
+	}
+
+	var userId, depth int
+	if ctx.QueryParam("ID") != "" {
+		userId = userIdFromQuery(ctx, &params)
+
+		depth = 1
+		if v := ctx.QueryParam("depth"); v != "" {
+			if depth, err = strconv.Atoi(v); err != nil || depth < 1 || service.MaxNeighbourhoodDepth < depth {
+				params.Add("query", "depth", "depth is invalid")
+			}
+		}
+	} else if ctx.QueryParam("depth") != "" {
+		params.Add("query", "depth", "depth is given without ID")
+	}
+	if err := params.Err(httputil.ErrorCodeInvalidParameter); err != nil {
+		return err
+	}
+
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, format.ContentType())
+	res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="graph.`+string(format)+`"`)
+	enc, err := graphexport.NewEncoder(res, format)
+	if err != nil {
+		return err
+	}
+
+	if err := c.graphExportUseCase.Export(ctx.Request().Context(), enc, userId, depth); err != nil {
+		if !res.Committed {
+			res.Header().Del(echo.HeaderContentType)
+			res.Header().Del(echo.HeaderContentDisposition)
+			return err
+		}
+
+		// the status is sent already, so abort the response for the client not to take
+		// the graph cut short for the whole
+		log.Println(err.Error())
+		panic(http.ErrAbortHandler)
+	}
+
+	return nil
+}
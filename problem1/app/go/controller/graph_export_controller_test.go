@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/mock/mock_usecase"
+	"problem1/model"
+	"problem1/pkg/graphexport"
+	"problem1/pkg/httputil"
+	"problem1/pkg/testutil"
+)
+
+type graphExportControllerTest struct {
+	geu  *mock_usecase.MockGraphExportUseCase
+	gec  GraphExportController
+	echo *echo.Echo
+}
+
+func newGraphExportControllerTest(t *testing.T) *graphExportControllerTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	geu := mock_usecase.NewMockGraphExportUseCase(ctrl)
+
+	return &graphExportControllerTest{
+		geu:  geu,
+		gec:  NewGraphExportController(geu),
+		echo: echo.New(),
+	}
+}
+
+// writeGraph writes one user and one friend link to the encoder given to Export.
+func writeGraph(_ context.Context, enc graphexport.Encoder, _, _ int) error {
+	if err := enc.Begin(); err != nil {
+		return err
+	}
+	if err := enc.User(&model.User{UserId: 1, Name: "hoge"}); err != nil {
+		return err
+	}
+	if err := enc.Edge(graphexport.Edge{Type: graphexport.EdgeTypeFriend, From: 1, To: 2}); err != nil {
+		return err
+	}
+
+	return enc.End()
+}
+
+func Test_graphExportController_ExportGraph(t *testing.T) {
+	tests := []struct {
+		name            string
+		expects         func(*graphExportControllerTest)
+		url             string
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name: "ok: whole graph as json",
+			expects: func(ct *graphExportControllerTest) {
+				ct.geu.EXPECT().Export(gomock.Any(), gomock.Any(), 0, 0).DoAndReturn(writeGraph)
+			},
+			url:             "/admin/graph/export",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/json; charset=utf-8",
+			wantBody:        `{"users":[{"userId":1,"name":"hoge"}],"edges":[{"type":"friend","from":1,"to":2}]}` + "\n",
+		},
+		{
+			name: "ok: ego network as dot",
+			expects: func(ct *graphExportControllerTest) {
+				ct.geu.EXPECT().Export(gomock.Any(), gomock.Any(), testutil.UserIDForDebug, 1).DoAndReturn(writeGraph)
+			},
+			url:             "/admin/graph/export?format=dot&ID=123456789",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/vnd.graphviz; charset=utf-8",
+			wantBody:        "digraph social {\n  1 [label=\"hoge\"];\n  1 -> 2 [type=friend];\n}\n",
+		},
+		{
+			name: "ok: ego network of depth",
+			expects: func(ct *graphExportControllerTest) {
+				ct.geu.EXPECT().Export(gomock.Any(), gomock.Any(), testutil.UserIDForDebug, 3).Return(nil)
+			},
+			url:             "/admin/graph/export?format=graphml&ID=123456789&depth=3",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/graphml+xml; charset=utf-8",
+			wantBody:        "",
+		},
+		{
+			name:       "ng: format invalid",
+			expects:    func(ct *graphExportControllerTest) {},
+			url:        "/admin/graph/export?format=csv",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "ng: depth invalid",
+			expects:    func(ct *graphExportControllerTest) {},
+			url:        "/admin/graph/export?ID=123456789&depth=5",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "ng: depth without ID",
+			expects:    func(ct *graphExportControllerTest) {},
+			url:        "/admin/graph/export?depth=2",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "ng: user not exist",
+			expects: func(ct *graphExportControllerTest) {
+				ct.geu.EXPECT().Export(gomock.Any(), gomock.Any(), 111111, 1).
+					Return(httputil.NewHTTPError(nil, http.StatusBadRequest, httputil.ErrorCodeUserNotFound, "user not exist"))
+			},
+			url:             "/admin/graph/export?format=dot&ID=111111",
+			wantStatus:      http.StatusBadRequest,
+			wantContentType: "application/json; charset=UTF-8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := newGraphExportControllerTest(t)
+			tt.expects(ct)
+
+			rec, req := httputil.NewRequestAndRecorder("GET", tt.url, nil)
+			ct.echo.GET("/admin/graph/export", func(c echo.Context) error {
+				if err := ct.gec.ExportGraph(c); err != nil {
+					return httputil.RespondError(c, err)
+				}
+
+				return nil
+			})
+			ct.echo.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantContentType != "" {
+				assert.Equal(t, tt.wantContentType, rec.Header().Get(echo.HeaderContentType))
+			}
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func Test_graphExportController_ExportGraph_abortsWhenCutShort(t *testing.T) {
+	ct := newGraphExportControllerTest(t)
+	ct.geu.EXPECT().Export(gomock.Any(), gomock.Any(), 0, 0).DoAndReturn(
+		func(_ context.Context, enc graphexport.Encoder, _, _ int) error {
+			if err := enc.Begin(); err != nil {
+				return err
+			}
+			return testutil.ErrTest
+		})
+
+	rec, req := httputil.NewRequestAndRecorder("GET", "/admin/graph/export", nil)
+	c := ct.echo.NewContext(req, rec)
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		_ = ct.gec.ExportGraph(c)
+	})
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"problem1/pkg/httputil"
+	"problem1/usecase"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+type PathController interface {
+	GetShortestPath(c echo.Context) error
+}
+
+type pathController struct {
+	pathUseCase usecase.PathUseCase
+	maxDepth    int
+}
+
+// NewPathController returns a PathController searching at most maxDepth links,
+// which is also the depth when the maxDepth query parameter is omitted.
+func NewPathController(pu usecase.PathUseCase, maxDepth int) PathController {
+	return &pathController{
+		pathUseCase: pu,
+		maxDepth:    maxDepth,
+	}
+}
+
+// userIdFromParam returns the user of the path parameter name, or adds it to params when
+// it is invalid.
+func userIdFromParam(ctx echo.Context, params *httputil.InvalidParams, name string) int {
+	return parseUserId(params, "path", name, ctx.Param(name))
+}
+
+func (c *pathController) GetShortestPath(ctx echo.Context) error {
+	var params httputil.InvalidParams
+	fromUserId := userIdFromParam(ctx, &params, "a")
+	toUserId := userIdFromParam(ctx, &params, "b")
+
+	maxDepth := c.maxDepth
+	if v := ctx.QueryParam("maxDepth"); v != "" {
+		var err error
+		if maxDepth, err = strconv.Atoi(v); err != nil || maxDepth < 1 {
+			params.Add("query", "maxDepth", "maxDepth is invalid")
+		} else if maxDepth > c.maxDepth {
+			maxDepth = c.maxDepth
+		}
+	}
+	if err := params.Err(httputil.ErrorCodeInvalidParameter); err != nil {
+		return err
+	}
+
+	friendPath, err := c.pathUseCase.GetShortestPath(ctx.Request().Context(), fromUserId, toUserId, maxDepth)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, friendPath)
+}
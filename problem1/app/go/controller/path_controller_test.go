@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/mock/mock_usecase"
+	"problem1/model"
+	"problem1/pkg/httputil"
+	"problem1/pkg/testutil"
+)
+
+type pathControllerTest struct {
+	pu   *mock_usecase.MockPathUseCase
+	pc   PathController
+	echo *echo.Echo
+}
+
+func newPathControllerTest(t *testing.T) *pathControllerTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	pu := mock_usecase.NewMockPathUseCase(ctrl)
+
+	return &pathControllerTest{
+		pu:   pu,
+		pc:   NewPathController(pu, 6),
+		echo: echo.New(),
+	}
+}
+
+func Test_pathController_GetShortestPath(t *testing.T) {
+	want := &model.FriendPath{
+		Users: []*model.Friend{
+			{UserId: testutil.UserIDForDebug, Name: testutil.UserNameForDebug},
+			{UserId: 111111, Name: "hoge"},
+		},
+		Degree: 1,
+	}
+
+	tests := []struct {
+		name       string
+		expects    func(*pathControllerTest)
+		url        string
+		wantStatus int
+		wantErr    bool
+		wantParams []*httputil.InvalidParam
+	}{
+		{
+			name: "ok: default max depth",
+			expects: func(ct *pathControllerTest) {
+				ct.pu.EXPECT().GetShortestPath(gomock.Any(), testutil.UserIDForDebug, 111111, 6).Return(want, nil)
+			},
+			url:        "/users/123456789/path/111111",
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name: "ok: max depth",
+			expects: func(ct *pathControllerTest) {
+				ct.pu.EXPECT().GetShortestPath(gomock.Any(), testutil.UserIDForDebug, 111111, 3).Return(want, nil)
+			},
+			url:        "/users/123456789/path/111111?maxDepth=3",
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name: "ok: max depth over limit",
+			expects: func(ct *pathControllerTest) {
+				ct.pu.EXPECT().GetShortestPath(gomock.Any(), testutil.UserIDForDebug, 111111, 6).Return(want, nil)
+			},
+			url:        "/users/123456789/path/111111?maxDepth=100",
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:       "ng: max depth invalid",
+			expects:    func(ct *pathControllerTest) {},
+			url:        "/users/123456789/path/111111?maxDepth=0",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "ng: userId not integer",
+			expects:    func(ct *pathControllerTest) {},
+			url:        "/users/123456789/path/invalid",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "ng: userId over mysql max limit",
+			expects:    func(ct *pathControllerTest) {},
+			url:        "/users/999999999999999999/path/111111",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "ng: every invalid parameter",
+			expects:    func(ct *pathControllerTest) {},
+			url:        "/users/invalid/path/-1?maxDepth=0",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+			wantParams: []*httputil.InvalidParam{
+				{In: "path", Name: "a", Reason: "a is not integer"},
+				{In: "path", Name: "b", Reason: "b is invalid"},
+				{In: "query", Name: "maxDepth", Reason: "maxDepth is invalid"},
+			},
+		},
+		{
+			name: "ng: path not exist",
+			expects: func(ct *pathControllerTest) {
+				ct.pu.EXPECT().GetShortestPath(gomock.Any(), testutil.UserIDForDebug, 111111, 6).Return(nil, httputil.NewHTTPError(testutil.ErrTest, http.StatusNotFound, httputil.ErrorCodePathNotFound, ""))
+			},
+			url:        "/users/123456789/path/111111",
+			wantStatus: http.StatusNotFound,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := newPathControllerTest(t)
+			tt.expects(ct)
+
+			rec, req := httputil.NewRequestAndRecorder("GET", tt.url, nil)
+			ct.echo.GET("/users/:a/path/:b", func(c echo.Context) error {
+				if err := ct.pc.GetShortestPath(c); err != nil {
+					return httputil.RespondError(c, err)
+				}
+
+				return nil
+			})
+			ct.echo.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if !tt.wantErr {
+				testutil.AssertResponseBody(t, want, rec.Body)
+			}
+			if tt.wantParams != nil {
+				var got httputil.ErrorResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+				assert.Equal(t, tt.wantParams, got.Errors)
+			}
+		})
+	}
+}
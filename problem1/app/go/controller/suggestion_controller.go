@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"problem1/pkg/httputil"
+	"problem1/service"
+	"problem1/usecase"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+type SuggestionController interface {
+	GetSuggestions(c echo.Context) error
+}
+
+type suggestionController struct {
+	suggestionUseCase usecase.SuggestionUseCase
+}
+
+func NewSuggestionController(su usecase.SuggestionUseCase) SuggestionController {
+	return &suggestionController{
+		suggestionUseCase: su,
+	}
+}
+
+// scorerFromQuery returns the scorer named by the query, or the mutual one when none is.
+func scorerFromQuery(ctx echo.Context, params *httputil.InvalidParams) string {
+	scorer := ctx.QueryParam("scorer")
+	if scorer == "" {
+		return service.ScorerMutual
+	}
+	if _, err := service.GetScorer(scorer); err != nil {
+		params.Add("query", "scorer", "scorer is invalid")
+
+		return ""
+	}
+
+	return scorer
+}
+
+func (c *suggestionController) GetSuggestions(ctx echo.Context) error {
+	var params httputil.InvalidParams
+	userId := userIdFromQuery(ctx, &params)
+	scorer := scorerFromQuery(ctx, &params)
+	if err := params.Err(httputil.ErrorCodeInvalidParameter); err != nil {
+		return err
+	}
+
+	limit, offset, err := pageFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	suggestionList, err := c.suggestionUseCase.GetSuggestions(ctx.Request().Context(), userId, scorer, limit, offset)
+	if err != nil {
+		return err
+	}
+
+	setPagingLinkHeader(ctx, suggestionList.Paging, "")
+
+	return ctx.JSON(http.StatusOK, suggestionList)
+}
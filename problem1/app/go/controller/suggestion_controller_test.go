@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/mock/mock_usecase"
+	"problem1/model"
+	"problem1/pkg/httputil"
+	"problem1/pkg/testutil"
+)
+
+type suggestionControllerTest struct {
+	su   *mock_usecase.MockSuggestionUseCase
+	sc   SuggestionController
+	echo *echo.Echo
+}
+
+func newSuggestionControllerTest(t *testing.T) *suggestionControllerTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	su := mock_usecase.NewMockSuggestionUseCase(ctrl)
+
+	return &suggestionControllerTest{
+		su:   su,
+		sc:   NewSuggestionController(su),
+		echo: echo.New(),
+	}
+}
+
+func Test_suggestionController_GetSuggestions(t *testing.T) {
+	want := &model.SuggestionList{
+		Suggestions: []*model.Suggestion{
+			{
+				UserId:            333333,
+				Name:              "bar",
+				Score:             2,
+				MutualFriendCount: 2,
+				MutualFriends:     newFriendList().Friends,
+			},
+		},
+		Paging: &model.Paging{Total: 1, Page: 1, Limit: 20, HasNext: false},
+	}
+
+	tests := []struct {
+		name       string
+		expects    func(*suggestionControllerTest)
+		url        string
+		wantStatus int
+		wantErr    bool
+		wantParams []*httputil.InvalidParam
+	}{
+		{
+			name: "ok: default scorer",
+			expects: func(ct *suggestionControllerTest) {
+				ct.su.EXPECT().GetSuggestions(gomock.Any(), testutil.UserIDForDebug, "mutual", 20, 0).Return(want, nil)
+			},
+			url:        "/get_friend_suggestion_list?ID=123456789",
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name: "ok: adamic adar",
+			expects: func(ct *suggestionControllerTest) {
+				ct.su.EXPECT().GetSuggestions(gomock.Any(), testutil.UserIDForDebug, "adamic_adar", 20, 0).Return(want, nil)
+			},
+			url:        "/get_friend_suggestion_list?ID=123456789&scorer=adamic_adar",
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:       "ng: userId not integer",
+			expects:    func(ct *suggestionControllerTest) {},
+			url:        "/get_friend_suggestion_list?ID=invalid",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "ng: scorer not exist",
+			expects:    func(ct *suggestionControllerTest) {},
+			url:        "/get_friend_suggestion_list?ID=123456789&scorer=unknown",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+			wantParams: []*httputil.InvalidParam{
+				{In: "query", Name: "scorer", Reason: "scorer is invalid"},
+			},
+		},
+		{
+			name:       "ng: every invalid parameter",
+			expects:    func(ct *suggestionControllerTest) {},
+			url:        "/get_friend_suggestion_list?ID=invalid&scorer=unknown",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+			wantParams: []*httputil.InvalidParam{
+				{In: "query", Name: "ID", Reason: "ID is not integer"},
+				{In: "query", Name: "scorer", Reason: "scorer is invalid"},
+			},
+		},
+		{
+			name: "ng: error at GetSuggestions()",
+			expects: func(ct *suggestionControllerTest) {
+				ct.su.EXPECT().GetSuggestions(gomock.Any(), testutil.UserIDForDebug, "mutual", 20, 0).Return(nil, testutil.ErrTest)
+			},
+			url:        "/get_friend_suggestion_list?ID=123456789",
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := newSuggestionControllerTest(t)
+			tt.expects(ct)
+
+			rec, req := httputil.NewRequestAndRecorder("GET", tt.url, nil)
+			ct.echo.GET("/get_friend_suggestion_list", func(c echo.Context) error {
+				if err := ct.sc.GetSuggestions(c); err != nil {
+					return httputil.RespondError(c, err)
+				}
+
+				return nil
+			}, testPaging)
+			ct.echo.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if !tt.wantErr {
+				testutil.AssertResponseBody(t, want, rec.Body)
+			}
+			if tt.wantParams != nil {
+				var got httputil.ErrorResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+				assert.Equal(t, tt.wantParams, got.Errors)
+			}
+		})
+	}
+}
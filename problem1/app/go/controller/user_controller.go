@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+
+	"problem1/model"
+	"problem1/pkg/httputil"
+	"problem1/pkg/textnorm"
+	"problem1/usecase"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+// maxNameLen is the length of users.name, varchar(64), which MySQL counts in characters.
+const maxNameLen = 64
+
+type UserController interface {
+	PostUser(c echo.Context) error
+	GetUser(c echo.Context) error
+	PatchUser(c echo.Context) error
+	DeleteUser(c echo.Context) error
+	SearchUsers(c echo.Context) error
+}
+
+type userController struct {
+	userUseCase usecase.UserUseCase
+}
+
+func NewUserController(uu usecase.UserUseCase) UserController {
+	return &userController{
+		userUseCase: uu,
+	}
+}
+
+func validateUserName(params *httputil.InvalidParams, name string) {
+	if name == "" {
+		params.Add("body", "/name", "name is empty")
+	}
+	if utf8.RuneCountInString(name) > maxNameLen {
+		params.Add("body", "/name", "name is too long")
+	}
+}
+
+func (c *userController) PostUser(ctx echo.Context) error {
+	var req model.UserForRequest
+	if err := json.NewDecoder(ctx.Request().Body).Decode(&req); err != nil {
+		return httputil.NewHTTPError(errors.New("request invalid"), http.StatusBadRequest, httputil.ErrorCodeInvalidRequest, "")
+	}
+
+	var params httputil.InvalidParams
+	if req.UserId < 0 || maxUserId < req.UserId {
+		params.Add("body", "/userId", "userId is invalid")
+	}
+	validateUserName(&params, req.Name)
+	if err := params.Err(httputil.ErrorCodeInvalidParameter); err != nil {
+		return err
+	}
+
+	user, err := c.userUseCase.CreateUser(ctx.Request().Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, user)
+}
+
+func (c *userController) GetUser(ctx echo.Context) error {
+	var params httputil.InvalidParams
+	userId := userIdFromParam(ctx, &params, "id")
+	if err := params.Err(httputil.ErrorCodeInvalidParameter); err != nil {
+		return err
+	}
+
+	user, err := c.userUseCase.GetUser(ctx.Request().Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, user)
+}
+
+func (c *userController) PatchUser(ctx echo.Context) error {
+	var params httputil.InvalidParams
+	userId := userIdFromParam(ctx, &params, "id")
+
+	var req model.UserPatchForRequest
+	if err := json.NewDecoder(ctx.Request().Body).Decode(&req); err != nil {
+		return httputil.NewHTTPError(errors.New("request invalid"), http.StatusBadRequest, httputil.ErrorCodeInvalidRequest, "")
+	}
+	if req.Name != nil {
+		validateUserName(&params, *req.Name)
+	}
+	if err := params.Err(httputil.ErrorCodeInvalidParameter); err != nil {
+		return err
+	}
+
+	user, err := c.userUseCase.UpdateUser(ctx.Request().Context(), userId, &req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, user)
+}
+
+func (c *userController) DeleteUser(ctx echo.Context) error {
+	var params httputil.InvalidParams
+	userId := userIdFromParam(ctx, &params, "id")
+	if err := params.Err(httputil.ErrorCodeInvalidParameter); err != nil {
+		return err
+	}
+
+	if err := c.userUseCase.DeleteUser(ctx.Request().Context(), userId); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// SearchUsers returns the users whose name contains the q query parameter, compared as
+// folded by textnorm.Normalize, to the user of the ID query parameter.
+func (c *userController) SearchUsers(ctx echo.Context) error {
+	var params httputil.InvalidParams
+	userId := userIdFromQuery(ctx, &params)
+	query := ctx.QueryParam("q")
+	if textnorm.Normalize(query) == "" {
+		params.Add("query", "q", "q is empty")
+	}
+	if utf8.RuneCountInString(query) > maxNameLen {
+		params.Add("query", "q", "q is too long")
+	}
+	if err := params.Err(httputil.ErrorCodeInvalidParameter); err != nil {
+		return err
+	}
+
+	limit, offset, err := pageFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	userList, err := c.userUseCase.SearchUsers(ctx.Request().Context(), userId, query, limit, offset)
+	if err != nil {
+		return err
+	}
+
+	setPagingLinkHeader(ctx, userList.Paging, "")
+
+	return ctx.JSON(http.StatusOK, userList)
+}
@@ -0,0 +1,415 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/mock/mock_usecase"
+	"problem1/model"
+	"problem1/pkg/httputil"
+	"problem1/pkg/testutil"
+)
+
+type userControllerTest struct {
+	uu   *mock_usecase.MockUserUseCase
+	uc   UserController
+	echo *echo.Echo
+}
+
+func newUserControllerTest(t *testing.T) *userControllerTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	uu := mock_usecase.NewMockUserUseCase(ctrl)
+
+	return &userControllerTest{
+		uu:   uu,
+		uc:   NewUserController(uu),
+		echo: echo.New(),
+	}
+}
+
+func Test_userController_PostUser(t *testing.T) {
+	testRequest := &model.UserForRequest{UserId: testutil.UserIDForDebug, Name: "藤井 太郎"}
+	want := &model.User{UserId: testRequest.UserId, Name: testRequest.Name}
+	maxName := strings.Repeat("あ", maxNameLen)
+
+	tests := []struct {
+		name       string
+		expects    func(*userControllerTest)
+		payload    any
+		wantStatus int
+		wantErr    bool
+		wantParams []*httputil.InvalidParam
+	}{
+		{
+			name: "ok",
+			expects: func(ct *userControllerTest) {
+				ct.uu.EXPECT().CreateUser(gomock.Any(), testRequest).Return(want, nil)
+			},
+			payload:    testRequest,
+			wantStatus: http.StatusCreated,
+			wantErr:    false,
+		},
+		{
+			name: "ok: name of 64 multibyte characters",
+			expects: func(ct *userControllerTest) {
+				ct.uu.EXPECT().CreateUser(gomock.Any(), &model.UserForRequest{UserId: 111111, Name: maxName}).Return(want, nil)
+			},
+			payload:    &model.UserForRequest{UserId: 111111, Name: maxName},
+			wantStatus: http.StatusCreated,
+			wantErr:    false,
+		},
+		{
+			name:       "ng: error at Decode()",
+			expects:    func(ct *userControllerTest) {},
+			payload:    "invalid",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "ng: userId invalid",
+			expects:    func(ct *userControllerTest) {},
+			payload:    &model.UserForRequest{UserId: -1, Name: "hoge"},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "ng: name empty",
+			expects:    func(ct *userControllerTest) {},
+			payload:    &model.UserForRequest{UserId: 111111},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "ng: name too long",
+			expects:    func(ct *userControllerTest) {},
+			payload:    &model.UserForRequest{UserId: 111111, Name: maxName + "a"},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "ng: every invalid field",
+			expects:    func(ct *userControllerTest) {},
+			payload:    &model.UserForRequest{UserId: -1, Name: maxName + "a"},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+			wantParams: []*httputil.InvalidParam{
+				{In: "body", Name: "/userId", Reason: "userId is invalid"},
+				{In: "body", Name: "/name", Reason: "name is too long"},
+			},
+		},
+		{
+			name: "ng: duplicated",
+			expects: func(ct *userControllerTest) {
+				ct.uu.EXPECT().CreateUser(gomock.Any(), testRequest).Return(nil, httputil.NewHTTPError(testutil.ErrTest, http.StatusConflict, httputil.ErrorCodeUserDuplicated, ""))
+			},
+			payload:    testRequest,
+			wantStatus: http.StatusConflict,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := newUserControllerTest(t)
+			tt.expects(ct)
+
+			rec, req := httputil.NewRequestAndRecorder("POST", "/users", testutil.I2Reader(t, tt.payload))
+			ct.echo.POST("/users", func(c echo.Context) error {
+				if err := ct.uc.PostUser(c); err != nil {
+					return httputil.RespondError(c, err)
+				}
+
+				return nil
+			})
+			ct.echo.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if !tt.wantErr {
+				testutil.AssertResponseBody(t, want, rec.Body)
+			}
+			if tt.wantParams != nil {
+				var got httputil.ErrorResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+				assert.Equal(t, tt.wantParams, got.Errors)
+			}
+		})
+	}
+}
+
+func Test_userController_GetUser(t *testing.T) {
+	want := &model.User{UserId: testutil.UserIDForDebug, Name: testutil.UserNameForDebug}
+
+	tests := []struct {
+		name       string
+		expects    func(*userControllerTest)
+		url        string
+		wantStatus int
+		wantErr    bool
+	}{
+		{
+			name: "ok",
+			expects: func(ct *userControllerTest) {
+				ct.uu.EXPECT().GetUser(gomock.Any(), testutil.UserIDForDebug).Return(want, nil)
+			},
+			url:        "/users/123456789",
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:       "ng: userId not integer",
+			expects:    func(ct *userControllerTest) {},
+			url:        "/users/invalid",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name: "ng: user not exist",
+			expects: func(ct *userControllerTest) {
+				ct.uu.EXPECT().GetUser(gomock.Any(), testutil.UserIDForDebug).Return(nil, httputil.NewHTTPError(testutil.ErrTest, http.StatusNotFound, httputil.ErrorCodeUserNotFound, ""))
+			},
+			url:        "/users/123456789",
+			wantStatus: http.StatusNotFound,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := newUserControllerTest(t)
+			tt.expects(ct)
+
+			rec, req := httputil.NewRequestAndRecorder("GET", tt.url, nil)
+			ct.echo.GET("/users/:id", func(c echo.Context) error {
+				if err := ct.uc.GetUser(c); err != nil {
+					return httputil.RespondError(c, err)
+				}
+
+				return nil
+			})
+			ct.echo.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if !tt.wantErr {
+				testutil.AssertResponseBody(t, want, rec.Body)
+			}
+		})
+	}
+}
+
+func Test_userController_PatchUser(t *testing.T) {
+	name := "hoge"
+	long := strings.Repeat("あ", maxNameLen+1)
+	want := &model.User{UserId: testutil.UserIDForDebug, Name: name}
+
+	tests := []struct {
+		name       string
+		expects    func(*userControllerTest)
+		payload    any
+		wantStatus int
+		wantErr    bool
+	}{
+		{
+			name: "ok",
+			expects: func(ct *userControllerTest) {
+				ct.uu.EXPECT().UpdateUser(gomock.Any(), testutil.UserIDForDebug, &model.UserPatchForRequest{Name: &name}).Return(want, nil)
+			},
+			payload:    &model.UserPatchForRequest{Name: &name},
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:       "ng: name too long",
+			expects:    func(ct *userControllerTest) {},
+			payload:    &model.UserPatchForRequest{Name: &long},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name: "ng: user not exist",
+			expects: func(ct *userControllerTest) {
+				ct.uu.EXPECT().UpdateUser(gomock.Any(), testutil.UserIDForDebug, &model.UserPatchForRequest{Name: &name}).Return(nil, httputil.NewHTTPError(testutil.ErrTest, http.StatusNotFound, httputil.ErrorCodeUserNotFound, ""))
+			},
+			payload:    &model.UserPatchForRequest{Name: &name},
+			wantStatus: http.StatusNotFound,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := newUserControllerTest(t)
+			tt.expects(ct)
+
+			rec, req := httputil.NewRequestAndRecorder("PATCH", "/users/123456789", testutil.I2Reader(t, tt.payload))
+			ct.echo.PATCH("/users/:id", func(c echo.Context) error {
+				if err := ct.uc.PatchUser(c); err != nil {
+					return httputil.RespondError(c, err)
+				}
+
+				return nil
+			})
+			ct.echo.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if !tt.wantErr {
+				testutil.AssertResponseBody(t, want, rec.Body)
+			}
+		})
+	}
+}
+
+func Test_userController_DeleteUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		expects    func(*userControllerTest)
+		wantStatus int
+	}{
+		{
+			name: "ok",
+			expects: func(ct *userControllerTest) {
+				ct.uu.EXPECT().DeleteUser(gomock.Any(), testutil.UserIDForDebug).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "ng: user not exist",
+			expects: func(ct *userControllerTest) {
+				ct.uu.EXPECT().DeleteUser(gomock.Any(), testutil.UserIDForDebug).Return(httputil.NewHTTPError(testutil.ErrTest, http.StatusNotFound, httputil.ErrorCodeUserNotFound, ""))
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := newUserControllerTest(t)
+			tt.expects(ct)
+
+			rec, req := httputil.NewRequestAndRecorder("DELETE", "/users/123456789", nil)
+			ct.echo.DELETE("/users/:id", func(c echo.Context) error {
+				if err := ct.uc.DeleteUser(c); err != nil {
+					return httputil.RespondError(c, err)
+				}
+
+				return nil
+			})
+			ct.echo.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func Test_userController_SearchUsers(t *testing.T) {
+	want := &model.UserList{
+		Users:  []*model.User{{UserId: 111111, Name: "スズキ"}},
+		Paging: &model.Paging{Total: 3, Page: 1, Limit: 1, HasNext: true},
+	}
+
+	tests := []struct {
+		name       string
+		expects    func(*userControllerTest)
+		url        string
+		wantLink   string
+		wantStatus int
+		wantErr    bool
+		wantParams []*httputil.InvalidParam
+	}{
+		{
+			name: "ok",
+			expects: func(ct *userControllerTest) {
+				ct.uu.EXPECT().SearchUsers(gomock.Any(), testutil.UserIDForDebug, "ｽｽﾞｷ", 1, 0).Return(want, nil)
+			},
+			url: "/users/search?ID=123456789&q=%EF%BD%BD%EF%BD%BD%EF%BE%9E%EF%BD%B7&limit=1",
+			wantLink: `</users/search?ID=123456789&limit=1&page=1&q=%EF%BD%BD%EF%BD%BD%EF%BE%9E%EF%BD%B7>; rel="first", ` +
+				`</users/search?ID=123456789&limit=1&page=2&q=%EF%BD%BD%EF%BD%BD%EF%BE%9E%EF%BD%B7>; rel="next", ` +
+				`</users/search?ID=123456789&limit=1&page=3&q=%EF%BD%BD%EF%BD%BD%EF%BE%9E%EF%BD%B7>; rel="last"`,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:       "ng: q missing",
+			expects:    func(ct *userControllerTest) {},
+			url:        "/users/search?ID=123456789",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "ng: q only whitespace",
+			expects:    func(ct *userControllerTest) {},
+			url:        "/users/search?ID=123456789&q=%E3%80%80+",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "ng: q too long",
+			expects:    func(ct *userControllerTest) {},
+			url:        "/users/search?ID=123456789&q=" + strings.Repeat("a", maxNameLen+1),
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "ng: userId missing in query parameter",
+			expects:    func(ct *userControllerTest) {},
+			url:        "/users/search?q=hoge",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "ng: every invalid parameter",
+			expects:    func(ct *userControllerTest) {},
+			url:        "/users/search?ID=-1&q=%20",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+			wantParams: []*httputil.InvalidParam{
+				{In: "query", Name: "ID", Reason: "ID is invalid"},
+				{In: "query", Name: "q", Reason: "q is empty"},
+			},
+		},
+		{
+			name: "ng: error at SearchUsers()",
+			expects: func(ct *userControllerTest) {
+				ct.uu.EXPECT().SearchUsers(gomock.Any(), testutil.UserIDForDebug, "hoge", 20, 0).Return(nil, testutil.ErrTest)
+			},
+			url:        "/users/search?ID=123456789&q=hoge",
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := newUserControllerTest(t)
+			tt.expects(ct)
+
+			rec, req := httputil.NewRequestAndRecorder("GET", tt.url, nil)
+			ct.echo.GET("/users/search", func(c echo.Context) error {
+				if err := ct.uc.SearchUsers(c); err != nil {
+					return httputil.RespondError(c, err)
+				}
+
+				return nil
+			}, testPaging)
+			ct.echo.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if !tt.wantErr {
+				assert.Equal(t, tt.wantLink, rec.Header().Get("Link"))
+				testutil.AssertResponseBody(t, want, rec.Body)
+			}
+			if tt.wantParams != nil {
+				var got httputil.ErrorResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+				assert.Equal(t, tt.wantParams, got.Errors)
+			}
+		})
+	}
+}
@@ -34,6 +34,48 @@ func (m *MockFriendListController) EXPECT() *MockFriendListControllerMockRecorde
 	return m.recorder
 }
 
+// DeleteUserLink mocks base method.
+func (m *MockFriendListController) DeleteUserLink(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserLink", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserLink indicates an expected call of DeleteUserLink.
+func (mr *MockFriendListControllerMockRecorder) DeleteUserLink(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserLink", reflect.TypeOf((*MockFriendListController)(nil).DeleteUserLink), c)
+}
+
+// GetBlockListByUserId mocks base method.
+func (m *MockFriendListController) GetBlockListByUserId(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockListByUserId", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetBlockListByUserId indicates an expected call of GetBlockListByUserId.
+func (mr *MockFriendListControllerMockRecorder) GetBlockListByUserId(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockListByUserId", reflect.TypeOf((*MockFriendListController)(nil).GetBlockListByUserId), c)
+}
+
+// GetBlocks mocks base method.
+func (m *MockFriendListController) GetBlocks(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlocks", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetBlocks indicates an expected call of GetBlocks.
+func (mr *MockFriendListControllerMockRecorder) GetBlocks(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlocks", reflect.TypeOf((*MockFriendListController)(nil).GetBlocks), c)
+}
+
 // GetFriendListByUserId mocks base method.
 func (m *MockFriendListController) GetFriendListByUserId(c echo.Context) error {
 	m.ctrl.T.Helper()
@@ -76,6 +118,48 @@ func (mr *MockFriendListControllerMockRecorder) GetFriendListOfFriendsByUserIdWi
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListOfFriendsByUserIdWithPaging", reflect.TypeOf((*MockFriendListController)(nil).GetFriendListOfFriendsByUserIdWithPaging), c)
 }
 
+// GetFriends mocks base method.
+func (m *MockFriendListController) GetFriends(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFriends", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetFriends indicates an expected call of GetFriends.
+func (mr *MockFriendListControllerMockRecorder) GetFriends(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriends", reflect.TypeOf((*MockFriendListController)(nil).GetFriends), c)
+}
+
+// GetFriendsOfFriends mocks base method.
+func (m *MockFriendListController) GetFriendsOfFriends(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFriendsOfFriends", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetFriendsOfFriends indicates an expected call of GetFriendsOfFriends.
+func (mr *MockFriendListControllerMockRecorder) GetFriendsOfFriends(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendsOfFriends", reflect.TypeOf((*MockFriendListController)(nil).GetFriendsOfFriends), c)
+}
+
+// GetNeighbourhood mocks base method.
+func (m *MockFriendListController) GetNeighbourhood(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNeighbourhood", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetNeighbourhood indicates an expected call of GetNeighbourhood.
+func (mr *MockFriendListControllerMockRecorder) GetNeighbourhood(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNeighbourhood", reflect.TypeOf((*MockFriendListController)(nil).GetNeighbourhood), c)
+}
+
 // PostUserLink mocks base method.
 func (m *MockFriendListController) PostUserLink(c echo.Context) error {
 	m.ctrl.T.Helper()
@@ -89,3 +173,17 @@ func (mr *MockFriendListControllerMockRecorder) PostUserLink(c interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostUserLink", reflect.TypeOf((*MockFriendListController)(nil).PostUserLink), c)
 }
+
+// PostUserLinks mocks base method.
+func (m *MockFriendListController) PostUserLinks(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostUserLinks", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostUserLinks indicates an expected call of PostUserLinks.
+func (mr *MockFriendListControllerMockRecorder) PostUserLinks(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostUserLinks", reflect.TypeOf((*MockFriendListController)(nil).PostUserLinks), c)
+}
@@ -0,0 +1,119 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: friend_request_controller.go
+
+// Package mock_controller is a generated GoMock package.
+package mock_controller
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	echo "github.com/labstack/echo/v4"
+)
+
+// MockFriendRequestController is a mock of FriendRequestController interface.
+type MockFriendRequestController struct {
+	ctrl     *gomock.Controller
+	recorder *MockFriendRequestControllerMockRecorder
+}
+
+// MockFriendRequestControllerMockRecorder is the mock recorder for MockFriendRequestController.
+type MockFriendRequestControllerMockRecorder struct {
+	mock *MockFriendRequestController
+}
+
+// NewMockFriendRequestController creates a new mock instance.
+func NewMockFriendRequestController(ctrl *gomock.Controller) *MockFriendRequestController {
+	mock := &MockFriendRequestController{ctrl: ctrl}
+	mock.recorder = &MockFriendRequestControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFriendRequestController) EXPECT() *MockFriendRequestControllerMockRecorder {
+	return m.recorder
+}
+
+// AcceptFriendRequest mocks base method.
+func (m *MockFriendRequestController) AcceptFriendRequest(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptFriendRequest", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcceptFriendRequest indicates an expected call of AcceptFriendRequest.
+func (mr *MockFriendRequestControllerMockRecorder) AcceptFriendRequest(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptFriendRequest", reflect.TypeOf((*MockFriendRequestController)(nil).AcceptFriendRequest), c)
+}
+
+// CancelFriendRequest mocks base method.
+func (m *MockFriendRequestController) CancelFriendRequest(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelFriendRequest", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelFriendRequest indicates an expected call of CancelFriendRequest.
+func (mr *MockFriendRequestControllerMockRecorder) CancelFriendRequest(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelFriendRequest", reflect.TypeOf((*MockFriendRequestController)(nil).CancelFriendRequest), c)
+}
+
+// GetIncomingFriendRequests mocks base method.
+func (m *MockFriendRequestController) GetIncomingFriendRequests(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncomingFriendRequests", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetIncomingFriendRequests indicates an expected call of GetIncomingFriendRequests.
+func (mr *MockFriendRequestControllerMockRecorder) GetIncomingFriendRequests(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncomingFriendRequests", reflect.TypeOf((*MockFriendRequestController)(nil).GetIncomingFriendRequests), c)
+}
+
+// GetOutgoingFriendRequests mocks base method.
+func (m *MockFriendRequestController) GetOutgoingFriendRequests(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutgoingFriendRequests", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetOutgoingFriendRequests indicates an expected call of GetOutgoingFriendRequests.
+func (mr *MockFriendRequestControllerMockRecorder) GetOutgoingFriendRequests(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutgoingFriendRequests", reflect.TypeOf((*MockFriendRequestController)(nil).GetOutgoingFriendRequests), c)
+}
+
+// PostFriendRequest mocks base method.
+func (m *MockFriendRequestController) PostFriendRequest(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostFriendRequest", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostFriendRequest indicates an expected call of PostFriendRequest.
+func (mr *MockFriendRequestControllerMockRecorder) PostFriendRequest(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostFriendRequest", reflect.TypeOf((*MockFriendRequestController)(nil).PostFriendRequest), c)
+}
+
+// RejectFriendRequest mocks base method.
+func (m *MockFriendRequestController) RejectFriendRequest(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectFriendRequest", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectFriendRequest indicates an expected call of RejectFriendRequest.
+func (mr *MockFriendRequestControllerMockRecorder) RejectFriendRequest(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectFriendRequest", reflect.TypeOf((*MockFriendRequestController)(nil).RejectFriendRequest), c)
+}
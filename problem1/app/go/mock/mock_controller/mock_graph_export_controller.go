@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: graph_export_controller.go
+
+// Package mock_controller is a generated GoMock package.
+package mock_controller
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	echo "github.com/labstack/echo/v4"
+)
+
+// MockGraphExportController is a mock of GraphExportController interface.
+type MockGraphExportController struct {
+	ctrl     *gomock.Controller
+	recorder *MockGraphExportControllerMockRecorder
+}
+
+// MockGraphExportControllerMockRecorder is the mock recorder for MockGraphExportController.
+type MockGraphExportControllerMockRecorder struct {
+	mock *MockGraphExportController
+}
+
+// NewMockGraphExportController creates a new mock instance.
+func NewMockGraphExportController(ctrl *gomock.Controller) *MockGraphExportController {
+	mock := &MockGraphExportController{ctrl: ctrl}
+	mock.recorder = &MockGraphExportControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGraphExportController) EXPECT() *MockGraphExportControllerMockRecorder {
+	return m.recorder
+}
+
+// ExportGraph mocks base method.
+func (m *MockGraphExportController) ExportGraph(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportGraph", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportGraph indicates an expected call of ExportGraph.
+func (mr *MockGraphExportControllerMockRecorder) ExportGraph(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportGraph", reflect.TypeOf((*MockGraphExportController)(nil).ExportGraph), c)
+}
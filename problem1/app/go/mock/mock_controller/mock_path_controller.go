@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: path_controller.go
+
+// Package mock_controller is a generated GoMock package.
+package mock_controller
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	echo "github.com/labstack/echo/v4"
+)
+
+// MockPathController is a mock of PathController interface.
+type MockPathController struct {
+	ctrl     *gomock.Controller
+	recorder *MockPathControllerMockRecorder
+}
+
+// MockPathControllerMockRecorder is the mock recorder for MockPathController.
+type MockPathControllerMockRecorder struct {
+	mock *MockPathController
+}
+
+// NewMockPathController creates a new mock instance.
+func NewMockPathController(ctrl *gomock.Controller) *MockPathController {
+	mock := &MockPathController{ctrl: ctrl}
+	mock.recorder = &MockPathControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPathController) EXPECT() *MockPathControllerMockRecorder {
+	return m.recorder
+}
+
+// GetShortestPath mocks base method.
+func (m *MockPathController) GetShortestPath(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShortestPath", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetShortestPath indicates an expected call of GetShortestPath.
+func (mr *MockPathControllerMockRecorder) GetShortestPath(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShortestPath", reflect.TypeOf((*MockPathController)(nil).GetShortestPath), c)
+}
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: suggestion_controller.go
+
+// Package mock_controller is a generated GoMock package.
+package mock_controller
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	echo "github.com/labstack/echo/v4"
+)
+
+// MockSuggestionController is a mock of SuggestionController interface.
+type MockSuggestionController struct {
+	ctrl     *gomock.Controller
+	recorder *MockSuggestionControllerMockRecorder
+}
+
+// MockSuggestionControllerMockRecorder is the mock recorder for MockSuggestionController.
+type MockSuggestionControllerMockRecorder struct {
+	mock *MockSuggestionController
+}
+
+// NewMockSuggestionController creates a new mock instance.
+func NewMockSuggestionController(ctrl *gomock.Controller) *MockSuggestionController {
+	mock := &MockSuggestionController{ctrl: ctrl}
+	mock.recorder = &MockSuggestionControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSuggestionController) EXPECT() *MockSuggestionControllerMockRecorder {
+	return m.recorder
+}
+
+// GetSuggestions mocks base method.
+func (m *MockSuggestionController) GetSuggestions(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSuggestions", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetSuggestions indicates an expected call of GetSuggestions.
+func (mr *MockSuggestionControllerMockRecorder) GetSuggestions(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSuggestions", reflect.TypeOf((*MockSuggestionController)(nil).GetSuggestions), c)
+}
@@ -0,0 +1,105 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: user_controller.go
+
+// Package mock_controller is a generated GoMock package.
+package mock_controller
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	echo "github.com/labstack/echo/v4"
+)
+
+// MockUserController is a mock of UserController interface.
+type MockUserController struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserControllerMockRecorder
+}
+
+// MockUserControllerMockRecorder is the mock recorder for MockUserController.
+type MockUserControllerMockRecorder struct {
+	mock *MockUserController
+}
+
+// NewMockUserController creates a new mock instance.
+func NewMockUserController(ctrl *gomock.Controller) *MockUserController {
+	mock := &MockUserController{ctrl: ctrl}
+	mock.recorder = &MockUserControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserController) EXPECT() *MockUserControllerMockRecorder {
+	return m.recorder
+}
+
+// DeleteUser mocks base method.
+func (m *MockUserController) DeleteUser(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockUserControllerMockRecorder) DeleteUser(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserController)(nil).DeleteUser), c)
+}
+
+// GetUser mocks base method.
+func (m *MockUserController) GetUser(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockUserControllerMockRecorder) GetUser(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserController)(nil).GetUser), c)
+}
+
+// PatchUser mocks base method.
+func (m *MockUserController) PatchUser(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchUser", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PatchUser indicates an expected call of PatchUser.
+func (mr *MockUserControllerMockRecorder) PatchUser(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchUser", reflect.TypeOf((*MockUserController)(nil).PatchUser), c)
+}
+
+// PostUser mocks base method.
+func (m *MockUserController) PostUser(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostUser", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostUser indicates an expected call of PostUser.
+func (mr *MockUserControllerMockRecorder) PostUser(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostUser", reflect.TypeOf((*MockUserController)(nil).PostUser), c)
+}
+
+// SearchUsers mocks base method.
+func (m *MockUserController) SearchUsers(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUsers", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SearchUsers indicates an expected call of SearchUsers.
+func (mr *MockUserControllerMockRecorder) SearchUsers(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUsers", reflect.TypeOf((*MockUserController)(nil).SearchUsers), c)
+}
@@ -5,6 +5,7 @@
 package mock_repository
 
 import (
+	context "context"
 	model "problem1/model"
 	reflect "reflect"
 
@@ -35,134 +36,400 @@ func (m *MockFriendListRepository) EXPECT() *MockFriendListRepositoryMockRecorde
 }
 
 // CheckUserExist mocks base method.
-func (m *MockFriendListRepository) CheckUserExist(userId int) (bool, error) {
+func (m *MockFriendListRepository) CheckUserExist(ctx context.Context, userId int) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckUserExist", userId)
+	ret := m.ctrl.Call(m, "CheckUserExist", ctx, userId)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CheckUserExist indicates an expected call of CheckUserExist.
-func (mr *MockFriendListRepositoryMockRecorder) CheckUserExist(userId interface{}) *gomock.Call {
+func (mr *MockFriendListRepositoryMockRecorder) CheckUserExist(ctx, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUserExist", reflect.TypeOf((*MockFriendListRepository)(nil).CheckUserExist), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUserExist", reflect.TypeOf((*MockFriendListRepository)(nil).CheckUserExist), ctx, userId)
 }
 
 // CheckUserLink mocks base method.
-func (m *MockFriendListRepository) CheckUserLink(user1Id, user2Id int, table string) error {
+func (m *MockFriendListRepository) CheckUserLink(ctx context.Context, user1Id, user2Id int, table string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckUserLink", user1Id, user2Id, table)
+	ret := m.ctrl.Call(m, "CheckUserLink", ctx, user1Id, user2Id, table)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CheckUserLink indicates an expected call of CheckUserLink.
-func (mr *MockFriendListRepositoryMockRecorder) CheckUserLink(user1Id, user2Id, table interface{}) *gomock.Call {
+func (mr *MockFriendListRepositoryMockRecorder) CheckUserLink(ctx, user1Id, user2Id, table interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUserLink", reflect.TypeOf((*MockFriendListRepository)(nil).CheckUserLink), user1Id, user2Id, table)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUserLink", reflect.TypeOf((*MockFriendListRepository)(nil).CheckUserLink), ctx, user1Id, user2Id, table)
+}
+
+// CountBlockListByUserId mocks base method.
+func (m *MockFriendListRepository) CountBlockListByUserId(ctx context.Context, userId int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBlockListByUserId", ctx, userId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBlockListByUserId indicates an expected call of CountBlockListByUserId.
+func (mr *MockFriendListRepositoryMockRecorder) CountBlockListByUserId(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBlockListByUserId", reflect.TypeOf((*MockFriendListRepository)(nil).CountBlockListByUserId), ctx, userId)
+}
+
+// CountFriendListByUserId mocks base method.
+func (m *MockFriendListRepository) CountFriendListByUserId(ctx context.Context, userId int, excludeBlockedBy bool) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountFriendListByUserId", ctx, userId, excludeBlockedBy)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountFriendListByUserId indicates an expected call of CountFriendListByUserId.
+func (mr *MockFriendListRepositoryMockRecorder) CountFriendListByUserId(ctx, userId, excludeBlockedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountFriendListByUserId", reflect.TypeOf((*MockFriendListRepository)(nil).CountFriendListByUserId), ctx, userId, excludeBlockedBy)
+}
+
+// CountFriendListOfFriendsByUserId mocks base method.
+func (m *MockFriendListRepository) CountFriendListOfFriendsByUserId(ctx context.Context, userId int, excludeBlockedBy bool) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountFriendListOfFriendsByUserId", ctx, userId, excludeBlockedBy)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountFriendListOfFriendsByUserId indicates an expected call of CountFriendListOfFriendsByUserId.
+func (mr *MockFriendListRepositoryMockRecorder) CountFriendListOfFriendsByUserId(ctx, userId, excludeBlockedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountFriendListOfFriendsByUserId", reflect.TypeOf((*MockFriendListRepository)(nil).CountFriendListOfFriendsByUserId), ctx, userId, excludeBlockedBy)
+}
+
+// DeleteFriendLinksBetween mocks base method.
+func (m *MockFriendListRepository) DeleteFriendLinksBetween(ctx context.Context, user1Id, user2Id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFriendLinksBetween", ctx, user1Id, user2Id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFriendLinksBetween indicates an expected call of DeleteFriendLinksBetween.
+func (mr *MockFriendListRepositoryMockRecorder) DeleteFriendLinksBetween(ctx, user1Id, user2Id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFriendLinksBetween", reflect.TypeOf((*MockFriendListRepository)(nil).DeleteFriendLinksBetween), ctx, user1Id, user2Id)
+}
+
+// DeleteUserLink mocks base method.
+func (m *MockFriendListRepository) DeleteUserLink(ctx context.Context, user1Id, user2Id int, table string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserLink", ctx, user1Id, user2Id, table)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserLink indicates an expected call of DeleteUserLink.
+func (mr *MockFriendListRepositoryMockRecorder) DeleteUserLink(ctx, user1Id, user2Id, table interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserLink", reflect.TypeOf((*MockFriendListRepository)(nil).DeleteUserLink), ctx, user1Id, user2Id, table)
+}
+
+// DeleteUserLinksOfUser mocks base method.
+func (m *MockFriendListRepository) DeleteUserLinksOfUser(ctx context.Context, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserLinksOfUser", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserLinksOfUser indicates an expected call of DeleteUserLinksOfUser.
+func (mr *MockFriendListRepositoryMockRecorder) DeleteUserLinksOfUser(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserLinksOfUser", reflect.TypeOf((*MockFriendListRepository)(nil).DeleteUserLinksOfUser), ctx, userId)
+}
+
+// GetBlockListByUserId mocks base method.
+func (m *MockFriendListRepository) GetBlockListByUserId(ctx context.Context, userId int) (*model.BlockList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockListByUserId", ctx, userId)
+	ret0, _ := ret[0].(*model.BlockList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockListByUserId indicates an expected call of GetBlockListByUserId.
+func (mr *MockFriendListRepositoryMockRecorder) GetBlockListByUserId(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockListByUserId", reflect.TypeOf((*MockFriendListRepository)(nil).GetBlockListByUserId), ctx, userId)
+}
+
+// GetBlockListByUserIdWithPaging mocks base method.
+func (m *MockFriendListRepository) GetBlockListByUserIdWithPaging(ctx context.Context, userId, limit, offset int) (*model.BlockList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockListByUserIdWithPaging", ctx, userId, limit, offset)
+	ret0, _ := ret[0].(*model.BlockList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockListByUserIdWithPaging indicates an expected call of GetBlockListByUserIdWithPaging.
+func (mr *MockFriendListRepositoryMockRecorder) GetBlockListByUserIdWithPaging(ctx, userId, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockListByUserIdWithPaging", reflect.TypeOf((*MockFriendListRepository)(nil).GetBlockListByUserIdWithPaging), ctx, userId, limit, offset)
 }
 
 // GetBlockUsersIdList mocks base method.
-func (m *MockFriendListRepository) GetBlockUsersIdList(userId int) ([]int, error) {
+func (m *MockFriendListRepository) GetBlockUsersIdList(ctx context.Context, userId int) ([]int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBlockUsersIdList", userId)
+	ret := m.ctrl.Call(m, "GetBlockUsersIdList", ctx, userId)
 	ret0, _ := ret[0].([]int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBlockUsersIdList indicates an expected call of GetBlockUsersIdList.
-func (mr *MockFriendListRepositoryMockRecorder) GetBlockUsersIdList(userId interface{}) *gomock.Call {
+func (mr *MockFriendListRepositoryMockRecorder) GetBlockUsersIdList(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockUsersIdList", reflect.TypeOf((*MockFriendListRepository)(nil).GetBlockUsersIdList), ctx, userId)
+}
+
+// GetBlockedByUsersIdList mocks base method.
+func (m *MockFriendListRepository) GetBlockedByUsersIdList(ctx context.Context, userId int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockedByUsersIdList", ctx, userId)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockedByUsersIdList indicates an expected call of GetBlockedByUsersIdList.
+func (mr *MockFriendListRepositoryMockRecorder) GetBlockedByUsersIdList(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockedByUsersIdList", reflect.TypeOf((*MockFriendListRepository)(nil).GetBlockedByUsersIdList), ctx, userId)
+}
+
+// GetExistingUserLinks mocks base method.
+func (m *MockFriendListRepository) GetExistingUserLinks(ctx context.Context, table string, userLinks [][2]int) (map[[2]int]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExistingUserLinks", ctx, table, userLinks)
+	ret0, _ := ret[0].(map[[2]int]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExistingUserLinks indicates an expected call of GetExistingUserLinks.
+func (mr *MockFriendListRepositoryMockRecorder) GetExistingUserLinks(ctx, table, userLinks interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockUsersIdList", reflect.TypeOf((*MockFriendListRepository)(nil).GetBlockUsersIdList), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExistingUserLinks", reflect.TypeOf((*MockFriendListRepository)(nil).GetExistingUserLinks), ctx, table, userLinks)
+}
+
+// GetFriendCountByUserIds mocks base method.
+func (m *MockFriendListRepository) GetFriendCountByUserIds(ctx context.Context, userIds []int) (map[int]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFriendCountByUserIds", ctx, userIds)
+	ret0, _ := ret[0].(map[int]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFriendCountByUserIds indicates an expected call of GetFriendCountByUserIds.
+func (mr *MockFriendListRepositoryMockRecorder) GetFriendCountByUserIds(ctx, userIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendCountByUserIds", reflect.TypeOf((*MockFriendListRepository)(nil).GetFriendCountByUserIds), ctx, userIds)
 }
 
 // GetFriendListByUserId mocks base method.
-func (m *MockFriendListRepository) GetFriendListByUserId(userId int) (*model.FriendList, error) {
+func (m *MockFriendListRepository) GetFriendListByUserId(ctx context.Context, userId int, sort model.FriendListSort) (*model.FriendList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFriendListByUserId", userId)
+	ret := m.ctrl.Call(m, "GetFriendListByUserId", ctx, userId, sort)
 	ret0, _ := ret[0].(*model.FriendList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFriendListByUserId indicates an expected call of GetFriendListByUserId.
-func (mr *MockFriendListRepositoryMockRecorder) GetFriendListByUserId(userId interface{}) *gomock.Call {
+func (mr *MockFriendListRepositoryMockRecorder) GetFriendListByUserId(ctx, userId, sort interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListByUserId", reflect.TypeOf((*MockFriendListRepository)(nil).GetFriendListByUserId), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListByUserId", reflect.TypeOf((*MockFriendListRepository)(nil).GetFriendListByUserId), ctx, userId, sort)
 }
 
 // GetFriendListByUserIdExcludingBlockUsers mocks base method.
-func (m *MockFriendListRepository) GetFriendListByUserIdExcludingBlockUsers(userId int, blockUsers []int) (*model.FriendList, error) {
+func (m *MockFriendListRepository) GetFriendListByUserIdExcludingBlockUsers(ctx context.Context, userId int, excludeBlockedBy bool, sort model.FriendListSort) (*model.FriendList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFriendListByUserIdExcludingBlockUsers", userId, blockUsers)
+	ret := m.ctrl.Call(m, "GetFriendListByUserIdExcludingBlockUsers", ctx, userId, excludeBlockedBy, sort)
 	ret0, _ := ret[0].(*model.FriendList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFriendListByUserIdExcludingBlockUsers indicates an expected call of GetFriendListByUserIdExcludingBlockUsers.
-func (mr *MockFriendListRepositoryMockRecorder) GetFriendListByUserIdExcludingBlockUsers(userId, blockUsers interface{}) *gomock.Call {
+func (mr *MockFriendListRepositoryMockRecorder) GetFriendListByUserIdExcludingBlockUsers(ctx, userId, excludeBlockedBy, sort interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListByUserIdExcludingBlockUsers", reflect.TypeOf((*MockFriendListRepository)(nil).GetFriendListByUserIdExcludingBlockUsers), userId, blockUsers)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListByUserIdExcludingBlockUsers", reflect.TypeOf((*MockFriendListRepository)(nil).GetFriendListByUserIdExcludingBlockUsers), ctx, userId, excludeBlockedBy, sort)
+}
+
+// GetFriendListByUserIdWithPaging mocks base method.
+func (m *MockFriendListRepository) GetFriendListByUserIdWithPaging(ctx context.Context, userId int, excludeBlockedBy bool, sort model.FriendListSort, limit, offset int) (*model.FriendList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFriendListByUserIdWithPaging", ctx, userId, excludeBlockedBy, sort, limit, offset)
+	ret0, _ := ret[0].(*model.FriendList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFriendListByUserIdWithPaging indicates an expected call of GetFriendListByUserIdWithPaging.
+func (mr *MockFriendListRepositoryMockRecorder) GetFriendListByUserIdWithPaging(ctx, userId, excludeBlockedBy, sort, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListByUserIdWithPaging", reflect.TypeOf((*MockFriendListRepository)(nil).GetFriendListByUserIdWithPaging), ctx, userId, excludeBlockedBy, sort, limit, offset)
 }
 
 // GetFriendListOfFriendsByUserId mocks base method.
-func (m *MockFriendListRepository) GetFriendListOfFriendsByUserId(userId int, excludeUsers []int) (*model.FriendList, error) {
+func (m *MockFriendListRepository) GetFriendListOfFriendsByUserId(ctx context.Context, userId int, excludeBlockedBy bool, sort model.FriendListSort) (*model.FriendList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFriendListOfFriendsByUserId", userId, excludeUsers)
+	ret := m.ctrl.Call(m, "GetFriendListOfFriendsByUserId", ctx, userId, excludeBlockedBy, sort)
 	ret0, _ := ret[0].(*model.FriendList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFriendListOfFriendsByUserId indicates an expected call of GetFriendListOfFriendsByUserId.
-func (mr *MockFriendListRepositoryMockRecorder) GetFriendListOfFriendsByUserId(userId, excludeUsers interface{}) *gomock.Call {
+func (mr *MockFriendListRepositoryMockRecorder) GetFriendListOfFriendsByUserId(ctx, userId, excludeBlockedBy, sort interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListOfFriendsByUserId", reflect.TypeOf((*MockFriendListRepository)(nil).GetFriendListOfFriendsByUserId), ctx, userId, excludeBlockedBy, sort)
+}
+
+// GetFriendListOfFriendsByUserIdWithCursor mocks base method.
+func (m *MockFriendListRepository) GetFriendListOfFriendsByUserIdWithCursor(ctx context.Context, userId int, excludeBlockedBy bool, lastUserId, limit int) (*model.FriendList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFriendListOfFriendsByUserIdWithCursor", ctx, userId, excludeBlockedBy, lastUserId, limit)
+	ret0, _ := ret[0].(*model.FriendList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFriendListOfFriendsByUserIdWithCursor indicates an expected call of GetFriendListOfFriendsByUserIdWithCursor.
+func (mr *MockFriendListRepositoryMockRecorder) GetFriendListOfFriendsByUserIdWithCursor(ctx, userId, excludeBlockedBy, lastUserId, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListOfFriendsByUserId", reflect.TypeOf((*MockFriendListRepository)(nil).GetFriendListOfFriendsByUserId), userId, excludeUsers)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListOfFriendsByUserIdWithCursor", reflect.TypeOf((*MockFriendListRepository)(nil).GetFriendListOfFriendsByUserIdWithCursor), ctx, userId, excludeBlockedBy, lastUserId, limit)
 }
 
 // GetFriendListOfFriendsByUserIdWithPaging mocks base method.
-func (m *MockFriendListRepository) GetFriendListOfFriendsByUserIdWithPaging(userId int, excludeUsers []int, limit, offset int) (*model.FriendList, error) {
+func (m *MockFriendListRepository) GetFriendListOfFriendsByUserIdWithPaging(ctx context.Context, userId int, excludeBlockedBy bool, sort model.FriendListSort, limit, offset int) (*model.FriendList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFriendListOfFriendsByUserIdWithPaging", userId, excludeUsers, limit, offset)
+	ret := m.ctrl.Call(m, "GetFriendListOfFriendsByUserIdWithPaging", ctx, userId, excludeBlockedBy, sort, limit, offset)
 	ret0, _ := ret[0].(*model.FriendList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFriendListOfFriendsByUserIdWithPaging indicates an expected call of GetFriendListOfFriendsByUserIdWithPaging.
-func (mr *MockFriendListRepositoryMockRecorder) GetFriendListOfFriendsByUserIdWithPaging(userId, excludeUsers, limit, offset interface{}) *gomock.Call {
+func (mr *MockFriendListRepositoryMockRecorder) GetFriendListOfFriendsByUserIdWithPaging(ctx, userId, excludeBlockedBy, sort, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListOfFriendsByUserIdWithPaging", reflect.TypeOf((*MockFriendListRepository)(nil).GetFriendListOfFriendsByUserIdWithPaging), ctx, userId, excludeBlockedBy, sort, limit, offset)
+}
+
+// GetFriendOfUserIdsByUserIds mocks base method.
+func (m *MockFriendListRepository) GetFriendOfUserIdsByUserIds(ctx context.Context, userIds []int) (map[int][]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFriendOfUserIdsByUserIds", ctx, userIds)
+	ret0, _ := ret[0].(map[int][]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFriendOfUserIdsByUserIds indicates an expected call of GetFriendOfUserIdsByUserIds.
+func (mr *MockFriendListRepositoryMockRecorder) GetFriendOfUserIdsByUserIds(ctx, userIds interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListOfFriendsByUserIdWithPaging", reflect.TypeOf((*MockFriendListRepository)(nil).GetFriendListOfFriendsByUserIdWithPaging), userId, excludeUsers, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendOfUserIdsByUserIds", reflect.TypeOf((*MockFriendListRepository)(nil).GetFriendOfUserIdsByUserIds), ctx, userIds)
+}
+
+// GetFriendUserIdsByUserIds mocks base method.
+func (m *MockFriendListRepository) GetFriendUserIdsByUserIds(ctx context.Context, userIds []int) (map[int][]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFriendUserIdsByUserIds", ctx, userIds)
+	ret0, _ := ret[0].(map[int][]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFriendUserIdsByUserIds indicates an expected call of GetFriendUserIdsByUserIds.
+func (mr *MockFriendListRepositoryMockRecorder) GetFriendUserIdsByUserIds(ctx, userIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendUserIdsByUserIds", reflect.TypeOf((*MockFriendListRepository)(nil).GetFriendUserIdsByUserIds), ctx, userIds)
+}
+
+// GetMutualFriendsByUserId mocks base method.
+func (m *MockFriendListRepository) GetMutualFriendsByUserId(ctx context.Context, userId int, excludeBlockedBy bool) ([]*model.MutualFriend, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMutualFriendsByUserId", ctx, userId, excludeBlockedBy)
+	ret0, _ := ret[0].([]*model.MutualFriend)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMutualFriendsByUserId indicates an expected call of GetMutualFriendsByUserId.
+func (mr *MockFriendListRepositoryMockRecorder) GetMutualFriendsByUserId(ctx, userId, excludeBlockedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMutualFriendsByUserId", reflect.TypeOf((*MockFriendListRepository)(nil).GetMutualFriendsByUserId), ctx, userId, excludeBlockedBy)
 }
 
 // GetOneHopFriendsUserIdList mocks base method.
-func (m *MockFriendListRepository) GetOneHopFriendsUserIdList(userId int) ([]int, error) {
+func (m *MockFriendListRepository) GetOneHopFriendsUserIdList(ctx context.Context, userId int) ([]int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOneHopFriendsUserIdList", userId)
+	ret := m.ctrl.Call(m, "GetOneHopFriendsUserIdList", ctx, userId)
 	ret0, _ := ret[0].([]int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOneHopFriendsUserIdList indicates an expected call of GetOneHopFriendsUserIdList.
-func (mr *MockFriendListRepositoryMockRecorder) GetOneHopFriendsUserIdList(userId interface{}) *gomock.Call {
+func (mr *MockFriendListRepositoryMockRecorder) GetOneHopFriendsUserIdList(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOneHopFriendsUserIdList", reflect.TypeOf((*MockFriendListRepository)(nil).GetOneHopFriendsUserIdList), ctx, userId)
+}
+
+// GetUserListByUserIds mocks base method.
+func (m *MockFriendListRepository) GetUserListByUserIds(ctx context.Context, userIds []int) (*model.FriendList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserListByUserIds", ctx, userIds)
+	ret0, _ := ret[0].(*model.FriendList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserListByUserIds indicates an expected call of GetUserListByUserIds.
+func (mr *MockFriendListRepositoryMockRecorder) GetUserListByUserIds(ctx, userIds interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOneHopFriendsUserIdList", reflect.TypeOf((*MockFriendListRepository)(nil).GetOneHopFriendsUserIdList), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserListByUserIds", reflect.TypeOf((*MockFriendListRepository)(nil).GetUserListByUserIds), ctx, userIds)
 }
 
 // InsertUserLink mocks base method.
-func (m *MockFriendListRepository) InsertUserLink(user1Id, user2Id int, table string) error {
+func (m *MockFriendListRepository) InsertUserLink(ctx context.Context, user1Id, user2Id int, table string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertUserLink", user1Id, user2Id, table)
+	ret := m.ctrl.Call(m, "InsertUserLink", ctx, user1Id, user2Id, table)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // InsertUserLink indicates an expected call of InsertUserLink.
-func (mr *MockFriendListRepositoryMockRecorder) InsertUserLink(user1Id, user2Id, table interface{}) *gomock.Call {
+func (mr *MockFriendListRepositoryMockRecorder) InsertUserLink(ctx, user1Id, user2Id, table interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserLink", reflect.TypeOf((*MockFriendListRepository)(nil).InsertUserLink), ctx, user1Id, user2Id, table)
+}
+
+// InsertUserLinks mocks base method.
+func (m *MockFriendListRepository) InsertUserLinks(ctx context.Context, table string, userLinks [][2]int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUserLinks", ctx, table, userLinks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertUserLinks indicates an expected call of InsertUserLinks.
+func (mr *MockFriendListRepositoryMockRecorder) InsertUserLinks(ctx, table, userLinks interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserLink", reflect.TypeOf((*MockFriendListRepository)(nil).InsertUserLink), user1Id, user2Id, table)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserLinks", reflect.TypeOf((*MockFriendListRepository)(nil).InsertUserLinks), ctx, table, userLinks)
 }
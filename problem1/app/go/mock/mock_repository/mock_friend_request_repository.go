@@ -0,0 +1,153 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: friend_request_repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	model "problem1/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockFriendRequestRepository is a mock of FriendRequestRepository interface.
+type MockFriendRequestRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFriendRequestRepositoryMockRecorder
+}
+
+// MockFriendRequestRepositoryMockRecorder is the mock recorder for MockFriendRequestRepository.
+type MockFriendRequestRepositoryMockRecorder struct {
+	mock *MockFriendRequestRepository
+}
+
+// NewMockFriendRequestRepository creates a new mock instance.
+func NewMockFriendRequestRepository(ctrl *gomock.Controller) *MockFriendRequestRepository {
+	mock := &MockFriendRequestRepository{ctrl: ctrl}
+	mock.recorder = &MockFriendRequestRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFriendRequestRepository) EXPECT() *MockFriendRequestRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CancelPendingFriendRequestsBetween mocks base method.
+func (m *MockFriendRequestRepository) CancelPendingFriendRequestsBetween(ctx context.Context, user1Id, user2Id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelPendingFriendRequestsBetween", ctx, user1Id, user2Id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelPendingFriendRequestsBetween indicates an expected call of CancelPendingFriendRequestsBetween.
+func (mr *MockFriendRequestRepositoryMockRecorder) CancelPendingFriendRequestsBetween(ctx, user1Id, user2Id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelPendingFriendRequestsBetween", reflect.TypeOf((*MockFriendRequestRepository)(nil).CancelPendingFriendRequestsBetween), ctx, user1Id, user2Id)
+}
+
+// CancelPendingFriendRequestsOfUser mocks base method.
+func (m *MockFriendRequestRepository) CancelPendingFriendRequestsOfUser(ctx context.Context, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelPendingFriendRequestsOfUser", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelPendingFriendRequestsOfUser indicates an expected call of CancelPendingFriendRequestsOfUser.
+func (mr *MockFriendRequestRepositoryMockRecorder) CancelPendingFriendRequestsOfUser(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelPendingFriendRequestsOfUser", reflect.TypeOf((*MockFriendRequestRepository)(nil).CancelPendingFriendRequestsOfUser), ctx, userId)
+}
+
+// ExistsPendingFriendRequest mocks base method.
+func (m *MockFriendRequestRepository) ExistsPendingFriendRequest(ctx context.Context, user1Id, user2Id int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsPendingFriendRequest", ctx, user1Id, user2Id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsPendingFriendRequest indicates an expected call of ExistsPendingFriendRequest.
+func (mr *MockFriendRequestRepositoryMockRecorder) ExistsPendingFriendRequest(ctx, user1Id, user2Id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsPendingFriendRequest", reflect.TypeOf((*MockFriendRequestRepository)(nil).ExistsPendingFriendRequest), ctx, user1Id, user2Id)
+}
+
+// GetFriendRequestForUpdate mocks base method.
+func (m *MockFriendRequestRepository) GetFriendRequestForUpdate(ctx context.Context, requestId int) (*model.FriendRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFriendRequestForUpdate", ctx, requestId)
+	ret0, _ := ret[0].(*model.FriendRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFriendRequestForUpdate indicates an expected call of GetFriendRequestForUpdate.
+func (mr *MockFriendRequestRepositoryMockRecorder) GetFriendRequestForUpdate(ctx, requestId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendRequestForUpdate", reflect.TypeOf((*MockFriendRequestRepository)(nil).GetFriendRequestForUpdate), ctx, requestId)
+}
+
+// GetIncomingFriendRequests mocks base method.
+func (m *MockFriendRequestRepository) GetIncomingFriendRequests(ctx context.Context, userId int) (*model.FriendRequestList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncomingFriendRequests", ctx, userId)
+	ret0, _ := ret[0].(*model.FriendRequestList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncomingFriendRequests indicates an expected call of GetIncomingFriendRequests.
+func (mr *MockFriendRequestRepositoryMockRecorder) GetIncomingFriendRequests(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncomingFriendRequests", reflect.TypeOf((*MockFriendRequestRepository)(nil).GetIncomingFriendRequests), ctx, userId)
+}
+
+// GetOutgoingFriendRequests mocks base method.
+func (m *MockFriendRequestRepository) GetOutgoingFriendRequests(ctx context.Context, userId int) (*model.FriendRequestList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutgoingFriendRequests", ctx, userId)
+	ret0, _ := ret[0].(*model.FriendRequestList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutgoingFriendRequests indicates an expected call of GetOutgoingFriendRequests.
+func (mr *MockFriendRequestRepositoryMockRecorder) GetOutgoingFriendRequests(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutgoingFriendRequests", reflect.TypeOf((*MockFriendRequestRepository)(nil).GetOutgoingFriendRequests), ctx, userId)
+}
+
+// InsertFriendRequest mocks base method.
+func (m *MockFriendRequestRepository) InsertFriendRequest(ctx context.Context, fromUserId, toUserId int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertFriendRequest", ctx, fromUserId, toUserId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertFriendRequest indicates an expected call of InsertFriendRequest.
+func (mr *MockFriendRequestRepositoryMockRecorder) InsertFriendRequest(ctx, fromUserId, toUserId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertFriendRequest", reflect.TypeOf((*MockFriendRequestRepository)(nil).InsertFriendRequest), ctx, fromUserId, toUserId)
+}
+
+// UpdatePendingFriendRequestStatus mocks base method.
+func (m *MockFriendRequestRepository) UpdatePendingFriendRequestStatus(ctx context.Context, requestId int, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePendingFriendRequestStatus", ctx, requestId, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePendingFriendRequestStatus indicates an expected call of UpdatePendingFriendRequestStatus.
+func (mr *MockFriendRequestRepositoryMockRecorder) UpdatePendingFriendRequestStatus(ctx, requestId, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePendingFriendRequestStatus", reflect.TypeOf((*MockFriendRequestRepository)(nil).UpdatePendingFriendRequestStatus), ctx, requestId, status)
+}
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: graph_export_repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	model "problem1/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockGraphExportRepository is a mock of GraphExportRepository interface.
+type MockGraphExportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGraphExportRepositoryMockRecorder
+}
+
+// MockGraphExportRepositoryMockRecorder is the mock recorder for MockGraphExportRepository.
+type MockGraphExportRepositoryMockRecorder struct {
+	mock *MockGraphExportRepository
+}
+
+// NewMockGraphExportRepository creates a new mock instance.
+func NewMockGraphExportRepository(ctrl *gomock.Controller) *MockGraphExportRepository {
+	mock := &MockGraphExportRepository{ctrl: ctrl}
+	mock.recorder = &MockGraphExportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGraphExportRepository) EXPECT() *MockGraphExportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// EachUser mocks base method.
+func (m *MockGraphExportRepository) EachUser(ctx context.Context, userIds []int, fn func(*model.User) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EachUser", ctx, userIds, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EachUser indicates an expected call of EachUser.
+func (mr *MockGraphExportRepositoryMockRecorder) EachUser(ctx, userIds, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EachUser", reflect.TypeOf((*MockGraphExportRepository)(nil).EachUser), ctx, userIds, fn)
+}
+
+// EachUserLink mocks base method.
+func (m *MockGraphExportRepository) EachUserLink(ctx context.Context, table string, userIds []int, fn func(int, int) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EachUserLink", ctx, table, userIds, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EachUserLink indicates an expected call of EachUserLink.
+func (mr *MockGraphExportRepositoryMockRecorder) EachUserLink(ctx, table, userIds, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EachUserLink", reflect.TypeOf((*MockGraphExportRepository)(nil).EachUserLink), ctx, table, userIds, fn)
+}
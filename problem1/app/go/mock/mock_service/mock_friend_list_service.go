@@ -5,11 +5,11 @@
 package mock_service
 
 import (
+	context "context"
 	model "problem1/model"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
-	echo "github.com/labstack/echo/v4"
 )
 
 // MockFriendListService is a mock of FriendListService interface.
@@ -35,76 +35,223 @@ func (m *MockFriendListService) EXPECT() *MockFriendListServiceMockRecorder {
 	return m.recorder
 }
 
+// AddFriendship mocks base method.
+func (m *MockFriendListService) AddFriendship(ctx context.Context, user1Id, user2Id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddFriendship", ctx, user1Id, user2Id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddFriendship indicates an expected call of AddFriendship.
+func (mr *MockFriendListServiceMockRecorder) AddFriendship(ctx, user1Id, user2Id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFriendship", reflect.TypeOf((*MockFriendListService)(nil).AddFriendship), ctx, user1Id, user2Id)
+}
+
+// BlockUser mocks base method.
+func (m *MockFriendListService) BlockUser(ctx context.Context, userId, blockUserId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUser", ctx, userId, blockUserId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockUser indicates an expected call of BlockUser.
+func (mr *MockFriendListServiceMockRecorder) BlockUser(ctx, userId, blockUserId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUser", reflect.TypeOf((*MockFriendListService)(nil).BlockUser), ctx, userId, blockUserId)
+}
+
 // CheckUserExist mocks base method.
-func (m *MockFriendListService) CheckUserExist(userId int) (bool, error) {
+func (m *MockFriendListService) CheckUserExist(ctx context.Context, userId int) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckUserExist", userId)
+	ret := m.ctrl.Call(m, "CheckUserExist", ctx, userId)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CheckUserExist indicates an expected call of CheckUserExist.
-func (mr *MockFriendListServiceMockRecorder) CheckUserExist(userId interface{}) *gomock.Call {
+func (mr *MockFriendListServiceMockRecorder) CheckUserExist(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUserExist", reflect.TypeOf((*MockFriendListService)(nil).CheckUserExist), ctx, userId)
+}
+
+// CheckUsersExist mocks base method.
+func (m *MockFriendListService) CheckUsersExist(ctx context.Context, userIds []int) (map[int]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckUsersExist", ctx, userIds)
+	ret0, _ := ret[0].(map[int]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckUsersExist indicates an expected call of CheckUsersExist.
+func (mr *MockFriendListServiceMockRecorder) CheckUsersExist(ctx, userIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUsersExist", reflect.TypeOf((*MockFriendListService)(nil).CheckUsersExist), ctx, userIds)
+}
+
+// DeleteUserLink mocks base method.
+func (m *MockFriendListService) DeleteUserLink(ctx context.Context, ulfr *model.UserLinkForRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserLink", ctx, ulfr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserLink indicates an expected call of DeleteUserLink.
+func (mr *MockFriendListServiceMockRecorder) DeleteUserLink(ctx, ulfr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserLink", reflect.TypeOf((*MockFriendListService)(nil).DeleteUserLink), ctx, ulfr)
+}
+
+// GetBlockListByUserId mocks base method.
+func (m *MockFriendListService) GetBlockListByUserId(ctx context.Context, userId int) (*model.BlockList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockListByUserId", ctx, userId)
+	ret0, _ := ret[0].(*model.BlockList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockListByUserId indicates an expected call of GetBlockListByUserId.
+func (mr *MockFriendListServiceMockRecorder) GetBlockListByUserId(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockListByUserId", reflect.TypeOf((*MockFriendListService)(nil).GetBlockListByUserId), ctx, userId)
+}
+
+// GetBlockListByUserIdWithPaging mocks base method.
+func (m *MockFriendListService) GetBlockListByUserIdWithPaging(ctx context.Context, userId, limit, offset int) (*model.BlockList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockListByUserIdWithPaging", ctx, userId, limit, offset)
+	ret0, _ := ret[0].(*model.BlockList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockListByUserIdWithPaging indicates an expected call of GetBlockListByUserIdWithPaging.
+func (mr *MockFriendListServiceMockRecorder) GetBlockListByUserIdWithPaging(ctx, userId, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUserExist", reflect.TypeOf((*MockFriendListService)(nil).CheckUserExist), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockListByUserIdWithPaging", reflect.TypeOf((*MockFriendListService)(nil).GetBlockListByUserIdWithPaging), ctx, userId, limit, offset)
 }
 
 // GetFriendListByUserId mocks base method.
-func (m *MockFriendListService) GetFriendListByUserId(c echo.Context) (*model.FriendList, error) {
+func (m *MockFriendListService) GetFriendListByUserId(ctx context.Context, userId int, sort model.FriendListSort) (*model.FriendList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFriendListByUserId", c)
+	ret := m.ctrl.Call(m, "GetFriendListByUserId", ctx, userId, sort)
 	ret0, _ := ret[0].(*model.FriendList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFriendListByUserId indicates an expected call of GetFriendListByUserId.
-func (mr *MockFriendListServiceMockRecorder) GetFriendListByUserId(c interface{}) *gomock.Call {
+func (mr *MockFriendListServiceMockRecorder) GetFriendListByUserId(ctx, userId, sort interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListByUserId", reflect.TypeOf((*MockFriendListService)(nil).GetFriendListByUserId), c)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListByUserId", reflect.TypeOf((*MockFriendListService)(nil).GetFriendListByUserId), ctx, userId, sort)
+}
+
+// GetFriendListByUserIdWithPaging mocks base method.
+func (m *MockFriendListService) GetFriendListByUserIdWithPaging(ctx context.Context, userId int, sort model.FriendListSort, limit, offset int) (*model.FriendList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFriendListByUserIdWithPaging", ctx, userId, sort, limit, offset)
+	ret0, _ := ret[0].(*model.FriendList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFriendListByUserIdWithPaging indicates an expected call of GetFriendListByUserIdWithPaging.
+func (mr *MockFriendListServiceMockRecorder) GetFriendListByUserIdWithPaging(ctx, userId, sort, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListByUserIdWithPaging", reflect.TypeOf((*MockFriendListService)(nil).GetFriendListByUserIdWithPaging), ctx, userId, sort, limit, offset)
 }
 
 // GetFriendListOfFriendsByUserId mocks base method.
-func (m *MockFriendListService) GetFriendListOfFriendsByUserId(c echo.Context) (*model.FriendList, error) {
+func (m *MockFriendListService) GetFriendListOfFriendsByUserId(ctx context.Context, userId int, sort model.FriendListSort) (*model.FriendList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFriendListOfFriendsByUserId", c)
+	ret := m.ctrl.Call(m, "GetFriendListOfFriendsByUserId", ctx, userId, sort)
 	ret0, _ := ret[0].(*model.FriendList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFriendListOfFriendsByUserId indicates an expected call of GetFriendListOfFriendsByUserId.
-func (mr *MockFriendListServiceMockRecorder) GetFriendListOfFriendsByUserId(c interface{}) *gomock.Call {
+func (mr *MockFriendListServiceMockRecorder) GetFriendListOfFriendsByUserId(ctx, userId, sort interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListOfFriendsByUserId", reflect.TypeOf((*MockFriendListService)(nil).GetFriendListOfFriendsByUserId), c)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListOfFriendsByUserId", reflect.TypeOf((*MockFriendListService)(nil).GetFriendListOfFriendsByUserId), ctx, userId, sort)
+}
+
+// GetFriendListOfFriendsByUserIdWithCursor mocks base method.
+func (m *MockFriendListService) GetFriendListOfFriendsByUserIdWithCursor(ctx context.Context, userId, lastUserId, limit int) (*model.FriendList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFriendListOfFriendsByUserIdWithCursor", ctx, userId, lastUserId, limit)
+	ret0, _ := ret[0].(*model.FriendList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFriendListOfFriendsByUserIdWithCursor indicates an expected call of GetFriendListOfFriendsByUserIdWithCursor.
+func (mr *MockFriendListServiceMockRecorder) GetFriendListOfFriendsByUserIdWithCursor(ctx, userId, lastUserId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListOfFriendsByUserIdWithCursor", reflect.TypeOf((*MockFriendListService)(nil).GetFriendListOfFriendsByUserIdWithCursor), ctx, userId, lastUserId, limit)
 }
 
 // GetFriendListOfFriendsByUserIdWithPaging mocks base method.
-func (m *MockFriendListService) GetFriendListOfFriendsByUserIdWithPaging(c echo.Context) (*model.FriendList, error) {
+func (m *MockFriendListService) GetFriendListOfFriendsByUserIdWithPaging(ctx context.Context, userId int, sort model.FriendListSort, limit, offset int) (*model.FriendList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFriendListOfFriendsByUserIdWithPaging", c)
+	ret := m.ctrl.Call(m, "GetFriendListOfFriendsByUserIdWithPaging", ctx, userId, sort, limit, offset)
 	ret0, _ := ret[0].(*model.FriendList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFriendListOfFriendsByUserIdWithPaging indicates an expected call of GetFriendListOfFriendsByUserIdWithPaging.
-func (mr *MockFriendListServiceMockRecorder) GetFriendListOfFriendsByUserIdWithPaging(c interface{}) *gomock.Call {
+func (mr *MockFriendListServiceMockRecorder) GetFriendListOfFriendsByUserIdWithPaging(ctx, userId, sort, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListOfFriendsByUserIdWithPaging", reflect.TypeOf((*MockFriendListService)(nil).GetFriendListOfFriendsByUserIdWithPaging), ctx, userId, sort, limit, offset)
+}
+
+// GetNeighbourhood mocks base method.
+func (m *MockFriendListService) GetNeighbourhood(ctx context.Context, userId, minDepth, maxDepth, limit, offset int) (*model.NeighbourList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNeighbourhood", ctx, userId, minDepth, maxDepth, limit, offset)
+	ret0, _ := ret[0].(*model.NeighbourList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNeighbourhood indicates an expected call of GetNeighbourhood.
+func (mr *MockFriendListServiceMockRecorder) GetNeighbourhood(ctx, userId, minDepth, maxDepth, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListOfFriendsByUserIdWithPaging", reflect.TypeOf((*MockFriendListService)(nil).GetFriendListOfFriendsByUserIdWithPaging), c)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNeighbourhood", reflect.TypeOf((*MockFriendListService)(nil).GetNeighbourhood), ctx, userId, minDepth, maxDepth, limit, offset)
 }
 
 // InsertUserLink mocks base method.
-func (m *MockFriendListService) InsertUserLink(ulfr *model.UserLinkForRequest) error {
+func (m *MockFriendListService) InsertUserLink(ctx context.Context, ulfr *model.UserLinkForRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertUserLink", ulfr)
+	ret := m.ctrl.Call(m, "InsertUserLink", ctx, ulfr)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // InsertUserLink indicates an expected call of InsertUserLink.
-func (mr *MockFriendListServiceMockRecorder) InsertUserLink(ulfr interface{}) *gomock.Call {
+func (mr *MockFriendListServiceMockRecorder) InsertUserLink(ctx, ulfr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserLink", reflect.TypeOf((*MockFriendListService)(nil).InsertUserLink), ctx, ulfr)
+}
+
+// InsertUserLinks mocks base method.
+func (m *MockFriendListService) InsertUserLinks(ctx context.Context, ulfrs []*model.UserLinkForRequest) ([]model.UserLinkStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUserLinks", ctx, ulfrs)
+	ret0, _ := ret[0].([]model.UserLinkStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertUserLinks indicates an expected call of InsertUserLinks.
+func (mr *MockFriendListServiceMockRecorder) InsertUserLinks(ctx, ulfrs interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserLink", reflect.TypeOf((*MockFriendListService)(nil).InsertUserLink), ulfr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserLinks", reflect.TypeOf((*MockFriendListService)(nil).InsertUserLinks), ctx, ulfrs)
 }
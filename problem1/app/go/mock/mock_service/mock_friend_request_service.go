@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: friend_request_service.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	model "problem1/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockFriendRequestService is a mock of FriendRequestService interface.
+type MockFriendRequestService struct {
+	ctrl     *gomock.Controller
+	recorder *MockFriendRequestServiceMockRecorder
+}
+
+// MockFriendRequestServiceMockRecorder is the mock recorder for MockFriendRequestService.
+type MockFriendRequestServiceMockRecorder struct {
+	mock *MockFriendRequestService
+}
+
+// NewMockFriendRequestService creates a new mock instance.
+func NewMockFriendRequestService(ctrl *gomock.Controller) *MockFriendRequestService {
+	mock := &MockFriendRequestService{ctrl: ctrl}
+	mock.recorder = &MockFriendRequestServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFriendRequestService) EXPECT() *MockFriendRequestServiceMockRecorder {
+	return m.recorder
+}
+
+// AcceptFriendRequest mocks base method.
+func (m *MockFriendRequestService) AcceptFriendRequest(ctx context.Context, requestId, userId int) (*model.FriendRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptFriendRequest", ctx, requestId, userId)
+	ret0, _ := ret[0].(*model.FriendRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptFriendRequest indicates an expected call of AcceptFriendRequest.
+func (mr *MockFriendRequestServiceMockRecorder) AcceptFriendRequest(ctx, requestId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptFriendRequest", reflect.TypeOf((*MockFriendRequestService)(nil).AcceptFriendRequest), ctx, requestId, userId)
+}
+
+// CancelFriendRequest mocks base method.
+func (m *MockFriendRequestService) CancelFriendRequest(ctx context.Context, requestId, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelFriendRequest", ctx, requestId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelFriendRequest indicates an expected call of CancelFriendRequest.
+func (mr *MockFriendRequestServiceMockRecorder) CancelFriendRequest(ctx, requestId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelFriendRequest", reflect.TypeOf((*MockFriendRequestService)(nil).CancelFriendRequest), ctx, requestId, userId)
+}
+
+// GetIncomingFriendRequests mocks base method.
+func (m *MockFriendRequestService) GetIncomingFriendRequests(ctx context.Context, userId int) (*model.FriendRequestList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncomingFriendRequests", ctx, userId)
+	ret0, _ := ret[0].(*model.FriendRequestList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncomingFriendRequests indicates an expected call of GetIncomingFriendRequests.
+func (mr *MockFriendRequestServiceMockRecorder) GetIncomingFriendRequests(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncomingFriendRequests", reflect.TypeOf((*MockFriendRequestService)(nil).GetIncomingFriendRequests), ctx, userId)
+}
+
+// GetOutgoingFriendRequests mocks base method.
+func (m *MockFriendRequestService) GetOutgoingFriendRequests(ctx context.Context, userId int) (*model.FriendRequestList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutgoingFriendRequests", ctx, userId)
+	ret0, _ := ret[0].(*model.FriendRequestList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutgoingFriendRequests indicates an expected call of GetOutgoingFriendRequests.
+func (mr *MockFriendRequestServiceMockRecorder) GetOutgoingFriendRequests(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutgoingFriendRequests", reflect.TypeOf((*MockFriendRequestService)(nil).GetOutgoingFriendRequests), ctx, userId)
+}
+
+// RejectFriendRequest mocks base method.
+func (m *MockFriendRequestService) RejectFriendRequest(ctx context.Context, requestId, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectFriendRequest", ctx, requestId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectFriendRequest indicates an expected call of RejectFriendRequest.
+func (mr *MockFriendRequestServiceMockRecorder) RejectFriendRequest(ctx, requestId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectFriendRequest", reflect.TypeOf((*MockFriendRequestService)(nil).RejectFriendRequest), ctx, requestId, userId)
+}
+
+// SendFriendRequest mocks base method.
+func (m *MockFriendRequestService) SendFriendRequest(ctx context.Context, frfr *model.FriendRequestForRequest) (*model.FriendRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendFriendRequest", ctx, frfr)
+	ret0, _ := ret[0].(*model.FriendRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendFriendRequest indicates an expected call of SendFriendRequest.
+func (mr *MockFriendRequestServiceMockRecorder) SendFriendRequest(ctx, frfr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendFriendRequest", reflect.TypeOf((*MockFriendRequestService)(nil).SendFriendRequest), ctx, frfr)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: graph_export_service.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	graphexport "problem1/pkg/graphexport"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockGraphExportService is a mock of GraphExportService interface.
+type MockGraphExportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockGraphExportServiceMockRecorder
+}
+
+// MockGraphExportServiceMockRecorder is the mock recorder for MockGraphExportService.
+type MockGraphExportServiceMockRecorder struct {
+	mock *MockGraphExportService
+}
+
+// NewMockGraphExportService creates a new mock instance.
+func NewMockGraphExportService(ctrl *gomock.Controller) *MockGraphExportService {
+	mock := &MockGraphExportService{ctrl: ctrl}
+	mock.recorder = &MockGraphExportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGraphExportService) EXPECT() *MockGraphExportServiceMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockGraphExportService) Export(ctx context.Context, enc graphexport.Encoder, userId, depth int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, enc, userId, depth)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockGraphExportServiceMockRecorder) Export(ctx, enc, userId, depth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockGraphExportService)(nil).Export), ctx, enc, userId, depth)
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: path_service.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	model "problem1/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPathService is a mock of PathService interface.
+type MockPathService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPathServiceMockRecorder
+}
+
+// MockPathServiceMockRecorder is the mock recorder for MockPathService.
+type MockPathServiceMockRecorder struct {
+	mock *MockPathService
+}
+
+// NewMockPathService creates a new mock instance.
+func NewMockPathService(ctrl *gomock.Controller) *MockPathService {
+	mock := &MockPathService{ctrl: ctrl}
+	mock.recorder = &MockPathServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPathService) EXPECT() *MockPathServiceMockRecorder {
+	return m.recorder
+}
+
+// GetShortestPath mocks base method.
+func (m *MockPathService) GetShortestPath(ctx context.Context, fromUserId, toUserId, maxDepth int) (*model.FriendPath, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShortestPath", ctx, fromUserId, toUserId, maxDepth)
+	ret0, _ := ret[0].(*model.FriendPath)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShortestPath indicates an expected call of GetShortestPath.
+func (mr *MockPathServiceMockRecorder) GetShortestPath(ctx, fromUserId, toUserId, maxDepth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShortestPath", reflect.TypeOf((*MockPathService)(nil).GetShortestPath), ctx, fromUserId, toUserId, maxDepth)
+}
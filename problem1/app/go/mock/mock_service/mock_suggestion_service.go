@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: suggestion_service.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	model "problem1/model"
+	service "problem1/service"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSuggestionService is a mock of SuggestionService interface.
+type MockSuggestionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSuggestionServiceMockRecorder
+}
+
+// MockSuggestionServiceMockRecorder is the mock recorder for MockSuggestionService.
+type MockSuggestionServiceMockRecorder struct {
+	mock *MockSuggestionService
+}
+
+// NewMockSuggestionService creates a new mock instance.
+func NewMockSuggestionService(ctrl *gomock.Controller) *MockSuggestionService {
+	mock := &MockSuggestionService{ctrl: ctrl}
+	mock.recorder = &MockSuggestionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSuggestionService) EXPECT() *MockSuggestionServiceMockRecorder {
+	return m.recorder
+}
+
+// GetSuggestions mocks base method.
+func (m *MockSuggestionService) GetSuggestions(ctx context.Context, userId int, scorer service.Scorer, limit, offset int) (*model.SuggestionList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSuggestions", ctx, userId, scorer, limit, offset)
+	ret0, _ := ret[0].(*model.SuggestionList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSuggestions indicates an expected call of GetSuggestions.
+func (mr *MockSuggestionServiceMockRecorder) GetSuggestions(ctx, userId, scorer, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSuggestions", reflect.TypeOf((*MockSuggestionService)(nil).GetSuggestions), ctx, userId, scorer, limit, offset)
+}
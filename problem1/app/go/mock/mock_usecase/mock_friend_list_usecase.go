@@ -5,11 +5,11 @@
 package mock_usecase
 
 import (
+	context "context"
 	model "problem1/model"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
-	echo "github.com/labstack/echo/v4"
 )
 
 // MockFriendListUseCase is a mock of FriendListUseCase interface.
@@ -35,61 +35,135 @@ func (m *MockFriendListUseCase) EXPECT() *MockFriendListUseCaseMockRecorder {
 	return m.recorder
 }
 
+// DeleteUserLink mocks base method.
+func (m *MockFriendListUseCase) DeleteUserLink(ctx context.Context, ulfr *model.UserLinkForRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserLink", ctx, ulfr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserLink indicates an expected call of DeleteUserLink.
+func (mr *MockFriendListUseCaseMockRecorder) DeleteUserLink(ctx, ulfr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserLink", reflect.TypeOf((*MockFriendListUseCase)(nil).DeleteUserLink), ctx, ulfr)
+}
+
+// GetBlockListByUserId mocks base method.
+func (m *MockFriendListUseCase) GetBlockListByUserId(ctx context.Context, userId, limit, offset int) (*model.BlockList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockListByUserId", ctx, userId, limit, offset)
+	ret0, _ := ret[0].(*model.BlockList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockListByUserId indicates an expected call of GetBlockListByUserId.
+func (mr *MockFriendListUseCaseMockRecorder) GetBlockListByUserId(ctx, userId, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockListByUserId", reflect.TypeOf((*MockFriendListUseCase)(nil).GetBlockListByUserId), ctx, userId, limit, offset)
+}
+
 // GetFriendListByUserId mocks base method.
-func (m *MockFriendListUseCase) GetFriendListByUserId(c echo.Context) (*model.FriendList, error) {
+func (m *MockFriendListUseCase) GetFriendListByUserId(ctx context.Context, userId int, sort model.FriendListSort, limit, offset int) (*model.FriendList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFriendListByUserId", c)
+	ret := m.ctrl.Call(m, "GetFriendListByUserId", ctx, userId, sort, limit, offset)
 	ret0, _ := ret[0].(*model.FriendList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFriendListByUserId indicates an expected call of GetFriendListByUserId.
-func (mr *MockFriendListUseCaseMockRecorder) GetFriendListByUserId(c interface{}) *gomock.Call {
+func (mr *MockFriendListUseCaseMockRecorder) GetFriendListByUserId(ctx, userId, sort, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListByUserId", reflect.TypeOf((*MockFriendListUseCase)(nil).GetFriendListByUserId), c)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListByUserId", reflect.TypeOf((*MockFriendListUseCase)(nil).GetFriendListByUserId), ctx, userId, sort, limit, offset)
 }
 
 // GetFriendListOfFriendsByUserId mocks base method.
-func (m *MockFriendListUseCase) GetFriendListOfFriendsByUserId(c echo.Context) (*model.FriendList, error) {
+func (m *MockFriendListUseCase) GetFriendListOfFriendsByUserId(ctx context.Context, userId int, sort model.FriendListSort, limit, offset int) (*model.FriendList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFriendListOfFriendsByUserId", c)
+	ret := m.ctrl.Call(m, "GetFriendListOfFriendsByUserId", ctx, userId, sort, limit, offset)
 	ret0, _ := ret[0].(*model.FriendList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFriendListOfFriendsByUserId indicates an expected call of GetFriendListOfFriendsByUserId.
-func (mr *MockFriendListUseCaseMockRecorder) GetFriendListOfFriendsByUserId(c interface{}) *gomock.Call {
+func (mr *MockFriendListUseCaseMockRecorder) GetFriendListOfFriendsByUserId(ctx, userId, sort, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListOfFriendsByUserId", reflect.TypeOf((*MockFriendListUseCase)(nil).GetFriendListOfFriendsByUserId), c)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListOfFriendsByUserId", reflect.TypeOf((*MockFriendListUseCase)(nil).GetFriendListOfFriendsByUserId), ctx, userId, sort, limit, offset)
+}
+
+// GetFriendListOfFriendsByUserIdWithCursor mocks base method.
+func (m *MockFriendListUseCase) GetFriendListOfFriendsByUserIdWithCursor(ctx context.Context, userId, lastUserId, limit int) (*model.FriendList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFriendListOfFriendsByUserIdWithCursor", ctx, userId, lastUserId, limit)
+	ret0, _ := ret[0].(*model.FriendList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFriendListOfFriendsByUserIdWithCursor indicates an expected call of GetFriendListOfFriendsByUserIdWithCursor.
+func (mr *MockFriendListUseCaseMockRecorder) GetFriendListOfFriendsByUserIdWithCursor(ctx, userId, lastUserId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListOfFriendsByUserIdWithCursor", reflect.TypeOf((*MockFriendListUseCase)(nil).GetFriendListOfFriendsByUserIdWithCursor), ctx, userId, lastUserId, limit)
 }
 
 // GetFriendListOfFriendsByUserIdWithPaging mocks base method.
-func (m *MockFriendListUseCase) GetFriendListOfFriendsByUserIdWithPaging(c echo.Context) (*model.FriendList, error) {
+func (m *MockFriendListUseCase) GetFriendListOfFriendsByUserIdWithPaging(ctx context.Context, userId int, sort model.FriendListSort, limit, offset int) (*model.FriendList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFriendListOfFriendsByUserIdWithPaging", c)
+	ret := m.ctrl.Call(m, "GetFriendListOfFriendsByUserIdWithPaging", ctx, userId, sort, limit, offset)
 	ret0, _ := ret[0].(*model.FriendList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFriendListOfFriendsByUserIdWithPaging indicates an expected call of GetFriendListOfFriendsByUserIdWithPaging.
-func (mr *MockFriendListUseCaseMockRecorder) GetFriendListOfFriendsByUserIdWithPaging(c interface{}) *gomock.Call {
+func (mr *MockFriendListUseCaseMockRecorder) GetFriendListOfFriendsByUserIdWithPaging(ctx, userId, sort, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListOfFriendsByUserIdWithPaging", reflect.TypeOf((*MockFriendListUseCase)(nil).GetFriendListOfFriendsByUserIdWithPaging), ctx, userId, sort, limit, offset)
+}
+
+// GetNeighbourhood mocks base method.
+func (m *MockFriendListUseCase) GetNeighbourhood(ctx context.Context, userId, minDepth, maxDepth, limit, offset int) (*model.NeighbourList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNeighbourhood", ctx, userId, minDepth, maxDepth, limit, offset)
+	ret0, _ := ret[0].(*model.NeighbourList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNeighbourhood indicates an expected call of GetNeighbourhood.
+func (mr *MockFriendListUseCaseMockRecorder) GetNeighbourhood(ctx, userId, minDepth, maxDepth, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListOfFriendsByUserIdWithPaging", reflect.TypeOf((*MockFriendListUseCase)(nil).GetFriendListOfFriendsByUserIdWithPaging), c)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNeighbourhood", reflect.TypeOf((*MockFriendListUseCase)(nil).GetNeighbourhood), ctx, userId, minDepth, maxDepth, limit, offset)
 }
 
 // PostUserLink mocks base method.
-func (m *MockFriendListUseCase) PostUserLink(ulfr *model.UserLinkForRequest) error {
+func (m *MockFriendListUseCase) PostUserLink(ctx context.Context, ulfr *model.UserLinkForRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PostUserLink", ulfr)
+	ret := m.ctrl.Call(m, "PostUserLink", ctx, ulfr)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // PostUserLink indicates an expected call of PostUserLink.
-func (mr *MockFriendListUseCaseMockRecorder) PostUserLink(ulfr interface{}) *gomock.Call {
+func (mr *MockFriendListUseCaseMockRecorder) PostUserLink(ctx, ulfr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostUserLink", reflect.TypeOf((*MockFriendListUseCase)(nil).PostUserLink), ctx, ulfr)
+}
+
+// PostUserLinks mocks base method.
+func (m *MockFriendListUseCase) PostUserLinks(ctx context.Context, ulfrs []*model.UserLinkForRequest, mode model.UserLinksMode) ([]model.UserLinkStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostUserLinks", ctx, ulfrs, mode)
+	ret0, _ := ret[0].([]model.UserLinkStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostUserLinks indicates an expected call of PostUserLinks.
+func (mr *MockFriendListUseCaseMockRecorder) PostUserLinks(ctx, ulfrs, mode interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostUserLink", reflect.TypeOf((*MockFriendListUseCase)(nil).PostUserLink), ulfr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostUserLinks", reflect.TypeOf((*MockFriendListUseCase)(nil).PostUserLinks), ctx, ulfrs, mode)
 }
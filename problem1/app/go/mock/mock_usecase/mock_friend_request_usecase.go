@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: friend_request_usecase.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	model "problem1/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockFriendRequestUseCase is a mock of FriendRequestUseCase interface.
+type MockFriendRequestUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockFriendRequestUseCaseMockRecorder
+}
+
+// MockFriendRequestUseCaseMockRecorder is the mock recorder for MockFriendRequestUseCase.
+type MockFriendRequestUseCaseMockRecorder struct {
+	mock *MockFriendRequestUseCase
+}
+
+// NewMockFriendRequestUseCase creates a new mock instance.
+func NewMockFriendRequestUseCase(ctrl *gomock.Controller) *MockFriendRequestUseCase {
+	mock := &MockFriendRequestUseCase{ctrl: ctrl}
+	mock.recorder = &MockFriendRequestUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFriendRequestUseCase) EXPECT() *MockFriendRequestUseCaseMockRecorder {
+	return m.recorder
+}
+
+// AcceptFriendRequest mocks base method.
+func (m *MockFriendRequestUseCase) AcceptFriendRequest(ctx context.Context, frafr *model.FriendRequestActionForRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptFriendRequest", ctx, frafr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcceptFriendRequest indicates an expected call of AcceptFriendRequest.
+func (mr *MockFriendRequestUseCaseMockRecorder) AcceptFriendRequest(ctx, frafr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptFriendRequest", reflect.TypeOf((*MockFriendRequestUseCase)(nil).AcceptFriendRequest), ctx, frafr)
+}
+
+// CancelFriendRequest mocks base method.
+func (m *MockFriendRequestUseCase) CancelFriendRequest(ctx context.Context, frafr *model.FriendRequestActionForRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelFriendRequest", ctx, frafr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelFriendRequest indicates an expected call of CancelFriendRequest.
+func (mr *MockFriendRequestUseCaseMockRecorder) CancelFriendRequest(ctx, frafr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelFriendRequest", reflect.TypeOf((*MockFriendRequestUseCase)(nil).CancelFriendRequest), ctx, frafr)
+}
+
+// GetIncomingFriendRequests mocks base method.
+func (m *MockFriendRequestUseCase) GetIncomingFriendRequests(ctx context.Context, userId int) (*model.FriendRequestList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncomingFriendRequests", ctx, userId)
+	ret0, _ := ret[0].(*model.FriendRequestList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncomingFriendRequests indicates an expected call of GetIncomingFriendRequests.
+func (mr *MockFriendRequestUseCaseMockRecorder) GetIncomingFriendRequests(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncomingFriendRequests", reflect.TypeOf((*MockFriendRequestUseCase)(nil).GetIncomingFriendRequests), ctx, userId)
+}
+
+// GetOutgoingFriendRequests mocks base method.
+func (m *MockFriendRequestUseCase) GetOutgoingFriendRequests(ctx context.Context, userId int) (*model.FriendRequestList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutgoingFriendRequests", ctx, userId)
+	ret0, _ := ret[0].(*model.FriendRequestList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutgoingFriendRequests indicates an expected call of GetOutgoingFriendRequests.
+func (mr *MockFriendRequestUseCaseMockRecorder) GetOutgoingFriendRequests(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutgoingFriendRequests", reflect.TypeOf((*MockFriendRequestUseCase)(nil).GetOutgoingFriendRequests), ctx, userId)
+}
+
+// RejectFriendRequest mocks base method.
+func (m *MockFriendRequestUseCase) RejectFriendRequest(ctx context.Context, frafr *model.FriendRequestActionForRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectFriendRequest", ctx, frafr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectFriendRequest indicates an expected call of RejectFriendRequest.
+func (mr *MockFriendRequestUseCaseMockRecorder) RejectFriendRequest(ctx, frafr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectFriendRequest", reflect.TypeOf((*MockFriendRequestUseCase)(nil).RejectFriendRequest), ctx, frafr)
+}
+
+// SendFriendRequest mocks base method.
+func (m *MockFriendRequestUseCase) SendFriendRequest(ctx context.Context, frfr *model.FriendRequestForRequest) (*model.FriendRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendFriendRequest", ctx, frfr)
+	ret0, _ := ret[0].(*model.FriendRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendFriendRequest indicates an expected call of SendFriendRequest.
+func (mr *MockFriendRequestUseCaseMockRecorder) SendFriendRequest(ctx, frfr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendFriendRequest", reflect.TypeOf((*MockFriendRequestUseCase)(nil).SendFriendRequest), ctx, frfr)
+}
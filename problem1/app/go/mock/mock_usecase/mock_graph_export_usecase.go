@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: graph_export_usecase.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	graphexport "problem1/pkg/graphexport"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockGraphExportUseCase is a mock of GraphExportUseCase interface.
+type MockGraphExportUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockGraphExportUseCaseMockRecorder
+}
+
+// MockGraphExportUseCaseMockRecorder is the mock recorder for MockGraphExportUseCase.
+type MockGraphExportUseCaseMockRecorder struct {
+	mock *MockGraphExportUseCase
+}
+
+// NewMockGraphExportUseCase creates a new mock instance.
+func NewMockGraphExportUseCase(ctrl *gomock.Controller) *MockGraphExportUseCase {
+	mock := &MockGraphExportUseCase{ctrl: ctrl}
+	mock.recorder = &MockGraphExportUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGraphExportUseCase) EXPECT() *MockGraphExportUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockGraphExportUseCase) Export(ctx context.Context, enc graphexport.Encoder, userId, depth int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, enc, userId, depth)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockGraphExportUseCaseMockRecorder) Export(ctx, enc, userId, depth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockGraphExportUseCase)(nil).Export), ctx, enc, userId, depth)
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: path_usecase.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	model "problem1/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPathUseCase is a mock of PathUseCase interface.
+type MockPathUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockPathUseCaseMockRecorder
+}
+
+// MockPathUseCaseMockRecorder is the mock recorder for MockPathUseCase.
+type MockPathUseCaseMockRecorder struct {
+	mock *MockPathUseCase
+}
+
+// NewMockPathUseCase creates a new mock instance.
+func NewMockPathUseCase(ctrl *gomock.Controller) *MockPathUseCase {
+	mock := &MockPathUseCase{ctrl: ctrl}
+	mock.recorder = &MockPathUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPathUseCase) EXPECT() *MockPathUseCaseMockRecorder {
+	return m.recorder
+}
+
+// GetShortestPath mocks base method.
+func (m *MockPathUseCase) GetShortestPath(ctx context.Context, fromUserId, toUserId, maxDepth int) (*model.FriendPath, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShortestPath", ctx, fromUserId, toUserId, maxDepth)
+	ret0, _ := ret[0].(*model.FriendPath)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShortestPath indicates an expected call of GetShortestPath.
+func (mr *MockPathUseCaseMockRecorder) GetShortestPath(ctx, fromUserId, toUserId, maxDepth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShortestPath", reflect.TypeOf((*MockPathUseCase)(nil).GetShortestPath), ctx, fromUserId, toUserId, maxDepth)
+}
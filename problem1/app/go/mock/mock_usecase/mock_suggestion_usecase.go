@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: suggestion_usecase.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	model "problem1/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSuggestionUseCase is a mock of SuggestionUseCase interface.
+type MockSuggestionUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockSuggestionUseCaseMockRecorder
+}
+
+// MockSuggestionUseCaseMockRecorder is the mock recorder for MockSuggestionUseCase.
+type MockSuggestionUseCaseMockRecorder struct {
+	mock *MockSuggestionUseCase
+}
+
+// NewMockSuggestionUseCase creates a new mock instance.
+func NewMockSuggestionUseCase(ctrl *gomock.Controller) *MockSuggestionUseCase {
+	mock := &MockSuggestionUseCase{ctrl: ctrl}
+	mock.recorder = &MockSuggestionUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSuggestionUseCase) EXPECT() *MockSuggestionUseCaseMockRecorder {
+	return m.recorder
+}
+
+// GetSuggestions mocks base method.
+func (m *MockSuggestionUseCase) GetSuggestions(ctx context.Context, userId int, scorerName string, limit, offset int) (*model.SuggestionList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSuggestions", ctx, userId, scorerName, limit, offset)
+	ret0, _ := ret[0].(*model.SuggestionList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSuggestions indicates an expected call of GetSuggestions.
+func (mr *MockSuggestionUseCaseMockRecorder) GetSuggestions(ctx, userId, scorerName, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSuggestions", reflect.TypeOf((*MockSuggestionUseCase)(nil).GetSuggestions), ctx, userId, scorerName, limit, offset)
+}
@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: user_usecase.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	model "problem1/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUserUseCase is a mock of UserUseCase interface.
+type MockUserUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserUseCaseMockRecorder
+}
+
+// MockUserUseCaseMockRecorder is the mock recorder for MockUserUseCase.
+type MockUserUseCaseMockRecorder struct {
+	mock *MockUserUseCase
+}
+
+// NewMockUserUseCase creates a new mock instance.
+func NewMockUserUseCase(ctrl *gomock.Controller) *MockUserUseCase {
+	mock := &MockUserUseCase{ctrl: ctrl}
+	mock.recorder = &MockUserUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserUseCase) EXPECT() *MockUserUseCaseMockRecorder {
+	return m.recorder
+}
+
+// CreateUser mocks base method.
+func (m *MockUserUseCase) CreateUser(ctx context.Context, ufr *model.UserForRequest) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, ufr)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockUserUseCaseMockRecorder) CreateUser(ctx, ufr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserUseCase)(nil).CreateUser), ctx, ufr)
+}
+
+// DeleteUser mocks base method.
+func (m *MockUserUseCase) DeleteUser(ctx context.Context, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockUserUseCaseMockRecorder) DeleteUser(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserUseCase)(nil).DeleteUser), ctx, userId)
+}
+
+// GetUser mocks base method.
+func (m *MockUserUseCase) GetUser(ctx context.Context, userId int) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, userId)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockUserUseCaseMockRecorder) GetUser(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserUseCase)(nil).GetUser), ctx, userId)
+}
+
+// SearchUsers mocks base method.
+func (m *MockUserUseCase) SearchUsers(ctx context.Context, searcherId int, query string, limit, offset int) (*model.UserList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUsers", ctx, searcherId, query, limit, offset)
+	ret0, _ := ret[0].(*model.UserList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchUsers indicates an expected call of SearchUsers.
+func (mr *MockUserUseCaseMockRecorder) SearchUsers(ctx, searcherId, query, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUsers", reflect.TypeOf((*MockUserUseCase)(nil).SearchUsers), ctx, searcherId, query, limit, offset)
+}
+
+// UpdateUser mocks base method.
+func (m *MockUserUseCase) UpdateUser(ctx context.Context, userId int, upfr *model.UserPatchForRequest) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", ctx, userId, upfr)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockUserUseCaseMockRecorder) UpdateUser(ctx, userId, upfr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserUseCase)(nil).UpdateUser), ctx, userId, upfr)
+}
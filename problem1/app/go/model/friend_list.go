@@ -7,13 +7,74 @@ type UserLinkForRequest struct {
 	Table   string `json:"table"`
 }
 
+// UserLinksMode decides what POST /user_links applies when some of the links fail.
+type UserLinksMode string
+
+const (
+	// UserLinksModeAllOrNothing applies none of the links unless all of them can be applied.
+	UserLinksModeAllOrNothing UserLinksMode = "all-or-nothing"
+	// UserLinksModeBestEffort applies the links that can be applied.
+	UserLinksModeBestEffort UserLinksMode = "best-effort"
+)
+
+// UserLinkStatus is the result of one link given to POST /user_links.
+type UserLinkStatus string
+
+const (
+	UserLinkStatusCreated      UserLinkStatus = "created"
+	UserLinkStatusExists       UserLinkStatus = "exists"
+	UserLinkStatusInvalid      UserLinkStatus = "invalid"
+	UserLinkStatusUserNotExist UserLinkStatus = "user_not_exist"
+	// UserLinkStatusSkipped is a valid link not applied since another link failed in all-or-nothing mode.
+	UserLinkStatusSkipped UserLinkStatus = "skipped"
+	// UserLinkStatusSuperseded is a friend link not written since a block between the same users
+	// is given with it, and a block severs the friendship.
+	UserLinkStatusSuperseded UserLinkStatus = "superseded"
+)
+
+// Applied reports whether the link was written, already there or superseded by a block given with it.
+func (s UserLinkStatus) Applied() bool {
+	return s == UserLinkStatusCreated || s == UserLinkStatusExists || s == UserLinkStatusSuperseded
+}
+
+// UserLinkResult OpenAPI: UserLinkResult
+type UserLinkResult struct {
+	Index   int            `json:"index"`
+	Status  UserLinkStatus `json:"status"`
+	Message string         `json:"message,omitempty"`
+}
+
+// UserLinksReport OpenAPI: UserLinksReport
+type UserLinksReport struct {
+	Mode    UserLinksMode     `json:"mode"`
+	Results []*UserLinkResult `json:"results"`
+}
+
 // Friend OpenAPI: Friend
 type Friend struct {
 	UserId int    `json:"userId" db:"user_id"`
 	Name   string `json:"name" db:"name"`
 }
 
+// FriendListSort is the order of a friend list given by the sort query parameter.
+type FriendListSort string
+
+const (
+	FriendListSortUserId   FriendListSort = "userId"
+	FriendListSortName     FriendListSort = "name"
+	FriendListSortNameDesc FriendListSort = "-name"
+	FriendListSortAddedAt  FriendListSort = "addedAt"
+)
+
 // FriendList OpenAPI: FriendList
 type FriendList struct {
 	Friends []*Friend `json:"friends"`
+	*Paging
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// BlockList OpenAPI: BlockList
+type BlockList struct {
+	BlockUsers []*Friend `json:"blockUsers"`
+	*Paging
 }
@@ -0,0 +1,7 @@
+package model
+
+// FriendPath OpenAPI: FriendPath
+type FriendPath struct {
+	Users  []*Friend `json:"users"`
+	Degree int       `json:"degree"`
+}
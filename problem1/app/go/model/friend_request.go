@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+const (
+	FriendRequestStatusPending  = "pending"
+	FriendRequestStatusAccepted = "accepted"
+	FriendRequestStatusRejected = "rejected"
+	FriendRequestStatusCanceled = "canceled"
+)
+
+// FriendRequestForRequest OpenAPI: FriendRequestForRequest
+type FriendRequestForRequest struct {
+	FromUserId int `json:"fromUserId"`
+	ToUserId   int `json:"toUserId"`
+}
+
+// FriendRequestActionForRequest OpenAPI: FriendRequestActionForRequest
+type FriendRequestActionForRequest struct {
+	RequestId int `json:"requestId"`
+	UserId    int `json:"userId"`
+}
+
+// FriendRequest OpenAPI: FriendRequest
+type FriendRequest struct {
+	RequestId  int       `json:"requestId" db:"id"`
+	FromUserId int       `json:"fromUserId" db:"from_user_id"`
+	ToUserId   int       `json:"toUserId" db:"to_user_id"`
+	Status     string    `json:"status" db:"status"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+}
+
+// FriendRequestList OpenAPI: FriendRequestList
+type FriendRequestList struct {
+	FriendRequests []*FriendRequest `json:"friendRequests"`
+}
@@ -0,0 +1,14 @@
+package model
+
+// Neighbour OpenAPI: Neighbour
+type Neighbour struct {
+	UserId   int    `json:"userId"`
+	Name     string `json:"name"`
+	Distance int    `json:"distance"`
+}
+
+// NeighbourList OpenAPI: NeighbourList
+type NeighbourList struct {
+	Neighbours []*Neighbour `json:"neighbours"`
+	*Paging
+}
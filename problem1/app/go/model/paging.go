@@ -0,0 +1,28 @@
+package model
+
+// Paging OpenAPI: Paging
+type Paging struct {
+	Total   int  `json:"total"`
+	Page    int  `json:"page,omitempty"`
+	Limit   int  `json:"limit"`
+	HasNext bool `json:"hasNext"`
+}
+
+// NewPaging returns the paging of the page starting at offset.
+func NewPaging(total, limit, offset int) *Paging {
+	return &Paging{
+		Total:   total,
+		Page:    offset/limit + 1,
+		Limit:   limit,
+		HasNext: offset+limit < total,
+	}
+}
+
+// LastPage returns the number of the last page, at least 1.
+func (p *Paging) LastPage() int {
+	if p.Total == 0 {
+		return 1
+	}
+
+	return (p.Total + p.Limit - 1) / p.Limit
+}
@@ -0,0 +1,9 @@
+package model
+
+// RouteUsage OpenAPI: RouteUsage
+type RouteUsage struct {
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Successor string `json:"successor"`
+	Count     uint64 `json:"count"`
+}
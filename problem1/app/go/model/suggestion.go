@@ -0,0 +1,22 @@
+package model
+
+// MutualFriend is a friend of a user who is also a friend of Candidate.
+type MutualFriend struct {
+	Candidate *Friend
+	Friend    *Friend
+}
+
+// Suggestion OpenAPI: Suggestion
+type Suggestion struct {
+	UserId            int       `json:"userId"`
+	Name              string    `json:"name"`
+	Score             float64   `json:"score"`
+	MutualFriendCount int       `json:"mutualFriendCount"`
+	MutualFriends     []*Friend `json:"mutualFriends"`
+}
+
+// SuggestionList OpenAPI: SuggestionList
+type SuggestionList struct {
+	Suggestions []*Suggestion `json:"suggestions"`
+	*Paging
+}
@@ -0,0 +1,24 @@
+package model
+
+// User OpenAPI: User
+type User struct {
+	UserId int    `json:"userId" db:"user_id"`
+	Name   string `json:"name" db:"name"`
+}
+
+// UserForRequest OpenAPI: UserForRequest
+type UserForRequest struct {
+	UserId int    `json:"userId"`
+	Name   string `json:"name"`
+}
+
+// UserPatchForRequest OpenAPI: UserPatchForRequest
+type UserPatchForRequest struct {
+	Name *string `json:"name"`
+}
+
+// UserList OpenAPI: UserList
+type UserList struct {
+	Users []*User `json:"users"`
+	*Paging
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLRU(size int, ttl time.Duration) (*LRU[string, int], *time.Time) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewLRU[string, int](size, ttl)
+	c.now = func() time.Time { return now }
+
+	return c, &now
+}
+
+func Test_LRU_GetAdd(t *testing.T) {
+	c, _ := newTestLRU(2, time.Minute)
+
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	c.Add("a", 1)
+	c.Add("a", 2)
+	got, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, got)
+	assert.Equal(t, 1, c.Len())
+}
+
+func Test_LRU_evictsLeastRecentlyUsed(t *testing.T) {
+	c, _ := newTestLRU(2, time.Minute)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Get("a")
+	c.Add("c", 3)
+
+	_, ok := c.Get("b")
+	assert.False(t, ok, "b was used least recently")
+	_, ok = c.Get("a")
+	assert.True(t, ok)
+	_, ok = c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 2, c.Len())
+}
+
+func Test_LRU_expires(t *testing.T) {
+	c, now := newTestLRU(2, time.Minute)
+	c.Add("a", 1)
+
+	*now = now.Add(time.Minute - time.Nanosecond)
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+
+	*now = now.Add(time.Nanosecond)
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func Test_LRU_Remove(t *testing.T) {
+	c, _ := newTestLRU(2, time.Minute)
+	c.Add("a", 1)
+	c.Remove("a")
+	c.Remove("b")
+
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func Test_LRU_Purge(t *testing.T) {
+	c, _ := newTestLRU(2, time.Minute)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Purge()
+
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+
+	c.Add("a", 1)
+	assert.Equal(t, 1, c.Len())
+}
+
+func Test_LRU_zeroSize(t *testing.T) {
+	c, _ := newTestLRU(0, time.Minute)
+	c.Add("a", 1)
+
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+}
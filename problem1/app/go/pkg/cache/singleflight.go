@@ -0,0 +1,47 @@
+package cache
+
+import "sync"
+
+type call[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+	// joined counts the callers waiting for the result of another.
+	joined int
+}
+
+// Group collapses the concurrent calls for the same key into one.
+type Group[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*call[V]
+}
+
+// Do calls fn once for the callers passing key while it runs, and returns its result to all of them.
+func (g *Group[K, V]) Do(key K, fn func() (V, error)) (V, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		c.joined++
+		g.mu.Unlock()
+		c.wg.Wait()
+
+		return c.value, c.err
+	}
+
+	c := &call[V]{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		c.wg.Done()
+	}()
+	c.value, c.err = fn()
+
+	return c.value, c.err
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"problem1/pkg/testutil"
+)
+
+func Test_Group_Do(t *testing.T) {
+	var g Group[string, int]
+
+	got, err := g.Do("a", func() (int, error) { return 1, nil })
+	assert.NoError(t, err)
+	assert.Equal(t, 1, got)
+
+	_, err = g.Do("a", func() (int, error) { return 0, testutil.ErrTest })
+	assert.ErrorIs(t, err, testutil.ErrTest, "a finished call is not shared")
+}
+
+func Test_Group_Do_collapsesConcurrentCalls(t *testing.T) {
+	const callers = 10
+
+	var (
+		g     Group[string, int]
+		calls int32
+		wg    sync.WaitGroup
+	)
+	release := make(chan struct{})
+	fn := func() (int, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return 42, nil
+	}
+
+	results := make([]int, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = g.Do("a", fn)
+		}(i)
+	}
+
+	// hold the first call until every other caller joined it
+	for joined := 0; joined < callers-1; {
+		runtime.Gosched()
+		g.mu.Lock()
+		if c, ok := g.calls["a"]; ok {
+			joined = c.joined
+		}
+		g.mu.Unlock()
+	}
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for _, r := range results {
+		assert.Equal(t, 42, r)
+	}
+}
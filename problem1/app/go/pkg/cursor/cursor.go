@@ -0,0 +1,73 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidCursor is returned when a token is malformed or its signature does not match.
+var ErrInvalidCursor = errors.New("cursor is invalid")
+
+// Cursor is the position a page ended at: the last user_id of the list of UserId.
+type Cursor struct {
+	UserId     int
+	LastUserId int
+}
+
+// Codec encodes a Cursor into an opaque token signed with HMAC-SHA256, so clients can not
+// forge a position they have not been given nor replay one given for another user's list.
+type Codec struct {
+	secret []byte
+}
+
+func NewCodec(secret []byte) *Codec {
+	return &Codec{
+		secret: secret,
+	}
+}
+
+func (c *Codec) sign(payload string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (c *Codec) Encode(cur Cursor) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(cur.UserId) + ":" + strconv.Itoa(cur.LastUserId)))
+
+	return payload + "." + c.sign(payload)
+}
+
+func (c *Codec) Decode(token string) (Cursor, error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if !hmac.Equal([]byte(signature), []byte(c.sign(payload))) {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	userId, lastUserId, ok := strings.Cut(string(b), ":")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var cur Cursor
+	if cur.UserId, err = strconv.Atoi(userId); err != nil || cur.UserId < 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if cur.LastUserId, err = strconv.Atoi(lastUserId); err != nil || cur.LastUserId < 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return cur, nil
+}
@@ -0,0 +1,78 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Codec_EncodeDecode(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		want    Cursor
+		wantErr bool
+	}{
+		{
+			name:    "ok",
+			token:   codec.Encode(Cursor{UserId: 1, LastUserId: 123456789}),
+			want:    Cursor{UserId: 1, LastUserId: 123456789},
+			wantErr: false,
+		},
+		{
+			name:    "ok: zero",
+			token:   codec.Encode(Cursor{}),
+			want:    Cursor{},
+			wantErr: false,
+		},
+		{
+			name:    "ng: signed with another secret",
+			token:   NewCodec([]byte("another")).Encode(Cursor{UserId: 1, LastUserId: 123456789}),
+			want:    Cursor{},
+			wantErr: true,
+		},
+		{
+			name:    "ng: payload forged",
+			token:   encode("1:100") + "." + codec.sign(encode("1:999")),
+			want:    Cursor{},
+			wantErr: true,
+		},
+		{
+			name:    "ng: no signature",
+			token:   encode("1:100"),
+			want:    Cursor{},
+			wantErr: true,
+		},
+		{
+			name:    "ng: payload without user",
+			token:   encode("100") + "." + codec.sign(encode("100")),
+			want:    Cursor{},
+			wantErr: true,
+		},
+		{
+			name:    "ng: payload not integer",
+			token:   encode("1:hoge") + "." + codec.sign(encode("1:hoge")),
+			want:    Cursor{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := codec.Decode(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decode() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if err != nil {
+				assert.ErrorIs(t, err, ErrInvalidCursor)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+// Package graph holds directed user links as sorted uint32 adjacency sets.
+package graph
+
+import "sort"
+
+// Graph is a directed graph keeping both the outgoing and the incoming vertices of each vertex
+// as sorted slices. It is not safe for concurrent use; callers synchronize reads and writes.
+type Graph struct {
+	out map[uint32][]uint32
+	in  map[uint32][]uint32
+}
+
+func New() *Graph {
+	return &Graph{
+		out: make(map[uint32][]uint32),
+		in:  make(map[uint32][]uint32),
+	}
+}
+
+// Build returns the graph of edges, sorting each adjacency set once instead of inserting
+// the edges one by one. Duplicated edges are kept once.
+func Build(edges [][2]uint32) *Graph {
+	g := New()
+	for _, e := range edges {
+		g.out[e[0]] = append(g.out[e[0]], e[1])
+		g.in[e[1]] = append(g.in[e[1]], e[0])
+	}
+	for _, m := range []map[uint32][]uint32{g.out, g.in} {
+		for v, set := range m {
+			m[v] = sortUnique(set)
+		}
+	}
+
+	return g
+}
+
+func sortUnique(set []uint32) []uint32 {
+	sort.Slice(set, func(i, j int) bool { return set[i] < set[j] })
+
+	n := 0
+	for i, v := range set {
+		if i == 0 || v != set[n-1] {
+			set[n] = v
+			n++
+		}
+	}
+
+	return set[:n]
+}
+
+// AddEdge adds the edge from -> to and reports whether it was not there yet.
+func (g *Graph) AddEdge(from, to uint32) bool {
+	out, added := insert(g.out[from], to)
+	if !added {
+		return false
+	}
+	g.out[from] = out
+	g.in[to], _ = insert(g.in[to], from)
+
+	return true
+}
+
+// RemoveEdge removes the edge from -> to and reports whether it was there.
+func (g *Graph) RemoveEdge(from, to uint32) bool {
+	out, removed := remove(g.out[from], to)
+	if !removed {
+		return false
+	}
+	setOrDelete(g.out, from, out)
+	in, _ := remove(g.in[to], from)
+	setOrDelete(g.in, to, in)
+
+	return true
+}
+
+func setOrDelete(m map[uint32][]uint32, v uint32, set []uint32) {
+	if len(set) == 0 {
+		delete(m, v)
+		return
+	}
+	m[v] = set
+}
+
+// HasEdge reports whether the edge from -> to exists.
+func (g *Graph) HasEdge(from, to uint32) bool {
+	return Contains(g.out[from], to)
+}
+
+// Out returns the sorted vertices v having the edge from -> v. The slice is shared with
+// the graph and must not be modified or read after the next write.
+func (g *Graph) Out(from uint32) []uint32 {
+	return g.out[from]
+}
+
+// In returns the sorted vertices v having the edge v -> to, shared like Out.
+func (g *Graph) In(to uint32) []uint32 {
+	return g.in[to]
+}
+
+// TwoHop returns the sorted distinct vertices w having the edges from -> v -> w
+// for which keep(w) is true.
+func (g *Graph) TwoHop(from uint32, keep func(w uint32) bool) []uint32 {
+	seen := make(map[uint32]struct{})
+	var reached []uint32
+	for _, v := range g.out[from] {
+		for _, w := range g.out[v] {
+			if _, ok := seen[w]; ok {
+				continue
+			}
+			seen[w] = struct{}{}
+
+			if keep(w) {
+				reached = append(reached, w)
+			}
+		}
+	}
+	sort.Slice(reached, func(i, j int) bool { return reached[i] < reached[j] })
+
+	return reached
+}
+
+// Contains reports whether the sorted set holds v.
+func Contains(set []uint32, v uint32) bool {
+	i := sort.Search(len(set), func(i int) bool { return set[i] >= v })
+
+	return i < len(set) && set[i] == v
+}
+
+// insert returns a new sorted set holding v, so that slices already handed out by Out and In
+// keep their contents.
+func insert(set []uint32, v uint32) ([]uint32, bool) {
+	i := sort.Search(len(set), func(i int) bool { return set[i] >= v })
+	if i < len(set) && set[i] == v {
+		return set, false
+	}
+
+	inserted := make([]uint32, 0, len(set)+1)
+	inserted = append(inserted, set[:i]...)
+	inserted = append(inserted, v)
+
+	return append(inserted, set[i:]...), true
+}
+
+// remove returns a new sorted set without v, for the same reason as insert.
+func remove(set []uint32, v uint32) ([]uint32, bool) {
+	i := sort.Search(len(set), func(i int) bool { return set[i] >= v })
+	if i == len(set) || set[i] != v {
+		return set, false
+	}
+
+	removed := make([]uint32, 0, len(set)-1)
+	removed = append(removed, set[:i]...)
+
+	return append(removed, set[i+1:]...), true
+}
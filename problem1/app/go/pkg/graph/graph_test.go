@@ -0,0 +1,176 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Build(t *testing.T) {
+	g := Build([][2]uint32{{1, 3}, {1, 2}, {2, 1}, {1, 3}, {3, 1}})
+
+	assert.Equal(t, []uint32{2, 3}, g.Out(1))
+	assert.Equal(t, []uint32{1}, g.Out(2))
+	assert.Equal(t, []uint32{2, 3}, g.In(1))
+	assert.Equal(t, []uint32{1}, g.In(3))
+	assert.Nil(t, g.Out(4))
+}
+
+func Test_Graph_AddEdge(t *testing.T) {
+	tests := []struct {
+		name    string
+		edges   [][2]uint32
+		from    uint32
+		to      uint32
+		want    bool
+		wantOut []uint32
+		wantIn  []uint32
+	}{
+		{
+			name:    "ok: first edge",
+			from:    1,
+			to:      2,
+			want:    true,
+			wantOut: []uint32{2},
+			wantIn:  []uint32{1},
+		},
+		{
+			name:    "ok: kept sorted",
+			edges:   [][2]uint32{{1, 2}, {1, 4}, {3, 4}},
+			from:    1,
+			to:      3,
+			want:    true,
+			wantOut: []uint32{2, 3, 4},
+			wantIn:  []uint32{1},
+		},
+		{
+			name:    "ok: already there",
+			edges:   [][2]uint32{{1, 2}},
+			from:    1,
+			to:      2,
+			want:    false,
+			wantOut: []uint32{2},
+			wantIn:  []uint32{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := Build(tt.edges)
+
+			assert.Equal(t, tt.want, g.AddEdge(tt.from, tt.to))
+			assert.Equal(t, tt.wantOut, g.Out(tt.from))
+			assert.Equal(t, tt.wantIn, g.In(tt.to))
+			assert.True(t, g.HasEdge(tt.from, tt.to))
+		})
+	}
+}
+
+func Test_Graph_RemoveEdge(t *testing.T) {
+	tests := []struct {
+		name    string
+		edges   [][2]uint32
+		from    uint32
+		to      uint32
+		want    bool
+		wantOut []uint32
+		wantIn  []uint32
+	}{
+		{
+			name:    "ok",
+			edges:   [][2]uint32{{1, 2}, {1, 3}, {4, 3}},
+			from:    1,
+			to:      3,
+			want:    true,
+			wantOut: []uint32{2},
+			wantIn:  []uint32{4},
+		},
+		{
+			name:    "ok: last edge",
+			edges:   [][2]uint32{{1, 2}},
+			from:    1,
+			to:      2,
+			want:    true,
+			wantOut: nil,
+			wantIn:  nil,
+		},
+		{
+			name:    "ok: not there",
+			edges:   [][2]uint32{{1, 2}, {2, 3}},
+			from:    2,
+			to:      1,
+			want:    false,
+			wantOut: []uint32{3},
+			wantIn:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := Build(tt.edges)
+
+			assert.Equal(t, tt.want, g.RemoveEdge(tt.from, tt.to))
+			assert.Equal(t, tt.wantOut, g.Out(tt.from))
+			assert.Equal(t, tt.wantIn, g.In(tt.to))
+			assert.False(t, g.HasEdge(tt.from, tt.to))
+		})
+	}
+}
+
+func Test_Graph_writesKeepHandedOutSlices(t *testing.T) {
+	g := Build([][2]uint32{{1, 2}, {1, 4}})
+	out := g.Out(1)
+
+	g.AddEdge(1, 3)
+	g.RemoveEdge(1, 2)
+
+	assert.Equal(t, []uint32{2, 4}, out)
+	assert.Equal(t, []uint32{3, 4}, g.Out(1))
+}
+
+func Test_Graph_TwoHop(t *testing.T) {
+	g := Build([][2]uint32{
+		{1, 2}, {2, 1},
+		{1, 3}, {3, 1},
+		{2, 5}, {3, 5},
+		{3, 4},
+		{4, 6},
+	})
+
+	tests := []struct {
+		name string
+		keep func(w uint32) bool
+		want []uint32
+	}{
+		{
+			name: "ok: distinct and sorted",
+			keep: func(uint32) bool { return true },
+			want: []uint32{1, 4, 5},
+		},
+		{
+			name: "ok: filtered",
+			keep: func(w uint32) bool { return w != 1 && w != 5 },
+			want: []uint32{4},
+		},
+		{
+			name: "ok: nothing kept",
+			keep: func(uint32) bool { return false },
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, g.TwoHop(1, tt.keep))
+		})
+	}
+}
+
+func Test_Contains(t *testing.T) {
+	set := []uint32{2, 4, 8}
+
+	assert.True(t, Contains(set, 4))
+	assert.False(t, Contains(set, 3))
+	assert.False(t, Contains(set, 9))
+	assert.False(t, Contains(nil, 1))
+}
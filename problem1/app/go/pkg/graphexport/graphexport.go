@@ -0,0 +1,214 @@
+// Package graphexport writes the social graph as DOT, GraphML or JSON as it is read,
+// so that a graph larger than memory can be exported.
+package graphexport
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"problem1/model"
+)
+
+type Format string
+
+const (
+	FormatDOT     Format = "dot"
+	FormatGraphML Format = "graphml"
+	FormatJSON    Format = "json"
+)
+
+var ErrFormatNotSupported = errors.New("format not supported")
+
+// ParseFormat returns the format named s, or json when s is empty.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case "":
+		return FormatJSON, nil
+	case FormatDOT, FormatGraphML, FormatJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrFormatNotSupported, strconv.Quote(s))
+	}
+}
+
+// ContentType returns the media type of the documents of f.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatDOT:
+		return "text/vnd.graphviz; charset=utf-8"
+	case FormatGraphML:
+		return "application/graphml+xml; charset=utf-8"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// EdgeType tells the table an edge comes from.
+type EdgeType string
+
+const (
+	EdgeTypeFriend EdgeType = "friend"
+	EdgeTypeBlock  EdgeType = "block"
+)
+
+// Edge is a row of friend_link or block_list, from user1_id to user2_id.
+type Edge struct {
+	Type EdgeType `json:"type"`
+	From int      `json:"from"`
+	To   int      `json:"to"`
+}
+
+// Encoder writes one graph. All the users are written before the edges, between Begin and End.
+type Encoder interface {
+	Begin() error
+	User(u *model.User) error
+	Edge(e Edge) error
+	End() error
+}
+
+func NewEncoder(w io.Writer, f Format) (Encoder, error) {
+	switch f {
+	case FormatDOT:
+		return &dotEncoder{w: w}, nil
+	case FormatGraphML:
+		return &graphMLEncoder{w: w}, nil
+	case FormatJSON:
+		return &jsonEncoder{w: w}, nil
+	default:
+		return nil, ErrFormatNotSupported
+	}
+}
+
+// dotEncoder writes a digraph whose nodes are the user ids labelled with the names.
+// The blocks are drawn as red dashed edges.
+type dotEncoder struct {
+	w io.Writer
+}
+
+func (e *dotEncoder) Begin() error {
+	_, err := io.WriteString(e.w, "digraph social {\n")
+	return err
+}
+
+func (e *dotEncoder) User(u *model.User) error {
+	_, err := fmt.Fprintf(e.w, "  %d [label=%s];\n", u.UserId, dotQuote(u.Name))
+	return err
+}
+
+func (e *dotEncoder) Edge(edge Edge) error {
+	attrs := "type=friend"
+	if edge.Type == EdgeTypeBlock {
+		attrs = "type=block, color=red, style=dashed"
+	}
+	_, err := fmt.Fprintf(e.w, "  %d -> %d [%s];\n", edge.From, edge.To, attrs)
+	return err
+}
+
+func (e *dotEncoder) End() error {
+	_, err := io.WriteString(e.w, "}\n")
+	return err
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+// graphMLEncoder writes a directed graph whose nodes are "u" and the user id,
+// with the name of the users and the type of the edges as data.
+type graphMLEncoder struct {
+	w io.Writer
+}
+
+const graphMLHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="name" for="node" attr.name="name" attr.type="string"/>
+  <key id="type" for="edge" attr.name="type" attr.type="string"/>
+  <graph id="social" edgedefault="directed">
+`
+
+func (e *graphMLEncoder) Begin() error {
+	_, err := io.WriteString(e.w, graphMLHeader)
+	return err
+}
+
+func (e *graphMLEncoder) User(u *model.User) error {
+	var name strings.Builder
+	if err := xml.EscapeText(&name, []byte(u.Name)); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(e.w, "    <node id=\"u%d\"><data key=\"name\">%s</data></node>\n", u.UserId, name.String())
+	return err
+}
+
+func (e *graphMLEncoder) Edge(edge Edge) error {
+	_, err := fmt.Fprintf(e.w, "    <edge source=\"u%d\" target=\"u%d\"><data key=\"type\">%s</data></edge>\n", edge.From, edge.To, edge.Type)
+	return err
+}
+
+func (e *graphMLEncoder) End() error {
+	_, err := io.WriteString(e.w, "  </graph>\n</graphml>\n")
+	return err
+}
+
+// jsonEncoder writes {"users": [...], "edges": [...]}, with the users shaped as model.User.
+type jsonEncoder struct {
+	w io.Writer
+	// edges is set once the users array is closed
+	edges bool
+	count int
+}
+
+func (e *jsonEncoder) Begin() error {
+	_, err := io.WriteString(e.w, `{"users":[`)
+	return err
+}
+
+func (e *jsonEncoder) User(u *model.User) error {
+	return e.item(u)
+}
+
+func (e *jsonEncoder) Edge(edge Edge) error {
+	if err := e.closeUsers(); err != nil {
+		return err
+	}
+
+	return e.item(edge)
+}
+
+func (e *jsonEncoder) End() error {
+	if err := e.closeUsers(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.w, "]}\n")
+	return err
+}
+
+func (e *jsonEncoder) closeUsers() error {
+	if e.edges {
+		return nil
+	}
+	e.edges = true
+	e.count = 0
+	_, err := io.WriteString(e.w, `],"edges":[`)
+	return err
+}
+
+func (e *jsonEncoder) item(v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if e.count > 0 {
+		b = append([]byte{','}, b...)
+	}
+	e.count++
+	_, err = e.w.Write(b)
+	return err
+}
@@ -0,0 +1,150 @@
+package graphexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"problem1/model"
+)
+
+func encode(t *testing.T, f Format, users []*model.User, edges []Edge) string {
+	t.Helper()
+
+	var b bytes.Buffer
+	enc, err := NewEncoder(&b, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range users {
+		if err := enc.User(u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, e := range edges {
+		if err := enc.Edge(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.End(); err != nil {
+		t.Fatal(err)
+	}
+
+	return b.String()
+}
+
+var (
+	testUsers = []*model.User{{UserId: 1, Name: `藤井 "太郎"`}, {UserId: 2, Name: "<hoge> & fuga"}}
+	testEdges = []Edge{{Type: EdgeTypeFriend, From: 1, To: 2}, {Type: EdgeTypeBlock, From: 2, To: 1}}
+)
+
+func Test_dotEncoder(t *testing.T) {
+	want := `digraph social {
+  1 [label="藤井 \"太郎\""];
+  2 [label="<hoge> & fuga"];
+  1 -> 2 [type=friend];
+  2 -> 1 [type=block, color=red, style=dashed];
+}
+`
+	assert.Equal(t, want, encode(t, FormatDOT, testUsers, testEdges))
+	assert.Equal(t, "digraph social {\n}\n", encode(t, FormatDOT, nil, nil))
+}
+
+func Test_graphMLEncoder(t *testing.T) {
+	got := encode(t, FormatGraphML, testUsers, testEdges)
+
+	var doc struct {
+		Graph struct {
+			Nodes []struct {
+				Id   string `xml:"id,attr"`
+				Data string `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+				Data   string `xml:"data"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal([]byte(got), &doc); err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, doc.Graph.Nodes, 2)
+	assert.Equal(t, "u1", doc.Graph.Nodes[0].Id)
+	assert.Equal(t, `藤井 "太郎"`, doc.Graph.Nodes[0].Data)
+	assert.Equal(t, "<hoge> & fuga", doc.Graph.Nodes[1].Data)
+	assert.Len(t, doc.Graph.Edges, 2)
+	assert.Equal(t, "u2", doc.Graph.Edges[1].Source)
+	assert.Equal(t, "u1", doc.Graph.Edges[1].Target)
+	assert.Equal(t, "block", doc.Graph.Edges[1].Data)
+}
+
+func Test_jsonEncoder(t *testing.T) {
+	tests := []struct {
+		name  string
+		users []*model.User
+		edges []Edge
+		want  string
+	}{
+		{
+			name:  "ok",
+			users: testUsers,
+			edges: testEdges,
+			want: `{"users":[{"userId":1,"name":"藤井 \"太郎\""},{"userId":2,"name":"<hoge> & fuga"}],
+				"edges":[{"type":"friend","from":1,"to":2},{"type":"block","from":2,"to":1}]}`,
+		},
+		{
+			name:  "ok: no edge",
+			users: testUsers[:1],
+			edges: nil,
+			want:  `{"users":[{"userId":1,"name":"藤井 \"太郎\""}],"edges":[]}`,
+		},
+		{
+			name:  "ok: empty",
+			users: nil,
+			edges: nil,
+			want:  `{"users":[],"edges":[]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := encode(t, FormatJSON, tt.users, tt.edges)
+			assert.True(t, json.Valid([]byte(got)))
+			assert.JSONEq(t, tt.want, got)
+		})
+	}
+}
+
+func Test_ParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Format
+		wantErr bool
+	}{
+		{in: "", want: FormatJSON, wantErr: false},
+		{in: "dot", want: FormatDOT, wantErr: false},
+		{in: "GraphML", want: FormatGraphML, wantErr: false},
+		{in: "csv", want: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseFormat(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if err != nil {
+				assert.True(t, errors.Is(err, ErrFormatNotSupported))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
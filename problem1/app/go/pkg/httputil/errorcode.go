@@ -0,0 +1,29 @@
+package httputil
+
+// ErrorCode is the machine-readable code of an error response. The codes are stable for the
+// clients to branch on, whereas the messages may change.
+type ErrorCode string
+
+const (
+	ErrorCodeInternal          ErrorCode = "INTERNAL_ERROR"
+	ErrorCodeInvalidRequest    ErrorCode = "INVALID_REQUEST"
+	ErrorCodeInvalidParameter  ErrorCode = "INVALID_PARAMETER"
+	ErrorCodeInvalidTable      ErrorCode = "INVALID_TABLE"
+	ErrorCodeInvalidCursor     ErrorCode = "INVALID_CURSOR"
+	ErrorCodeNotFound          ErrorCode = "NOT_FOUND"
+	ErrorCodeMethodNotAllowed  ErrorCode = "METHOD_NOT_ALLOWED"
+	ErrorCodeAdminDisabled     ErrorCode = "ADMIN_DISABLED"
+	ErrorCodeAdminTokenInvalid ErrorCode = "ADMIN_TOKEN_INVALID"
+
+	ErrorCodeUserNotFound             ErrorCode = "USER_NOT_FOUND"
+	ErrorCodeUserDuplicated           ErrorCode = "USER_DUPLICATED"
+	ErrorCodeUserLinkNotFound         ErrorCode = "USER_LINK_NOT_FOUND"
+	ErrorCodeFriendRequestNotFound    ErrorCode = "FRIEND_REQUEST_NOT_FOUND"
+	ErrorCodeFriendRequestBlocked     ErrorCode = "FRIEND_REQUEST_BLOCKED"
+	ErrorCodeFriendRequestForbidden   ErrorCode = "FRIEND_REQUEST_FORBIDDEN"
+	ErrorCodeFriendRequestDuplicated  ErrorCode = "FRIEND_REQUEST_DUPLICATED"
+	ErrorCodeFriendRequestNotPending  ErrorCode = "FRIEND_REQUEST_NOT_PENDING"
+	ErrorCodeAlreadyFriends           ErrorCode = "ALREADY_FRIENDS"
+	ErrorCodePathNotFound             ErrorCode = "PATH_NOT_FOUND"
+	ErrorCodePathSearchBudgetExceeded ErrorCode = "PATH_SEARCH_BUDGET_EXCEEDED"
+)
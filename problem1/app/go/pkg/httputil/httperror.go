@@ -3,20 +3,25 @@ package httputil
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 )
 
 type HTTPError interface {
 	error
 	StatusCode() int
+	ErrorCode() ErrorCode
 }
 
 type httpError struct {
 	origin     error
 	statusCode int
+	code       ErrorCode
 	message    string
+	params     []*InvalidParam
 }
 
-func NewHTTPError(origin error, statusCode int, message string) error {
+func NewHTTPError(origin error, statusCode int, code ErrorCode, message string) error {
 	if message == "" {
 		message = origin.Error()
 	}
@@ -24,18 +29,42 @@ func NewHTTPError(origin error, statusCode int, message string) error {
 	return &httpError{
 		origin:     origin,
 		statusCode: statusCode,
+		code:       code,
 		message:    message,
 	}
 }
 
+// NewValidationError is the 400 error of code listing every invalid part of the request.
+func NewValidationError(code ErrorCode, message string, params []*InvalidParam) error {
+	return &httpError{
+		origin:     errors.New(message),
+		statusCode: http.StatusBadRequest,
+		code:       code,
+		message:    message,
+		params:     params,
+	}
+}
+
 func (e *httpError) Error() string {
-	return fmt.Sprintf("StatusCode = %d, msg = %s", e.statusCode, e.message)
+	if len(e.params) == 0 {
+		return fmt.Sprintf("StatusCode = %d, code = %s, msg = %s", e.statusCode, e.code, e.message)
+	}
+
+	params := make([]string, len(e.params))
+	for i, p := range e.params {
+		params[i] = p.In + " " + p.Name + ": " + p.Reason
+	}
+	return fmt.Sprintf("StatusCode = %d, code = %s, msg = %s, params = [%s]", e.statusCode, e.code, e.message, strings.Join(params, ", "))
 }
 
 func (e *httpError) StatusCode() int {
 	return e.statusCode
 }
 
+func (e *httpError) ErrorCode() ErrorCode {
+	return e.code
+}
+
 func As(err error, c int) bool {
 	var hErr HTTPError
 	if errors.As(err, &hErr) && hErr.StatusCode() == c {
@@ -44,3 +73,38 @@ func As(err error, c int) bool {
 
 	return false
 }
+
+// InvalidParam is a part of the request violating the spec. In is "query", "path",
+// "header" or "cookie" with the parameter name, or "body" with the JSON pointer.
+type InvalidParam struct {
+	In     string `json:"in"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// InvalidParams collects the invalid parts of a request, to report all of them at once
+// rather than only the first one checked.
+type InvalidParams []*InvalidParam
+
+func (p *InvalidParams) Add(in, name, reason string) {
+	*p = append(*p, &InvalidParam{In: in, Name: name, Reason: reason})
+}
+
+// Message joins the reasons.
+func (p InvalidParams) Message() string {
+	reasons := make([]string, len(p))
+	for i, param := range p {
+		reasons[i] = param.Reason
+	}
+
+	return strings.Join(reasons, ", ")
+}
+
+// Err returns the validation error of code listing p with Message, or nil when p is empty.
+func (p InvalidParams) Err(code ErrorCode) error {
+	if len(p) == 0 {
+		return nil
+	}
+
+	return NewValidationError(code, p.Message(), p)
+}
@@ -1,6 +1,7 @@
 package httputil
 
 import (
+	"errors"
 	"net/http"
 	"testing"
 
@@ -23,6 +24,7 @@ func Test_httpError_NewHTTPError(t *testing.T) {
 			want: &httpError{
 				origin:     testutil.ErrTest,
 				statusCode: statusCode,
+				code:       ErrorCodeInternal,
 				message:    testutil.ErrTest.Error(),
 			},
 		},
@@ -30,7 +32,7 @@ func Test_httpError_NewHTTPError(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := NewHTTPError(tt.origin, statusCode, "")
+			err := NewHTTPError(tt.origin, statusCode, ErrorCodeInternal, "")
 			assert.Equal(t, tt.want, err)
 		})
 	}
@@ -44,12 +46,12 @@ func Test_httpError_As(t *testing.T) {
 	}{
 		{
 			name: "ok",
-			err:  NewHTTPError(testutil.ErrTest, http.StatusInternalServerError, ""),
+			err:  NewHTTPError(testutil.ErrTest, http.StatusInternalServerError, ErrorCodeInternal, ""),
 			want: true,
 		},
 		{
 			name: "ng: status code not equal",
-			err:  NewHTTPError(testutil.ErrTest, http.StatusBadRequest, ""),
+			err:  NewHTTPError(testutil.ErrTest, http.StatusBadRequest, ErrorCodeInvalidParameter, ""),
 			want: false,
 		},
 		{
@@ -66,3 +68,41 @@ func Test_httpError_As(t *testing.T) {
 		})
 	}
 }
+
+func Test_InvalidParams_Err(t *testing.T) {
+	tests := []struct {
+		name   string
+		params InvalidParams
+		want   error
+	}{
+		{
+			name: "ok",
+			params: InvalidParams{
+				{In: "query", Name: "depth", Reason: "depth is invalid"},
+				{In: "query", Name: "exact", Reason: "exact is not boolean"},
+			},
+			want: &httpError{
+				origin:     errors.New("depth is invalid, exact is not boolean"),
+				statusCode: http.StatusBadRequest,
+				code:       ErrorCodeInvalidParameter,
+				message:    "depth is invalid, exact is not boolean",
+				params: []*InvalidParam{
+					{In: "query", Name: "depth", Reason: "depth is invalid"},
+					{In: "query", Name: "exact", Reason: "exact is not boolean"},
+				},
+			},
+		},
+		{
+			name:   "ok: none invalid",
+			params: nil,
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Err(ErrorCodeInvalidParameter)
+			assert.Equal(t, tt.want, err)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package httputil
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Link is a link-value of an RFC 8288 Link header pointing at the request URL
+// with Query overriding its parameters. An empty value removes the parameter.
+type Link struct {
+	Rel   string
+	Query map[string]string
+}
+
+// SetLinkHeader sets the Link header built from the request URL of c.
+// Nothing is set when links is empty.
+func SetLinkHeader(c echo.Context, links ...Link) {
+	if len(links) == 0 {
+		return
+	}
+
+	values := make([]string, 0, len(links))
+	for _, l := range links {
+		u := *c.Request().URL
+		q := u.Query()
+		for k, v := range l.Query {
+			if v == "" {
+				q.Del(k)
+				continue
+			}
+			q.Set(k, v)
+		}
+		u.RawQuery = q.Encode()
+
+		values = append(values, fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), l.Rel))
+	}
+
+	c.Response().Header().Set("Link", strings.Join(values, ", "))
+}
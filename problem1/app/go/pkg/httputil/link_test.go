@@ -0,0 +1,52 @@
+package httputil
+
+import (
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SetLinkHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		url   string
+		links []Link
+		want  string
+	}{
+		{
+			name: "ok: next and prev",
+			url:  "/get_friend_list?ID=1&page=2",
+			links: []Link{
+				{Rel: "prev", Query: map[string]string{"page": "1"}},
+				{Rel: "next", Query: map[string]string{"page": "3"}},
+			},
+			want: `</get_friend_list?ID=1&page=1>; rel="prev", </get_friend_list?ID=1&page=3>; rel="next"`,
+		},
+		{
+			name: "ok: parameter removed",
+			url:  "/get_friend_list?ID=1&page=2",
+			links: []Link{
+				{Rel: "next", Query: map[string]string{"page": "", "cursor": "abc"}},
+			},
+			want: `</get_friend_list?ID=1&cursor=abc>; rel="next"`,
+		},
+		{
+			name:  "ok: no links",
+			url:   "/get_friend_list?ID=1",
+			links: nil,
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, req := NewRequestAndRecorder("GET", tt.url, nil)
+			c := echo.New().NewContext(req, rec)
+
+			SetLinkHeader(c, tt.links...)
+
+			assert.Equal(t, tt.want, rec.Header().Get("Link"))
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"problem1/pkg/httputil"
+)
+
+// Admin lets through the requests with "Authorization: Bearer token". With an empty
+// token it lets through none, so that the admin routes stay closed unless configured.
+func Admin(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if token == "" {
+				return httputil.RespondError(c, httputil.NewHTTPError(errors.New("admin api disabled"), http.StatusForbidden, httputil.ErrorCodeAdminDisabled, ""))
+			}
+
+			auth := c.Request().Header.Get(echo.HeaderAuthorization)
+			got := strings.TrimPrefix(auth, "Bearer ")
+			if got == auth || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+				return httputil.RespondError(c, httputil.NewHTTPError(errors.New("admin token invalid"), http.StatusUnauthorized, httputil.ErrorCodeAdminTokenInvalid, ""))
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/pkg/httputil"
+)
+
+func Test_Admin(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		wantStatus    int
+	}{
+		{
+			name:          "ok",
+			token:         "secret",
+			authorization: "Bearer secret",
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:          "ng: token wrong",
+			token:         "secret",
+			authorization: "Bearer secreT",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "ng: not bearer",
+			token:         "secret",
+			authorization: "secret",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "ng: no authorization",
+			token:         "secret",
+			authorization: "",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "ng: disabled",
+			token:         "",
+			authorization: "Bearer ",
+			wantStatus:    http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, req := httputil.NewRequestAndRecorder("GET", "/admin/test", nil)
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			e := echo.New()
+			e.GET("/admin/test", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}, Admin(tt.token))
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
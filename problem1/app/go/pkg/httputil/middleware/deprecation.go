@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/labstack/echo/v4"
+
+	"problem1/configs"
+	"problem1/model"
+)
+
+// DeprecatedRoutes marks the legacy routes as deprecated and counts their requests, so that
+// they can be retired once no client calls them.
+type DeprecatedRoutes struct {
+	conf configs.LegacyConfig
+
+	mu     sync.Mutex
+	routes []*deprecatedRoute
+}
+
+type deprecatedRoute struct {
+	method    string
+	path      string
+	successor string
+	count     uint64
+}
+
+func NewDeprecatedRoutes(conf configs.LegacyConfig) *DeprecatedRoutes {
+	return &DeprecatedRoutes{
+		conf: conf,
+	}
+}
+
+// Route returns the middleware of the route of method and path, which successor replaces.
+// It sets the Deprecation header of RFC 9745 and the Sunset header of RFC 8594 on the
+// responses, leaving out each whose time is zero.
+func (d *DeprecatedRoutes) Route(method, path, successor string) echo.MiddlewareFunc {
+	r := &deprecatedRoute{method: method, path: path, successor: successor}
+	d.mu.Lock()
+	d.routes = append(d.routes, r)
+	d.mu.Unlock()
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			atomic.AddUint64(&r.count, 1)
+
+			h := c.Response().Header()
+			if !d.conf.DeprecatedAt.IsZero() {
+				h.Set("Deprecation", "@"+strconv.FormatInt(d.conf.DeprecatedAt.Unix(), 10))
+			}
+			if !d.conf.SunsetAt.IsZero() {
+				h.Set("Sunset", d.conf.SunsetAt.UTC().Format(http.TimeFormat))
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// Usage returns the number of requests of each route since the start, in the order the
+// routes were added.
+func (d *DeprecatedRoutes) Usage() []*model.RouteUsage {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	usage := make([]*model.RouteUsage, len(d.routes))
+	for i, r := range d.routes {
+		usage[i] = &model.RouteUsage{
+			Method:    r.method,
+			Path:      r.path,
+			Successor: r.successor,
+			Count:     atomic.LoadUint64(&r.count),
+		}
+	}
+
+	return usage
+}
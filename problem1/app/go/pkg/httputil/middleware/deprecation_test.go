@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/configs"
+	"problem1/model"
+	"problem1/pkg/httputil"
+)
+
+func Test_DeprecatedRoutes(t *testing.T) {
+	tests := []struct {
+		name            string
+		conf            configs.LegacyConfig
+		handlerStatus   int
+		wantDeprecation string
+		wantSunset      string
+	}{
+		{
+			name: "ok",
+			conf: configs.LegacyConfig{
+				DeprecatedAt: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+				SunsetAt:     time.Date(2027, 4, 18, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60)),
+			},
+			handlerStatus:   http.StatusOK,
+			wantDeprecation: "@1792281600",
+			wantSunset:      "Sun, 18 Apr 2027 00:00:00 GMT",
+		},
+		{
+			name:            "ok: on errors as well",
+			conf:            configs.LegacyConfig{DeprecatedAt: time.Unix(0, 0)},
+			handlerStatus:   http.StatusBadRequest,
+			wantDeprecation: "@0",
+			wantSunset:      "",
+		},
+		{
+			name:            "ok: times not set",
+			conf:            configs.LegacyConfig{},
+			handlerStatus:   http.StatusOK,
+			wantDeprecation: "",
+			wantSunset:      "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDeprecatedRoutes(tt.conf)
+			e := echo.New()
+			e.GET("/old", func(c echo.Context) error {
+				return c.NoContent(tt.handlerStatus)
+			}, d.Route(http.MethodGet, "/old", "/v1/new"))
+			e.GET("/unused", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}, d.Route(http.MethodGet, "/unused", "/v1/unused"))
+
+			for i := 0; i < 2; i++ {
+				rec, req := httputil.NewRequestAndRecorder("GET", "/old", nil)
+				e.ServeHTTP(rec, req)
+
+				assert.Equal(t, tt.handlerStatus, rec.Code)
+				assert.Equal(t, tt.wantDeprecation, rec.Header().Get("Deprecation"))
+				assert.Equal(t, tt.wantSunset, rec.Header().Get("Sunset"))
+			}
+
+			assert.Equal(t, []*model.RouteUsage{
+				{Method: http.MethodGet, Path: "/old", Successor: "/v1/new", Count: 2},
+				{Method: http.MethodGet, Path: "/unused", Successor: "/v1/unused", Count: 0},
+			}, d.Usage())
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/labstack/echo/v4"
+
+	"problem1/configs"
+	"problem1/pkg/httputil"
+)
+
+func init() {
+	// the graph export serves these besides JSON
+	openapi3filter.RegisterBodyDecoder("text/vnd.graphviz", openapi3filter.FileBodyDecoder)
+	openapi3filter.RegisterBodyDecoder("application/graphml+xml", openapi3filter.FileBodyDecoder)
+}
+
+var specPathParam = regexp.MustCompile(`{([^}/]+)}`)
+
+// OpenAPI validates the requests against the OpenAPI document, and the responses too if
+// configured, by looking up the operation of the route Echo matched.
+type OpenAPI struct {
+	validateResponses bool
+	// routes are the operations keyed by the method and the Echo route path, e.g. "GET /users/:id"
+	routes map[string]*routers.Route
+	// streamed are the keys of routes declaring responses other than JSON, such as the graph
+	// export, which are streamed and so never buffered to validate
+	streamed map[string]bool
+}
+
+// NewOpenAPI loads the OpenAPI document of conf.
+func NewOpenAPI(conf configs.OpenAPIConfig) (*OpenAPI, error) {
+	doc, err := openapi3.NewLoader().LoadFromFile(conf.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+
+	routes := make(map[string]*routers.Route)
+	streamed := make(map[string]bool)
+	for path, pathItem := range doc.Paths {
+		echoPath := specPathParam.ReplaceAllString(path, ":$1")
+		for method, operation := range pathItem.Operations() {
+			if !respondsOnlyJSON(operation) {
+				streamed[method+" "+echoPath] = true
+			}
+			routes[method+" "+echoPath] = &routers.Route{
+				Spec:      doc,
+				Path:      path,
+				PathItem:  pathItem,
+				Method:    method,
+				Operation: operation,
+			}
+		}
+	}
+
+	return &OpenAPI{
+		validateResponses: conf.ValidateResponses,
+		routes:            routes,
+		streamed:          streamed,
+	}, nil
+}
+
+// respondsOnlyJSON reports whether every response of operation is JSON or has no content.
+func respondsOnlyJSON(operation *openapi3.Operation) bool {
+	for _, res := range operation.Responses {
+		if res.Value == nil {
+			continue
+		}
+		for contentType := range res.Value.Content {
+			if !isJSON(contentType) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == echo.MIMEApplicationJSON || strings.HasSuffix(mediaType, "+json")
+}
+
+// Validate responds 400 listing every invalid part of the request violating the spec.
+// The routes missing from the spec are let through. Authentication is left to Admin.
+//
+// With ValidateResponses it buffers the JSON responses and logs those drifting from the spec,
+// including the status codes it does not declare. The routes responding other content, and
+// the responses that are not JSON or are flushed, are streamed without being validated.
+func (o *OpenAPI) Validate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		route, ok := o.routes[req.Method+" "+c.Path()]
+		if !ok {
+			return next(c)
+		}
+
+		pathParams := make(map[string]string, len(c.ParamNames()))
+		for i, name := range c.ParamNames() {
+			pathParams[name] = c.ParamValues()[i]
+		}
+		input := &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				MultiError:            true,
+				IncludeResponseStatus: true,
+				AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+		if err := openapi3filter.ValidateRequest(req.Context(), input); err != nil {
+			return httputil.RespondError(c, httputil.NewValidationError(httputil.ErrorCodeInvalidRequest, "request invalid", invalidParams(err)))
+		}
+
+		if !o.validateResponses || o.streamed[req.Method+" "+c.Path()] {
+			return next(c)
+		}
+
+		res := c.Response()
+		w := &bufferedWriter{ResponseWriter: res.Writer}
+		res.Writer = w
+		defer func() { res.Writer = w.ResponseWriter }()
+
+		if err := next(c); err != nil {
+			return err
+		}
+		if w.streamed {
+			return nil
+		}
+
+		resInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 res.Status,
+			Header:                 res.Header(),
+			Options:                input.Options,
+		}
+		resInput.SetBodyBytes(w.body.Bytes())
+		if err := openapi3filter.ValidateResponse(req.Context(), resInput); err != nil {
+			log.Printf("response drifts from the spec: %s %s %d: %s", route.Method, route.Path, res.Status, err.Error())
+		}
+
+		return nil
+	}
+}
+
+// bufferedWriter keeps a copy of the JSON response body written through it. It drops the
+// copy once the body turns out to be streamed, by another content type or by a flush.
+type bufferedWriter struct {
+	http.ResponseWriter
+	body     bytes.Buffer
+	streamed bool
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	if !w.streamed && !isJSON(w.Header().Get(echo.HeaderContentType)) {
+		w.stream()
+	}
+	if !w.streamed {
+		w.body.Write(b)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bufferedWriter) Flush() {
+	w.stream()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *bufferedWriter) stream() {
+	w.streamed = true
+	w.body = bytes.Buffer{}
+}
+
+// invalidParams flattens the errors of ValidateRequest, which are a MultiError of
+// RequestErrors with MultiError causes of the body values.
+func invalidParams(err error) []*httputil.InvalidParam {
+	if me, ok := err.(openapi3.MultiError); ok {
+		var params []*httputil.InvalidParam
+		for _, e := range me {
+			params = append(params, invalidParams(e)...)
+		}
+		return params
+	}
+
+	reqErr, ok := err.(*openapi3filter.RequestError)
+	if !ok {
+		return []*httputil.InvalidParam{{Reason: err.Error()}}
+	}
+
+	if reqErr.Parameter != nil {
+		return []*httputil.InvalidParam{{In: reqErr.Parameter.In, Name: reqErr.Parameter.Name, Reason: reason(reqErr.Err, reqErr.Reason)}}
+	}
+
+	if me, ok := reqErr.Err.(openapi3.MultiError); ok {
+		params := make([]*httputil.InvalidParam, 0, len(me))
+		for _, e := range me {
+			params = append(params, bodyParam(e, reqErr.Reason))
+		}
+		return params
+	}
+
+	return []*httputil.InvalidParam{bodyParam(reqErr.Err, reqErr.Reason)}
+}
+
+func bodyParam(err error, fallback string) *httputil.InvalidParam {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		return &httputil.InvalidParam{In: "body", Name: "/" + strings.Join(schemaErr.JSONPointer(), "/"), Reason: schemaErr.Reason}
+	}
+
+	return &httputil.InvalidParam{In: "body", Name: "/", Reason: reason(err, fallback)}
+}
+
+func reason(err error, fallback string) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		return schemaErr.Reason
+	}
+	if err != nil {
+		return err.Error()
+	}
+
+	return fallback
+}
@@ -0,0 +1,237 @@
+package middleware
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"problem1/configs"
+	"problem1/pkg/httputil"
+)
+
+const specPath = "../../../../../spec/openapi.yaml"
+
+func Test_OpenAPI_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		url        string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "ok",
+			method:     http.MethodGet,
+			url:        "/get_friend_list?ID=1&limit=100&sort=name",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"friends":[]}`,
+		},
+		{
+			name:       "ok: route not in spec",
+			method:     http.MethodGet,
+			url:        "/",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"friends":[]}`,
+		},
+		{
+			name:       "ok: body",
+			method:     http.MethodPost,
+			url:        "/user_link",
+			body:       `{"user1Id":1,"user2Id":2,"table":"friend_link"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"friends":[]}`,
+		},
+		{
+			name:       "ng: limit over max",
+			method:     http.MethodGet,
+			url:        "/get_friend_list?ID=1&limit=1000",
+			wantStatus: http.StatusBadRequest,
+			wantBody: `{"code":400,"errorCode":"INVALID_REQUEST","message":"request invalid","errors":[
+				{"in":"query","name":"limit","reason":"number must be at most 100"}
+			]}`,
+		},
+		{
+			name:       "ng: every invalid param",
+			method:     http.MethodGet,
+			url:        "/get_friend_list?limit=a&sort=age",
+			wantStatus: http.StatusBadRequest,
+			wantBody: `{"code":400,"errorCode":"INVALID_REQUEST","message":"request invalid","errors":[
+				{"in":"query","name":"ID","reason":"value is required but missing"},
+				{"in":"query","name":"limit","reason":"value a: an invalid integer: invalid syntax"},
+				{"in":"query","name":"sort","reason":"value is not one of the allowed values"}
+			]}`,
+		},
+		{
+			name:       "ng: path param",
+			method:     http.MethodGet,
+			url:        "/v1/users/abc/friends",
+			wantStatus: http.StatusBadRequest,
+			wantBody: `{"code":400,"errorCode":"INVALID_REQUEST","message":"request invalid","errors":[
+				{"in":"path","name":"id","reason":"value abc: an invalid integer: invalid syntax"}
+			]}`,
+		},
+		{
+			name:       "ng: body values",
+			method:     http.MethodPost,
+			url:        "/user_link",
+			body:       `{"user1Id":"1","table":"friend_link"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody: `{"code":400,"errorCode":"INVALID_REQUEST","message":"request invalid","errors":[
+				{"in":"body","name":"/user1Id","reason":"Field must be set to integer or not be present"},
+				{"in":"body","name":"/user2Id","reason":"property \"user2Id\" is missing"}
+			]}`,
+		},
+		{
+			name:       "ng: body missing",
+			method:     http.MethodPost,
+			url:        "/user_link",
+			wantStatus: http.StatusBadRequest,
+			wantBody: `{"code":400,"errorCode":"INVALID_REQUEST","message":"request invalid","errors":[
+				{"in":"body","name":"/","reason":"value is required but missing"}
+			]}`,
+		},
+	}
+
+	o, err := NewOpenAPI(configs.OpenAPIConfig{Spec: specPath})
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, req := httputil.NewRequestAndRecorder(tt.method, tt.url, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			}
+
+			e := echo.New()
+			e.Use(o.Validate)
+			handler := func(c echo.Context) error {
+				return c.JSON(http.StatusOK, map[string]any{"friends": []any{}})
+			}
+			e.GET("/", handler)
+			e.GET("/get_friend_list", handler)
+			e.GET("/v1/users/:id/friends", handler)
+			e.POST("/user_link", func(c echo.Context) error {
+				// the body is left for the handler to bind
+				var v map[string]any
+				assert.NoError(t, c.Bind(&v))
+				assert.Equal(t, float64(1), v["user1Id"])
+				return handler(c)
+			})
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.JSONEq(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
+
+func Test_OpenAPI_ValidateResponses(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		handler    echo.HandlerFunc
+		status     int
+		body       any
+		wantStatus int
+		wantLog    string
+	}{
+		{
+			name:       "ok",
+			status:     http.StatusOK,
+			body:       map[string]any{"friends": []any{map[string]any{"userId": 1, "name": "a"}}},
+			wantStatus: http.StatusOK,
+			wantLog:    "",
+		},
+		{
+			name:       "ok: body drifting",
+			status:     http.StatusOK,
+			body:       map[string]any{"friends": []any{map[string]any{"userId": "1"}}},
+			wantStatus: http.StatusOK,
+			wantLog:    "response drifts from the spec: GET /get_friend_list 200",
+		},
+		{
+			name:       "ok: status not in spec",
+			status:     http.StatusNotFound,
+			body:       map[string]any{},
+			wantStatus: http.StatusNotFound,
+			wantLog:    "response drifts from the spec: GET /get_friend_list 404: status is not supported",
+		},
+		{
+			name: "ok: body not JSON is not buffered",
+			handler: func(c echo.Context) error {
+				return c.Blob(http.StatusOK, echo.MIMETextPlain, []byte("friends"))
+			},
+			wantStatus: http.StatusOK,
+			wantLog:    "",
+		},
+		{
+			name: "ok: flushed body is not buffered",
+			handler: func(c echo.Context) error {
+				c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				c.Response().WriteHeader(http.StatusOK)
+				c.Response().Flush()
+				_, err := c.Response().Write([]byte(`{"friends":[{"userId":"1"}]}`))
+				return err
+			},
+			wantStatus: http.StatusOK,
+			wantLog:    "",
+		},
+		{
+			name: "ok: route streaming other content is not buffered",
+			url:  "/admin/graph/export",
+			handler: func(c echo.Context) error {
+				return c.JSON(http.StatusOK, map[string]any{"nodes": "drifting"})
+			},
+			wantStatus: http.StatusOK,
+			wantLog:    "",
+		},
+	}
+
+	o, err := NewOpenAPI(configs.OpenAPIConfig{Spec: specPath, ValidateResponses: true})
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			log.SetOutput(&logs)
+			defer log.SetOutput(os.Stderr)
+
+			url := tt.url
+			if url == "" {
+				url = "/get_friend_list"
+			}
+			handler := tt.handler
+			if handler == nil {
+				handler = func(c echo.Context) error {
+					return c.JSON(tt.status, tt.body)
+				}
+			}
+
+			rec, req := httputil.NewRequestAndRecorder("GET", url+"?ID=1", nil)
+			e := echo.New()
+			e.Use(o.Validate)
+			e.GET(url, handler)
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantLog == "" {
+				assert.Empty(t, logs.String())
+			} else {
+				assert.Contains(t, logs.String(), tt.wantLog)
+			}
+		})
+	}
+}
+
+func Test_NewOpenAPI(t *testing.T) {
+	_, err := NewOpenAPI(configs.OpenAPIConfig{Spec: "not_found.yaml"})
+	assert.Error(t, err)
+}
@@ -1,37 +1,60 @@
 package middleware
 
 import (
+	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+
+	"problem1/configs"
+	"problem1/pkg/cursor"
+	"problem1/pkg/httputil"
 )
 
-func PagingFunc(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		limit, err := strconv.Atoi(c.QueryParam("limit"))
-		if err != nil {
-			limit = 20
-		}
-		if limit < 1 {
-			limit = 1
-		}
-		if limit > 100 {
-			limit = 100
-		}
-		c.Set("limit", limit)
+// Paging sets "limit" and either "cursor" (the cursor.Cursor the previous page ended at)
+// when the cursor query parameter is given, or "page" and "offset" otherwise.
+// The default and max limit are looked up by the route path of the request,
+// and the cursor is read with codec.
+func Paging(conf configs.PagingConfig, codec *cursor.Codec) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			defaultLimit, maxLimit := conf.Limits(c.Path())
 
-		page, err := strconv.Atoi(c.QueryParam("page"))
-		if err != nil {
-			page = 1
-		}
-		if page < 1 {
-			page = 1
-		}
-		c.Set("page", page)
+			limit, err := strconv.Atoi(c.QueryParam("limit"))
+			if err != nil {
+				limit = defaultLimit
+			}
+			if limit < 1 {
+				limit = 1
+			}
+			if limit > maxLimit {
+				limit = maxLimit
+			}
+			c.Set("limit", limit)
+
+			if token := c.QueryParam("cursor"); token != "" {
+				cur, err := codec.Decode(token)
+				if err != nil {
+					return httputil.RespondError(c, httputil.NewHTTPError(err, http.StatusBadRequest, httputil.ErrorCodeInvalidCursor, ""))
+				}
+				c.Set("cursor", cur)
+
+				return next(c)
+			}
 
-		offset := limit * (page - 1)
-		c.Set("offset", offset)
+			page, err := strconv.Atoi(c.QueryParam("page"))
+			if err != nil {
+				page = 1
+			}
+			if page < 1 {
+				page = 1
+			}
+			c.Set("page", page)
 
-		return next(c)
+			offset := limit * (page - 1)
+			c.Set("offset", offset)
+
+			return next(c)
+		}
 	}
 }
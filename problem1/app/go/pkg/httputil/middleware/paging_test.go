@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/configs"
+	"problem1/pkg/cursor"
+	"problem1/pkg/httputil"
+)
+
+func Test_Paging(t *testing.T) {
+	conf := configs.PagingConfig{DefaultLimit: 20, MaxLimit: 100}
+	codec := cursor.NewCodec([]byte("secret"))
+
+	tests := []struct {
+		name       string
+		url        string
+		want       map[string]any
+		wantStatus int
+	}{
+		{
+			name:       "ok: default",
+			url:        "/test",
+			want:       map[string]any{"limit": 20, "page": 1, "offset": 0},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "ok: page",
+			url:        "/test?limit=10&page=3",
+			want:       map[string]any{"limit": 10, "page": 3, "offset": 20},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "ok: limit over max",
+			url:        "/test?limit=1000",
+			want:       map[string]any{"limit": 100, "page": 1, "offset": 0},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "ok: cursor",
+			url:        "/test?limit=10&cursor=" + codec.Encode(cursor.Cursor{UserId: 1, LastUserId: 111111}),
+			want:       map[string]any{"limit": 10, "cursor": cursor.Cursor{UserId: 1, LastUserId: 111111}, "page": nil, "offset": nil},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "ng: cursor forged",
+			url:        "/test?cursor=" + cursor.NewCodec([]byte("forged")).Encode(cursor.Cursor{UserId: 1, LastUserId: 111111}),
+			want:       nil,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, req := httputil.NewRequestAndRecorder("GET", tt.url, nil)
+			e := echo.New()
+			e.GET("/test", func(c echo.Context) error {
+				for k, v := range tt.want {
+					assert.Equal(t, v, c.Get(k), k)
+				}
+
+				return c.NoContent(http.StatusOK)
+			}, Paging(conf, codec))
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func Test_Paging_RouteOverride(t *testing.T) {
+	conf := configs.PagingConfig{
+		DefaultLimit:      20,
+		MaxLimit:          100,
+		RouteDefaultLimit: map[string]int{"/override": 50},
+		RouteMaxLimit:     map[string]int{"/override": 500},
+	}
+
+	tests := []struct {
+		name      string
+		route     string
+		url       string
+		wantLimit int
+	}{
+		{
+			name:      "ok: default of route",
+			route:     "/override",
+			url:       "/override",
+			wantLimit: 50,
+		},
+		{
+			name:      "ok: max of route",
+			route:     "/override",
+			url:       "/override?limit=1000",
+			wantLimit: 500,
+		},
+		{
+			name:      "ok: route without override",
+			route:     "/test",
+			url:       "/test?limit=1000",
+			wantLimit: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, req := httputil.NewRequestAndRecorder("GET", tt.url, nil)
+			e := echo.New()
+			e.GET(tt.route, func(c echo.Context) error {
+				assert.Equal(t, tt.wantLimit, c.Get("limit"))
+
+				return c.NoContent(http.StatusOK)
+			}, Paging(conf, cursor.NewCodec([]byte("secret"))))
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/labstack/echo/v4"
+)
+
+// requestIDPattern bounds the request ids taken from the clients, since they go to the logs.
+var requestIDPattern = regexp.MustCompile(`^[0-9A-Za-z._-]{1,64}$`)
+
+// RequestID sets the X-Request-Id header of the response to that of the request, or to a new
+// random id when the request has none, so that an error response can be matched with its logs.
+func RequestID(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id := c.Request().Header.Get(echo.HeaderXRequestID)
+		if !requestIDPattern.MatchString(id) {
+			b := make([]byte, 16)
+			if _, err := rand.Read(b); err != nil {
+				return err
+			}
+			id = hex.EncodeToString(b)
+		}
+		c.Response().Header().Set(echo.HeaderXRequestID, id)
+
+		return next(c)
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/pkg/httputil"
+)
+
+func Test_RequestID(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		wantSame  bool
+	}{
+		{
+			name:      "ok: from the request",
+			requestID: "0f8fad5b-d9cb-469f-a165-70867728950e",
+			wantSame:  true,
+		},
+		{
+			name:      "ok: generated",
+			requestID: "",
+			wantSame:  false,
+		},
+		{
+			name:      "ok: generated for an invalid one",
+			requestID: "a\nb",
+			wantSame:  false,
+		},
+		{
+			name:      "ok: generated for a too long one",
+			requestID: strings.Repeat("a", 65),
+			wantSame:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, req := httputil.NewRequestAndRecorder("GET", "/test", nil)
+			req.Header.Set(echo.HeaderXRequestID, tt.requestID)
+			e := echo.New()
+			e.GET("/test", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}, RequestID)
+			e.ServeHTTP(rec, req)
+
+			got := rec.Header().Get(echo.HeaderXRequestID)
+			if tt.wantSame {
+				assert.Equal(t, tt.requestID, got)
+			} else {
+				assert.Regexp(t, "^[0-9a-f]{32}$", got)
+			}
+		})
+	}
+}
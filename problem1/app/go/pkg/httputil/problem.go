@@ -0,0 +1,106 @@
+package httputil
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object, extended with the members of ErrorResponse.
+type Problem struct {
+	Type          string          `json:"type"`
+	Title         string          `json:"title"`
+	Status        int             `json:"status"`
+	Detail        string          `json:"detail,omitempty"`
+	Instance      string          `json:"instance,omitempty"`
+	ErrorCode     ErrorCode       `json:"errorCode"`
+	RequestID     string          `json:"requestId,omitempty"`
+	InvalidParams []*InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// ProblemType is the type of the problems of an error code. URI is relative to the API.
+type ProblemType struct {
+	URI   string
+	Title string
+}
+
+// problemTypes is the registry of the problem types by the error codes. A code is added
+// here along with its constant, and is never reused for another type once published.
+var problemTypes = map[ErrorCode]ProblemType{
+	ErrorCodeInternal:                 {URI: "/problems/internal-error", Title: "Internal server error"},
+	ErrorCodeInvalidRequest:           {URI: "/problems/invalid-request", Title: "Request invalid"},
+	ErrorCodeInvalidParameter:         {URI: "/problems/invalid-parameter", Title: "Parameter invalid"},
+	ErrorCodeInvalidTable:             {URI: "/problems/invalid-table", Title: "Table not exist"},
+	ErrorCodeInvalidCursor:            {URI: "/problems/invalid-cursor", Title: "Cursor invalid"},
+	ErrorCodeNotFound:                 {URI: "/problems/not-found", Title: "Route not found"},
+	ErrorCodeMethodNotAllowed:         {URI: "/problems/method-not-allowed", Title: "Method not allowed"},
+	ErrorCodeAdminDisabled:            {URI: "/problems/admin-disabled", Title: "Admin API disabled"},
+	ErrorCodeAdminTokenInvalid:        {URI: "/problems/admin-token-invalid", Title: "Admin token invalid"},
+	ErrorCodeUserNotFound:             {URI: "/problems/user-not-found", Title: "User not exist"},
+	ErrorCodeUserDuplicated:           {URI: "/problems/user-duplicated", Title: "User already exists"},
+	ErrorCodeUserLinkNotFound:         {URI: "/problems/user-link-not-found", Title: "User link not exist"},
+	ErrorCodeFriendRequestNotFound:    {URI: "/problems/friend-request-not-found", Title: "Friend request not exist"},
+	ErrorCodeFriendRequestBlocked:     {URI: "/problems/friend-request-blocked", Title: "Friend request blocked"},
+	ErrorCodeFriendRequestForbidden:   {URI: "/problems/friend-request-forbidden", Title: "Friend request not allowed for the user"},
+	ErrorCodeFriendRequestDuplicated:  {URI: "/problems/friend-request-duplicated", Title: "Friend request already exists"},
+	ErrorCodeFriendRequestNotPending:  {URI: "/problems/friend-request-not-pending", Title: "Friend request not pending"},
+	ErrorCodeAlreadyFriends:           {URI: "/problems/already-friends", Title: "Users already friends"},
+	ErrorCodePathNotFound:             {URI: "/problems/path-not-found", Title: "Path not exist"},
+	ErrorCodePathSearchBudgetExceeded: {URI: "/problems/path-search-budget-exceeded", Title: "Path search budget exceeded"},
+}
+
+// ProblemTypeOf returns the problem type of code, or "about:blank" titled by the status
+// text of status when code is not registered, as RFC 7807 defines.
+func ProblemTypeOf(code ErrorCode, status int) ProblemType {
+	if pt, ok := problemTypes[code]; ok {
+		return pt
+	}
+
+	return ProblemType{URI: "about:blank", Title: http.StatusText(status)}
+}
+
+// NewProblem returns the problem details of the error response res to r.
+func NewProblem(r *http.Request, res *ErrorResponse) *Problem {
+	pt := ProblemTypeOf(res.ErrorCode, res.Code)
+
+	return &Problem{
+		Type:          pt.URI,
+		Title:         pt.Title,
+		Status:        res.Code,
+		Detail:        res.Message,
+		Instance:      r.URL.Path,
+		ErrorCode:     res.ErrorCode,
+		RequestID:     res.RequestID,
+		InvalidParams: res.Errors,
+	}
+}
+
+// acceptsProblem tells whether the Accept header of r takes application/problem+json
+// at least as much as application/json, so that the clients not asking keep the legacy body.
+func acceptsProblem(r *http.Request) bool {
+	var problemQ, jsonQ float64
+	for _, v := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		switch mediaType {
+		case MIMEApplicationProblemJSON:
+			problemQ = q
+		case "application/json":
+			jsonQ = q
+		}
+	}
+
+	return problemQ > 0 && problemQ >= jsonQ
+}
@@ -0,0 +1,142 @@
+package httputil
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/pkg/testutil"
+)
+
+func Test_problem_acceptsProblem(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   bool
+	}{
+		{
+			name:   "ok: problem+json",
+			accept: "application/problem+json",
+			want:   true,
+		},
+		{
+			name:   "ok: problem+json along with json",
+			accept: "application/json, application/problem+json",
+			want:   true,
+		},
+		{
+			name:   "ok: json preferred",
+			accept: "application/json, application/problem+json;q=0.5",
+			want:   false,
+		},
+		{
+			name:   "ok: problem+json refused",
+			accept: "application/problem+json;q=0",
+			want:   false,
+		},
+		{
+			name:   "ok: any",
+			accept: "*/*",
+			want:   false,
+		},
+		{
+			name:   "ok: no Accept",
+			accept: "",
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, req := NewRequestAndRecorder("GET", "/test", nil)
+			req.Header.Set(echo.HeaderAccept, tt.accept)
+
+			assert.Equal(t, tt.want, acceptsProblem(req))
+		})
+	}
+}
+
+func Test_problem_ProblemTypeOf(t *testing.T) {
+	assert.Equal(t, ProblemType{URI: "/problems/user-not-found", Title: "User not exist"}, ProblemTypeOf(ErrorCodeUserNotFound, http.StatusNotFound))
+	assert.Equal(t, ProblemType{URI: "about:blank", Title: "Bad Request"}, ProblemTypeOf("UNKNOWN", http.StatusBadRequest))
+}
+
+func Test_problem_RespondError(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		err       error
+		wantCode  int
+		want      *Problem
+	}{
+		{
+			name:      "HTTPError",
+			requestID: "abc",
+			err:       NewHTTPError(testutil.ErrTest, http.StatusNotFound, ErrorCodeUserNotFound, "user not exist"),
+			wantCode:  http.StatusNotFound,
+			want: &Problem{
+				Type:      "/problems/user-not-found",
+				Title:     "User not exist",
+				Status:    http.StatusNotFound,
+				Detail:    "user not exist",
+				Instance:  "/test",
+				ErrorCode: ErrorCodeUserNotFound,
+				RequestID: "abc",
+			},
+		},
+		{
+			name: "validation error",
+			err: InvalidParams{
+				{In: "body", Name: "/userId", Reason: "userId is invalid"},
+				{In: "body", Name: "/name", Reason: "name is empty"},
+			}.Err(ErrorCodeInvalidParameter),
+			wantCode: http.StatusBadRequest,
+			want: &Problem{
+				Type:      "/problems/invalid-parameter",
+				Title:     "Parameter invalid",
+				Status:    http.StatusBadRequest,
+				Detail:    "userId is invalid, name is empty",
+				Instance:  "/test",
+				ErrorCode: ErrorCodeInvalidParameter,
+				InvalidParams: []*InvalidParam{
+					{In: "body", Name: "/userId", Reason: "userId is invalid"},
+					{In: "body", Name: "/name", Reason: "name is empty"},
+				},
+			},
+		},
+		{
+			name:     "normalError",
+			err:      testutil.ErrTest,
+			wantCode: http.StatusInternalServerError,
+			want: &Problem{
+				Type:      "/problems/internal-error",
+				Title:     "Internal server error",
+				Status:    http.StatusInternalServerError,
+				Detail:    "Internal Server Error",
+				Instance:  "/test",
+				ErrorCode: ErrorCodeInternal,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, req := NewRequestAndRecorder("GET", "/test?ID=1", nil)
+			req.Header.Set(echo.HeaderAccept, MIMEApplicationProblemJSON)
+			e := echo.New()
+			e.GET("/test", func(c echo.Context) error {
+				if tt.requestID != "" {
+					c.Response().Header().Set(echo.HeaderXRequestID, tt.requestID)
+				}
+				return RespondError(c, tt.err)
+			})
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+			testutil.AssertResponseBody(t, tt.want, rec.Body)
+		})
+	}
+}
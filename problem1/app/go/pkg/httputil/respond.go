@@ -1,6 +1,7 @@
 package httputil
 
 import (
+	"encoding/json"
 	"errors"
 	"log"
 	"net/http"
@@ -8,13 +9,77 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// ErrorResponse is the body of the error responses.
+type ErrorResponse struct {
+	// Code is the status code.
+	Code      int       `json:"code"`
+	ErrorCode ErrorCode `json:"errorCode"`
+	Message   string    `json:"message"`
+	// RequestID is the X-Request-Id of the response, to find the logs of the request by.
+	RequestID string `json:"requestId,omitempty"`
+	// Errors are the invalid parts of the request.
+	Errors []*InvalidParam `json:"errors,omitempty"`
+}
+
+// NewErrorResponse returns the status code and the body of the response of err.
+// The server errors and the errors other than HTTPError are told only as internal errors,
+// not to expose their details.
+func NewErrorResponse(c echo.Context, err error) (int, *ErrorResponse) {
+	res := &ErrorResponse{
+		Code:      http.StatusInternalServerError,
+		ErrorCode: ErrorCodeInternal,
+		Message:   http.StatusText(http.StatusInternalServerError),
+		RequestID: c.Response().Header().Get(echo.HeaderXRequestID),
+	}
+
+	var hErr *httpError
+	var echoErr *echo.HTTPError
+	switch {
+	case errors.As(err, &hErr):
+		if hErr.statusCode < http.StatusInternalServerError {
+			res.Code, res.ErrorCode, res.Message, res.Errors = hErr.statusCode, hErr.code, hErr.message, hErr.params
+		}
+	case errors.As(err, &echoErr):
+		// the errors of Echo itself, e.g. of the routes not found
+		if echoErr.Code < http.StatusInternalServerError {
+			res.Code, res.Message = echoErr.Code, http.StatusText(echoErr.Code)
+			switch echoErr.Code {
+			case http.StatusNotFound:
+				res.ErrorCode = ErrorCodeNotFound
+			case http.StatusMethodNotAllowed:
+				res.ErrorCode = ErrorCodeMethodNotAllowed
+			default:
+				res.ErrorCode = ErrorCodeInvalidRequest
+			}
+		}
+	}
+
+	return res.Code, res
+}
+
+// RespondError responds err as an ErrorResponse, or as a Problem to the clients accepting
+// application/problem+json.
 func RespondError(c echo.Context, err error) error {
-	log.Println(err.Error())
+	code, res := NewErrorResponse(c, err)
+	log.Printf("request_id = %s, %s", res.RequestID, err.Error())
+
+	if acceptsProblem(c.Request()) {
+		b, err := json.Marshal(NewProblem(c.Request(), res))
+		if err != nil {
+			return err
+		}
 
-	var httpErr HTTPError
-	if errors.As(err, &httpErr) {
-		return c.JSON(httpErr.StatusCode(), httpErr)
-	} else {
-		return c.JSON(http.StatusInternalServerError, err)
+		return c.Blob(code, MIMEApplicationProblemJSON, b)
 	}
+
+	return c.JSON(code, res)
+}
+
+// HTTPErrorHandler is the echo.HTTPErrorHandler responding the errors by RespondError.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	_ = RespondError(c, err)
 }
@@ -1,6 +1,8 @@
 package httputil
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -12,19 +14,63 @@ import (
 
 func Test_respond_RespondError(t *testing.T) {
 	tests := []struct {
-		name     string
-		wantCode int
-		err      error
+		name      string
+		requestID string
+		err       error
+		wantCode  int
+		want      *ErrorResponse
 	}{
 		{
-			name:     "HTTPError",
+			name:      "HTTPError",
+			requestID: "abc",
+			err:       NewHTTPError(testutil.ErrTest, http.StatusNotFound, ErrorCodeUserNotFound, "user not exist"),
+			wantCode:  http.StatusNotFound,
+			want: &ErrorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: ErrorCodeUserNotFound,
+				Message:   "user not exist",
+				RequestID: "abc",
+			},
+		},
+		{
+			name:     "HTTPError wrapped",
+			err:      fmt.Errorf("wrapped: %w", NewHTTPError(testutil.ErrTest, http.StatusBadRequest, ErrorCodeInvalidTable, "")),
+			wantCode: http.StatusBadRequest,
+			want: &ErrorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: ErrorCodeInvalidTable,
+				Message:   testutil.ErrTest.Error(),
+			},
+		},
+		{
+			name:     "HTTPError of server error",
+			err:      NewHTTPError(errors.New("secret"), http.StatusInternalServerError, ErrorCodeInternal, ""),
 			wantCode: http.StatusInternalServerError,
-			err:      NewHTTPError(testutil.ErrTest, http.StatusInternalServerError, ""),
+			want: &ErrorResponse{
+				Code:      http.StatusInternalServerError,
+				ErrorCode: ErrorCodeInternal,
+				Message:   "Internal Server Error",
+			},
+		},
+		{
+			name:     "echo HTTPError",
+			err:      echo.ErrMethodNotAllowed,
+			wantCode: http.StatusMethodNotAllowed,
+			want: &ErrorResponse{
+				Code:      http.StatusMethodNotAllowed,
+				ErrorCode: ErrorCodeMethodNotAllowed,
+				Message:   "Method Not Allowed",
+			},
 		},
 		{
 			name:     "normalError",
+			err:      errors.New("secret"),
 			wantCode: http.StatusInternalServerError,
-			err:      testutil.ErrTest,
+			want: &ErrorResponse{
+				Code:      http.StatusInternalServerError,
+				ErrorCode: ErrorCodeInternal,
+				Message:   "Internal Server Error",
+			},
 		},
 	}
 
@@ -33,11 +79,29 @@ func Test_respond_RespondError(t *testing.T) {
 			rec, req := NewRequestAndRecorder("GET", "/test", testutil.I2Reader(t, "body"))
 			e := echo.New()
 			e.GET("/test", func(c echo.Context) error {
+				if tt.requestID != "" {
+					c.Response().Header().Set(echo.HeaderXRequestID, tt.requestID)
+				}
 				return RespondError(c, tt.err)
 			})
 			e.ServeHTTP(rec, req)
 
 			assert.Equal(t, tt.wantCode, rec.Code)
+			testutil.AssertResponseBody(t, tt.want, rec.Body)
 		})
 	}
 }
+
+func Test_respond_HTTPErrorHandler(t *testing.T) {
+	rec, req := NewRequestAndRecorder("GET", "/not_found", nil)
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	testutil.AssertResponseBody(t, &ErrorResponse{
+		Code:      http.StatusNotFound,
+		ErrorCode: ErrorCodeNotFound,
+		Message:   "Not Found",
+	}, rec.Body)
+}
@@ -0,0 +1,341 @@
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// ContractOptions tunes the requests generated by ContractCases.
+type ContractOptions struct {
+	// Params are the values of the parameters of the names, used instead of the generated ones,
+	// e.g. to request the users in the test data.
+	Params map[string]string
+	// Header is added to every request, e.g. the credentials of the security schemes.
+	Header http.Header
+	// Statuses are the status codes the cases of the names expect, e.g. a 5xx the server
+	// cannot avoid for the request, instead of the ones ContractCases expects.
+	Statuses map[string]int
+}
+
+// ContractCase is a request generated from an operation of an OpenAPI document.
+type ContractCase struct {
+	Name string
+	// WantStatus is the status code of the response the case expects, or 0 for any one
+	// the operation declares below 500.
+	WantStatus int
+
+	route       *routers.Route
+	pathParams  map[string]string
+	url         string
+	header      http.Header
+	body        []byte
+	contentType string
+}
+
+// ContractCases generates the requests of every operation of the OpenAPI document at path: one
+// with every parameter and the body filled from the examples, or from the schemas when there
+// are none, and one lacking each of the required query parameters and body, which expects 400.
+func ContractCases(t testing.TB, path string, opts ContractOptions) []*ContractCase {
+	t.Helper()
+
+	doc, err := openapi3.NewLoader().LoadFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for p := range doc.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var cases []*ContractCase
+	for _, p := range paths {
+		pathItem := doc.Paths[p]
+		operations := pathItem.Operations()
+		methods := make([]string, 0, len(operations))
+		for m := range operations {
+			methods = append(methods, m)
+		}
+		sort.Strings(methods)
+
+		for _, m := range methods {
+			route := &routers.Route{Spec: doc, Path: p, PathItem: pathItem, Method: m, Operation: operations[m]}
+			cases = append(cases, contractCasesOf(route, opts)...)
+		}
+	}
+
+	return cases
+}
+
+func contractCasesOf(route *routers.Route, opts ContractOptions) []*ContractCase {
+	name := route.Method + " " + route.Path
+	pathParams := make(map[string]string)
+	query := make(url.Values)
+	var required []string
+
+	params := append(openapi3.Parameters{}, route.PathItem.Parameters...)
+	params = append(params, route.Operation.Parameters...)
+	for _, ref := range params {
+		p := ref.Value
+		v, ok := opts.Params[p.Name]
+		if !ok {
+			v = paramString(paramExample(p))
+		}
+
+		switch p.In {
+		case openapi3.ParameterInPath:
+			pathParams[p.Name] = v
+		case openapi3.ParameterInQuery:
+			query.Set(p.Name, v)
+			if p.Required {
+				required = append(required, p.Name)
+			}
+		}
+	}
+
+	u := route.Path
+	for k, v := range pathParams {
+		u = strings.ReplaceAll(u, "{"+k+"}", url.PathEscape(v))
+	}
+
+	var body []byte
+	var contentType string
+	if rb := route.Operation.RequestBody; rb != nil {
+		contentType, body = bodyExample(rb.Value)
+	}
+
+	newCase := func(name string, wantStatus int, query url.Values, body []byte) *ContractCase {
+		if status, ok := opts.Statuses[name]; ok {
+			wantStatus = status
+		}
+		c := &ContractCase{
+			Name:        name,
+			WantStatus:  wantStatus,
+			route:       route,
+			pathParams:  pathParams,
+			url:         u,
+			header:      opts.Header,
+			body:        body,
+			contentType: contentType,
+		}
+		if len(query) > 0 {
+			c.url += "?" + query.Encode()
+		}
+
+		return c
+	}
+
+	cases := []*ContractCase{newCase(name, 0, query, body)}
+	for _, r := range required {
+		q := make(url.Values, len(query))
+		for k, v := range query {
+			q[k] = v
+		}
+		q.Del(r)
+		cases = append(cases, newCase(name+" without "+r, http.StatusBadRequest, q, body))
+	}
+	if rb := route.Operation.RequestBody; rb != nil && rb.Value.Required {
+		cases = append(cases, newCase(name+" without body", http.StatusBadRequest, query, nil))
+	}
+
+	return cases
+}
+
+// Request returns a new request of c.
+func (c *ContractCase) Request() *http.Request {
+	req := httptest.NewRequest(c.route.Method, c.url, bytes.NewReader(c.body))
+	for k, v := range c.header {
+		req.Header[k] = v
+	}
+	if c.body != nil {
+		req.Header.Set("Content-Type", c.contentType)
+	}
+
+	return req
+}
+
+// Check returns why the response to c is not one the operation declares, either by the
+// status code or by the body, or not the one c expects, or nil if it is. A 5xx is expected
+// only when WantStatus says so, since the spec declaring one does not make it a pass.
+func (c *ContractCase) Check(res *http.Response) error {
+	var body bytes.Buffer
+	if _, err := body.ReadFrom(res.Body); err != nil {
+		return err
+	}
+
+	switch {
+	case c.WantStatus != 0 && res.StatusCode != c.WantStatus:
+		return fmt.Errorf("%d %s: status is not %d", res.StatusCode, body.String(), c.WantStatus)
+	case c.WantStatus == 0 && res.StatusCode >= http.StatusInternalServerError:
+		return fmt.Errorf("%d %s: server error is not expected", res.StatusCode, body.String())
+	}
+
+	options := &openapi3filter.Options{
+		IncludeResponseStatus: true,
+		AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+	}
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    c.Request(),
+			PathParams: c.pathParams,
+			Route:      c.route,
+			Options:    options,
+		},
+		Status:  res.StatusCode,
+		Header:  res.Header,
+		Options: options,
+	}
+	input.SetBodyBytes(body.Bytes())
+	if err := openapi3filter.ValidateResponse(context.Background(), input); err != nil {
+		return fmt.Errorf("%d %s: %w", res.StatusCode, body.String(), err)
+	}
+
+	return nil
+}
+
+// RunContract serves each of ContractCases by the handler newHandler returns for it, failing
+// those of which the response is not declared by the operation or not the one the case expects.
+func RunContract(t *testing.T, path string, opts ContractOptions, newHandler func(t *testing.T) http.Handler) {
+	t.Helper()
+
+	for _, c := range ContractCases(t, path, opts) {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			newHandler(t).ServeHTTP(rec, c.Request())
+
+			if err := c.Check(rec.Result()); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func paramExample(p *openapi3.Parameter) any {
+	if p.Example != nil {
+		return p.Example
+	}
+	for _, ex := range p.Examples {
+		if ex.Value != nil && ex.Value.Value != nil {
+			return ex.Value.Value
+		}
+	}
+	if p.Schema != nil {
+		return schemaExample(p.Schema.Value)
+	}
+
+	return ""
+}
+
+func paramString(v any) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []any:
+		s := make([]string, len(v))
+		for i, e := range v {
+			s[i] = paramString(e)
+		}
+		return strings.Join(s, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// bodyExample returns the JSON body if the request body takes one, or else the first one
+// in the order of the content types.
+func bodyExample(rb *openapi3.RequestBody) (string, []byte) {
+	contentTypes := make([]string, 0, len(rb.Content))
+	for ct := range rb.Content {
+		contentTypes = append(contentTypes, ct)
+	}
+	sort.Strings(contentTypes)
+	if len(contentTypes) == 0 {
+		return "", nil
+	}
+
+	ct := contentTypes[0]
+	if _, ok := rb.Content["application/json"]; ok {
+		ct = "application/json"
+	}
+	mt := rb.Content[ct]
+
+	var v any
+	switch {
+	case mt.Example != nil:
+		v = mt.Example
+	case mt.Schema != nil:
+		v = schemaExample(mt.Schema.Value)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return ct, b
+}
+
+// schemaExample returns the example of s, or else its default, its first enum value or
+// a value built from its type.
+func schemaExample(s *openapi3.Schema) any {
+	switch {
+	case s.Example != nil:
+		return s.Example
+	case s.Default != nil:
+		return s.Default
+	case len(s.Enum) > 0:
+		return s.Enum[0]
+	case len(s.OneOf) > 0:
+		return schemaExample(s.OneOf[0].Value)
+	case len(s.AnyOf) > 0:
+		return schemaExample(s.AnyOf[0].Value)
+	}
+
+	switch s.Type {
+	case openapi3.TypeObject:
+		v := make(map[string]any, len(s.Properties))
+		for name, p := range s.Properties {
+			v[name] = schemaExample(p.Value)
+		}
+		return v
+	case openapi3.TypeArray:
+		n := int(s.MinItems)
+		if n == 0 {
+			n = 1
+		}
+		v := make([]any, n)
+		for i := range v {
+			v[i] = schemaExample(s.Items.Value)
+		}
+		return v
+	case openapi3.TypeInteger, openapi3.TypeNumber:
+		if s.Min != nil {
+			return *s.Min
+		}
+		return 1
+	case openapi3.TypeBoolean:
+		return false
+	case openapi3.TypeString:
+		n := int(s.MinLength)
+		if n == 0 {
+			n = 1
+		}
+		return strings.Repeat("a", n)
+	default:
+		return nil
+	}
+}
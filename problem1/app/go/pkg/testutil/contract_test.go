@@ -0,0 +1,229 @@
+package testutil
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const contractSpec = `openapi: 3.0.0
+info:
+  title: test
+  version: 1.0.0
+paths:
+  /items/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+            example: 7
+        - name: q
+          in: query
+          required: true
+          schema:
+            type: string
+            enum: [a, b]
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            minimum: 5
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: integer
+                required:
+                  - id
+        "400":
+          description: invalid
+        "500":
+          description: error
+  /items:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: array
+              minItems: 2
+              items:
+                type: object
+                properties:
+                  name:
+                    type: string
+                    example: "x"
+                  count:
+                    type: integer
+      responses:
+        "201":
+          description: ok
+`
+
+func writeContractSpec(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(path, []byte(contractSpec), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func Test_ContractCases(t *testing.T) {
+	type want struct {
+		name       string
+		wantStatus int
+		url        string
+		header     http.Header
+		body       string
+	}
+
+	tests := []struct {
+		name string
+		opts ContractOptions
+		want []want
+	}{
+		{
+			name: "ok: from examples and schemas",
+			opts: ContractOptions{},
+			want: []want{
+				{name: "POST /items", url: "/items", header: http.Header{"Content-Type": {"application/json"}}, body: `[{"name":"x","count":1},{"name":"x","count":1}]`},
+				{name: "POST /items without body", wantStatus: http.StatusBadRequest, url: "/items", header: http.Header{}, body: ""},
+				{name: "GET /items/{id}", url: "/items/7?limit=5&q=a", header: http.Header{}, body: ""},
+				{name: "GET /items/{id} without q", wantStatus: http.StatusBadRequest, url: "/items/7?limit=5", header: http.Header{}, body: ""},
+			},
+		},
+		{
+			name: "ok: options",
+			opts: ContractOptions{
+				Params:   map[string]string{"id": "1", "q": "b"},
+				Header:   http.Header{"Authorization": {"Bearer token"}},
+				Statuses: map[string]int{"GET /items/{id}": http.StatusInternalServerError},
+			},
+			want: []want{
+				{name: "POST /items", url: "/items", header: http.Header{"Authorization": {"Bearer token"}, "Content-Type": {"application/json"}}, body: `[{"name":"x","count":1},{"name":"x","count":1}]`},
+				{name: "POST /items without body", wantStatus: http.StatusBadRequest, url: "/items", header: http.Header{"Authorization": {"Bearer token"}}, body: ""},
+				{name: "GET /items/{id}", wantStatus: http.StatusInternalServerError, url: "/items/1?limit=5&q=b", header: http.Header{"Authorization": {"Bearer token"}}, body: ""},
+				{name: "GET /items/{id} without q", wantStatus: http.StatusBadRequest, url: "/items/1?limit=5", header: http.Header{"Authorization": {"Bearer token"}}, body: ""},
+			},
+		},
+	}
+
+	path := writeContractSpec(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := ContractCases(t, path, tt.opts)
+			require.Len(t, cases, len(tt.want))
+
+			for i, c := range cases {
+				req := c.Request()
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+
+				assert.Equal(t, tt.want[i].name, c.Name)
+				assert.Equal(t, tt.want[i].wantStatus, c.WantStatus)
+				assert.Equal(t, tt.want[i].url, req.URL.RequestURI())
+				assert.Equal(t, tt.want[i].header, req.Header)
+				if tt.want[i].body == "" {
+					assert.Empty(t, body)
+				} else {
+					assert.JSONEq(t, tt.want[i].body, string(body))
+				}
+			}
+		})
+	}
+}
+
+func Test_ContractCase_Check(t *testing.T) {
+	tests := []struct {
+		name       string
+		wantStatus int
+		status     int
+		body       string
+		wantErr    string
+	}{
+		{
+			name:   "ok",
+			status: http.StatusOK,
+			body:   `{"id":7}`,
+		},
+		{
+			name:   "ok: status without content",
+			status: http.StatusBadRequest,
+			body:   `{"code":400}`,
+		},
+		{
+			name:       "ok: server error expected",
+			wantStatus: http.StatusInternalServerError,
+			status:     http.StatusInternalServerError,
+			body:       `{}`,
+		},
+		{
+			name:    "ng: status not declared",
+			status:  http.StatusNotFound,
+			body:    `{}`,
+			wantErr: "status is not supported",
+		},
+		{
+			name:    "ng: server error not expected though declared",
+			status:  http.StatusInternalServerError,
+			body:    `{}`,
+			wantErr: "server error is not expected",
+		},
+		{
+			name:       "ng: status not the expected one",
+			wantStatus: http.StatusBadRequest,
+			status:     http.StatusOK,
+			body:       `{"id":7}`,
+			wantErr:    "status is not 400",
+		},
+		{
+			name:    "ng: body not matching the schema",
+			status:  http.StatusOK,
+			body:    `{"id":"7"}`,
+			wantErr: "response body doesn't match the schema",
+		},
+	}
+
+	cases := ContractCases(t, writeContractSpec(t), ContractOptions{})
+	require.Equal(t, "GET /items/{id}", cases[2].Name)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := *cases[2]
+			c.WantStatus = tt.wantStatus
+
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", "application/json")
+			rec.WriteHeader(tt.status)
+			_, _ = rec.WriteString(tt.body)
+
+			err := c.Check(rec.Result())
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.True(t, strings.Contains(err.Error(), tt.wantErr), err.Error())
+			}
+		})
+	}
+}
@@ -18,10 +18,10 @@ import (
 var txDBRegisterOnce sync.Once
 
 func registerTxDB() {
-	txdb.Register("txdb", "mysql", "root:@(localhost:3306)/app")
+	txdb.Register("txdb", "mysql", "root:@(localhost:3306)/app?parseTime=true")
 }
 
-func PrepareMySQL(t *testing.T) *sql.DB {
+func PrepareMySQL(t testing.TB) *sql.DB {
 	t.Helper()
 	txDBRegisterOnce.Do(registerTxDB)
 
@@ -37,7 +37,7 @@ func PrepareMySQL(t *testing.T) *sql.DB {
 	return db
 }
 
-func ValidateSQLArgs(t *testing.T, q string, args ...any) {
+func ValidateSQLArgs(t testing.TB, q string, args ...any) {
 	t.Helper()
 
 	numQ := strings.Count(q, "?")
@@ -46,7 +46,7 @@ func ValidateSQLArgs(t *testing.T, q string, args ...any) {
 	}
 }
 
-func ExecSQL(t *testing.T, db *sql.DB, q string, args ...any) {
+func ExecSQL(t testing.TB, db *sql.DB, q string, args ...any) {
 	t.Helper()
 
 	if _, err := db.Exec(q, args...); err != nil {
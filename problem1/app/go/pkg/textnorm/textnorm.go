@@ -0,0 +1,33 @@
+// Package textnorm folds the ways a Japanese name may be typed into one form to search by.
+package textnorm
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalize folds s so that the spellings a user may type for the same name compare equal:
+// the full-width alphanumerics and the half-width katakana are made NFKC (ｽｽﾞｷ to スズキ),
+// the katakana are made hiragana (スズキ to すずき), the letters lower case, and the
+// whitespace is removed. The kanji are kept as they are, since their reading is not known.
+func Normalize(s string) string {
+	s = norm.NFKC.String(s)
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r):
+			continue
+		case 'ァ' <= r && r <= 'ヶ', 'ヽ' <= r && r <= 'ヾ':
+			r -= 'ァ' - 'ぁ'
+		default:
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,31 @@
+package textnorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Normalize(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "hiragana", s: "すずき", want: "すずき"},
+		{name: "katakana", s: "スズキ", want: "すずき"},
+		{name: "half-width katakana", s: "ｽｽﾞｷ", want: "すずき"},
+		{name: "half-width semi-voiced", s: "ﾎﾟﾝ", want: "ぽん"},
+		{name: "small and iteration marks", s: "ヴァヽヾ", want: "ゔぁゝゞ"},
+		{name: "full-width alphanumerics", s: "ＳＵＺＵＫＩ１", want: "suzuki1"},
+		{name: "whitespace", s: " 鈴木　一郎\t", want: "鈴木一郎"},
+		{name: "long vowel mark kept", s: "ｽｰﾊﾟｰ", want: "すーぱー"},
+		{name: "empty", s: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Normalize(tt.s))
+		})
+	}
+}
@@ -0,0 +1,570 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"sort"
+	"sync"
+
+	"problem1/model"
+	"problem1/pkg/graph"
+)
+
+// friendListGraphRepository serves the reads of friend_link and block_list from in-memory
+// adjacency sets, with the same results as the MySQL repository it wraps. Writes go to MySQL
+// first and are applied in memory once committed, so this process must be the only writer of
+// the links. Sorts needing the collation of users or the time a link was added, and the
+// existence checks taken inside transactions, are left to MySQL.
+type friendListGraphRepository struct {
+	FriendListRepository
+	db *sql.DB
+
+	mu      sync.RWMutex
+	friends *graph.Graph
+	blocks  *graph.Graph
+	names   map[uint32]string
+}
+
+func NewFriendListGraphRepository(ctx context.Context, db *sql.DB) (FriendListRepository, error) {
+	r := &friendListGraphRepository{
+		FriendListRepository: NewFriendListRepository(db),
+		db:                   db,
+	}
+	if err := r.Load(ctx); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Load reads users, friend_link and block_list again and replaces the graphs with them.
+func (r *friendListGraphRepository) Load(ctx context.Context) error {
+	names := make(map[uint32]string)
+	if err := r.scanRows(ctx, `
+	SELECT user_id, name
+	FROM users`, func(rows *sql.Rows) error {
+		var (
+			userId int
+			name   string
+		)
+		if err := rows.Scan(&userId, &name); err != nil {
+			return err
+		}
+
+		names[uint32(userId)] = name
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	friends, err := r.loadGraph(ctx, `
+	SELECT user1_id, user2_id
+	FROM friend_link`)
+	if err != nil {
+		return err
+	}
+	blocks, err := r.loadGraph(ctx, `
+	SELECT user1_id, user2_id
+	FROM block_list`)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.names, r.friends, r.blocks = names, friends, blocks
+
+	return nil
+}
+
+func (r *friendListGraphRepository) loadGraph(ctx context.Context, q string) (*graph.Graph, error) {
+	var edges [][2]uint32
+	if err := r.scanRows(ctx, q, func(rows *sql.Rows) error {
+		var user1Id, user2Id int
+		if err := rows.Scan(&user1Id, &user2Id); err != nil {
+			return err
+		}
+
+		edges = append(edges, [2]uint32{uint32(user1Id), uint32(user2Id)})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return graph.Build(edges), nil
+}
+
+func (r *friendListGraphRepository) scanRows(ctx context.Context, q string, scan func(rows *sql.Rows) error) error {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// vertex reports false for the user ids out of the range of the int unsigned columns, which
+// have no rows. The ids read from the tables and the ids MySQL accepted always fit.
+func vertex(userId int) (uint32, bool) {
+	if userId < 0 || userId > math.MaxUint32 {
+		return 0, false
+	}
+
+	return uint32(userId), true
+}
+
+func (r *friendListGraphRepository) InsertUserLink(ctx context.Context, user1Id, user2Id int, table string) error {
+	if err := r.FriendListRepository.InsertUserLink(ctx, user1Id, user2Id, table); err != nil {
+		return err
+	}
+
+	r.afterCommit(ctx, func() {
+		r.graphOf(table).AddEdge(uint32(user1Id), uint32(user2Id))
+	})
+
+	return nil
+}
+
+func (r *friendListGraphRepository) InsertUserLinks(ctx context.Context, table string, userLinks [][2]int) error {
+	if err := r.FriendListRepository.InsertUserLinks(ctx, table, userLinks); err != nil {
+		return err
+	}
+
+	r.afterCommit(ctx, func() {
+		g := r.graphOf(table)
+		for _, ul := range userLinks {
+			g.AddEdge(uint32(ul[0]), uint32(ul[1]))
+		}
+	})
+
+	return nil
+}
+
+func (r *friendListGraphRepository) DeleteUserLink(ctx context.Context, user1Id, user2Id int, table string) error {
+	if err := r.FriendListRepository.DeleteUserLink(ctx, user1Id, user2Id, table); err != nil {
+		return err
+	}
+
+	r.afterCommit(ctx, func() {
+		r.graphOf(table).RemoveEdge(uint32(user1Id), uint32(user2Id))
+		if table == "friend_link" {
+			r.friends.RemoveEdge(uint32(user2Id), uint32(user1Id))
+		}
+	})
+
+	return nil
+}
+
+func (r *friendListGraphRepository) DeleteFriendLinksBetween(ctx context.Context, user1Id, user2Id int) error {
+	if err := r.FriendListRepository.DeleteFriendLinksBetween(ctx, user1Id, user2Id); err != nil {
+		return err
+	}
+
+	r.afterCommit(ctx, func() {
+		r.friends.RemoveEdge(uint32(user1Id), uint32(user2Id))
+		r.friends.RemoveEdge(uint32(user2Id), uint32(user1Id))
+	})
+
+	return nil
+}
+
+func (r *friendListGraphRepository) DeleteUserLinksOfUser(ctx context.Context, userId int) error {
+	if err := r.FriendListRepository.DeleteUserLinksOfUser(ctx, userId); err != nil {
+		return err
+	}
+
+	v, ok := vertex(userId)
+	if !ok {
+		return nil
+	}
+	r.afterCommit(ctx, func() {
+		for _, g := range []*graph.Graph{r.friends, r.blocks} {
+			for _, w := range g.Out(v) {
+				g.RemoveEdge(v, w)
+			}
+			for _, w := range g.In(v) {
+				g.RemoveEdge(w, v)
+			}
+		}
+	})
+
+	return nil
+}
+
+// afterCommit applies f to the graphs under the write lock once the write is committed.
+func (r *friendListGraphRepository) afterCommit(ctx context.Context, f func()) {
+	AfterCommit(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		f()
+	})
+}
+
+// graphOf is only called after MySQL accepted table, so it is one of the two below.
+func (r *friendListGraphRepository) graphOf(table string) *graph.Graph {
+	if table == "block_list" {
+		return r.blocks
+	}
+
+	return r.friends
+}
+
+func (r *friendListGraphRepository) GetOneHopFriendsUserIdList(ctx context.Context, userId int) ([]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return toUserIds(r.out(r.friends, userId)), nil
+}
+
+func (r *friendListGraphRepository) GetBlockUsersIdList(ctx context.Context, userId int) ([]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return toUserIds(r.out(r.blocks, userId)), nil
+}
+
+func (r *friendListGraphRepository) GetBlockedByUsersIdList(ctx context.Context, userId int) ([]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v, ok := vertex(userId)
+	if !ok {
+		return nil, nil
+	}
+
+	return toUserIds(r.blocks.In(v)), nil
+}
+
+// servedFromGraph reports whether the graph holds what sort orders by.
+func servedFromGraph(sort model.FriendListSort) bool {
+	return sort == model.FriendListSortUserId || sort == ""
+}
+
+func (r *friendListGraphRepository) GetFriendListByUserId(ctx context.Context, userId int, sort model.FriendListSort) (*model.FriendList, error) {
+	if !servedFromGraph(sort) {
+		return r.FriendListRepository.GetFriendListByUserId(ctx, userId, sort)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.friendList(r.out(r.friends, userId), nil), nil
+}
+
+func (r *friendListGraphRepository) GetFriendListByUserIdExcludingBlockUsers(ctx context.Context, userId int, excludeBlockedBy bool, sort model.FriendListSort) (*model.FriendList, error) {
+	if !servedFromGraph(sort) {
+		return r.FriendListRepository.GetFriendListByUserIdExcludingBlockUsers(ctx, userId, excludeBlockedBy, sort)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.friendList(r.friendsOf(userId, excludeBlockedBy), nil), nil
+}
+
+func (r *friendListGraphRepository) GetFriendListByUserIdWithPaging(ctx context.Context, userId int, excludeBlockedBy bool, sort model.FriendListSort, limit, offset int) (*model.FriendList, error) {
+	if !servedFromGraph(sort) {
+		return r.FriendListRepository.GetFriendListByUserIdWithPaging(ctx, userId, excludeBlockedBy, sort, limit, offset)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.friendList(page(r.friendsOf(userId, excludeBlockedBy), limit, offset), nil), nil
+}
+
+func (r *friendListGraphRepository) CountFriendListByUserId(ctx context.Context, userId int, excludeBlockedBy bool) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.friendsOf(userId, excludeBlockedBy)), nil
+}
+
+// friendsOf returns the friends of userId having a user row who are not blocked, ordered by
+// user_id. The caller holds the read lock.
+func (r *friendListGraphRepository) friendsOf(userId int, excludeBlockedBy bool) []uint32 {
+	u, ok := vertex(userId)
+	if !ok {
+		return nil
+	}
+
+	var friends []uint32
+	for _, v := range r.friends.Out(u) {
+		if _, ok := r.names[v]; !ok || r.blocked(u, v, excludeBlockedBy) {
+			continue
+		}
+
+		friends = append(friends, v)
+	}
+
+	return friends
+}
+
+// blocked reports whether u blocked v, or with excludeBlockedBy whether v blocked u.
+// The caller holds the read lock.
+func (r *friendListGraphRepository) blocked(u, v uint32, excludeBlockedBy bool) bool {
+	return r.blocks.HasEdge(u, v) || (excludeBlockedBy && r.blocks.HasEdge(v, u))
+}
+
+// friendsOfFriends returns the friends of friends of userId having a user row, without
+// the friends and the users blocked by userId, ordered by user_id. The caller holds the read lock.
+func (r *friendListGraphRepository) friendsOfFriends(userId int, excludeBlockedBy bool, keep func(w uint32) bool) []uint32 {
+	u, ok := vertex(userId)
+	if !ok {
+		return nil
+	}
+
+	return r.friends.TwoHop(u, func(w uint32) bool {
+		if _, ok := r.names[w]; !ok {
+			return false
+		}
+		// the user is a friend of their own friends when the links go both ways
+		if w == u || r.friends.HasEdge(u, w) || r.blocked(u, w, excludeBlockedBy) {
+			return false
+		}
+
+		return keep == nil || keep(w)
+	})
+}
+
+func (r *friendListGraphRepository) GetFriendListOfFriendsByUserId(ctx context.Context, userId int, excludeBlockedBy bool, sort model.FriendListSort) (*model.FriendList, error) {
+	if !servedFromGraph(sort) {
+		return r.FriendListRepository.GetFriendListOfFriendsByUserId(ctx, userId, excludeBlockedBy, sort)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.friendList(r.friendsOfFriends(userId, excludeBlockedBy, nil), nil), nil
+}
+
+func (r *friendListGraphRepository) GetFriendListOfFriendsByUserIdWithPaging(ctx context.Context, userId int, excludeBlockedBy bool, sort model.FriendListSort, limit, offset int) (*model.FriendList, error) {
+	if !servedFromGraph(sort) {
+		return r.FriendListRepository.GetFriendListOfFriendsByUserIdWithPaging(ctx, userId, excludeBlockedBy, sort, limit, offset)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.friendList(page(r.friendsOfFriends(userId, excludeBlockedBy, nil), limit, offset), nil), nil
+}
+
+func (r *friendListGraphRepository) GetFriendListOfFriendsByUserIdWithCursor(ctx context.Context, userId int, excludeBlockedBy bool, lastUserId, limit int) (*model.FriendList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	friendsOfFriends := r.friendsOfFriends(userId, excludeBlockedBy, func(w uint32) bool {
+		return int(w) > lastUserId
+	})
+
+	return r.friendList(page(friendsOfFriends, limit, 0), nil), nil
+}
+
+func (r *friendListGraphRepository) CountFriendListOfFriendsByUserId(ctx context.Context, userId int, excludeBlockedBy bool) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.friendsOfFriends(userId, excludeBlockedBy, nil)), nil
+}
+
+func (r *friendListGraphRepository) GetBlockListByUserId(ctx context.Context, userId int) (*model.BlockList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return &model.BlockList{BlockUsers: r.friendList(r.blockedUsers(userId), nil).Friends}, nil
+}
+
+func (r *friendListGraphRepository) GetBlockListByUserIdWithPaging(ctx context.Context, userId, limit, offset int) (*model.BlockList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return &model.BlockList{BlockUsers: r.friendList(page(r.blockedUsers(userId), limit, offset), nil).Friends}, nil
+}
+
+func (r *friendListGraphRepository) CountBlockListByUserId(ctx context.Context, userId int) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.blockedUsers(userId)), nil
+}
+
+// blockedUsers returns the users blocked by userId having a user row, ordered by user_id.
+// The caller holds the read lock.
+func (r *friendListGraphRepository) blockedUsers(userId int) []uint32 {
+	var blocked []uint32
+	for _, v := range r.out(r.blocks, userId) {
+		if _, ok := r.names[v]; ok {
+			blocked = append(blocked, v)
+		}
+	}
+
+	return blocked
+}
+
+func (r *friendListGraphRepository) GetMutualFriendsByUserId(ctx context.Context, userId int, excludeBlockedBy bool) ([]*model.MutualFriend, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := vertex(userId)
+	if !ok {
+		return nil, nil
+	}
+
+	var mutualFriends []*model.MutualFriend
+	for _, m := range r.friends.Out(u) {
+		mName, ok := r.names[m]
+		if !ok || r.blocked(u, m, excludeBlockedBy) {
+			continue
+		}
+
+		for _, w := range r.friends.Out(m) {
+			if w == u || r.friends.HasEdge(u, w) || r.blocked(u, w, excludeBlockedBy) {
+				continue
+			}
+			wName, ok := r.names[w]
+			if !ok {
+				continue
+			}
+
+			mutualFriends = append(mutualFriends, &model.MutualFriend{
+				Candidate: &model.Friend{UserId: int(w), Name: wName},
+				Friend:    &model.Friend{UserId: int(m), Name: mName},
+			})
+		}
+	}
+	// the friends are visited in order, so sorting by candidate keeps them ordered within each
+	sort.SliceStable(mutualFriends, func(i, j int) bool {
+		return mutualFriends[i].Candidate.UserId < mutualFriends[j].Candidate.UserId
+	})
+
+	return mutualFriends, nil
+}
+
+func (r *friendListGraphRepository) GetFriendCountByUserIds(ctx context.Context, userIds []int) (map[int]int, error) {
+	if len(userIds) == 0 {
+		return nil, errNoUserIds
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[int]int, len(userIds))
+	for _, userId := range userIds {
+		if count := len(r.out(r.friends, userId)); count > 0 {
+			counts[userId] = count
+		}
+	}
+
+	return counts, nil
+}
+
+func (r *friendListGraphRepository) GetFriendUserIdsByUserIds(ctx context.Context, userIds []int) (map[int][]int, error) {
+	return r.userIdMap(userIds, (*graph.Graph).Out)
+}
+
+func (r *friendListGraphRepository) GetFriendOfUserIdsByUserIds(ctx context.Context, userIds []int) (map[int][]int, error) {
+	return r.userIdMap(userIds, (*graph.Graph).In)
+}
+
+func (r *friendListGraphRepository) userIdMap(userIds []int, adjacent func(g *graph.Graph, v uint32) []uint32) (map[int][]int, error) {
+	if len(userIds) == 0 {
+		return nil, errNoUserIds
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	userIdMap := make(map[int][]int, len(userIds))
+	for _, userId := range userIds {
+		v, ok := vertex(userId)
+		if !ok {
+			continue
+		}
+		if ids := toUserIds(adjacent(r.friends, v)); ids != nil {
+			userIdMap[userId] = ids
+		}
+	}
+
+	return userIdMap, nil
+}
+
+func (r *friendListGraphRepository) GetUserListByUserIds(ctx context.Context, userIds []int) (*model.FriendList, error) {
+	if len(userIds) == 0 {
+		return nil, errNoUserIds
+	}
+
+	vs := make([]uint32, 0, len(userIds))
+	for _, userId := range userIds {
+		if v, ok := vertex(userId); ok {
+			vs = append(vs, v)
+		}
+	}
+	sort.Slice(vs, func(i, j int) bool { return vs[i] < vs[j] })
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.friendList(vs, nil), nil
+}
+
+// out returns the vertices linked from userId in g. The caller holds the read lock.
+func (r *friendListGraphRepository) out(g *graph.Graph, userId int) []uint32 {
+	v, ok := vertex(userId)
+	if !ok {
+		return nil
+	}
+
+	return g.Out(v)
+}
+
+// friendList returns the users of the sorted vs having a user row and for which keep is
+// true, once each. The caller holds the read lock.
+func (r *friendListGraphRepository) friendList(vs []uint32, keep func(v uint32) bool) *model.FriendList {
+	var friends []*model.Friend
+	for i, v := range vs {
+		if i > 0 && v == vs[i-1] {
+			continue
+		}
+		name, ok := r.names[v]
+		if !ok || keep != nil && !keep(v) {
+			continue
+		}
+
+		friends = append(friends, &model.Friend{UserId: int(v), Name: name})
+	}
+
+	return &model.FriendList{Friends: friends}
+}
+
+func toUserIds(vs []uint32) []int {
+	if len(vs) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(vs))
+	for i, v := range vs {
+		ids[i] = int(v)
+	}
+
+	return ids
+}
+
+// page cuts limit vertices starting at offset out of vs, like LIMIT and OFFSET.
+func page(vs []uint32, limit, offset int) []uint32 {
+	if offset >= len(vs) {
+		return nil
+	}
+	if offset+limit < len(vs) {
+		return vs[offset : offset+limit]
+	}
+
+	return vs[offset:]
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"problem1/model"
+	"problem1/pkg/testutil"
+)
+
+func Test_friendListGraphRepository_writesAppliedOnCommit(t *testing.T) {
+	userId := testutil.UserIDForDebug
+
+	tests := []struct {
+		name  string
+		fnErr error
+		want  []int
+	}{
+		{
+			name:  "ok: applied when committed",
+			fnErr: nil,
+			want:  []int{222222},
+		},
+		{
+			name:  "ok: dropped when rolled back",
+			fnErr: testutil.ErrTest,
+			want:  []int{111111},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testutil.PrepareMySQL(t)
+			rt := &friendListRepositoryTest{db: db, flr: NewFriendListRepository(db), ctx: context.Background()}
+			rt.insertTestFriendLink(t, db, userLink{user1Id: userId, user2Id: 111111})
+
+			flr, err := NewFriendListGraphRepository(rt.ctx, db)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = NewTransaction(db).DoInTx(rt.ctx, func(ctx context.Context) error {
+				if err := flr.InsertUserLink(ctx, userId, 222222, "friend_link"); err != nil {
+					return err
+				}
+				if err := flr.DeleteUserLink(ctx, userId, 111111, "friend_link"); err != nil {
+					return err
+				}
+
+				got, err := flr.GetOneHopFriendsUserIdList(ctx, userId)
+				if err != nil {
+					return err
+				}
+				assert.Equal(t, []int{111111}, got, "the graph is not written before the commit")
+
+				return tt.fnErr
+			})
+			assert.ErrorIs(t, err, tt.fnErr)
+
+			got, err := flr.GetOneHopFriendsUserIdList(rt.ctx, userId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListGraphRepository_DeleteFriendLinksBetween(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	db := testutil.PrepareMySQL(t)
+	rt := &friendListRepositoryTest{db: db, flr: NewFriendListRepository(db), ctx: context.Background()}
+	for _, tu := range newTestUsers() {
+		rt.insertTestUserList(t, db, tu)
+	}
+	for _, ul := range []userLink{
+		{user1Id: userId, user2Id: 111111},
+		{user1Id: 111111, user2Id: userId},
+		{user1Id: 111111, user2Id: 222222},
+	} {
+		rt.insertTestFriendLink(t, db, ul)
+	}
+
+	flr, err := NewFriendListGraphRepository(rt.ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := flr.GetFriendListOfFriendsByUserId(rt.ctx, userId, false, model.FriendListSortUserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*model.Friend{
+		{UserId: userId, Name: testutil.UserNameForDebug},
+		{UserId: 222222, Name: "fuga"},
+	}, got.Friends)
+
+	if err := flr.DeleteFriendLinksBetween(rt.ctx, 111111, userId); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = flr.GetFriendListOfFriendsByUserId(rt.ctx, userId, false, model.FriendListSortUserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, got.Friends)
+
+	friendOf, err := flr.GetFriendOfUserIdsByUserIds(rt.ctx, []int{userId, 111111})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[int][]int{}, friendOf)
+}
+
+func Test_userGraphRepository(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	db := testutil.PrepareMySQL(t)
+	rt := &friendListRepositoryTest{db: db, flr: NewFriendListRepository(db), ctx: context.Background()}
+	for _, tu := range newTestUsers() {
+		rt.insertTestUserList(t, db, tu)
+	}
+	rt.insertTestFriendLink(t, db, userLink{user1Id: userId, user2Id: 111111})
+	rt.insertTestFriendLink(t, db, userLink{user1Id: userId, user2Id: 222222})
+	rt.insertTestFriendLink(t, db, userLink{user1Id: 222222, user2Id: 111111})
+	rt.insertTestBlockList(t, db, userLink{user1Id: 111111, user2Id: 333333})
+
+	flr, err := NewFriendListGraphRepository(rt.ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ur, err := NewUserGraphRepository(NewUserRepository(db), flr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = NewTransaction(db).DoInTx(rt.ctx, func(ctx context.Context) error {
+		if err := ur.UpdateUserName(ctx, 222222, "piyo"); err != nil {
+			return err
+		}
+		if err := flr.DeleteUserLinksOfUser(ctx, 111111); err != nil {
+			return err
+		}
+		return ur.DeleteUser(ctx, 111111)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := flr.GetFriendListByUserId(rt.ctx, userId, model.FriendListSortUserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*model.Friend{{UserId: 222222, Name: "piyo"}}, got.Friends)
+
+	blocks, err := flr.GetBlockUsersIdList(rt.ctx, 111111)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, blocks)
+
+	_, err = NewUserGraphRepository(NewUserRepository(db), rt.flr)
+	assert.Error(t, err, "the MySQL repository has no names to keep")
+}
+
+func Test_page(t *testing.T) {
+	vs := []uint32{1, 2, 3}
+
+	tests := []struct {
+		name   string
+		limit  int
+		offset int
+		want   []uint32
+	}{
+		{
+			name:   "ok",
+			limit:  2,
+			offset: 0,
+			want:   []uint32{1, 2},
+		},
+		{
+			name:   "ok: last page",
+			limit:  2,
+			offset: 2,
+			want:   []uint32{3},
+		},
+		{
+			name:   "ok: past the end",
+			limit:  2,
+			offset: 3,
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, page(vs, tt.limit, tt.offset))
+		})
+	}
+}
@@ -1,10 +1,14 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 
 	"problem1/model"
@@ -14,15 +18,57 @@ import (
 //go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
 
 type FriendListRepository interface {
-	CheckUserExist(userId int) (bool, error)
-	CheckUserLink(user1Id, user2Id int, table string) error
-	InsertUserLink(user1Id, user2Id int, table string) error
-	GetOneHopFriendsUserIdList(userId int) ([]int, error)
-	GetBlockUsersIdList(userId int) ([]int, error)
-	GetFriendListByUserId(userId int) (*model.FriendList, error)
-	GetFriendListByUserIdExcludingBlockUsers(userId int, blockUsers []int) (*model.FriendList, error)
-	GetFriendListOfFriendsByUserId(userId int, excludeUsers []int) (*model.FriendList, error)
-	GetFriendListOfFriendsByUserIdWithPaging(userId int, excludeUsers []int, limit, offset int) (*model.FriendList, error)
+	CheckUserExist(ctx context.Context, userId int) (bool, error)
+	CheckUserLink(ctx context.Context, user1Id, user2Id int, table string) error
+	InsertUserLink(ctx context.Context, user1Id, user2Id int, table string) error
+	InsertUserLinks(ctx context.Context, table string, userLinks [][2]int) error
+	GetExistingUserLinks(ctx context.Context, table string, userLinks [][2]int) (map[[2]int]bool, error)
+	DeleteUserLink(ctx context.Context, user1Id, user2Id int, table string) error
+	DeleteFriendLinksBetween(ctx context.Context, user1Id, user2Id int) error
+	DeleteUserLinksOfUser(ctx context.Context, userId int) error
+	GetOneHopFriendsUserIdList(ctx context.Context, userId int) ([]int, error)
+	GetBlockUsersIdList(ctx context.Context, userId int) ([]int, error)
+	GetBlockedByUsersIdList(ctx context.Context, userId int) ([]int, error)
+	GetFriendListByUserId(ctx context.Context, userId int, sort model.FriendListSort) (*model.FriendList, error)
+	GetFriendListByUserIdExcludingBlockUsers(ctx context.Context, userId int, excludeBlockedBy bool, sort model.FriendListSort) (*model.FriendList, error)
+	GetFriendListByUserIdWithPaging(ctx context.Context, userId int, excludeBlockedBy bool, sort model.FriendListSort, limit, offset int) (*model.FriendList, error)
+	CountFriendListByUserId(ctx context.Context, userId int, excludeBlockedBy bool) (int, error)
+	GetFriendListOfFriendsByUserId(ctx context.Context, userId int, excludeBlockedBy bool, sort model.FriendListSort) (*model.FriendList, error)
+	GetFriendListOfFriendsByUserIdWithPaging(ctx context.Context, userId int, excludeBlockedBy bool, sort model.FriendListSort, limit, offset int) (*model.FriendList, error)
+	GetFriendListOfFriendsByUserIdWithCursor(ctx context.Context, userId int, excludeBlockedBy bool, lastUserId, limit int) (*model.FriendList, error)
+	CountFriendListOfFriendsByUserId(ctx context.Context, userId int, excludeBlockedBy bool) (int, error)
+	GetBlockListByUserId(ctx context.Context, userId int) (*model.BlockList, error)
+	GetBlockListByUserIdWithPaging(ctx context.Context, userId, limit, offset int) (*model.BlockList, error)
+	CountBlockListByUserId(ctx context.Context, userId int) (int, error)
+	GetMutualFriendsByUserId(ctx context.Context, userId int, excludeBlockedBy bool) ([]*model.MutualFriend, error)
+	GetFriendCountByUserIds(ctx context.Context, userIds []int) (map[int]int, error)
+	GetFriendUserIdsByUserIds(ctx context.Context, userIds []int) (map[int][]int, error)
+	GetFriendOfUserIdsByUserIds(ctx context.Context, userIds []int) (map[int][]int, error)
+	GetUserListByUserIds(ctx context.Context, userIds []int) (*model.FriendList, error)
+}
+
+var (
+	// ErrUserLinkDuplicated is returned when the link between the users already exists.
+	ErrUserLinkDuplicated = errors.New("user link already exists")
+	// ErrUserLinkNotFound is returned when the link between the users does not exist.
+	ErrUserLinkNotFound = errors.New("user link not exist")
+	// ErrSortNotSupported is returned when the list can not be ordered by the sort.
+	ErrSortNotSupported = errors.New("sort not supported")
+)
+
+const mysqlErrDuplicateEntry = 1062
+
+func isDuplicateEntryError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
+
+func convertDuplicateEntryError(err error) error {
+	if isDuplicateEntryError(err) {
+		return ErrUserLinkDuplicated
+	}
+
+	return err
 }
 
 type friendListRepository struct {
@@ -35,13 +81,13 @@ func NewFriendListRepository(db *sql.DB) FriendListRepository {
 	}
 }
 
-func (r *friendListRepository) CheckUserExist(userId int) (bool, error) {
+func (r *friendListRepository) CheckUserExist(ctx context.Context, userId int) (bool, error) {
 	const q = `
 	SELECT user_id, name
 	FROM users
 	WHERE user_id = ?`
 
-	row := r.db.QueryRow(q, userId)
+	row := conn(ctx, r.db).QueryRowContext(ctx, q, userId)
 
 	user := &model.Friend{}
 	if err := row.Scan(&user.UserId, &user.Name); err != nil {
@@ -55,7 +101,7 @@ func (r *friendListRepository) CheckUserExist(userId int) (bool, error) {
 	return true, nil
 }
 
-func (r *friendListRepository) CheckUserLink(user1Id, user2Id int, table string) error {
+func (r *friendListRepository) CheckUserLink(ctx context.Context, user1Id, user2Id int, table string) error {
 	switch table {
 	case "friend_link":
 		const q = `
@@ -64,7 +110,7 @@ func (r *friendListRepository) CheckUserLink(user1Id, user2Id int, table string)
 		WHERE user1_id = ? AND user2_id = ?`
 
 		userLink := &model.UserLinkForRequest{}
-		row := r.db.QueryRow(q, user1Id, user2Id)
+		row := conn(ctx, r.db).QueryRowContext(ctx, q, user1Id, user2Id)
 		if err := row.Scan(&userLink.User1Id, &userLink.User2Id); err != nil {
 			return err
 		}
@@ -77,49 +123,193 @@ func (r *friendListRepository) CheckUserLink(user1Id, user2Id int, table string)
 		WHERE user1_id = ? AND user2_id = ?`
 
 		userLink := &model.UserLinkForRequest{}
-		row := r.db.QueryRow(q, user1Id, user2Id)
+		row := conn(ctx, r.db).QueryRowContext(ctx, q, user1Id, user2Id)
 		if err := row.Scan(&userLink.User1Id, &userLink.User2Id); err != nil {
 			return err
 		}
 
 		return nil
 	default:
-		return httputil.NewHTTPError(errors.New("table not exist"), http.StatusBadRequest, "")
+		return httputil.NewHTTPError(errors.New("table not exist"), http.StatusBadRequest, httputil.ErrorCodeInvalidTable, "")
 	}
 }
 
-func (r *friendListRepository) InsertUserLink(user1Id, user2Id int, table string) error {
+func (r *friendListRepository) InsertUserLink(ctx context.Context, user1Id, user2Id int, table string) error {
 	switch table {
 	case "friend_link":
 		const q = `
 		INSERT INTO friend_link (id, user1_id, user2_id)
 		VALUES (0, ?, ?)`
 
-		if _, err := r.db.Exec(q, user1Id, user2Id); err != nil {
-			return err
+		if _, err := conn(ctx, r.db).ExecContext(ctx, q, user1Id, user2Id); err != nil {
+			return convertDuplicateEntryError(err)
 		}
 	case "block_list":
 		const q = `
 		INSERT INTO block_list (id, user1_id, user2_id)
 		VALUES (0, ?, ?)`
 
-		if _, err := r.db.Exec(q, user1Id, user2Id); err != nil {
-			return err
+		if _, err := conn(ctx, r.db).ExecContext(ctx, q, user1Id, user2Id); err != nil {
+			return convertDuplicateEntryError(err)
 		}
 	default:
-		return httputil.NewHTTPError(errors.New("table not exist"), http.StatusBadRequest, "")
+		return httputil.NewHTTPError(errors.New("table not exist"), http.StatusBadRequest, httputil.ErrorCodeInvalidTable, "")
+	}
+
+	return nil
+}
+
+// checkUserLinkTable returns an error unless table holds user links. The tables of the batch
+// queries below are only ever one of these names, never the argument itself.
+func checkUserLinkTable(table string) error {
+	switch table {
+	case "friend_link", "block_list":
+		return nil
+	default:
+		return httputil.NewHTTPError(errors.New("table not exist"), http.StatusBadRequest, httputil.ErrorCodeInvalidTable, "")
+	}
+}
+
+// userLinkRows returns the placeholders and the arguments of userLinks as rows of two columns.
+func userLinkRows(prefix string, userLinks [][2]int) (string, []any) {
+	rows := make([]string, 0, len(userLinks))
+	args := make([]any, 0, len(userLinks)*2)
+	for _, ul := range userLinks {
+		rows = append(rows, "("+prefix+"?, ?)")
+		args = append(args, ul[0], ul[1])
+	}
+
+	return strings.Join(rows, ", "), args
+}
+
+// InsertUserLinks inserts userLinks in one statement. The links already there are kept.
+func (r *friendListRepository) InsertUserLinks(ctx context.Context, table string, userLinks [][2]int) error {
+	if err := checkUserLinkTable(table); err != nil {
+		return err
+	}
+	if len(userLinks) == 0 {
+		return nil
+	}
+
+	rows, args := userLinkRows("0, ", userLinks)
+	q := `
+	INSERT INTO ` + table + ` (id, user1_id, user2_id)
+	VALUES ` + rows + `
+	ON DUPLICATE KEY UPDATE id = id`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, q, args...)
+
+	return err
+}
+
+// GetExistingUserLinks returns which of userLinks are in table.
+func (r *friendListRepository) GetExistingUserLinks(ctx context.Context, table string, userLinks [][2]int) (map[[2]int]bool, error) {
+	if err := checkUserLinkTable(table); err != nil {
+		return nil, err
+	}
+	existing := make(map[[2]int]bool, len(userLinks))
+	if len(userLinks) == 0 {
+		return existing, nil
+	}
+
+	rows, args := userLinkRows("", userLinks)
+	q := `
+	SELECT user1_id, user2_id
+	FROM ` + table + `
+	WHERE (user1_id, user2_id) IN (` + rows + `)`
+
+	queryRows, err := conn(ctx, r.db).QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer queryRows.Close()
+
+	for queryRows.Next() {
+		var ul [2]int
+		if err := queryRows.Scan(&ul[0], &ul[1]); err != nil {
+			return nil, err
+		}
+
+		existing[ul] = true
+	}
+	if err := queryRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// DeleteUserLink deletes a friendship in both directions, since accepting a friend request
+// writes both, and a block only in the given one.
+func (r *friendListRepository) DeleteUserLink(ctx context.Context, user1Id, user2Id int, table string) error {
+	var (
+		q    string
+		args []any
+	)
+	switch table {
+	case "friend_link":
+		q = `
+		DELETE FROM friend_link
+		WHERE (user1_id = ? AND user2_id = ?) OR (user1_id = ? AND user2_id = ?)`
+		args = []any{user1Id, user2Id, user2Id, user1Id}
+	case "block_list":
+		q = `
+		DELETE FROM block_list
+		WHERE user1_id = ? AND user2_id = ?`
+		args = []any{user1Id, user2Id}
+	default:
+		return httputil.NewHTTPError(errors.New("table not exist"), http.StatusBadRequest, httputil.ErrorCodeInvalidTable, "")
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrUserLinkNotFound
 	}
 
 	return nil
 }
 
-func (r *friendListRepository) GetOneHopFriendsUserIdList(userId int) ([]int, error) {
+// DeleteFriendLinksBetween removes the friend links in both directions. It is not an error when there is none.
+func (r *friendListRepository) DeleteFriendLinksBetween(ctx context.Context, user1Id, user2Id int) error {
+	const q = `
+	DELETE FROM friend_link
+	WHERE (user1_id = ? AND user2_id = ?) OR (user1_id = ? AND user2_id = ?)`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, q, user1Id, user2Id, user2Id, user1Id)
+
+	return err
+}
+
+// DeleteUserLinksOfUser deletes the friend links and the blocks from and to userId.
+func (r *friendListRepository) DeleteUserLinksOfUser(ctx context.Context, userId int) error {
+	for _, table := range []string{"friend_link", "block_list"} {
+		q := `
+		DELETE FROM ` + table + `
+		WHERE user1_id = ? OR user2_id = ?`
+
+		if _, err := conn(ctx, r.db).ExecContext(ctx, q, userId, userId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *friendListRepository) GetOneHopFriendsUserIdList(ctx context.Context, userId int) ([]int, error) {
 	const q = `
 	SELECT user2_id
 	FROM friend_link
 	WHERE user1_id = ?`
 
-	rows, err := r.db.Query(q, userId)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, userId)
 	if err != nil {
 		return nil, err
 	}
@@ -143,13 +333,13 @@ func (r *friendListRepository) GetOneHopFriendsUserIdList(userId int) ([]int, er
 	return oneHopFriends, nil
 }
 
-func (r *friendListRepository) GetBlockUsersIdList(userId int) ([]int, error) {
+func (r *friendListRepository) GetBlockUsersIdList(ctx context.Context, userId int) ([]int, error) {
 	const q = `
 	SELECT user2_id
 	FROM block_list
 	WHERE user1_id = ?`
 
-	rows, err := r.db.Query(q, userId)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, userId)
 	if err != nil {
 		return nil, err
 	}
@@ -173,105 +363,467 @@ func (r *friendListRepository) GetBlockUsersIdList(userId int) ([]int, error) {
 	return blockUsers, nil
 }
 
-func (r *friendListRepository) GetFriendListByUserId(userId int) (*model.FriendList, error) {
+// GetBlockedByUsersIdList returns the users who blocked userId.
+func (r *friendListRepository) GetBlockedByUsersIdList(ctx context.Context, userId int) ([]int, error) {
 	const q = `
-	SELECT U.user_id, U.name
-	FROM users AS U INNER JOIN friend_link AS FL
-	ON U.user_id = FL.user2_id
-	WHERE FL.user1_id = ?`
+	SELECT user1_id
+	FROM block_list
+	WHERE user2_id = ?`
 
-	rows, err := r.db.Query(q, userId)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, userId)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var friends []*model.Friend
+	var (
+		blockedBy []int
+		user      int
+	)
 	for rows.Next() {
-		friend := &model.Friend{}
-		if err := rows.Scan(&friend.UserId, &friend.Name); err != nil {
+		if err := rows.Scan(&user); err != nil {
 			return nil, err
 		}
 
-		friends = append(friends, friend)
+		blockedBy = append(blockedBy, user)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return &model.FriendList{Friends: friends}, nil
+	return blockedBy, nil
 }
 
-func (r *friendListRepository) GetFriendListByUserIdExcludingBlockUsers(userId int, blockUsers []int) (*model.FriendList, error) {
-	const q = `
+// friendListOrderBy translates sort into an ORDER BY clause of users aliased as U. Only the
+// fixed clauses below reach the query, never the sort itself. addedAt orders the rows by when
+// the link was added; it is empty when the list is not made of single links.
+// user_id breaks ties so that the order is the same on every call.
+func friendListOrderBy(sort model.FriendListSort, addedAt string) (string, error) {
+	switch sort {
+	case model.FriendListSortUserId, "":
+		return `
+	ORDER BY U.user_id`, nil
+	case model.FriendListSortName:
+		return `
+	ORDER BY U.name COLLATE utf8mb4_ja_0900_as_cs, U.user_id`, nil
+	case model.FriendListSortNameDesc:
+		return `
+	ORDER BY U.name COLLATE utf8mb4_ja_0900_as_cs DESC, U.user_id`, nil
+	case model.FriendListSortAddedAt:
+		if addedAt != "" {
+			return `
+	ORDER BY ` + addedAt + `, U.user_id`, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrSortNotSupported, sort)
+}
+
+// friendLinkAddedAt orders friend links by when they were added.
+const friendLinkAddedAt = "FL.created_at, FL.id"
+
+func (r *friendListRepository) GetFriendListByUserId(ctx context.Context, userId int, sort model.FriendListSort) (*model.FriendList, error) {
+	orderBy, err := friendListOrderBy(sort, friendLinkAddedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	q := `
 	SELECT U.user_id, U.name
 	FROM users AS U INNER JOIN friend_link AS FL
 	ON U.user_id = FL.user2_id
-	WHERE FL.user1_id = ?
-	AND	U.user_id NOT IN (?)`
+	WHERE FL.user1_id = ?` + orderBy
 
-	dbx := sqlx.NewDb(r.db, "mysql")
+	return r.selectFriendList(ctx, q, userId)
+}
 
-	query, args, err := sqlx.In(q, userId, blockUsers)
+// GetFriendListByUserIdExcludingBlockUsers leaves out the friends the user blocked, and with
+// excludeBlockedBy the friends who blocked the user.
+func (r *friendListRepository) GetFriendListByUserIdExcludingBlockUsers(ctx context.Context, userId int, excludeBlockedBy bool, sort model.FriendListSort) (*model.FriendList, error) {
+	orderBy, err := friendListOrderBy(sort, friendLinkAddedAt)
 	if err != nil {
 		return nil, err
 	}
 
-	var friends []*model.Friend
-	if err := dbx.Select(&friends, query, args...); err != nil {
+	q := friendListQuery(`
+	SELECT U.user_id, U.name`, excludeBlockedBy, orderBy)
+
+	return r.selectFriendList(ctx, q, userId)
+}
+
+func (r *friendListRepository) GetFriendListByUserIdWithPaging(ctx context.Context, userId int, excludeBlockedBy bool, sort model.FriendListSort, limit, offset int) (*model.FriendList, error) {
+	orderBy, err := friendListOrderBy(sort, friendLinkAddedAt)
+	if err != nil {
 		return nil, err
 	}
 
-	return &model.FriendList{Friends: friends}, nil
+	q := friendListQuery(`
+	SELECT U.user_id, U.name`, excludeBlockedBy, orderBy+`
+	LIMIT ? OFFSET ?`)
+
+	return r.selectFriendList(ctx, q, userId, limit, offset)
 }
 
-func (r *friendListRepository) GetFriendListOfFriendsByUserId(userId int, excludeUsers []int) (*model.FriendList, error) {
-	const q = `
-	SELECT DISTINCT U.user_id, U.name
+func (r *friendListRepository) CountFriendListByUserId(ctx context.Context, userId int, excludeBlockedBy bool) (int, error) {
+	q := friendListQuery(`
+	SELECT COUNT(*)`, excludeBlockedBy, "")
+
+	var count int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, q, userId).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// friendListQuery selects the friends of the user bound to FL.user1_id who are not blocked.
+func friendListQuery(selectClause string, excludeBlockedBy bool, tail string) string {
+	return selectClause + `
+	FROM users AS U INNER JOIN friend_link AS FL
+	ON U.user_id = FL.user2_id
+	WHERE FL.user1_id = ?` + notBlockedCondition("FL.user1_id", "U.user_id", excludeBlockedBy) + tail
+}
+
+// notBlockedCondition drops the rows where the user in the column userId blocked the user in
+// the column other, and with excludeBlockedBy also those where other blocked userId.
+func notBlockedCondition(userId, other string, excludeBlockedBy bool) string {
+	q := `
+	AND NOT EXISTS (
+		SELECT 1 FROM block_list AS B
+		WHERE B.user1_id = ` + userId + ` AND B.user2_id = ` + other + `
+	)`
+	if excludeBlockedBy {
+		q += `
+	AND NOT EXISTS (
+		SELECT 1 FROM block_list AS B
+		WHERE B.user1_id = ` + other + ` AND B.user2_id = ` + userId + `
+	)`
+	}
+
+	return q
+}
+
+// friendOfFriendCondition narrows the friends of friends of the user bound to FL2.user1_id
+// down to those who are neither the user, who is a friend of each of their friends when the
+// links go both ways, nor the user's friends nor blocked by the user. The exclusions are
+// anti-joins, so the statement does not grow with the number of friends or blocks.
+const friendOfFriendCondition = `
 	FROM users AS U
 	INNER JOIN friend_link AS FL
 	ON U.user_id = FL.user2_id
 	INNER JOIN friend_link AS FL2
 	ON FL.user1_id = FL2.user2_id
 	WHERE FL2.user1_id = ?
-	AND	U.user_id NOT IN (?)`
+	AND U.user_id <> FL2.user1_id
+	AND NOT EXISTS (
+		SELECT 1 FROM friend_link AS F
+		WHERE F.user1_id = FL2.user1_id AND F.user2_id = U.user_id
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM block_list AS B
+		WHERE B.user1_id = FL2.user1_id AND B.user2_id = U.user_id
+	)`
+
+// notBlockedByCondition also drops the users who blocked the user.
+const notBlockedByCondition = `
+	AND NOT EXISTS (
+		SELECT 1 FROM block_list AS BB
+		WHERE BB.user1_id = U.user_id AND BB.user2_id = FL2.user1_id
+	)`
+
+func friendOfFriendQuery(selectClause string, excludeBlockedBy bool, tail string) string {
+	q := selectClause + friendOfFriendCondition
+	if excludeBlockedBy {
+		q += notBlockedByCondition
+	}
 
-	dbx := sqlx.NewDb(r.db, "mysql")
+	return q + tail
+}
 
-	query, args, err := sqlx.In(q, userId, excludeUsers)
+// GetFriendListOfFriendsByUserId can not be sorted by addedAt since a friend of friend may be
+// reached through several links.
+func (r *friendListRepository) GetFriendListOfFriendsByUserId(ctx context.Context, userId int, excludeBlockedBy bool, sort model.FriendListSort) (*model.FriendList, error) {
+	orderBy, err := friendListOrderBy(sort, "")
 	if err != nil {
 		return nil, err
 	}
 
-	var friends []*model.Friend
-	if err := dbx.Select(&friends, query, args...); err != nil {
+	q := friendOfFriendQuery(`
+	SELECT DISTINCT U.user_id, U.name`, excludeBlockedBy, orderBy)
+
+	return r.selectFriendList(ctx, q, userId)
+}
+
+func (r *friendListRepository) GetFriendListOfFriendsByUserIdWithPaging(ctx context.Context, userId int, excludeBlockedBy bool, sort model.FriendListSort, limit, offset int) (*model.FriendList, error) {
+	orderBy, err := friendListOrderBy(sort, "")
+	if err != nil {
 		return nil, err
 	}
 
-	return &model.FriendList{Friends: friends}, nil
+	q := friendOfFriendQuery(`
+	SELECT DISTINCT U.user_id, U.name`, excludeBlockedBy, orderBy+`
+	LIMIT ? OFFSET ?`)
+
+	return r.selectFriendList(ctx, q, userId, limit, offset)
 }
 
-func (r *friendListRepository) GetFriendListOfFriendsByUserIdWithPaging(userId int, excludeUsers []int, limit, offset int) (*model.FriendList, error) {
+// GetFriendListOfFriendsByUserIdWithCursor seeks past lastUserId instead of skipping rows,
+// so pages stay stable while links are added between requests.
+func (r *friendListRepository) GetFriendListOfFriendsByUserIdWithCursor(ctx context.Context, userId int, excludeBlockedBy bool, lastUserId, limit int) (*model.FriendList, error) {
+	q := friendOfFriendQuery(`
+	SELECT DISTINCT U.user_id, U.name`, excludeBlockedBy, `
+	AND U.user_id > ?
+	ORDER BY U.user_id
+	LIMIT ?`)
+
+	return r.selectFriendList(ctx, q, userId, lastUserId, limit)
+}
+
+func (r *friendListRepository) CountFriendListOfFriendsByUserId(ctx context.Context, userId int, excludeBlockedBy bool) (int, error) {
+	q := friendOfFriendQuery(`
+	SELECT COUNT(DISTINCT U.user_id)`, excludeBlockedBy, "")
+
+	var count int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, q, userId).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// blockListCondition selects the users blocked by the user bound to BL.user1_id.
+const blockListCondition = `
+	FROM users AS U INNER JOIN block_list AS BL
+	ON U.user_id = BL.user2_id
+	WHERE BL.user1_id = ?`
+
+func (r *friendListRepository) GetBlockListByUserId(ctx context.Context, userId int) (*model.BlockList, error) {
 	const q = `
-	SELECT DISTINCT U.user_id, U.name
+	SELECT U.user_id, U.name` + blockListCondition + `
+	ORDER BY U.user_id`
+
+	friendList, err := r.selectFriendList(ctx, q, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.BlockList{BlockUsers: friendList.Friends}, nil
+}
+
+func (r *friendListRepository) GetBlockListByUserIdWithPaging(ctx context.Context, userId, limit, offset int) (*model.BlockList, error) {
+	const q = `
+	SELECT U.user_id, U.name` + blockListCondition + `
+	ORDER BY U.user_id
+	LIMIT ? OFFSET ?`
+
+	friendList, err := r.selectFriendList(ctx, q, userId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.BlockList{BlockUsers: friendList.Friends}, nil
+}
+
+func (r *friendListRepository) CountBlockListByUserId(ctx context.Context, userId int) (int, error) {
+	const q = `
+	SELECT COUNT(*)` + blockListCondition
+
+	var count int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, q, userId).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetMutualFriendsByUserId returns every friend of friend with the friend linking them,
+// ordered by the friend of friend. Neither the user, the user's friends nor the users blocked
+// by the user are returned, and a blocked friend does not link anyone. With excludeBlockedBy
+// the users who blocked the user are left out the same way.
+func (r *friendListRepository) GetMutualFriendsByUserId(ctx context.Context, userId int, excludeBlockedBy bool) ([]*model.MutualFriend, error) {
+	q := `
+	SELECT U.user_id, U.name, M.user_id, M.name
 	FROM users AS U
 	INNER JOIN friend_link AS FL
 	ON U.user_id = FL.user2_id
 	INNER JOIN friend_link AS FL2
 	ON FL.user1_id = FL2.user2_id
+	INNER JOIN users AS M
+	ON M.user_id = FL.user1_id
 	WHERE FL2.user1_id = ?
-	AND U.user_id NOT IN (?)
-	LIMIT ? OFFSET ?`
+	AND U.user_id <> FL2.user1_id
+	AND NOT EXISTS (
+		SELECT 1 FROM friend_link AS F
+		WHERE F.user1_id = FL2.user1_id AND F.user2_id = U.user_id
+	)` + notBlockedCondition("FL2.user1_id", "U.user_id", excludeBlockedBy) +
+		notBlockedCondition("FL2.user1_id", "M.user_id", excludeBlockedBy) + `
+	ORDER BY U.user_id, M.user_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mutualFriends []*model.MutualFriend
+	for rows.Next() {
+		mf := &model.MutualFriend{Candidate: &model.Friend{}, Friend: &model.Friend{}}
+		if err := rows.Scan(&mf.Candidate.UserId, &mf.Candidate.Name, &mf.Friend.UserId, &mf.Friend.Name); err != nil {
+			return nil, err
+		}
+
+		mutualFriends = append(mutualFriends, mf)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return mutualFriends, nil
+}
+
+// GetFriendCountByUserIds returns the number of friends of each user. Users without
+// friends are not in the map.
+func (r *friendListRepository) GetFriendCountByUserIds(ctx context.Context, userIds []int) (map[int]int, error) {
+	const q = `
+	SELECT user1_id, COUNT(*)
+	FROM friend_link
+	WHERE user1_id IN (?)
+	GROUP BY user1_id`
 
-	dbx := sqlx.NewDb(r.db, "mysql")
+	query, args, err := sqlx.In(q, userIds)
+	if err != nil {
+		return nil, err
+	}
 
-	query, args, err := sqlx.In(q, userId, excludeUsers, limit, offset)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+
+	counts := make(map[int]int, len(userIds))
+	for rows.Next() {
+		var userId, count int
+		if err := rows.Scan(&userId, &count); err != nil {
+			return nil, err
+		}
+
+		counts[userId] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// GetFriendUserIdsByUserIds returns the friends of each user ordered by user_id.
+func (r *friendListRepository) GetFriendUserIdsByUserIds(ctx context.Context, userIds []int) (map[int][]int, error) {
+	const q = `
+	SELECT user1_id, user2_id
+	FROM friend_link
+	WHERE user1_id IN (?)
+	ORDER BY user1_id, user2_id`
+
+	return r.selectUserIdMap(ctx, q, userIds)
+}
+
+// GetFriendOfUserIdsByUserIds returns the users having each user as a friend ordered by user_id.
+func (r *friendListRepository) GetFriendOfUserIdsByUserIds(ctx context.Context, userIds []int) (map[int][]int, error) {
+	const q = `
+	SELECT user2_id, user1_id
+	FROM friend_link
+	WHERE user2_id IN (?)
+	ORDER BY user2_id, user1_id`
+
+	return r.selectUserIdMap(ctx, q, userIds)
+}
+
+func (r *friendListRepository) GetUserListByUserIds(ctx context.Context, userIds []int) (*model.FriendList, error) {
+	const q = `
+	SELECT user_id, name
+	FROM users
+	WHERE user_id IN (?)
+	ORDER BY user_id`
+
+	query, args, err := sqlx.In(q, userIds)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.selectFriendList(ctx, query, args...)
+}
+
+// maxInUserIds bounds the IN list of one query, so that walking a large frontier does not
+// build a statement of any size.
+const maxInUserIds = 1000
+
+// errNoUserIds is returned for an empty user id list, which no IN list can hold.
+var errNoUserIds = errors.New("empty user id list")
+
+// selectUserIdMap runs q on at most maxInUserIds of userIds at a time. Each key is in one
+// of the batches, so the rows of a key keep the order of q.
+func (r *friendListRepository) selectUserIdMap(ctx context.Context, q string, userIds []int) (map[int][]int, error) {
+	if len(userIds) == 0 {
+		return nil, errNoUserIds
+	}
+
+	userIdMap := make(map[int][]int, len(userIds))
+	for start := 0; start < len(userIds); start += maxInUserIds {
+		end := start + maxInUserIds
+		if end > len(userIds) {
+			end = len(userIds)
+		}
+
+		if err := r.selectUserIdMapBatch(ctx, q, userIds[start:end], userIdMap); err != nil {
+			return nil, err
+		}
+	}
+
+	return userIdMap, nil
+}
+
+func (r *friendListRepository) selectUserIdMapBatch(ctx context.Context, q string, userIds []int, userIdMap map[int][]int) error {
+	query, args, err := sqlx.In(q, userIds)
+	if err != nil {
+		return err
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, userId int
+		if err := rows.Scan(&key, &userId); err != nil {
+			return err
+		}
+
+		userIdMap[key] = append(userIdMap[key], userId)
+	}
+
+	return rows.Err()
+}
+
+func (r *friendListRepository) selectFriendList(ctx context.Context, q string, args ...any) (*model.FriendList, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
 	var friends []*model.Friend
-	if err := dbx.Select(&friends, query, args...); err != nil {
+	for rows.Next() {
+		friend := &model.Friend{}
+		if err := rows.Scan(&friend.UserId, &friend.Name); err != nil {
+			return nil, err
+		}
+
+		friends = append(friends, friend)
+	}
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/model"
+	"problem1/pkg/testutil"
+)
+
+const (
+	benchUsers       = 5000
+	benchEdges       = 100000
+	benchHubUserId   = 1
+	benchHubFriends  = 2000
+	benchBlocks      = 500
+	benchInsertBatch = 1000
+)
+
+// legacyFriendOfFriendQuery is the plan the anti-joins replaced: the one-hop friends and
+// hidden users are loaded into Go and sent back as a NOT IN list.
+const legacyFriendOfFriendQuery = `
+	SELECT DISTINCT U.user_id, U.name
+	FROM users AS U
+	INNER JOIN friend_link AS FL
+	ON U.user_id = FL.user2_id
+	INNER JOIN friend_link AS FL2
+	ON FL.user1_id = FL2.user2_id
+	WHERE FL2.user1_id = ?
+	AND U.user_id NOT IN (?)
+	ORDER BY U.user_id`
+
+// prepareBenchGraph inserts benchEdges friend links, both directions of each friendship,
+// around a hub user with benchHubFriends friends, and benchBlocks blocks in either direction.
+// It returns the friends of friends of the hub worked out from the inserted rows, leaving out
+// the hub, their friends and the users blocked in either direction.
+func prepareBenchGraph(b *testing.B, db *sql.DB) *model.FriendList {
+	b.Helper()
+
+	r := rand.New(rand.NewSource(1))
+
+	users := make([][]any, 0, benchUsers)
+	for userId := 1; userId <= benchUsers; userId++ {
+		users = append(users, []any{userId, "user"})
+	}
+	insertBenchRows(b, db, "users (user_id, name)", users)
+
+	linked := make(map[[2]int]bool, benchEdges)
+	friends := make(map[int][]int, benchUsers)
+	links := make([][]any, 0, benchEdges)
+	link := func(user1Id, user2Id int) {
+		if user1Id == user2Id || linked[[2]int{user1Id, user2Id}] {
+			return
+		}
+		linked[[2]int{user1Id, user2Id}] = true
+		linked[[2]int{user2Id, user1Id}] = true
+		friends[user1Id] = append(friends[user1Id], user2Id)
+		friends[user2Id] = append(friends[user2Id], user1Id)
+		links = append(links, []any{user1Id, user2Id}, []any{user2Id, user1Id})
+	}
+	for _, friendId := range r.Perm(benchUsers - 1)[:benchHubFriends] {
+		link(benchHubUserId, friendId+2)
+	}
+	for len(links) < benchEdges {
+		link(r.Intn(benchUsers)+1, r.Intn(benchUsers)+1)
+	}
+	insertBenchRows(b, db, "friend_link (user1_id, user2_id)", links)
+
+	blocked := make(map[[2]int]bool, benchBlocks)
+	blocks := make([][]any, 0, benchBlocks)
+	for len(blocks) < benchBlocks {
+		other := r.Intn(benchUsers-1) + 2
+		ul := [2]int{benchHubUserId, other}
+		if r.Intn(2) == 0 {
+			ul = [2]int{other, benchHubUserId}
+		}
+		if blocked[ul] {
+			continue
+		}
+		blocked[ul] = true
+		blocks = append(blocks, []any{ul[0], ul[1]})
+	}
+	insertBenchRows(b, db, "block_list (user1_id, user2_id)", blocks)
+
+	reached := make(map[int]bool)
+	for _, friendId := range friends[benchHubUserId] {
+		for _, userId := range friends[friendId] {
+			if userId == benchHubUserId || linked[[2]int{benchHubUserId, userId}] ||
+				blocked[[2]int{benchHubUserId, userId}] || blocked[[2]int{userId, benchHubUserId}] {
+				continue
+			}
+			reached[userId] = true
+		}
+	}
+	want := &model.FriendList{}
+	for userId := 1; userId <= benchUsers; userId++ {
+		if reached[userId] {
+			want.Friends = append(want.Friends, &model.Friend{UserId: userId, Name: "user"})
+		}
+	}
+
+	return want
+}
+
+func insertBenchRows(b *testing.B, db *sql.DB, table string, rows [][]any) {
+	b.Helper()
+
+	for start := 0; start < len(rows); start += benchInsertBatch {
+		end := start + benchInsertBatch
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		placeholder := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(rows[start])), ", ") + ")"
+		values := make([]string, 0, end-start)
+		args := make([]any, 0, (end-start)*len(rows[start]))
+		for _, row := range rows[start:end] {
+			values = append(values, placeholder)
+			args = append(args, row...)
+		}
+
+		q := "INSERT INTO " + table + " VALUES " + strings.Join(values, ", ")
+		testutil.ValidateSQLArgs(b, q, args...)
+		testutil.ExecSQL(b, db, q, args...)
+	}
+}
+
+// legacyGetFriendListOfFriends is GetFriendListOfFriendsByUserId as it was before the anti-joins,
+// with the user in the NOT IN list as well since the friendships go both ways.
+func legacyGetFriendListOfFriends(ctx context.Context, db *sql.DB, flr FriendListRepository, userId int) (*model.FriendList, error) {
+	oneHopFriends, err := flr.GetOneHopFriendsUserIdList(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	blockUsers, err := flr.GetBlockUsersIdList(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	blockedBy, err := flr.GetBlockedByUsersIdList(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	excludeUsers := append(append(append(oneHopFriends, blockUsers...), blockedBy...), userId)
+
+	query, args, err := sqlx.In(legacyFriendOfFriendQuery, userId, excludeUsers)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&friendListRepository{db: db}).selectFriendList(ctx, query, args...)
+}
+
+func Benchmark_friendListRepository_GetFriendListOfFriendsByUserId(b *testing.B) {
+	db := testutil.PrepareMySQL(b)
+	flr := NewFriendListRepository(db)
+	ctx := context.Background()
+	want := prepareBenchGraph(b, db)
+
+	got, err := legacyGetFriendListOfFriends(ctx, db, flr, benchHubUserId)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if !assert.Equal(b, want, got, "the NOT IN plan must return the friends of friends") {
+		b.FailNow()
+	}
+	got, err = flr.GetFriendListOfFriendsByUserId(ctx, benchHubUserId, true, model.FriendListSortUserId)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if !assert.Equal(b, want, got, "the anti-joins must return the friends of friends") {
+		b.FailNow()
+	}
+	count, err := flr.CountFriendListOfFriendsByUserId(ctx, benchHubUserId, true)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if !assert.Equal(b, len(want.Friends), count) {
+		b.FailNow()
+	}
+
+	gr, err := NewFriendListGraphRepository(ctx, db)
+	if err != nil {
+		b.Fatal(err)
+	}
+	got, err = gr.GetFriendListOfFriendsByUserId(ctx, benchHubUserId, true, model.FriendListSortUserId)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if !assert.Equal(b, want, got, "the graph must return the friends of friends") {
+		b.FailNow()
+	}
+
+	b.Run("not_in", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := legacyGetFriendListOfFriends(ctx, db, flr, benchHubUserId); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("not_exists", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := flr.GetFriendListOfFriendsByUserId(ctx, benchHubUserId, true, model.FriendListSortUserId); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("not_exists_count", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := flr.CountFriendListOfFriendsByUserId(ctx, benchHubUserId, true); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("graph", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := gr.GetFriendListOfFriendsByUserId(ctx, benchHubUserId, true, model.FriendListSortUserId); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("graph_count", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := gr.CountFriendListOfFriendsByUserId(ctx, benchHubUserId, true); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,17 +15,67 @@ import (
 type friendListRepositoryTest struct {
 	db  *sql.DB
 	flr FriendListRepository
+	ctx context.Context
 }
 
-func newFriendListRepositoryTest(t *testing.T) *friendListRepositoryTest {
+// friendListRepositoryImpls are the implementations the friend list repository tests run
+// against, so that the graph repository keeps answering as MySQL does.
+var friendListRepositoryImpls = []struct {
+	name string
+	new  func(ctx context.Context, db *sql.DB) (FriendListRepository, error)
+}{
+	{
+		name: "mysql",
+		new: func(_ context.Context, db *sql.DB) (FriendListRepository, error) {
+			return NewFriendListRepository(db), nil
+		},
+	},
+	{
+		name: "graph",
+		new:  NewFriendListGraphRepository,
+	},
+}
+
+// forEachFriendListRepository runs fn as a subtest against each of friendListRepositoryImpls.
+func forEachFriendListRepository(t *testing.T, fn func(t *testing.T, rt *friendListRepositoryTest)) {
+	t.Helper()
+
+	for _, impl := range friendListRepositoryImpls {
+		impl := impl
+		t.Run(impl.name, func(t *testing.T) {
+			db := testutil.PrepareMySQL(t)
+			ctx := context.Background()
+			flr, err := impl.new(ctx, db)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fn(t, &friendListRepositoryTest{
+				db:  db,
+				flr: flr,
+				ctx: ctx,
+			})
+		})
+	}
+}
+
+// runFriendListRepositoryTest is forEachFriendListRepository in the subtest name.
+func runFriendListRepositoryTest(t *testing.T, name string, fn func(t *testing.T, rt *friendListRepositoryTest)) {
 	t.Helper()
 
-	db := testutil.PrepareMySQL(t)
-	flr := NewFriendListRepository(db)
+	t.Run(name, func(t *testing.T) {
+		forEachFriendListRepository(t, fn)
+	})
+}
+
+// reload makes the graph repository see the rows inserted by the helpers below.
+func (r *friendListRepositoryTest) reload(t *testing.T) {
+	t.Helper()
 
-	return &friendListRepositoryTest{
-		db:  db,
-		flr: flr,
+	if g, ok := r.flr.(*friendListGraphRepository); ok {
+		if err := g.Load(r.ctx); err != nil {
+			t.Fatal(err)
+		}
 	}
 }
 
@@ -81,6 +133,7 @@ func (r *friendListRepositoryTest) insertTestUserList(t *testing.T, db *sql.DB,
 	}
 	testutil.ValidateSQLArgs(t, q, testRecord...)
 	testutil.ExecSQL(t, db, q, testRecord...)
+	r.reload(t)
 }
 
 type userLink struct {
@@ -119,6 +172,7 @@ func (r *friendListRepositoryTest) insertTestFriendLink(t *testing.T, db *sql.DB
 	}
 	testutil.ValidateSQLArgs(t, q, testRecord...)
 	testutil.ExecSQL(t, db, q, testRecord...)
+	r.reload(t)
 }
 
 func (r *friendListRepositoryTest) insertTestBlockList(t *testing.T, db *sql.DB, ul userLink) {
@@ -134,19 +188,23 @@ func (r *friendListRepositoryTest) insertTestBlockList(t *testing.T, db *sql.DB,
 	}
 	testutil.ValidateSQLArgs(t, q, testRecord...)
 	testutil.ExecSQL(t, db, q, testRecord...)
+	r.reload(t)
 }
 
 func Test_friendListRepository_InsertUserLink(t *testing.T) {
 	tests := []struct {
-		name    string
-		user1Id int
-		user2Id int
-		table   string
-		want    []int
-		wantErr bool
+		name      string
+		prepare   func(*friendListRepositoryTest)
+		user1Id   int
+		user2Id   int
+		table     string
+		want      []int
+		wantErr   bool
+		wantErrIs error
 	}{
 		{
 			name:    "ok: friend_link",
+			prepare: func(rt *friendListRepositoryTest) {},
 			user1Id: testutil.UserIDForDebug,
 			user2Id: 111111,
 			table:   "friend_link",
@@ -155,14 +213,46 @@ func Test_friendListRepository_InsertUserLink(t *testing.T) {
 		},
 		{
 			name:    "ok: block_list",
+			prepare: func(rt *friendListRepositoryTest) {},
 			user1Id: testutil.UserIDForDebug,
 			user2Id: 111111,
 			table:   "block_list",
 			want:    []int{111111},
 			wantErr: false,
 		},
+		{
+			name: "ng: friend_link duplicated",
+			prepare: func(rt *friendListRepositoryTest) {
+				rt.insertTestFriendLink(t, rt.db, userLink{
+					user1Id: testutil.UserIDForDebug,
+					user2Id: 111111,
+				})
+			},
+			user1Id:   testutil.UserIDForDebug,
+			user2Id:   111111,
+			table:     "friend_link",
+			want:      []int{111111},
+			wantErr:   true,
+			wantErrIs: ErrUserLinkDuplicated,
+		},
+		{
+			name: "ng: block_list duplicated",
+			prepare: func(rt *friendListRepositoryTest) {
+				rt.insertTestBlockList(t, rt.db, userLink{
+					user1Id: testutil.UserIDForDebug,
+					user2Id: 111111,
+				})
+			},
+			user1Id:   testutil.UserIDForDebug,
+			user2Id:   111111,
+			table:     "block_list",
+			want:      []int{111111},
+			wantErr:   true,
+			wantErrIs: ErrUserLinkDuplicated,
+		},
 		{
 			name:    "ng: table invalid",
+			prepare: func(rt *friendListRepositoryTest) {},
 			user1Id: testutil.UserIDForDebug,
 			user2Id: 111111,
 			table:   "invalid",
@@ -172,25 +262,27 @@ func Test_friendListRepository_InsertUserLink(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			rt := newFriendListRepositoryTest(t)
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
+			tt.prepare(rt)
 
-			tx := testutil.BeginTx(t, rt.db)
-			err := rt.flr.InsertUserLink(tt.user1Id, tt.user2Id, tt.table)
+			err := NewTransaction(rt.db).DoInTx(rt.ctx, func(ctx context.Context) error {
+				return rt.flr.InsertUserLink(ctx, tt.user1Id, tt.user2Id, tt.table)
+			})
 			if (err != nil) != tt.wantErr {
-				testutil.RollBackTx(t, tx)
-				t.Fatalf("CheckUserExist() error = %v, wantErr = %v", err, tt.wantErr)
+				t.Fatalf("InsertUserLink() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
 			}
-			testutil.CommitTx(t, tx)
 
 			if tt.table == "friend_link" {
-				got, err := rt.flr.GetOneHopFriendsUserIdList(tt.user1Id)
+				got, err := rt.flr.GetOneHopFriendsUserIdList(rt.ctx, tt.user1Id)
 				if err != nil {
 					t.Fatal(err)
 				}
 				assert.Equal(t, tt.want, got)
 			} else {
-				got, err := rt.flr.GetBlockUsersIdList(tt.user1Id)
+				got, err := rt.flr.GetBlockUsersIdList(rt.ctx, tt.user1Id)
 				if err != nil {
 					t.Fatal(err)
 				}
@@ -200,6 +292,135 @@ func Test_friendListRepository_InsertUserLink(t *testing.T) {
 	}
 }
 
+func Test_friendListRepository_DeleteUserLink(t *testing.T) {
+	tests := []struct {
+		name      string
+		prepare   func(*friendListRepositoryTest)
+		user2Id   int
+		table     string
+		want      []int
+		wantUser2 []int
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name: "ok: friend_link",
+			prepare: func(rt *friendListRepositoryTest) {
+				for _, ul := range newTestUserLink() {
+					rt.insertTestFriendLink(t, rt.db, ul)
+				}
+			},
+			user2Id: 111111,
+			table:   "friend_link",
+			want:    []int{222222, 333333},
+			wantErr: false,
+		},
+		{
+			name: "ok: friend_link in both directions",
+			prepare: func(rt *friendListRepositoryTest) {
+				for _, ul := range []userLink{
+					{user1Id: testutil.UserIDForDebug, user2Id: 111111},
+					{user1Id: 111111, user2Id: testutil.UserIDForDebug},
+					{user1Id: testutil.UserIDForDebug, user2Id: 222222},
+					{user1Id: 111111, user2Id: 333333},
+				} {
+					rt.insertTestFriendLink(t, rt.db, ul)
+				}
+			},
+			user2Id:   111111,
+			table:     "friend_link",
+			want:      []int{222222},
+			wantUser2: []int{333333},
+			wantErr:   false,
+		},
+		{
+			name: "ok: block_list",
+			prepare: func(rt *friendListRepositoryTest) {
+				for _, ul := range newTestUserLink() {
+					rt.insertTestBlockList(t, rt.db, ul)
+				}
+			},
+			user2Id: 111111,
+			table:   "block_list",
+			want:    []int{222222, 333333},
+			wantErr: false,
+		},
+		{
+			name: "ok: block_list leaves the block the other way",
+			prepare: func(rt *friendListRepositoryTest) {
+				for _, ul := range []userLink{
+					{user1Id: testutil.UserIDForDebug, user2Id: 111111},
+					{user1Id: 111111, user2Id: testutil.UserIDForDebug},
+				} {
+					rt.insertTestBlockList(t, rt.db, ul)
+				}
+			},
+			user2Id:   111111,
+			table:     "block_list",
+			want:      nil,
+			wantUser2: []int{testutil.UserIDForDebug},
+			wantErr:   false,
+		},
+		{
+			name:      "ng: friend_link not exist",
+			prepare:   func(rt *friendListRepositoryTest) {},
+			user2Id:   111111,
+			table:     "friend_link",
+			want:      nil,
+			wantErr:   true,
+			wantErrIs: ErrUserLinkNotFound,
+		},
+		{
+			name:      "ng: block_list not exist",
+			prepare:   func(rt *friendListRepositoryTest) {},
+			user2Id:   111111,
+			table:     "block_list",
+			want:      nil,
+			wantErr:   true,
+			wantErrIs: ErrUserLinkNotFound,
+		},
+		{
+			name:    "ng: table invalid",
+			prepare: func(rt *friendListRepositoryTest) {},
+			user2Id: 111111,
+			table:   "invalid",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
+			tt.prepare(rt)
+
+			err := rt.flr.DeleteUserLink(rt.ctx, testutil.UserIDForDebug, tt.user2Id, tt.table)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeleteUserLink() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+			}
+
+			linked := rt.flr.GetOneHopFriendsUserIdList
+			if tt.table != "friend_link" {
+				linked = rt.flr.GetBlockUsersIdList
+			}
+			got, err := linked(rt.ctx, testutil.UserIDForDebug)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+			if tt.wantUser2 != nil {
+				got, err := linked(rt.ctx, tt.user2Id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, tt.wantUser2, got)
+			}
+		})
+	}
+}
+
 func Test_friendListRepository_CheckUserExist(t *testing.T) {
 	userId := testutil.UserIDForDebug
 	tests := []struct {
@@ -229,11 +450,10 @@ func Test_friendListRepository_CheckUserExist(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			rt := newFriendListRepositoryTest(t)
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
 			tt.prepare(rt)
 
-			got, err := rt.flr.CheckUserExist(userId)
+			got, err := rt.flr.CheckUserExist(rt.ctx, userId)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("CheckUserExist() error = %v, wantErr = %v", err, tt.wantErr)
 			}
@@ -298,11 +518,10 @@ func Test_friendListRepository_CheckUserLink(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			rt := newFriendListRepositoryTest(t)
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
 			tt.prepare(rt)
 
-			err := rt.flr.CheckUserLink(userId, tt.user2Id, tt.table)
+			err := rt.flr.CheckUserLink(rt.ctx, userId, tt.user2Id, tt.table)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("CheckUserLink() error = %v, wantErr = %v", err, tt.wantErr)
 			}
@@ -347,11 +566,10 @@ func Test_friendListRepository_GetOneHopFriendsUserIdList(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			rt := newFriendListRepositoryTest(t)
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
 			tt.prepare(rt)
 
-			got, err := rt.flr.GetOneHopFriendsUserIdList(userId)
+			got, err := rt.flr.GetOneHopFriendsUserIdList(rt.ctx, userId)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("GetOneHopFrinedsUserIdList() error = %v, wantErr = %v", err, tt.wantErr)
 			}
@@ -420,11 +638,10 @@ func Test_friendListRepository_GetBlockUsersIdList(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			rt := newFriendListRepositoryTest(t)
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
 			tt.prepare(rt)
 
-			got, err := rt.flr.GetBlockUsersIdList(userId)
+			got, err := rt.flr.GetBlockUsersIdList(rt.ctx, userId)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("GetBlockUsersIdList() error = %v, wantErr = %v", err, tt.wantErr)
 			}
@@ -472,11 +689,10 @@ func Test_friendListRepository_GetFriendListByUserId(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			rt := newFriendListRepositoryTest(t)
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
 			tt.prepare(rt)
 
-			got, err := rt.flr.GetFriendListByUserId(userId)
+			got, err := rt.flr.GetFriendListByUserId(rt.ctx, userId, model.FriendListSortUserId)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("GetFriendListByUserId() error = %v, wantErr = %v", err, tt.wantErr)
 			}
@@ -485,194 +701,459 @@ func Test_friendListRepository_GetFriendListByUserId(t *testing.T) {
 	}
 }
 
-func Test_friendListRepository_GetFriendListByUserIdExcludingBlockUsers(t *testing.T) {
+func Test_friendListRepository_GetFriendListByUserId_Sort(t *testing.T) {
 	userId := testutil.UserIDForDebug
-	testUsers := newTestUsers()
-	testUserLink := newTestUserLink()
+	testUsers := []testUser{
+		{
+			userId: userId,
+			name:   testutil.UserNameForDebug,
+		},
+		{
+			userId: 111111,
+			name:   "藤井 太郎",
+		},
+		{
+			userId: 222222,
+			name:   "あべ 花子",
+		},
+		{
+			userId: 333333,
+			name:   "Alice",
+		},
+	}
 
 	tests := []struct {
-		name       string
-		prepare    func(*friendListRepositoryTest)
-		blockUsers []int
-		want       *model.FriendList
-		wantErr    bool
+		name string
+		sort model.FriendListSort
+		want []int
 	}{
 		{
-			name: "ok: 1 friend blocked",
-			prepare: func(rt *friendListRepositoryTest) {
-				for _, tu := range testUsers {
-					rt.insertTestUserList(t, rt.db, tu)
-				}
-				for _, ul := range testUserLink {
-					rt.insertTestFriendLink(t, rt.db, ul)
-				}
-			},
-			blockUsers: []int{333333},
-			want:       newFriendList(),
-			wantErr:    false,
+			name: "ok: userId",
+			sort: model.FriendListSortUserId,
+			want: []int{111111, 222222, 333333},
 		},
 		{
-			name: "ok: all friends blocked",
-			prepare: func(rt *friendListRepositoryTest) {
-				for _, tu := range testUsers {
-					rt.insertTestUserList(t, rt.db, tu)
-				}
-				for _, ul := range testUserLink {
-					rt.insertTestFriendLink(t, rt.db, ul)
-				}
-			},
-			blockUsers: []int{111111, 222222, 333333},
-			want: &model.FriendList{
-				Friends: []*model.Friend(nil),
-			},
-			wantErr: false,
+			name: "ok: name in Japanese collation",
+			sort: model.FriendListSortName,
+			want: []int{333333, 222222, 111111},
+		},
+		{
+			name: "ok: name descending",
+			sort: model.FriendListSortNameDesc,
+			want: []int{111111, 222222, 333333},
 		},
 		{
-			name:       "ng: blockUsers nil",
-			prepare:    func(rt *friendListRepositoryTest) {},
-			blockUsers: nil,
-			want:       nil,
-			wantErr:    true,
+			name: "ok: addedAt",
+			sort: model.FriendListSortAddedAt,
+			want: []int{333333, 111111, 222222},
 		},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			rt := newFriendListRepositoryTest(t)
-			tt.prepare(rt)
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
+			for _, tu := range testUsers {
+				rt.insertTestUserList(t, rt.db, tu)
+			}
+			for _, friendId := range []int{333333, 111111, 222222} {
+				rt.insertTestFriendLink(t, rt.db, userLink{
+					user1Id: userId,
+					user2Id: friendId,
+				})
+			}
 
-			got, err := rt.flr.GetFriendListByUserIdExcludingBlockUsers(userId, tt.blockUsers)
-			if (err != nil) != tt.wantErr {
-				t.Fatalf("GetFriendListByUserIdExcludingBlockUsers() error = %v, wantErr = %v", err, tt.wantErr)
+			got, err := rt.flr.GetFriendListByUserId(rt.ctx, userId, tt.sort)
+			if err != nil {
+				t.Fatal(err)
 			}
-			assert.Equal(t, tt.want, got)
+
+			var gotUserIds []int
+			for _, f := range got.Friends {
+				gotUserIds = append(gotUserIds, f.UserId)
+			}
+			assert.Equal(t, tt.want, gotUserIds)
 		})
 	}
 }
 
-func Test_friendListRepository_GetFriendListOfFriendsByUserId(t *testing.T) {
-	userId := testutil.UserIDForDebug
-	testUsers := newTestUsers()
-	testUserLink := []userLink{
+func Test_friendListOrderBy(t *testing.T) {
+	tests := []struct {
+		name    string
+		sort    model.FriendListSort
+		addedAt string
+		want    string
+		wantErr error
+	}{
 		{
-			user1Id: 123456789,
-			user2Id: 444444,
+			name:    "ok: default",
+			sort:    "",
+			addedAt: friendLinkAddedAt,
+			want:    "ORDER BY U.user_id",
+			wantErr: nil,
 		},
 		{
-			user1Id: 444444,
-			user2Id: 111111,
+			name:    "ok: name",
+			sort:    model.FriendListSortName,
+			addedAt: friendLinkAddedAt,
+			want:    "ORDER BY U.name COLLATE utf8mb4_ja_0900_as_cs, U.user_id",
+			wantErr: nil,
 		},
 		{
-			user1Id: 444444,
-			user2Id: 222222,
+			name:    "ok: name descending",
+			sort:    model.FriendListSortNameDesc,
+			addedAt: friendLinkAddedAt,
+			want:    "ORDER BY U.name COLLATE utf8mb4_ja_0900_as_cs DESC, U.user_id",
+			wantErr: nil,
 		},
 		{
-			user1Id: 444444,
-			user2Id: 333333,
+			name:    "ok: addedAt",
+			sort:    model.FriendListSortAddedAt,
+			addedAt: friendLinkAddedAt,
+			want:    "ORDER BY FL.created_at, FL.id, U.user_id",
+			wantErr: nil,
+		},
+		{
+			name:    "ng: addedAt without link",
+			sort:    model.FriendListSortAddedAt,
+			addedAt: "",
+			want:    "",
+			wantErr: ErrSortNotSupported,
+		},
+		{
+			name:    "ng: unknown sort never reaches the query",
+			sort:    "name; DROP TABLE users",
+			addedAt: friendLinkAddedAt,
+			want:    "",
+			wantErr: ErrSortNotSupported,
 		},
 	}
-	testUserLink2 := newTestUserLink()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := friendListOrderBy(tt.sort, tt.addedAt)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, strings.TrimSpace(got))
+		})
+	}
+}
+
+func Test_friendListRepository_GetFriendListByUserIdExcludingBlockUsers(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	testUsers := newTestUsers()
+	testUserLink := newTestUserLink()
 
 	tests := []struct {
-		name         string
-		prepare      func(*friendListRepositoryTest)
-		excludeUsers []int
-		want         *model.FriendList
-		wantErr      bool
+		name             string
+		blockList        []userLink
+		excludeBlockedBy bool
+		want             *model.FriendList
+		wantErr          bool
 	}{
 		{
-			name: "ok: 1 friend excluded",
-			prepare: func(rt *friendListRepositoryTest) {
-				for _, tu := range testUsers {
-					rt.insertTestUserList(t, rt.db, tu)
-				}
-				rt.insertTestUserList(t, rt.db, testUser{
-					userId: 444444,
-					name:   "piyo",
-				})
-				for _, ul := range testUserLink {
-					rt.insertTestFriendLink(t, rt.db, ul)
-				}
-			},
-			excludeUsers: []int{333333},
-			want:         newFriendList(),
-			wantErr:      false,
-		},
-		{
-			name: "ok: all friends excluded",
-			prepare: func(rt *friendListRepositoryTest) {
-				for _, tu := range testUsers {
-					rt.insertTestUserList(t, rt.db, tu)
-				}
-				rt.insertTestUserList(t, rt.db, testUser{
-					userId: 444444,
-					name:   "piyo",
-				})
-				for _, ul := range testUserLink {
-					rt.insertTestFriendLink(t, rt.db, ul)
-				}
-			},
-			excludeUsers: []int{111111, 222222, 333333},
-			want: &model.FriendList{
-				Friends: []*model.Friend(nil),
-			},
-			wantErr: false,
+			name:             "ok: 1 friend blocked",
+			blockList:        []userLink{{user1Id: userId, user2Id: 333333}},
+			excludeBlockedBy: false,
+			want:             newFriendList(),
+			wantErr:          false,
 		},
 		{
-			name: "ok: have no 2hop friend",
-			prepare: func(rt *friendListRepositoryTest) {
-				for _, tu := range testUsers {
-					rt.insertTestUserList(t, rt.db, tu)
-				}
-				for _, ul := range testUserLink2 {
-					rt.insertTestFriendLink(t, rt.db, ul)
-				}
+			name: "ok: all friends blocked",
+			blockList: []userLink{
+				{user1Id: userId, user2Id: 111111},
+				{user1Id: userId, user2Id: 222222},
+				{user1Id: userId, user2Id: 333333},
 			},
-			excludeUsers: []int{444444},
+			excludeBlockedBy: false,
 			want: &model.FriendList{
 				Friends: []*model.Friend(nil),
 			},
 			wantErr: false,
 		},
 		{
-			name: "ok: have no friend",
-			prepare: func(rt *friendListRepositoryTest) {
-				for _, tu := range testUsers {
-					rt.insertTestUserList(t, rt.db, tu)
-				}
-			},
-			excludeUsers: []int{111111},
+			name:             "ok: blocked by a friend is kept one way",
+			blockList:        []userLink{{user1Id: 333333, user2Id: userId}},
+			excludeBlockedBy: false,
 			want: &model.FriendList{
-				Friends: []*model.Friend(nil),
+				Friends: append(newFriendList().Friends, &model.Friend{UserId: 333333, Name: "bar"}),
 			},
 			wantErr: false,
 		},
 		{
-			name:         "ng: excludeUsers nil",
-			prepare:      func(rt *friendListRepositoryTest) {},
-			excludeUsers: nil,
-			want:         nil,
-			wantErr:      true,
+			name:             "ok: blocked by a friend is excluded mutually",
+			blockList:        []userLink{{user1Id: 333333, user2Id: userId}},
+			excludeBlockedBy: true,
+			want:             newFriendList(),
+			wantErr:          false,
 		},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			rt := newFriendListRepositoryTest(t)
-			tt.prepare(rt)
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
+			for _, tu := range testUsers {
+				rt.insertTestUserList(t, rt.db, tu)
+			}
+			for _, ul := range testUserLink {
+				rt.insertTestFriendLink(t, rt.db, ul)
+			}
+			for _, bl := range tt.blockList {
+				rt.insertTestBlockList(t, rt.db, bl)
+			}
 
-			got, err := rt.flr.GetFriendListOfFriendsByUserId(userId, tt.excludeUsers)
+			got, err := rt.flr.GetFriendListByUserIdExcludingBlockUsers(rt.ctx, userId, tt.excludeBlockedBy, model.FriendListSortUserId)
 			if (err != nil) != tt.wantErr {
-				t.Fatalf("GetFriendListOfFriendsByUserId() error = %v, wantErr = %v", err, tt.wantErr)
+				t.Fatalf("GetFriendListByUserIdExcludingBlockUsers() error = %v, wantErr = %v", err, tt.wantErr)
 			}
 			assert.Equal(t, tt.want, got)
 		})
 	}
 }
 
-func Test_friendListRepository_GetFriendListOfFriendsByUserIdWithPaging(t *testing.T) {
+func Test_friendListRepository_GetFriendListByUserIdWithPaging(t *testing.T) {
 	userId := testutil.UserIDForDebug
-	testUsers := newTestUsers()
+
+	tests := []struct {
+		name    string
+		limit   int
+		offset  int
+		want    *model.FriendList
+		wantErr bool
+	}{
+		{
+			name:    "ok: limit",
+			limit:   2,
+			offset:  0,
+			want:    newFriendList(),
+			wantErr: false,
+		},
+		{
+			name:   "ok: offset skips the blocked friend",
+			limit:  2,
+			offset: 1,
+			want: &model.FriendList{
+				Friends: []*model.Friend{{UserId: 222222, Name: "fuga"}},
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
+			for _, tu := range newTestUsers() {
+				rt.insertTestUserList(t, rt.db, tu)
+			}
+			for _, ul := range newTestUserLink() {
+				rt.insertTestFriendLink(t, rt.db, ul)
+			}
+			rt.insertTestBlockList(t, rt.db, userLink{user1Id: userId, user2Id: 333333})
+
+			got, err := rt.flr.GetFriendListByUserIdWithPaging(rt.ctx, userId, false, model.FriendListSortUserId, tt.limit, tt.offset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetFriendListByUserIdWithPaging() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListRepository_CountFriendListByUserId(t *testing.T) {
+	userId := testutil.UserIDForDebug
+
+	tests := []struct {
+		name             string
+		prepare          func(*friendListRepositoryTest)
+		excludeBlockedBy bool
+		want             int
+		wantErr          bool
+	}{
+		{
+			name: "ok: blocked friend not counted",
+			prepare: func(rt *friendListRepositoryTest) {
+				rt.insertTestBlockList(t, rt.db, userLink{user1Id: userId, user2Id: 333333})
+				rt.insertTestBlockList(t, rt.db, userLink{user1Id: 222222, user2Id: userId})
+			},
+			excludeBlockedBy: false,
+			want:             2,
+			wantErr:          false,
+		},
+		{
+			name: "ok: friend who blocked the user not counted mutually",
+			prepare: func(rt *friendListRepositoryTest) {
+				rt.insertTestBlockList(t, rt.db, userLink{user1Id: userId, user2Id: 333333})
+				rt.insertTestBlockList(t, rt.db, userLink{user1Id: 222222, user2Id: userId})
+			},
+			excludeBlockedBy: true,
+			want:             1,
+			wantErr:          false,
+		},
+	}
+
+	for _, tt := range tests {
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
+			for _, tu := range newTestUsers() {
+				rt.insertTestUserList(t, rt.db, tu)
+			}
+			for _, ul := range newTestUserLink() {
+				rt.insertTestFriendLink(t, rt.db, ul)
+			}
+			tt.prepare(rt)
+
+			got, err := rt.flr.CountFriendListByUserId(rt.ctx, userId, tt.excludeBlockedBy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CountFriendListByUserId() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListRepository_GetFriendListOfFriendsByUserId(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	testUsers := newTestUsers()
+	testUserLink := []userLink{
+		{
+			user1Id: userId,
+			user2Id: 444444,
+		},
+		{
+			user1Id: 444444,
+			user2Id: 111111,
+		},
+		{
+			user1Id: 444444,
+			user2Id: 222222,
+		},
+		{
+			user1Id: 444444,
+			user2Id: 333333,
+		},
+	}
+	testUserLink2 := newTestUserLink()
+	prepareGraph := func(rt *friendListRepositoryTest) {
+		for _, tu := range testUsers {
+			rt.insertTestUserList(t, rt.db, tu)
+		}
+		rt.insertTestUserList(t, rt.db, testUser{
+			userId: 444444,
+			name:   "piyo",
+		})
+		for _, ul := range testUserLink {
+			rt.insertTestFriendLink(t, rt.db, ul)
+		}
+	}
+	allFriendsOfFriends := &model.FriendList{
+		Friends: append(newFriendList().Friends, &model.Friend{
+			UserId: 333333,
+			Name:   "bar",
+		}),
+	}
+
+	tests := []struct {
+		name             string
+		prepare          func(*friendListRepositoryTest)
+		excludeBlockedBy bool
+		want             *model.FriendList
+		wantErr          bool
+	}{
+		{
+			name:    "ok: all friends of friends",
+			prepare: prepareGraph,
+			want:    allFriendsOfFriends,
+			wantErr: false,
+		},
+		{
+			name: "ok: 1 friend excluded",
+			prepare: func(rt *friendListRepositoryTest) {
+				prepareGraph(rt)
+				rt.insertTestFriendLink(t, rt.db, userLink{
+					user1Id: userId,
+					user2Id: 333333,
+				})
+			},
+			want:    newFriendList(),
+			wantErr: false,
+		},
+		{
+			name: "ok: blocked user excluded",
+			prepare: func(rt *friendListRepositoryTest) {
+				prepareGraph(rt)
+				rt.insertTestBlockList(t, rt.db, userLink{
+					user1Id: userId,
+					user2Id: 333333,
+				})
+			},
+			want:    newFriendList(),
+			wantErr: false,
+		},
+		{
+			name: "ok: user who blocked me is kept",
+			prepare: func(rt *friendListRepositoryTest) {
+				prepareGraph(rt)
+				rt.insertTestBlockList(t, rt.db, userLink{
+					user1Id: 333333,
+					user2Id: userId,
+				})
+			},
+			excludeBlockedBy: false,
+			want:             allFriendsOfFriends,
+			wantErr:          false,
+		},
+		{
+			name: "ok: user who blocked me excluded",
+			prepare: func(rt *friendListRepositoryTest) {
+				prepareGraph(rt)
+				rt.insertTestBlockList(t, rt.db, userLink{
+					user1Id: 333333,
+					user2Id: userId,
+				})
+			},
+			excludeBlockedBy: true,
+			want:             newFriendList(),
+			wantErr:          false,
+		},
+		{
+			name: "ok: have no 2hop friend",
+			prepare: func(rt *friendListRepositoryTest) {
+				for _, tu := range testUsers {
+					rt.insertTestUserList(t, rt.db, tu)
+				}
+				for _, ul := range testUserLink2 {
+					rt.insertTestFriendLink(t, rt.db, ul)
+				}
+			},
+			want: &model.FriendList{
+				Friends: []*model.Friend(nil),
+			},
+			wantErr: false,
+		},
+		{
+			name: "ok: have no friend",
+			prepare: func(rt *friendListRepositoryTest) {
+				for _, tu := range testUsers {
+					rt.insertTestUserList(t, rt.db, tu)
+				}
+			},
+			want: &model.FriendList{
+				Friends: []*model.Friend(nil),
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
+			tt.prepare(rt)
+
+			got, err := rt.flr.GetFriendListOfFriendsByUserId(rt.ctx, userId, tt.excludeBlockedBy, model.FriendListSortUserId)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetFriendListOfFriendsByUserId() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListRepository_GetFriendListOfFriendsByUserIdWithPaging(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	testUsers := newTestUsers()
 	testUserLink := []userLink{
 		{
 			user1Id: 123456789,
@@ -693,13 +1174,12 @@ func Test_friendListRepository_GetFriendListOfFriendsByUserIdWithPaging(t *testi
 	}
 
 	tests := []struct {
-		name         string
-		prepare      func(*friendListRepositoryTest)
-		excludeUsers []int
-		limit        int
-		offset       int
-		want         *model.FriendList
-		wantErr      bool
+		name    string
+		prepare func(*friendListRepositoryTest)
+		limit   int
+		offset  int
+		want    *model.FriendList
+		wantErr bool
 	}{
 		{
 			name: "ok: limit",
@@ -715,11 +1195,10 @@ func Test_friendListRepository_GetFriendListOfFriendsByUserIdWithPaging(t *testi
 					rt.insertTestFriendLink(t, rt.db, ul)
 				}
 			},
-			excludeUsers: []int{444444},
-			limit:        2,
-			offset:       0,
-			want:         newFriendList(),
-			wantErr:      false,
+			limit:   2,
+			offset:  0,
+			want:    newFriendList(),
+			wantErr: false,
 		},
 		{
 			name: "ok: offset",
@@ -735,9 +1214,8 @@ func Test_friendListRepository_GetFriendListOfFriendsByUserIdWithPaging(t *testi
 					rt.insertTestFriendLink(t, rt.db, ul)
 				}
 			},
-			excludeUsers: []int{444444},
-			limit:        3,
-			offset:       2,
+			limit:  3,
+			offset: 2,
 			want: &model.FriendList{
 				Friends: []*model.Friend{
 					{
@@ -748,25 +1226,775 @@ func Test_friendListRepository_GetFriendListOfFriendsByUserIdWithPaging(t *testi
 			},
 			wantErr: false,
 		},
+	}
+
+	for _, tt := range tests {
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
+			tt.prepare(rt)
+
+			got, err := rt.flr.GetFriendListOfFriendsByUserIdWithPaging(rt.ctx, userId, false, model.FriendListSortUserId, tt.limit, tt.offset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetFriendListOfFriendsByUserIdWithPaging() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListRepository_GetFriendListOfFriendsByUserIdWithCursor(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	testUsers := newTestUsers()
+	testUserLink := []userLink{
+		{
+			user1Id: 123456789,
+			user2Id: 444444,
+		},
+		{
+			user1Id: 444444,
+			user2Id: 111111,
+		},
+		{
+			user1Id: 444444,
+			user2Id: 222222,
+		},
+		{
+			user1Id: 444444,
+			user2Id: 333333,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		prepare    func(*friendListRepositoryTest)
+		lastUserId int
+		limit      int
+		want       *model.FriendList
+		wantErr    bool
+	}{
+		{
+			name: "ok: first page",
+			prepare: func(rt *friendListRepositoryTest) {
+				for _, tu := range testUsers {
+					rt.insertTestUserList(t, rt.db, tu)
+				}
+				rt.insertTestUserList(t, rt.db, testUser{
+					userId: 444444,
+					name:   "piyo",
+				})
+				for _, ul := range testUserLink {
+					rt.insertTestFriendLink(t, rt.db, ul)
+				}
+			},
+			lastUserId: 0,
+			limit:      2,
+			want:       newFriendList(),
+			wantErr:    false,
+		},
 		{
-			name:         "ng: excludeUsers nil",
-			prepare:      func(rt *friendListRepositoryTest) {},
-			excludeUsers: nil,
-			want:         nil,
-			wantErr:      true,
+			name: "ok: seek past last user",
+			prepare: func(rt *friendListRepositoryTest) {
+				for _, tu := range testUsers {
+					rt.insertTestUserList(t, rt.db, tu)
+				}
+				rt.insertTestUserList(t, rt.db, testUser{
+					userId: 444444,
+					name:   "piyo",
+				})
+				for _, ul := range testUserLink {
+					rt.insertTestFriendLink(t, rt.db, ul)
+				}
+			},
+			lastUserId: 222222,
+			limit:      2,
+			want: &model.FriendList{
+				Friends: []*model.Friend{
+					{
+						UserId: 333333,
+						Name:   "bar",
+					},
+				},
+			},
+			wantErr: false,
 		},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			rt := newFriendListRepositoryTest(t)
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
 			tt.prepare(rt)
 
-			got, err := rt.flr.GetFriendListOfFriendsByUserIdWithPaging(userId, tt.excludeUsers, tt.limit, tt.offset)
+			got, err := rt.flr.GetFriendListOfFriendsByUserIdWithCursor(rt.ctx, userId, false, tt.lastUserId, tt.limit)
 			if (err != nil) != tt.wantErr {
-				t.Fatalf("GetFriendListOfFriendsByUserIdWithPaging() error = %v, wantErr = %v", err, tt.wantErr)
+				t.Fatalf("GetFriendListOfFriendsByUserIdWithCursor() error = %v, wantErr = %v", err, tt.wantErr)
 			}
 			assert.Equal(t, tt.want, got)
 		})
 	}
 }
+
+func Test_friendListRepository_GetFriendListOfFriendsByUserIdWithCursor_StableWhileInserting(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	forEachFriendListRepository(t, func(t *testing.T, rt *friendListRepositoryTest) {
+		for _, tu := range newTestUsers() {
+			rt.insertTestUserList(t, rt.db, tu)
+		}
+		for _, tu := range []testUser{{userId: 100000, name: "foo"}, {userId: 444444, name: "piyo"}} {
+			rt.insertTestUserList(t, rt.db, tu)
+		}
+		for _, ul := range []userLink{
+			{user1Id: userId, user2Id: 444444},
+			{user1Id: 444444, user2Id: 111111},
+			{user1Id: 444444, user2Id: 222222},
+			{user1Id: 444444, user2Id: 333333},
+		} {
+			rt.insertTestFriendLink(t, rt.db, ul)
+		}
+
+		page1, err := rt.flr.GetFriendListOfFriendsByUserIdWithCursor(rt.ctx, userId, false, 0, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, newFriendList(), page1)
+
+		// a new friend of friend sorting before the cursor would shift an OFFSET based page
+		rt.insertTestFriendLink(t, rt.db, userLink{user1Id: 444444, user2Id: 100000})
+
+		lastUserId := page1.Friends[len(page1.Friends)-1].UserId
+		page2, err := rt.flr.GetFriendListOfFriendsByUserIdWithCursor(rt.ctx, userId, false, lastUserId, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, &model.FriendList{
+			Friends: []*model.Friend{
+				{
+					UserId: 333333,
+					Name:   "bar",
+				},
+			},
+		}, page2)
+
+		offsetPage2, err := rt.flr.GetFriendListOfFriendsByUserIdWithPaging(rt.ctx, userId, false, model.FriendListSortUserId, 2, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 222222, offsetPage2.Friends[0].UserId, "OFFSET paging repeats a row already returned on page 1")
+	})
+}
+
+func Test_friendListRepository_CountFriendListOfFriendsByUserId(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	testUserLink := []userLink{
+		{
+			user1Id: testutil.UserIDForDebug,
+			user2Id: 444444,
+		},
+		{
+			user1Id: 444444,
+			user2Id: 111111,
+		},
+		{
+			user1Id: 444444,
+			user2Id: 222222,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		prepare func(*friendListRepositoryTest)
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			prepare: func(rt *friendListRepositoryTest) {
+				for _, tu := range newTestUsers() {
+					rt.insertTestUserList(t, rt.db, tu)
+				}
+				rt.insertTestUserList(t, rt.db, testUser{
+					userId: 444444,
+					name:   "piyo",
+				})
+				for _, ul := range testUserLink {
+					rt.insertTestFriendLink(t, rt.db, ul)
+				}
+			},
+			want:    2,
+			wantErr: false,
+		},
+		{
+			name:    "ok: have no friend",
+			prepare: func(rt *friendListRepositoryTest) {},
+			want:    0,
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
+			tt.prepare(rt)
+
+			got, err := rt.flr.CountFriendListOfFriendsByUserId(rt.ctx, userId, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CountFriendListOfFriendsByUserId() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListRepository_FriendListOfFriends_AcceptedRequest(t *testing.T) {
+	userId := testutil.UserIDForDebug
+
+	forEachFriendListRepository(t, func(t *testing.T, rt *friendListRepositoryTest) {
+		for _, tu := range newTestUsers() {
+			rt.insertTestUserList(t, rt.db, tu)
+		}
+		rt.insertTestFriendLink(t, rt.db, userLink{user1Id: 111111, user2Id: 222222})
+
+		// accepting writes the friendship both ways, so the user is a friend of their friend
+		frt := &friendRequestRepositoryTest{db: rt.db, frr: NewFriendRequestRepository(rt.db), ctx: rt.ctx}
+		requestId := frt.insertTestFriendRequest(t, userId, 111111, model.FriendRequestStatusPending)
+		if err := frt.frr.UpdatePendingFriendRequestStatus(rt.ctx, requestId, model.FriendRequestStatusAccepted); err != nil {
+			t.Fatal(err)
+		}
+		for _, ul := range []userLink{{user1Id: userId, user2Id: 111111}, {user1Id: 111111, user2Id: userId}} {
+			if err := rt.flr.InsertUserLink(rt.ctx, ul.user1Id, ul.user2Id, "friend_link"); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		want := &model.FriendList{
+			Friends: []*model.Friend{
+				{
+					UserId: 222222,
+					Name:   "fuga",
+				},
+			},
+		}
+		got, err := rt.flr.GetFriendListOfFriendsByUserId(rt.ctx, userId, false, model.FriendListSortUserId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, want, got)
+
+		got, err = rt.flr.GetFriendListOfFriendsByUserIdWithPaging(rt.ctx, userId, false, model.FriendListSortName, 20, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, want, got)
+
+		got, err = rt.flr.GetFriendListOfFriendsByUserIdWithCursor(rt.ctx, userId, false, 0, 20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, want, got)
+
+		count, err := rt.flr.CountFriendListOfFriendsByUserId(rt.ctx, userId, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 1, count)
+	})
+}
+
+func Test_friendListRepository_GetBlockListByUserId(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	testUsers := newTestUsers()
+
+	tests := []struct {
+		name    string
+		prepare func(*friendListRepositoryTest)
+		want    *model.BlockList
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			prepare: func(rt *friendListRepositoryTest) {
+				for _, tu := range testUsers {
+					rt.insertTestUserList(t, rt.db, tu)
+				}
+				rt.insertTestBlockList(t, rt.db, userLink{
+					user1Id: testutil.UserIDForDebug,
+					user2Id: 222222,
+				})
+				rt.insertTestBlockList(t, rt.db, userLink{
+					user1Id: testutil.UserIDForDebug,
+					user2Id: 111111,
+				})
+			},
+			want: &model.BlockList{
+				BlockUsers: newFriendList().Friends,
+			},
+			wantErr: false,
+		},
+		{
+			name: "ok: no user blocked",
+			prepare: func(rt *friendListRepositoryTest) {
+				for _, tu := range testUsers {
+					rt.insertTestUserList(t, rt.db, tu)
+				}
+			},
+			want:    &model.BlockList{BlockUsers: nil},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
+			tt.prepare(rt)
+
+			got, err := rt.flr.GetBlockListByUserId(rt.ctx, userId)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetBlockListByUserId() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListRepository_GetBlockListByUserIdWithPaging(t *testing.T) {
+	userId := testutil.UserIDForDebug
+
+	forEachFriendListRepository(t, func(t *testing.T, rt *friendListRepositoryTest) {
+		for _, tu := range newTestUsers() {
+			rt.insertTestUserList(t, rt.db, tu)
+		}
+		for _, ul := range newTestUserLink() {
+			rt.insertTestBlockList(t, rt.db, ul)
+		}
+
+		got, err := rt.flr.GetBlockListByUserIdWithPaging(rt.ctx, userId, 2, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, &model.BlockList{
+			BlockUsers: []*model.Friend{{UserId: 222222, Name: "fuga"}, {UserId: 333333, Name: "bar"}},
+		}, got)
+
+		count, err := rt.flr.CountBlockListByUserId(rt.ctx, userId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 3, count)
+	})
+}
+
+func Test_friendListRepository_GetMutualFriendsByUserId(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	hoge := &model.Friend{UserId: 111111, Name: "hoge"}
+	fuga := &model.Friend{UserId: 222222, Name: "fuga"}
+	bar := &model.Friend{UserId: 333333, Name: "bar"}
+	piyo := &model.Friend{UserId: 444444, Name: "piyo"}
+
+	tests := []struct {
+		name             string
+		blockList        []userLink
+		excludeBlockedBy bool
+		want             []*model.MutualFriend
+	}{
+		{
+			name:             "ok: no block",
+			excludeBlockedBy: false,
+			want: []*model.MutualFriend{
+				{Candidate: bar, Friend: hoge},
+				{Candidate: bar, Friend: fuga},
+				{Candidate: piyo, Friend: fuga},
+			},
+		},
+		{
+			name:             "ok: blocked candidate",
+			blockList:        []userLink{{user1Id: userId, user2Id: 444444}},
+			excludeBlockedBy: false,
+			want: []*model.MutualFriend{
+				{Candidate: bar, Friend: hoge},
+				{Candidate: bar, Friend: fuga},
+			},
+		},
+		{
+			name:             "ok: blocked friend does not count",
+			blockList:        []userLink{{user1Id: userId, user2Id: 111111}},
+			excludeBlockedBy: false,
+			want: []*model.MutualFriend{
+				{Candidate: bar, Friend: fuga},
+				{Candidate: piyo, Friend: fuga},
+			},
+		},
+		{
+			name:             "ok: blocked by a candidate is kept one way",
+			blockList:        []userLink{{user1Id: 444444, user2Id: userId}},
+			excludeBlockedBy: false,
+			want: []*model.MutualFriend{
+				{Candidate: bar, Friend: hoge},
+				{Candidate: bar, Friend: fuga},
+				{Candidate: piyo, Friend: fuga},
+			},
+		},
+		{
+			name:             "ok: blocked by a candidate is excluded mutually",
+			blockList:        []userLink{{user1Id: 444444, user2Id: userId}},
+			excludeBlockedBy: true,
+			want: []*model.MutualFriend{
+				{Candidate: bar, Friend: hoge},
+				{Candidate: bar, Friend: fuga},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
+			for _, tu := range newTestUsers() {
+				rt.insertTestUserList(t, rt.db, tu)
+			}
+			rt.insertTestUserList(t, rt.db, testUser{
+				userId: 444444,
+				name:   "piyo",
+			})
+			for _, ul := range []userLink{
+				{user1Id: userId, user2Id: 111111},
+				{user1Id: userId, user2Id: 222222},
+				{user1Id: 111111, user2Id: userId},
+				{user1Id: 222222, user2Id: userId},
+				{user1Id: 111111, user2Id: 333333},
+				{user1Id: 222222, user2Id: 333333},
+				{user1Id: 222222, user2Id: 444444},
+			} {
+				rt.insertTestFriendLink(t, rt.db, ul)
+			}
+			for _, bl := range tt.blockList {
+				rt.insertTestBlockList(t, rt.db, bl)
+			}
+
+			got, err := rt.flr.GetMutualFriendsByUserId(rt.ctx, userId, tt.excludeBlockedBy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListRepository_GetFriendCountByUserIds(t *testing.T) {
+	forEachFriendListRepository(t, func(t *testing.T, rt *friendListRepositoryTest) {
+		for _, tu := range newTestUsers() {
+			rt.insertTestUserList(t, rt.db, tu)
+		}
+		for _, ul := range newTestUserLink() {
+			rt.insertTestFriendLink(t, rt.db, ul)
+		}
+		rt.insertTestFriendLink(t, rt.db, userLink{
+			user1Id: 111111,
+			user2Id: 222222,
+		})
+
+		got, err := rt.flr.GetFriendCountByUserIds(rt.ctx, []int{testutil.UserIDForDebug, 111111, 333333})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, map[int]int{testutil.UserIDForDebug: 3, 111111: 1}, got)
+	})
+}
+
+func Test_friendListRepository_GetFriendUserIdsByUserIds(t *testing.T) {
+	forEachFriendListRepository(t, func(t *testing.T, rt *friendListRepositoryTest) {
+		for _, ul := range []userLink{
+			{user1Id: 111111, user2Id: 333333},
+			{user1Id: 111111, user2Id: 222222},
+			{user1Id: 222222, user2Id: 333333},
+			{user1Id: 333333, user2Id: 444444},
+		} {
+			rt.insertTestFriendLink(t, rt.db, ul)
+		}
+
+		got, err := rt.flr.GetFriendUserIdsByUserIds(rt.ctx, []int{111111, 222222, 555555})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, map[int][]int{111111: {222222, 333333}, 222222: {333333}}, got)
+
+		got, err = rt.flr.GetFriendOfUserIdsByUserIds(rt.ctx, []int{333333, 444444, 555555})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, map[int][]int{333333: {111111, 222222}, 444444: {333333}}, got)
+	})
+}
+
+func Test_friendListRepository_GetFriendUserIdsByUserIds_Batches(t *testing.T) {
+	forEachFriendListRepository(t, func(t *testing.T, rt *friendListRepositoryTest) {
+		rt.insertTestFriendLink(t, rt.db, userLink{user1Id: 111111, user2Id: 333333})
+		rt.insertTestFriendLink(t, rt.db, userLink{user1Id: 222222, user2Id: 333333})
+
+		// 111111 and 222222 fall in different batches of the IN list
+		userIds := []int{111111}
+		for i := 0; i < maxInUserIds; i++ {
+			userIds = append(userIds, 900000+i)
+		}
+		userIds = append(userIds, 222222)
+
+		got, err := rt.flr.GetFriendUserIdsByUserIds(rt.ctx, userIds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, map[int][]int{111111: {333333}, 222222: {333333}}, got)
+
+		_, err = rt.flr.GetFriendUserIdsByUserIds(rt.ctx, nil)
+		assert.ErrorIs(t, err, errNoUserIds)
+	})
+}
+
+func Test_friendListRepository_GetUserListByUserIds(t *testing.T) {
+	forEachFriendListRepository(t, func(t *testing.T, rt *friendListRepositoryTest) {
+		for _, tu := range newTestUsers() {
+			rt.insertTestUserList(t, rt.db, tu)
+		}
+
+		got, err := rt.flr.GetUserListByUserIds(rt.ctx, []int{222222, 111111, 555555})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, newFriendList(), got)
+	})
+}
+
+func Test_friendListRepository_GetBlockedByUsersIdList(t *testing.T) {
+	userId := testutil.UserIDForDebug
+
+	tests := []struct {
+		name    string
+		prepare func(*friendListRepositoryTest)
+		want    []int
+		wantErr bool
+	}{
+		{
+			name: "ok: blocked by another user",
+			prepare: func(rt *friendListRepositoryTest) {
+				rt.insertTestBlockList(t, rt.db, userLink{
+					user1Id: 111111,
+					user2Id: userId,
+				})
+			},
+			want:    []int{111111},
+			wantErr: false,
+		},
+		{
+			name: "ok: blocking another user is not being blocked",
+			prepare: func(rt *friendListRepositoryTest) {
+				rt.insertTestBlockList(t, rt.db, userLink{
+					user1Id: userId,
+					user2Id: 111111,
+				})
+			},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "ok: blocked each other",
+			prepare: func(rt *friendListRepositoryTest) {
+				rt.insertTestBlockList(t, rt.db, userLink{
+					user1Id: userId,
+					user2Id: 111111,
+				})
+				rt.insertTestBlockList(t, rt.db, userLink{
+					user1Id: 111111,
+					user2Id: userId,
+				})
+				rt.insertTestBlockList(t, rt.db, userLink{
+					user1Id: 222222,
+					user2Id: userId,
+				})
+			},
+			want:    []int{111111, 222222},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
+			tt.prepare(rt)
+
+			got, err := rt.flr.GetBlockedByUsersIdList(rt.ctx, userId)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetBlockedByUsersIdList() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListRepository_DeleteFriendLinksBetween(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	tests := []struct {
+		name    string
+		prepare func(*friendListRepositoryTest)
+		want    []int
+	}{
+		{
+			name: "ok: both directions are deleted",
+			prepare: func(rt *friendListRepositoryTest) {
+				rt.insertTestFriendLink(t, rt.db, userLink{user1Id: userId, user2Id: 111111})
+				rt.insertTestFriendLink(t, rt.db, userLink{user1Id: 111111, user2Id: userId})
+				rt.insertTestFriendLink(t, rt.db, userLink{user1Id: userId, user2Id: 222222})
+			},
+			want: []int{222222},
+		},
+		{
+			name:    "ok: no friend link",
+			prepare: func(rt *friendListRepositoryTest) {},
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
+			tt.prepare(rt)
+
+			if err := rt.flr.DeleteFriendLinksBetween(rt.ctx, userId, 111111); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := rt.flr.GetOneHopFriendsUserIdList(rt.ctx, userId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.ElementsMatch(t, tt.want, got)
+
+			got, err = rt.flr.GetOneHopFriendsUserIdList(rt.ctx, 111111)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Empty(t, got)
+		})
+	}
+}
+
+func Test_friendListRepository_DeleteUserLinksOfUser(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	forEachFriendListRepository(t, func(t *testing.T, rt *friendListRepositoryTest) {
+		rt.insertTestFriendLink(t, rt.db, userLink{user1Id: userId, user2Id: 111111})
+		rt.insertTestFriendLink(t, rt.db, userLink{user1Id: 111111, user2Id: userId})
+		rt.insertTestFriendLink(t, rt.db, userLink{user1Id: 111111, user2Id: 222222})
+		rt.insertTestBlockList(t, rt.db, userLink{user1Id: userId, user2Id: 333333})
+		rt.insertTestBlockList(t, rt.db, userLink{user1Id: 222222, user2Id: userId})
+
+		if err := rt.flr.DeleteUserLinksOfUser(rt.ctx, userId); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, tt := range []struct {
+			name string
+			get  func(ctx context.Context, userId int) ([]int, error)
+			user int
+			want []int
+		}{
+			{name: "friends", get: rt.flr.GetOneHopFriendsUserIdList, user: userId, want: nil},
+			{name: "friends of the others are kept", get: rt.flr.GetOneHopFriendsUserIdList, user: 111111, want: []int{222222}},
+			{name: "blocks", get: rt.flr.GetBlockUsersIdList, user: userId, want: nil},
+			{name: "blocked by", get: rt.flr.GetBlockedByUsersIdList, user: userId, want: nil},
+		} {
+			got, err := tt.get(rt.ctx, tt.user)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.ElementsMatch(t, tt.want, got, tt.name)
+		}
+	})
+}
+
+func Test_friendListRepository_InsertUserLinks(t *testing.T) {
+	userId := testutil.UserIDForDebug
+
+	tests := []struct {
+		name      string
+		prepare   func(*friendListRepositoryTest)
+		table     string
+		userLinks [][2]int
+		want      []int
+		wantErr   bool
+	}{
+		{
+			name:      "ok: friend_link",
+			prepare:   func(rt *friendListRepositoryTest) {},
+			table:     "friend_link",
+			userLinks: [][2]int{{userId, 222222}, {userId, 111111}},
+			want:      []int{111111, 222222},
+			wantErr:   false,
+		},
+		{
+			name: "ok: block_list keeps the links already there",
+			prepare: func(rt *friendListRepositoryTest) {
+				rt.insertTestBlockList(t, rt.db, userLink{user1Id: userId, user2Id: 111111})
+			},
+			table:     "block_list",
+			userLinks: [][2]int{{userId, 111111}, {userId, 333333}, {userId, 333333}},
+			want:      []int{111111, 333333},
+			wantErr:   false,
+		},
+		{
+			name:      "ok: no link",
+			prepare:   func(rt *friendListRepositoryTest) {},
+			table:     "friend_link",
+			userLinks: nil,
+			want:      nil,
+			wantErr:   false,
+		},
+		{
+			name:      "ng: table invalid",
+			prepare:   func(rt *friendListRepositoryTest) {},
+			table:     "users",
+			userLinks: [][2]int{{userId, 111111}},
+			want:      nil,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		runFriendListRepositoryTest(t, tt.name, func(t *testing.T, rt *friendListRepositoryTest) {
+			tt.prepare(rt)
+
+			err := NewTransaction(rt.db).DoInTx(rt.ctx, func(ctx context.Context) error {
+				return rt.flr.InsertUserLinks(ctx, tt.table, tt.userLinks)
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("InsertUserLinks() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+
+			var got []int
+			if tt.table == "block_list" {
+				got, err = rt.flr.GetBlockUsersIdList(rt.ctx, userId)
+			} else {
+				got, err = rt.flr.GetOneHopFriendsUserIdList(rt.ctx, userId)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListRepository_GetExistingUserLinks(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	forEachFriendListRepository(t, func(t *testing.T, rt *friendListRepositoryTest) {
+		rt.insertTestFriendLink(t, rt.db, userLink{user1Id: userId, user2Id: 111111})
+		rt.insertTestFriendLink(t, rt.db, userLink{user1Id: 222222, user2Id: userId})
+		rt.insertTestBlockList(t, rt.db, userLink{user1Id: userId, user2Id: 222222})
+
+		got, err := rt.flr.GetExistingUserLinks(rt.ctx, "friend_link", [][2]int{{userId, 111111}, {userId, 222222}, {222222, userId}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, map[[2]int]bool{{userId, 111111}: true, {222222, userId}: true}, got)
+
+		got, err = rt.flr.GetExistingUserLinks(rt.ctx, "block_list", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Empty(t, got)
+	})
+}
+
+func Test_userLinkRows(t *testing.T) {
+	rows, args := userLinkRows("0, ", [][2]int{{1, 2}, {3, 4}})
+
+	assert.Equal(t, "(0, ?, ?), (0, ?, ?)", rows)
+	assert.Equal(t, []any{1, 2, 3, 4}, args)
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"problem1/model"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+type FriendRequestRepository interface {
+	InsertFriendRequest(ctx context.Context, fromUserId, toUserId int) (int, error)
+	GetFriendRequestForUpdate(ctx context.Context, requestId int) (*model.FriendRequest, error)
+	ExistsPendingFriendRequest(ctx context.Context, user1Id, user2Id int) (bool, error)
+	GetIncomingFriendRequests(ctx context.Context, userId int) (*model.FriendRequestList, error)
+	GetOutgoingFriendRequests(ctx context.Context, userId int) (*model.FriendRequestList, error)
+	UpdatePendingFriendRequestStatus(ctx context.Context, requestId int, status string) error
+	CancelPendingFriendRequestsBetween(ctx context.Context, user1Id, user2Id int) error
+	CancelPendingFriendRequestsOfUser(ctx context.Context, userId int) error
+}
+
+var (
+	// ErrFriendRequestNotFound is returned when the friend request does not exist or is no longer pending.
+	ErrFriendRequestNotFound = errors.New("friend request not exist")
+	// ErrFriendRequestDuplicated is returned when a request between the users is already pending.
+	ErrFriendRequestDuplicated = errors.New("friend request already pending")
+)
+
+type friendRequestRepository struct {
+	db *sql.DB
+}
+
+func NewFriendRequestRepository(db *sql.DB) FriendRequestRepository {
+	return &friendRequestRepository{
+		db: db,
+	}
+}
+
+// InsertFriendRequest inserts a pending request. The unique key on the pending users makes a
+// request racing another one between the same users fail with ErrFriendRequestDuplicated.
+func (r *friendRequestRepository) InsertFriendRequest(ctx context.Context, fromUserId, toUserId int) (int, error) {
+	const q = `
+	INSERT INTO friend_request (id, from_user_id, to_user_id, status)
+	VALUES (0, ?, ?, ?)`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, q, fromUserId, toUserId, model.FriendRequestStatusPending)
+	if err != nil {
+		if isDuplicateEntryError(err) {
+			return 0, ErrFriendRequestDuplicated
+		}
+
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// GetFriendRequestForUpdate locks the row until the surrounding transaction ends.
+func (r *friendRequestRepository) GetFriendRequestForUpdate(ctx context.Context, requestId int) (*model.FriendRequest, error) {
+	const q = `
+	SELECT id, from_user_id, to_user_id, status, created_at
+	FROM friend_request
+	WHERE id = ?
+	FOR UPDATE`
+
+	fr := &model.FriendRequest{}
+	row := conn(ctx, r.db).QueryRowContext(ctx, q, requestId)
+	if err := row.Scan(&fr.RequestId, &fr.FromUserId, &fr.ToUserId, &fr.Status, &fr.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrFriendRequestNotFound
+		}
+
+		return nil, err
+	}
+
+	return fr, nil
+}
+
+func (r *friendRequestRepository) ExistsPendingFriendRequest(ctx context.Context, user1Id, user2Id int) (bool, error) {
+	const q = `
+	SELECT EXISTS (
+		SELECT 1
+		FROM friend_request
+		WHERE status = ?
+		AND ((from_user_id = ? AND to_user_id = ?) OR (from_user_id = ? AND to_user_id = ?))
+	)`
+
+	var exists bool
+	row := conn(ctx, r.db).QueryRowContext(ctx, q, model.FriendRequestStatusPending, user1Id, user2Id, user2Id, user1Id)
+	if err := row.Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (r *friendRequestRepository) GetIncomingFriendRequests(ctx context.Context, userId int) (*model.FriendRequestList, error) {
+	const q = `
+	SELECT id, from_user_id, to_user_id, status, created_at
+	FROM friend_request
+	WHERE to_user_id = ? AND status = ?
+	ORDER BY id`
+
+	return r.selectFriendRequestList(ctx, q, userId, model.FriendRequestStatusPending)
+}
+
+func (r *friendRequestRepository) GetOutgoingFriendRequests(ctx context.Context, userId int) (*model.FriendRequestList, error) {
+	const q = `
+	SELECT id, from_user_id, to_user_id, status, created_at
+	FROM friend_request
+	WHERE from_user_id = ? AND status = ?
+	ORDER BY id`
+
+	return r.selectFriendRequestList(ctx, q, userId, model.FriendRequestStatusPending)
+}
+
+func (r *friendRequestRepository) UpdatePendingFriendRequestStatus(ctx context.Context, requestId int, status string) error {
+	const q = `
+	UPDATE friend_request
+	SET status = ?
+	WHERE id = ? AND status = ?`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, q, status, requestId, model.FriendRequestStatusPending)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrFriendRequestNotFound
+	}
+
+	return nil
+}
+
+// CancelPendingFriendRequestsBetween cancels the pending requests sent in either direction.
+func (r *friendRequestRepository) CancelPendingFriendRequestsBetween(ctx context.Context, user1Id, user2Id int) error {
+	const q = `
+	UPDATE friend_request
+	SET status = ?
+	WHERE status = ?
+	AND ((from_user_id = ? AND to_user_id = ?) OR (from_user_id = ? AND to_user_id = ?))`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, q, model.FriendRequestStatusCanceled, model.FriendRequestStatusPending, user1Id, user2Id, user2Id, user1Id)
+
+	return err
+}
+
+func (r *friendRequestRepository) selectFriendRequestList(ctx context.Context, q string, args ...any) (*model.FriendRequestList, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var friendRequests []*model.FriendRequest
+	for rows.Next() {
+		fr := &model.FriendRequest{}
+		if err := rows.Scan(&fr.RequestId, &fr.FromUserId, &fr.ToUserId, &fr.Status, &fr.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		friendRequests = append(friendRequests, fr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &model.FriendRequestList{FriendRequests: friendRequests}, nil
+}
+
+// CancelPendingFriendRequestsOfUser cancels the pending requests sent by or to userId.
+func (r *friendRequestRepository) CancelPendingFriendRequestsOfUser(ctx context.Context, userId int) error {
+	const q = `
+	UPDATE friend_request
+	SET status = ?
+	WHERE status = ?
+	AND (from_user_id = ? OR to_user_id = ?)`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, q, model.FriendRequestStatusCanceled, model.FriendRequestStatusPending, userId, userId)
+
+	return err
+}
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"problem1/model"
+	"problem1/pkg/testutil"
+)
+
+type friendRequestRepositoryTest struct {
+	db  *sql.DB
+	frr FriendRequestRepository
+	ctx context.Context
+}
+
+func newFriendRequestRepositoryTest(t *testing.T) *friendRequestRepositoryTest {
+	t.Helper()
+
+	db := testutil.PrepareMySQL(t)
+
+	return &friendRequestRepositoryTest{
+		db:  db,
+		frr: NewFriendRequestRepository(db),
+		ctx: context.Background(),
+	}
+}
+
+func (r *friendRequestRepositoryTest) insertTestFriendRequest(t *testing.T, fromUserId, toUserId int, status string) int {
+	t.Helper()
+
+	const q = `
+	INSERT INTO friend_request (id, from_user_id, to_user_id, status)
+	VALUES (0, ?, ?, ?)`
+
+	testRecord := []any{
+		fromUserId,
+		toUserId,
+		status,
+	}
+	testutil.ValidateSQLArgs(t, q, testRecord...)
+
+	result, err := r.db.Exec(q, testRecord...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return int(id)
+}
+
+func Test_friendRequestRepository_InsertFriendRequest(t *testing.T) {
+	rt := newFriendRequestRepositoryTest(t)
+
+	requestId, err := rt.frr.InsertFriendRequest(rt.ctx, testutil.UserIDForDebug, 111111)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := rt.frr.GetFriendRequestForUpdate(rt.ctx, requestId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, requestId, got.RequestId)
+	assert.Equal(t, testutil.UserIDForDebug, got.FromUserId)
+	assert.Equal(t, 111111, got.ToUserId)
+	assert.Equal(t, model.FriendRequestStatusPending, got.Status)
+	assert.False(t, got.CreatedAt.IsZero())
+}
+
+func Test_friendRequestRepository_InsertFriendRequest_Duplicated(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(*friendRequestRepositoryTest)
+		wantErr error
+	}{
+		{
+			name: "ng: pending request sent",
+			prepare: func(rt *friendRequestRepositoryTest) {
+				rt.insertTestFriendRequest(t, testutil.UserIDForDebug, 111111, model.FriendRequestStatusPending)
+			},
+			wantErr: ErrFriendRequestDuplicated,
+		},
+		{
+			name: "ng: pending request received",
+			prepare: func(rt *friendRequestRepositoryTest) {
+				rt.insertTestFriendRequest(t, 111111, testutil.UserIDForDebug, model.FriendRequestStatusPending)
+			},
+			wantErr: ErrFriendRequestDuplicated,
+		},
+		{
+			name: "ok: answered requests",
+			prepare: func(rt *friendRequestRepositoryTest) {
+				rt.insertTestFriendRequest(t, testutil.UserIDForDebug, 111111, model.FriendRequestStatusRejected)
+				rt.insertTestFriendRequest(t, testutil.UserIDForDebug, 111111, model.FriendRequestStatusCanceled)
+			},
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newFriendRequestRepositoryTest(t)
+			tt.prepare(rt)
+
+			_, err := rt.frr.InsertFriendRequest(rt.ctx, testutil.UserIDForDebug, 111111)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_friendRequestRepository_GetFriendRequestForUpdate(t *testing.T) {
+	rt := newFriendRequestRepositoryTest(t)
+
+	_, err := rt.frr.GetFriendRequestForUpdate(rt.ctx, 1)
+	assert.ErrorIs(t, err, ErrFriendRequestNotFound)
+}
+
+func Test_friendRequestRepository_ExistsPendingFriendRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(*friendRequestRepositoryTest)
+		want    bool
+	}{
+		{
+			name: "ok: pending request sent",
+			prepare: func(rt *friendRequestRepositoryTest) {
+				rt.insertTestFriendRequest(t, testutil.UserIDForDebug, 111111, model.FriendRequestStatusPending)
+			},
+			want: true,
+		},
+		{
+			name: "ok: pending request received",
+			prepare: func(rt *friendRequestRepositoryTest) {
+				rt.insertTestFriendRequest(t, 111111, testutil.UserIDForDebug, model.FriendRequestStatusPending)
+			},
+			want: true,
+		},
+		{
+			name: "ok: only rejected request",
+			prepare: func(rt *friendRequestRepositoryTest) {
+				rt.insertTestFriendRequest(t, testutil.UserIDForDebug, 111111, model.FriendRequestStatusRejected)
+			},
+			want: false,
+		},
+		{
+			name:    "ok: no request",
+			prepare: func(rt *friendRequestRepositoryTest) {},
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newFriendRequestRepositoryTest(t)
+			tt.prepare(rt)
+
+			got, err := rt.frr.ExistsPendingFriendRequest(rt.ctx, testutil.UserIDForDebug, 111111)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendRequestRepository_GetIncomingAndOutgoingFriendRequests(t *testing.T) {
+	rt := newFriendRequestRepositoryTest(t)
+	incoming := rt.insertTestFriendRequest(t, 111111, testutil.UserIDForDebug, model.FriendRequestStatusPending)
+	rt.insertTestFriendRequest(t, 222222, testutil.UserIDForDebug, model.FriendRequestStatusRejected)
+	outgoing := rt.insertTestFriendRequest(t, testutil.UserIDForDebug, 333333, model.FriendRequestStatusPending)
+	rt.insertTestFriendRequest(t, testutil.UserIDForDebug, 444444, model.FriendRequestStatusCanceled)
+
+	got, err := rt.frr.GetIncomingFriendRequests(rt.ctx, testutil.UserIDForDebug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, got.FriendRequests, 1) {
+		assert.Equal(t, incoming, got.FriendRequests[0].RequestId)
+	}
+
+	got, err = rt.frr.GetOutgoingFriendRequests(rt.ctx, testutil.UserIDForDebug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, got.FriendRequests, 1) {
+		assert.Equal(t, outgoing, got.FriendRequests[0].RequestId)
+	}
+}
+
+func Test_friendRequestRepository_UpdatePendingFriendRequestStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		wantStatus string
+		wantErr    error
+	}{
+		{
+			name:       "ok: pending request",
+			status:     model.FriendRequestStatusPending,
+			wantStatus: model.FriendRequestStatusAccepted,
+			wantErr:    nil,
+		},
+		{
+			name:       "ng: request already rejected",
+			status:     model.FriendRequestStatusRejected,
+			wantStatus: model.FriendRequestStatusRejected,
+			wantErr:    ErrFriendRequestNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newFriendRequestRepositoryTest(t)
+			requestId := rt.insertTestFriendRequest(t, testutil.UserIDForDebug, 111111, tt.status)
+
+			err := rt.frr.UpdatePendingFriendRequestStatus(rt.ctx, requestId, model.FriendRequestStatusAccepted)
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			got, err := rt.frr.GetFriendRequestForUpdate(rt.ctx, requestId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.wantStatus, got.Status)
+		})
+	}
+}
+
+func Test_friendRequestRepository_CancelPendingFriendRequestsBetween(t *testing.T) {
+	userId := testutil.UserIDForDebug
+
+	tests := []struct {
+		name       string
+		fromUserId int
+		toUserId   int
+	}{
+		{
+			name:       "ok: outgoing request",
+			fromUserId: userId,
+			toUserId:   111111,
+		},
+		{
+			name:       "ok: incoming request",
+			fromUserId: 111111,
+			toUserId:   userId,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newFriendRequestRepositoryTest(t)
+
+			pending := rt.insertTestFriendRequest(t, tt.fromUserId, tt.toUserId, model.FriendRequestStatusPending)
+			rejected := rt.insertTestFriendRequest(t, tt.fromUserId, tt.toUserId, model.FriendRequestStatusRejected)
+			other := rt.insertTestFriendRequest(t, userId, 222222, model.FriendRequestStatusPending)
+
+			if err := rt.frr.CancelPendingFriendRequestsBetween(rt.ctx, userId, 111111); err != nil {
+				t.Fatal(err)
+			}
+
+			for requestId, want := range map[int]string{
+				pending:  model.FriendRequestStatusCanceled,
+				rejected: model.FriendRequestStatusRejected,
+				other:    model.FriendRequestStatusPending,
+			} {
+				got, err := rt.frr.GetFriendRequestForUpdate(rt.ctx, requestId)
+				if err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, want, got.Status)
+			}
+		})
+	}
+}
+
+func Test_friendRequestRepository_CancelPendingFriendRequestsOfUser(t *testing.T) {
+	rt := newFriendRequestRepositoryTest(t)
+	userId := testutil.UserIDForDebug
+
+	outgoing := rt.insertTestFriendRequest(t, userId, 111111, model.FriendRequestStatusPending)
+	incoming := rt.insertTestFriendRequest(t, 222222, userId, model.FriendRequestStatusPending)
+	accepted := rt.insertTestFriendRequest(t, 333333, userId, model.FriendRequestStatusAccepted)
+	other := rt.insertTestFriendRequest(t, 111111, 222222, model.FriendRequestStatusPending)
+
+	if err := rt.frr.CancelPendingFriendRequestsOfUser(rt.ctx, userId); err != nil {
+		t.Fatal(err)
+	}
+
+	for requestId, want := range map[int]string{
+		outgoing: model.FriendRequestStatusCanceled,
+		incoming: model.FriendRequestStatusCanceled,
+		accepted: model.FriendRequestStatusAccepted,
+		other:    model.FriendRequestStatusPending,
+	} {
+		got, err := rt.frr.GetFriendRequestForUpdate(rt.ctx, requestId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, want, got.Status)
+	}
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"problem1/model"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+// GraphExportRepository reads the whole graph row by row, so that it never sits in memory.
+type GraphExportRepository interface {
+	EachUser(ctx context.Context, userIds []int, fn func(u *model.User) error) error
+	EachUserLink(ctx context.Context, table string, userIds []int, fn func(user1Id, user2Id int) error) error
+}
+
+type graphExportRepository struct {
+	db *sql.DB
+}
+
+func NewGraphExportRepository(db *sql.DB) GraphExportRepository {
+	return &graphExportRepository{
+		db: db,
+	}
+}
+
+// EachUser calls fn with each of the users ordered by user_id, or only with the users of
+// userIds when it is not nil. It stops at the first error of fn.
+func (r *graphExportRepository) EachUser(ctx context.Context, userIds []int, fn func(u *model.User) error) error {
+	q := `
+	SELECT user_id, name
+	FROM users
+	ORDER BY user_id`
+	var args []any
+	if userIds != nil {
+		if len(userIds) == 0 {
+			return nil
+		}
+
+		var err error
+		q, args, err = sqlx.In(`
+		SELECT user_id, name
+		FROM users
+		WHERE user_id IN (?)
+		ORDER BY user_id`, userIds)
+		if err != nil {
+			return err
+		}
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		u := &model.User{}
+		if err := rows.Scan(&u.UserId, &u.Name); err != nil {
+			return err
+		}
+		if err := fn(u); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// EachUserLink calls fn with each of the links of table ordered by user1_id and user2_id,
+// or only with the links between the users of userIds when it is not nil. It stops at the
+// first error of fn.
+func (r *graphExportRepository) EachUserLink(ctx context.Context, table string, userIds []int, fn func(user1Id, user2Id int) error) error {
+	if err := checkUserLinkTable(table); err != nil {
+		return err
+	}
+
+	q := `
+	SELECT user1_id, user2_id
+	FROM ` + table + `
+	ORDER BY user1_id, user2_id`
+	var args []any
+	if userIds != nil {
+		if len(userIds) == 0 {
+			return nil
+		}
+
+		var err error
+		q, args, err = sqlx.In(`
+		SELECT user1_id, user2_id
+		FROM `+table+`
+		WHERE user1_id IN (?) AND user2_id IN (?)
+		ORDER BY user1_id, user2_id`, userIds, userIds)
+		if err != nil {
+			return err
+		}
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user1Id, user2Id int
+		if err := rows.Scan(&user1Id, &user2Id); err != nil {
+			return err
+		}
+		if err := fn(user1Id, user2Id); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
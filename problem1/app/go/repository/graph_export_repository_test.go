@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"problem1/model"
+	"problem1/pkg/testutil"
+)
+
+type graphExportRepositoryTest struct {
+	db  *sql.DB
+	ger GraphExportRepository
+	ctx context.Context
+}
+
+func newGraphExportRepositoryTest(t *testing.T) *graphExportRepositoryTest {
+	t.Helper()
+
+	db := testutil.PrepareMySQL(t)
+	for _, u := range newTestUsers() {
+		testutil.ExecSQL(t, db, `INSERT INTO users (id, user_id, name) VALUES (0, ?, ?)`, u.userId, u.name)
+	}
+	for _, ul := range newTestUserLink() {
+		testutil.ExecSQL(t, db, `INSERT INTO friend_link (id, user1_id, user2_id) VALUES (0, ?, ?)`, ul.user1Id, ul.user2Id)
+	}
+	testutil.ExecSQL(t, db, `INSERT INTO block_list (id, user1_id, user2_id) VALUES (0, ?, ?)`, 222222, 111111)
+
+	return &graphExportRepositoryTest{
+		db:  db,
+		ger: NewGraphExportRepository(db),
+		ctx: context.Background(),
+	}
+}
+
+func Test_graphExportRepository_EachUser(t *testing.T) {
+	tests := []struct {
+		name    string
+		userIds []int
+		want    []int
+	}{
+		{
+			name:    "ok: all",
+			userIds: nil,
+			want:    []int{111111, 222222, 333333, testutil.UserIDForDebug},
+		},
+		{
+			name:    "ok: some",
+			userIds: []int{333333, 111111, 999999},
+			want:    []int{111111, 333333},
+		},
+		{
+			name:    "ok: none",
+			userIds: []int{},
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newGraphExportRepositoryTest(t)
+
+			var got []int
+			err := rt.ger.EachUser(rt.ctx, tt.userIds, func(u *model.User) error {
+				got = append(got, u.UserId)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_graphExportRepository_EachUserLink(t *testing.T) {
+	userId := testutil.UserIDForDebug
+
+	tests := []struct {
+		name    string
+		table   string
+		userIds []int
+		want    [][2]int
+		wantErr bool
+	}{
+		{
+			name:    "ok: all friend links",
+			table:   "friend_link",
+			userIds: nil,
+			want:    [][2]int{{userId, 111111}, {userId, 222222}, {userId, 333333}},
+			wantErr: false,
+		},
+		{
+			name:    "ok: links between the users",
+			table:   "friend_link",
+			userIds: []int{userId, 222222, 111111},
+			want:    [][2]int{{userId, 111111}, {userId, 222222}},
+			wantErr: false,
+		},
+		{
+			name:    "ok: block list",
+			table:   "block_list",
+			userIds: nil,
+			want:    [][2]int{{222222, 111111}},
+			wantErr: false,
+		},
+		{
+			name:    "ng: table invalid",
+			table:   "users",
+			userIds: nil,
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newGraphExportRepositoryTest(t)
+
+			var got [][2]int
+			err := rt.ger.EachUserLink(rt.ctx, tt.table, tt.userIds, func(user1Id, user2Id int) error {
+				got = append(got, [2]int{user1Id, user2Id})
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EachUserLink() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_graphExportRepository_EachUser_stopsAtError(t *testing.T) {
+	rt := newGraphExportRepositoryTest(t)
+
+	calls := 0
+	err := rt.ger.EachUser(rt.ctx, nil, func(u *model.User) error {
+		calls++
+		return testutil.ErrTest
+	})
+	assert.ErrorIs(t, err, testutil.ErrTest)
+	assert.Equal(t, 1, calls)
+}
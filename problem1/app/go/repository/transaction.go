@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+// Transaction runs a unit of work. Repositories called with the context passed to fn
+// execute their queries on the same *sql.Tx.
+type Transaction interface {
+	DoInTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// DBTX is implemented by both *sql.DB and *sql.Tx.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+// txState is the transaction carried by the context of a unit of work, with the callbacks
+// registered through AfterCommit.
+type txState struct {
+	tx          *sql.Tx
+	afterCommit []func()
+}
+
+type transaction struct {
+	db *sql.DB
+}
+
+func NewTransaction(db *sql.DB) Transaction {
+	return &transaction{
+		db: db,
+	}
+}
+
+// DoInTx commits when fn returns nil and rolls back otherwise.
+// When ctx already carries a transaction, fn joins it instead of starting a new one.
+func (t *transaction) DoInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*txState); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	state := &txState{tx: tx}
+	if err := fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for _, f := range state.afterCommit {
+		f()
+	}
+
+	return nil
+}
+
+// conn returns the transaction carried by ctx, or db when there is none.
+func conn(ctx context.Context, db *sql.DB) DBTX {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx
+	}
+
+	return db
+}
+
+// AfterCommit runs f once the transaction carried by ctx has been committed, and never
+// when it is rolled back. Without a transaction the write is already done, so f runs at once.
+func AfterCommit(ctx context.Context, f func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, f)
+		return
+	}
+
+	f()
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/pkg/testutil"
+)
+
+func Test_transaction_DoInTx(t *testing.T) {
+	tests := []struct {
+		name    string
+		expects func(mock sqlmock.Sqlmock)
+		fn      func(tx Transaction) func(ctx context.Context) error
+		wantErr bool
+	}{
+		{
+			name: "ok: commit",
+			expects: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO friend_link").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			fn: func(tx Transaction) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					_, err := conn(ctx, nil).ExecContext(ctx, "INSERT INTO friend_link (id, user1_id, user2_id) VALUES (0, ?, ?)", 1, 2)
+					return err
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "ok: nested DoInTx joins outer transaction",
+			expects: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+			fn: func(tx Transaction) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					return tx.DoInTx(ctx, func(ctx context.Context) error {
+						return nil
+					})
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "ng: rollback when fn returns error",
+			expects: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			fn: func(tx Transaction) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					return testutil.ErrTest
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "ng: error at BeginTx()",
+			expects: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin().WillReturnError(testutil.ErrTest)
+			},
+			fn: func(tx Transaction) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					return nil
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "ng: error at Commit()",
+			expects: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit().WillReturnError(testutil.ErrTest)
+			},
+			fn: func(tx Transaction) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					return nil
+				}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := testutil.NewSQLMock(t)
+			tt.expects(mock)
+
+			tx := NewTransaction(db)
+			err := tx.DoInTx(context.Background(), tt.fn(tx))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DoInTx() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_transaction_conn(t *testing.T) {
+	db, mock := testutil.NewSQLMock(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	assert.Equal(t, DBTX(db), conn(context.Background(), db))
+
+	err := NewTransaction(db).DoInTx(context.Background(), func(ctx context.Context) error {
+		assert.NotEqual(t, DBTX(db), conn(ctx, db))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_AfterCommit(t *testing.T) {
+	tests := []struct {
+		name    string
+		expects func(mock sqlmock.Sqlmock)
+		fnErr   error
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "ok: runs after commit in registration order",
+			expects: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+			want: []string{"fn", "first", "second"},
+		},
+		{
+			name: "ng: skipped on rollback",
+			expects: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			fnErr:   testutil.ErrTest,
+			want:    []string{"fn"},
+			wantErr: true,
+		},
+		{
+			name: "ng: skipped when Commit() fails",
+			expects: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit().WillReturnError(testutil.ErrTest)
+			},
+			want:    []string{"fn"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := testutil.NewSQLMock(t)
+			tt.expects(mock)
+
+			var got []string
+			err := NewTransaction(db).DoInTx(context.Background(), func(ctx context.Context) error {
+				AfterCommit(ctx, func() { got = append(got, "first") })
+				AfterCommit(ctx, func() { got = append(got, "second") })
+				got = append(got, "fn")
+				return tt.fnErr
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DoInTx() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("ok: runs at once without transaction", func(t *testing.T) {
+		ran := false
+		AfterCommit(context.Background(), func() { ran = true })
+		assert.True(t, ran)
+	})
+}
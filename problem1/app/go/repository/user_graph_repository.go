@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"problem1/model"
+)
+
+// userGraphRepository keeps the names held by a friendListGraphRepository in step with the
+// writes of the UserRepository it wraps, applying them once committed as the links are.
+type userGraphRepository struct {
+	UserRepository
+	g *friendListGraphRepository
+}
+
+// NewUserGraphRepository wraps ur so that its writes reach flr, which must have been made by
+// NewFriendListGraphRepository.
+func NewUserGraphRepository(ur UserRepository, flr FriendListRepository) (UserRepository, error) {
+	g, ok := flr.(*friendListGraphRepository)
+	if !ok {
+		return nil, errors.New("not a graph repository")
+	}
+
+	return &userGraphRepository{
+		UserRepository: ur,
+		g:              g,
+	}, nil
+}
+
+func (r *userGraphRepository) InsertUser(ctx context.Context, user *model.User) error {
+	if err := r.UserRepository.InsertUser(ctx, user); err != nil {
+		return err
+	}
+
+	r.setNames(ctx, user)
+
+	return nil
+}
+
+func (r *userGraphRepository) InsertUsers(ctx context.Context, users []*model.User) error {
+	if err := r.UserRepository.InsertUsers(ctx, users); err != nil {
+		return err
+	}
+
+	r.setNames(ctx, users...)
+
+	return nil
+}
+
+func (r *userGraphRepository) UpdateUserName(ctx context.Context, userId int, name string) error {
+	if err := r.UserRepository.UpdateUserName(ctx, userId, name); err != nil {
+		return err
+	}
+
+	r.setNames(ctx, &model.User{UserId: userId, Name: name})
+
+	return nil
+}
+
+func (r *userGraphRepository) DeleteUser(ctx context.Context, userId int) error {
+	if err := r.UserRepository.DeleteUser(ctx, userId); err != nil {
+		return err
+	}
+
+	r.g.afterCommit(ctx, func() {
+		delete(r.g.names, uint32(userId))
+	})
+
+	return nil
+}
+
+func (r *userGraphRepository) setNames(ctx context.Context, users ...*model.User) {
+	r.g.afterCommit(ctx, func() {
+		for _, u := range users {
+			r.g.names[uint32(u.UserId)] = u.Name
+		}
+	})
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"problem1/model"
+	"problem1/pkg/textnorm"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+type UserRepository interface {
+	InsertUser(ctx context.Context, user *model.User) error
+	InsertUsers(ctx context.Context, users []*model.User) error
+	GetUser(ctx context.Context, userId int) (*model.User, error)
+	UpdateUserName(ctx context.Context, userId int, name string) error
+	DeleteUser(ctx context.Context, userId int) error
+	SearchUsers(ctx context.Context, searcherId int, query string, limit, offset int) ([]*model.User, error)
+	CountSearchUsers(ctx context.Context, searcherId int, query string) (int, error)
+}
+
+var (
+	// ErrUserDuplicated is returned when a user of the user_id already exists.
+	ErrUserDuplicated = errors.New("user already exists")
+	// ErrUserNotFound is returned when the user does not exist.
+	ErrUserNotFound = errors.New("user not exist")
+)
+
+type userRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) UserRepository {
+	return &userRepository{
+		db: db,
+	}
+}
+
+func (r *userRepository) InsertUser(ctx context.Context, user *model.User) error {
+	const q = `
+	INSERT INTO users (id, user_id, name, search_name)
+	VALUES (0, ?, ?, ?)`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, q, user.UserId, user.Name, textnorm.Normalize(user.Name)); err != nil {
+		if isDuplicateEntryError(err) {
+			return ErrUserDuplicated
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// InsertUsers inserts users in one statement. The users already there take the new name.
+func (r *userRepository) InsertUsers(ctx context.Context, users []*model.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	rows := make([]string, 0, len(users))
+	args := make([]any, 0, len(users)*3)
+	for _, u := range users {
+		rows = append(rows, "(0, ?, ?, ?)")
+		args = append(args, u.UserId, u.Name, textnorm.Normalize(u.Name))
+	}
+	q := `
+	INSERT INTO users (id, user_id, name, search_name)
+	VALUES ` + strings.Join(rows, ", ") + ` AS new
+	ON DUPLICATE KEY UPDATE name = new.name, search_name = new.search_name`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, q, args...)
+
+	return err
+}
+
+func (r *userRepository) GetUser(ctx context.Context, userId int) (*model.User, error) {
+	const q = `
+	SELECT user_id, name
+	FROM users
+	WHERE user_id = ?`
+
+	user := &model.User{}
+	if err := conn(ctx, r.db).QueryRowContext(ctx, q, userId).Scan(&user.UserId, &user.Name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (r *userRepository) UpdateUserName(ctx context.Context, userId int, name string) error {
+	const q = `
+	UPDATE users
+	SET name = ?, search_name = ?
+	WHERE user_id = ?`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, q, name, textnorm.Normalize(name), userId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// MySQL counts only the rows changed, so the same name affects none either
+		if _, err := r.GetUser(ctx, userId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteUser deletes the row of users only. The links and the friend requests of the user
+// are left to the callers.
+func (r *userRepository) DeleteUser(ctx context.Context, userId int) error {
+	const q = `
+	DELETE FROM users
+	WHERE user_id = ?`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, q, userId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// likeEscaper escapes the wildcards of LIKE, with ! as the escape character.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// searchUsersCondition narrows the users down to those whose search_name contains the
+// pattern bound first, leaving out the searcher bound next and the users the searcher
+// blocked or is blocked by.
+const searchUsersCondition = `
+	FROM users AS U
+	WHERE U.search_name LIKE ? ESCAPE '!'
+	AND U.user_id <> ?
+	AND NOT EXISTS (
+		SELECT 1 FROM block_list AS B
+		WHERE B.user1_id = ? AND B.user2_id = U.user_id
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM block_list AS BB
+		WHERE BB.user1_id = U.user_id AND BB.user2_id = ?
+	)`
+
+// SearchUsers returns the users whose name contains query once both are folded by
+// textnorm.Normalize. The names starting with it come first, then in name order.
+func (r *userRepository) SearchUsers(ctx context.Context, searcherId int, query string, limit, offset int) ([]*model.User, error) {
+	const q = `
+	SELECT U.user_id, U.name` + searchUsersCondition + `
+	ORDER BY U.search_name LIKE ? ESCAPE '!' DESC, U.name, U.user_id
+	LIMIT ? OFFSET ?`
+
+	escaped := likeEscaper.Replace(textnorm.Normalize(query))
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, "%"+escaped+"%", searcherId, searcherId, searcherId, escaped+"%", limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*model.User
+	for rows.Next() {
+		user := &model.User{}
+		if err := rows.Scan(&user.UserId, &user.Name); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *userRepository) CountSearchUsers(ctx context.Context, searcherId int, query string) (int, error) {
+	const q = `
+	SELECT COUNT(*)` + searchUsersCondition
+
+	escaped := likeEscaper.Replace(textnorm.Normalize(query))
+	var count int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, q, "%"+escaped+"%", searcherId, searcherId, searcherId).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
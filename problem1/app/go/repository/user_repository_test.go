@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"problem1/model"
+	"problem1/pkg/testutil"
+)
+
+type userRepositoryTest struct {
+	db  *sql.DB
+	ur  UserRepository
+	flr FriendListRepository
+	ctx context.Context
+}
+
+func newUserRepositoryTest(t *testing.T) *userRepositoryTest {
+	t.Helper()
+
+	db := testutil.PrepareMySQL(t)
+
+	return &userRepositoryTest{
+		db:  db,
+		ur:  NewUserRepository(db),
+		flr: NewFriendListRepository(db),
+		ctx: context.Background(),
+	}
+}
+
+func Test_userRepository_InsertUsers(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(*userRepositoryTest)
+		users   []*model.User
+		want    []*model.Friend
+		wantErr bool
+	}{
+		{
+			name:    "ok",
+			prepare: func(rt *userRepositoryTest) {},
+			users:   []*model.User{{UserId: 222222, Name: "fuga"}, {UserId: 111111, Name: "hoge"}},
+			want:    []*model.Friend{{UserId: 111111, Name: "hoge"}, {UserId: 222222, Name: "fuga"}},
+			wantErr: false,
+		},
+		{
+			name: "ok: renames the users already there",
+			prepare: func(rt *userRepositoryTest) {
+				testutil.ExecSQL(t, rt.db, `INSERT INTO users (id, user_id, name) VALUES (0, ?, ?)`, 111111, "before")
+			},
+			users:   []*model.User{{UserId: 111111, Name: "hoge"}},
+			want:    []*model.Friend{{UserId: 111111, Name: "hoge"}},
+			wantErr: false,
+		},
+		{
+			name:    "ok: no user",
+			prepare: func(rt *userRepositoryTest) {},
+			users:   nil,
+			want:    nil,
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newUserRepositoryTest(t)
+			tt.prepare(rt)
+
+			if err := rt.ur.InsertUsers(rt.ctx, tt.users); (err != nil) != tt.wantErr {
+				t.Fatalf("InsertUsers() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+
+			got, err := rt.flr.GetUserListByUserIds(rt.ctx, []int{111111, 222222})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var friends []*model.Friend
+			if got != nil {
+				friends = got.Friends
+			}
+			assert.Equal(t, tt.want, friends)
+		})
+	}
+}
+
+func Test_userRepository_InsertUser(t *testing.T) {
+	rt := newUserRepositoryTest(t)
+
+	if err := rt.ur.InsertUser(rt.ctx, &model.User{UserId: 111111, Name: "藤井 太郎"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := rt.ur.GetUser(rt.ctx, 111111)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &model.User{UserId: 111111, Name: "藤井 太郎"}, got)
+
+	err = rt.ur.InsertUser(rt.ctx, &model.User{UserId: 111111, Name: "hoge"})
+	assert.ErrorIs(t, err, ErrUserDuplicated)
+}
+
+func Test_userRepository_GetUser(t *testing.T) {
+	rt := newUserRepositoryTest(t)
+
+	_, err := rt.ur.GetUser(rt.ctx, 111111)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func Test_userRepository_UpdateUserName(t *testing.T) {
+	tests := []struct {
+		name    string
+		userId  int
+		newName string
+		want    *model.User
+		wantErr error
+	}{
+		{
+			name:    "ok",
+			userId:  111111,
+			newName: "fuga",
+			want:    &model.User{UserId: 111111, Name: "fuga"},
+			wantErr: nil,
+		},
+		{
+			name:    "ok: same name",
+			userId:  111111,
+			newName: "hoge",
+			want:    &model.User{UserId: 111111, Name: "hoge"},
+			wantErr: nil,
+		},
+		{
+			name:    "ng: user not exist",
+			userId:  222222,
+			newName: "fuga",
+			want:    nil,
+			wantErr: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newUserRepositoryTest(t)
+			testutil.ExecSQL(t, rt.db, `INSERT INTO users (id, user_id, name) VALUES (0, ?, ?)`, 111111, "hoge")
+
+			err := rt.ur.UpdateUserName(rt.ctx, tt.userId, tt.newName)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if err != nil {
+				return
+			}
+
+			got, err := rt.ur.GetUser(rt.ctx, tt.userId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_userRepository_DeleteUser(t *testing.T) {
+	rt := newUserRepositoryTest(t)
+	testutil.ExecSQL(t, rt.db, `INSERT INTO users (id, user_id, name) VALUES (0, ?, ?)`, 111111, "hoge")
+
+	if err := rt.ur.DeleteUser(rt.ctx, 111111); err != nil {
+		t.Fatal(err)
+	}
+	_, err := rt.ur.GetUser(rt.ctx, 111111)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	err = rt.ur.DeleteUser(rt.ctx, 111111)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func Test_userRepository_SearchUsers(t *testing.T) {
+	searcherId := testutil.UserIDForDebug
+
+	tests := []struct {
+		name      string
+		query     string
+		limit     int
+		offset    int
+		want      []*model.User
+		wantTotal int
+	}{
+		{
+			name:  "ok: half-width katakana matches hiragana and katakana, prefix first",
+			query: "ｽｽﾞｷ",
+			limit: 10,
+			want: []*model.User{
+				{UserId: 111111, Name: "スズキ イチロウ"},
+				{UserId: 222222, Name: "すずき はなこ"},
+				{UserId: 333333, Name: "おお すずき"},
+			},
+			wantTotal: 3,
+		},
+		{
+			name:      "ok: paged",
+			query:     "すずき",
+			limit:     1,
+			offset:    1,
+			want:      []*model.User{{UserId: 222222, Name: "すずき はなこ"}},
+			wantTotal: 3,
+		},
+		{
+			name:      "ok: whitespace and full-width ignored",
+			query:     "ＳＵＺＵ ｋｉ",
+			limit:     10,
+			want:      []*model.User{{UserId: 555555, Name: "Suzuki"}},
+			wantTotal: 1,
+		},
+		{
+			name:      "ok: the searcher is left out",
+			query:     "藤井",
+			limit:     10,
+			want:      nil,
+			wantTotal: 0,
+		},
+		{
+			name:      "ok: wildcards are literal",
+			query:     "%",
+			limit:     10,
+			want:      nil,
+			wantTotal: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newUserRepositoryTest(t)
+			users := []*model.User{
+				{UserId: searcherId, Name: "藤井 太郎"},
+				{UserId: 111111, Name: "スズキ イチロウ"},
+				{UserId: 222222, Name: "すずき はなこ"},
+				{UserId: 333333, Name: "おお すずき"},
+				{UserId: 444444, Name: "鈴木 すずき"},
+				{UserId: 555555, Name: "Suzuki"},
+				{UserId: 666666, Name: "スズキ ブロック"},
+			}
+			if err := rt.ur.InsertUsers(rt.ctx, users); err != nil {
+				t.Fatal(err)
+			}
+			// hidden whoever blocked
+			testutil.ExecSQL(t, rt.db, `INSERT INTO block_list (id, user1_id, user2_id) VALUES (0, ?, ?)`, searcherId, 444444)
+			testutil.ExecSQL(t, rt.db, `INSERT INTO block_list (id, user1_id, user2_id) VALUES (0, ?, ?)`, 666666, searcherId)
+
+			got, err := rt.ur.SearchUsers(rt.ctx, searcherId, tt.query, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+
+			total, err := rt.ur.CountSearchUsers(rt.ctx, searcherId, tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.wantTotal, total)
+		})
+	}
+}
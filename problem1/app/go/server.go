@@ -0,0 +1,299 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"problem1/configs"
+	"problem1/controller"
+	"problem1/model"
+	"problem1/pkg/cache"
+	"problem1/pkg/cursor"
+	"problem1/pkg/httputil"
+	"problem1/pkg/httputil/middleware"
+	"problem1/repository"
+	"problem1/service"
+	"problem1/usecase"
+)
+
+// newServer wires the layers on db and routes them.
+func newServer(conf configs.Config, db *sql.DB) (*echo.Echo, error) {
+	if conf.Paging.CursorSecret == "" {
+		return nil, errors.New("PAGING_CURSOR_SECRET is required to sign the paging cursors")
+	}
+
+	cursorCodec := cursor.NewCodec([]byte(conf.Paging.CursorSecret))
+
+	blockPolicy, err := service.ParseBlockPolicy(conf.Block.Policy)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := repository.NewTransaction(db)
+	friendListRepository := repository.NewFriendListRepository(db)
+	if conf.Graph.Enabled {
+		if friendListRepository, err = repository.NewFriendListGraphRepository(context.Background(), db); err != nil {
+			return nil, err
+		}
+	}
+	userRepository := repository.NewUserRepository(db)
+	if conf.Graph.Enabled {
+		if userRepository, err = repository.NewUserGraphRepository(userRepository, friendListRepository); err != nil {
+			return nil, err
+		}
+	}
+	friendRequestRepository := repository.NewFriendRequestRepository(db)
+	friendListService := service.NewFriendListService(friendListRepository, friendRequestRepository, blockPolicy)
+	userService := service.NewUserService(userRepository, friendListRepository, friendRequestRepository)
+	if conf.Cache.Enabled {
+		friendListCache := service.NewFriendListCache(cache.NewLRU[service.FriendListCacheKey, *model.FriendList](conf.Cache.Size, conf.Cache.TTL), conf.Cache.LoadTimeout)
+		friendListService = service.NewCachedFriendListService(friendListService, friendListRepository, friendListCache)
+		userService = service.NewCachedUserService(userService, friendListCache)
+	}
+	friendListUseCase := usecase.NewFriendListUseCase(transaction, friendListService)
+	friendListController := controller.NewFriendListController(friendListUseCase, cursorCodec)
+	friendRequestService := service.NewFriendRequestService(friendRequestRepository, friendListRepository)
+	friendRequestUseCase := usecase.NewFriendRequestUseCase(transaction, friendListService, friendRequestService)
+	friendRequestController := controller.NewFriendRequestController(friendRequestUseCase)
+	suggestionService := service.NewSuggestionService(friendListRepository, blockPolicy)
+	suggestionUseCase := usecase.NewSuggestionUseCase(friendListService, suggestionService)
+	suggestionController := controller.NewSuggestionController(suggestionUseCase)
+	pathService := service.NewPathService(friendListRepository, blockPolicy, conf.Path.MaxVisitedUsers)
+	pathUseCase := usecase.NewPathUseCase(friendListService, pathService)
+	pathController := controller.NewPathController(pathUseCase, conf.Path.MaxDepth)
+	userUseCase := usecase.NewUserUseCase(transaction, userService)
+	userController := controller.NewUserController(userUseCase)
+	graphExportRepository := repository.NewGraphExportRepository(db)
+	graphExportService := service.NewGraphExportService(friendListRepository, graphExportRepository)
+	graphExportUseCase := usecase.NewGraphExportUseCase(transaction, friendListService, graphExportService)
+	graphExportController := controller.NewGraphExportController(graphExportUseCase)
+
+	paging := middleware.Paging(conf.Paging, cursorCodec)
+	admin := middleware.Admin(conf.Admin.Token)
+	deprecatedRoutes := middleware.NewDeprecatedRoutes(conf.Legacy)
+	openAPI, err := middleware.NewOpenAPI(conf.OpenAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	e := echo.New()
+	e.HTTPErrorHandler = httputil.HTTPErrorHandler
+	e.Use(middleware.RequestID, openAPI.Validate)
+
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "minimal_sns_app")
+	})
+
+	e.GET("/get_friend_list", func(c echo.Context) error {
+		if err := friendListController.GetFriendListByUserId(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	}, deprecatedRoutes.Route(http.MethodGet, "/get_friend_list", "/v1/users/{id}/friends"), paging)
+
+	e.GET("/get_friend_of_friend_list", func(c echo.Context) error {
+		if err := friendListController.GetFriendListOfFriendsByUserId(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	}, deprecatedRoutes.Route(http.MethodGet, "/get_friend_of_friend_list", "/v1/users/{id}/friends-of-friends"), paging)
+
+	e.GET("/get_friend_of_friend_list_paging", func(c echo.Context) error {
+		if err := friendListController.GetFriendListOfFriendsByUserIdWithPaging(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	}, deprecatedRoutes.Route(http.MethodGet, "/get_friend_of_friend_list_paging", "/v1/users/{id}/friends-of-friends"), paging)
+
+	e.GET("/get_block_list", func(c echo.Context) error {
+		if err := friendListController.GetBlockListByUserId(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	}, deprecatedRoutes.Route(http.MethodGet, "/get_block_list", "/v1/users/{id}/blocks"), paging)
+
+	// the legacy routes above are kept as aliases of these
+	v1 := e.Group("/v1")
+
+	v1.GET("/users/:id/friends", func(c echo.Context) error {
+		if err := friendListController.GetFriends(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	}, paging)
+
+	v1.GET("/users/:id/friends-of-friends", func(c echo.Context) error {
+		if err := friendListController.GetFriendsOfFriends(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	}, paging)
+
+	v1.GET("/users/:id/blocks", func(c echo.Context) error {
+		if err := friendListController.GetBlocks(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	}, paging)
+
+	e.GET("/get_neighbourhood_list", func(c echo.Context) error {
+		if err := friendListController.GetNeighbourhood(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	}, paging)
+
+	e.GET("/get_friend_suggestion_list", func(c echo.Context) error {
+		if err := suggestionController.GetSuggestions(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	}, paging)
+
+	e.POST("/users", func(c echo.Context) error {
+		if err := userController.PostUser(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	})
+
+	e.GET("/users/search", func(c echo.Context) error {
+		if err := userController.SearchUsers(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	}, paging)
+
+	e.GET("/users/:id", func(c echo.Context) error {
+		if err := userController.GetUser(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	})
+
+	e.PATCH("/users/:id", func(c echo.Context) error {
+		if err := userController.PatchUser(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	})
+
+	e.DELETE("/users/:id", func(c echo.Context) error {
+		if err := userController.DeleteUser(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	})
+
+	e.GET("/users/:a/path/:b", func(c echo.Context) error {
+		if err := pathController.GetShortestPath(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	})
+
+	e.POST("/user_link", func(c echo.Context) error {
+		if err := friendListController.PostUserLink(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	})
+
+	e.POST("/user_links", func(c echo.Context) error {
+		if err := friendListController.PostUserLinks(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	})
+
+	e.DELETE("/user_link", func(c echo.Context) error {
+		if err := friendListController.DeleteUserLink(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	})
+
+	e.POST("/friend_request", func(c echo.Context) error {
+		if err := friendRequestController.PostFriendRequest(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	})
+
+	e.GET("/friend_request/incoming", func(c echo.Context) error {
+		if err := friendRequestController.GetIncomingFriendRequests(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	})
+
+	e.GET("/friend_request/outgoing", func(c echo.Context) error {
+		if err := friendRequestController.GetOutgoingFriendRequests(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	})
+
+	e.POST("/friend_request/accept", func(c echo.Context) error {
+		if err := friendRequestController.AcceptFriendRequest(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	})
+
+	e.POST("/friend_request/reject", func(c echo.Context) error {
+		if err := friendRequestController.RejectFriendRequest(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	})
+
+	e.POST("/friend_request/cancel", func(c echo.Context) error {
+		if err := friendRequestController.CancelFriendRequest(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	})
+
+	e.GET("/admin/graph/export", func(c echo.Context) error {
+		if err := graphExportController.ExportGraph(c); err != nil {
+			return httputil.RespondError(c, err)
+		}
+
+		return nil
+	}, admin)
+
+	e.GET("/admin/legacy_usage", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, deprecatedRoutes.Usage())
+	}, admin)
+
+	return e, nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"problem1/configs"
+	"problem1/pkg/httputil"
+	"problem1/pkg/testutil"
+)
+
+func Test_newServer_contract(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "contract")
+	t.Setenv("PAGING_CURSOR_SECRET", "contract")
+	conf := configs.Get()
+
+	newHandler := func(t *testing.T) http.Handler {
+		e, err := newServer(conf, testutil.PrepareMySQL(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return e
+	}
+
+	t.Run("json", func(t *testing.T) {
+		testutil.RunContract(t, conf.OpenAPI.Spec, testutil.ContractOptions{
+			// the users in the test data
+			Params: map[string]string{"ID": "1", "id": "1", "a": "1", "b": "2"},
+			Header: http.Header{echo.HeaderAuthorization: {"Bearer contract"}},
+		}, newHandler)
+	})
+
+	t.Run("problem+json", func(t *testing.T) {
+		testutil.RunContract(t, conf.OpenAPI.Spec, testutil.ContractOptions{
+			Params: map[string]string{"ID": "1", "id": "1", "a": "1", "b": "2"},
+			Header: http.Header{
+				echo.HeaderAuthorization: {"Bearer contract"},
+				echo.HeaderAccept:        {httputil.MIMEApplicationProblemJSON},
+			},
+		}, newHandler)
+	})
+}
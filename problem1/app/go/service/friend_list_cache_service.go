@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"problem1/model"
+	"problem1/pkg/cache"
+	"problem1/repository"
+)
+
+// FriendListCacheKey names a friend list, or a friends of friends list with OfFriends, in the cache.
+type FriendListCacheKey struct {
+	OfFriends bool
+	UserId    int
+	Sort      model.FriendListSort
+}
+
+// cachedSorts are all the sorts a list may be cached under, so that evicting a user drops each of them.
+var cachedSorts = []model.FriendListSort{
+	"",
+	model.FriendListSortUserId,
+	model.FriendListSortName,
+	model.FriendListSortNameDesc,
+	model.FriendListSortAddedAt,
+}
+
+// FriendListCache holds the friend lists and the friends of friends lists cached by the services
+// writing what they are made of: the links by the cached FriendListService, the names by the
+// cached UserService. The concurrent misses of a list share one computation.
+type FriendListCache struct {
+	cache cache.Cache[FriendListCacheKey, *model.FriendList]
+	group cache.Group[FriendListCacheKey, *model.FriendList]
+	// generation counts the evictions, so that a list computed while one ran is not cached.
+	generation uint64
+	// loadTimeout bounds a computation, which outlives the caller that started it.
+	loadTimeout time.Duration
+}
+
+func NewFriendListCache(c cache.Cache[FriendListCacheKey, *model.FriendList], loadTimeout time.Duration) *FriendListCache {
+	return &FriendListCache{cache: c, loadTimeout: loadTimeout}
+}
+
+// get returns a copy of the cached list, since the callers set their page on it.
+// The list is loaded on a context detached from ctx, since the callers waiting on the
+// computation would otherwise fail with the first one when it goes away.
+func (fc *FriendListCache) get(ctx context.Context, key FriendListCacheKey, load func(ctx context.Context) (*model.FriendList, error)) (*model.FriendList, error) {
+	friendList, ok := fc.cache.Get(key)
+	if !ok {
+		var err error
+		friendList, err = fc.group.Do(key, func() (*model.FriendList, error) {
+			loadCtx, cancel := context.WithTimeout(detachedContext{ctx}, fc.loadTimeout)
+			defer cancel()
+
+			generation := atomic.LoadUint64(&fc.generation)
+			friendList, err := load(loadCtx)
+			if err != nil {
+				return nil, err
+			}
+			if atomic.LoadUint64(&fc.generation) == generation {
+				fc.cache.Add(key, friendList)
+			}
+
+			return friendList, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	copied := *friendList
+	return &copied, nil
+}
+
+// detachedContext carries the values of its parent but neither its deadline nor its cancellation.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+func (c detachedContext) Value(key any) any         { return c.parent.Value(key) }
+
+// evict drops the lists of the users, and the friends of friends lists of friendOf[userId] for each of them.
+func (fc *FriendListCache) evict(userIds []int, friendOf map[int][]int) {
+	atomic.AddUint64(&fc.generation, 1)
+
+	for _, userId := range userIds {
+		fc.evictLists(false, userId)
+		fc.evictLists(true, userId)
+		for _, friendOfId := range friendOf[userId] {
+			fc.evictLists(true, friendOfId)
+		}
+	}
+}
+
+func (fc *FriendListCache) evictLists(ofFriends bool, userId int) {
+	for _, sort := range cachedSorts {
+		fc.cache.Remove(FriendListCacheKey{OfFriends: ofFriends, UserId: userId, Sort: sort})
+	}
+}
+
+// purge drops every list.
+func (fc *FriendListCache) purge() {
+	atomic.AddUint64(&fc.generation, 1)
+	fc.cache.Purge()
+}
+
+// cachedFriendListService caches the friend lists and the friends of friends lists computed by
+// the FriendListService it wraps in fc. Every link written through it evicts the lists the link
+// may change once committed.
+type cachedFriendListService struct {
+	FriendListService
+	flr repository.FriendListRepository
+	fc  *FriendListCache
+}
+
+func NewCachedFriendListService(fls FriendListService, flr repository.FriendListRepository, fc *FriendListCache) FriendListService {
+	return &cachedFriendListService{
+		FriendListService: fls,
+		flr:               flr,
+		fc:                fc,
+	}
+}
+
+func (s *cachedFriendListService) InsertUserLink(ctx context.Context, ulfr *model.UserLinkForRequest) error {
+	if err := s.FriendListService.InsertUserLink(ctx, ulfr); err != nil {
+		return err
+	}
+
+	return s.evictAfterCommit(ctx, ulfr.User1Id, ulfr.User2Id)
+}
+
+func (s *cachedFriendListService) InsertUserLinks(ctx context.Context, ulfrs []*model.UserLinkForRequest) ([]model.UserLinkStatus, error) {
+	statuses, err := s.FriendListService.InsertUserLinks(ctx, ulfrs)
+	if err != nil {
+		return nil, err
+	}
+
+	userIds := make([]int, 0, len(ulfrs)*2)
+	for _, ulfr := range ulfrs {
+		userIds = append(userIds, ulfr.User1Id, ulfr.User2Id)
+	}
+	if err := s.evictAfterCommit(ctx, userIds...); err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+func (s *cachedFriendListService) DeleteUserLink(ctx context.Context, ulfr *model.UserLinkForRequest) error {
+	if err := s.FriendListService.DeleteUserLink(ctx, ulfr); err != nil {
+		return err
+	}
+
+	return s.evictAfterCommit(ctx, ulfr.User1Id, ulfr.User2Id)
+}
+
+func (s *cachedFriendListService) BlockUser(ctx context.Context, userId, blockUserId int) error {
+	if err := s.FriendListService.BlockUser(ctx, userId, blockUserId); err != nil {
+		return err
+	}
+
+	return s.evictAfterCommit(ctx, userId, blockUserId)
+}
+
+func (s *cachedFriendListService) AddFriendship(ctx context.Context, user1Id, user2Id int) error {
+	if err := s.FriendListService.AddFriendship(ctx, user1Id, user2Id); err != nil {
+		return err
+	}
+
+	return s.evictAfterCommit(ctx, user1Id, user2Id)
+}
+
+// evictAfterCommit drops the lists a change of the links between the users may change: the
+// users' friend lists and friends of friends lists, and the friends of friends lists of the
+// users having any of them as a friend. The blocks between the users only hide them from
+// each other, so the lists of the others stay.
+func (s *cachedFriendListService) evictAfterCommit(ctx context.Context, userIds ...int) error {
+	if len(userIds) == 0 {
+		return nil
+	}
+
+	friendOf, err := s.flr.GetFriendOfUserIdsByUserIds(ctx, userIds)
+	if err != nil {
+		return err
+	}
+
+	repository.AfterCommit(ctx, func() {
+		s.fc.evict(userIds, friendOf)
+	})
+
+	return nil
+}
+
+func (s *cachedFriendListService) GetFriendListByUserId(ctx context.Context, userId int, sort model.FriendListSort) (*model.FriendList, error) {
+	return s.fc.get(ctx, FriendListCacheKey{UserId: userId, Sort: sort}, func(ctx context.Context) (*model.FriendList, error) {
+		return s.FriendListService.GetFriendListByUserId(ctx, userId, sort)
+	})
+}
+
+func (s *cachedFriendListService) GetFriendListOfFriendsByUserId(ctx context.Context, userId int, sort model.FriendListSort) (*model.FriendList, error) {
+	return s.fc.get(ctx, FriendListCacheKey{OfFriends: true, UserId: userId, Sort: sort}, func(ctx context.Context) (*model.FriendList, error) {
+		return s.FriendListService.GetFriendListOfFriendsByUserId(ctx, userId, sort)
+	})
+}
+
+// GetFriendListByUserIdWithPaging cuts the page out of the cached list, so that paging through
+// a list reads it once.
+func (s *cachedFriendListService) GetFriendListByUserIdWithPaging(ctx context.Context, userId int, sort model.FriendListSort, limit, offset int) (*model.FriendList, error) {
+	friendList, err := s.GetFriendListByUserId(ctx, userId, sort)
+	if err != nil {
+		return nil, err
+	}
+	friendList.Friends, friendList.Paging = paginate(friendList.Friends, limit, offset)
+
+	return friendList, nil
+}
+
+// GetFriendListOfFriendsByUserIdWithPaging cuts the page out of the cached list, as
+// GetFriendListByUserIdWithPaging does.
+func (s *cachedFriendListService) GetFriendListOfFriendsByUserIdWithPaging(ctx context.Context, userId int, sort model.FriendListSort, limit, offset int) (*model.FriendList, error) {
+	friendList, err := s.GetFriendListOfFriendsByUserId(ctx, userId, sort)
+	if err != nil {
+		return nil, err
+	}
+	friendList.Friends, friendList.Paging = paginate(friendList.Friends, limit, offset)
+
+	return friendList, nil
+}
+
+// GetFriendListOfFriendsByUserIdWithCursor seeks the page after lastUserId in the cached list
+// sorted by user_id.
+func (s *cachedFriendListService) GetFriendListOfFriendsByUserIdWithCursor(ctx context.Context, userId, lastUserId, limit int) (*model.FriendList, error) {
+	friendList, err := s.GetFriendListOfFriendsByUserId(ctx, userId, model.FriendListSortUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	friends := friendList.Friends
+	start := sort.Search(len(friends), func(i int) bool {
+		return friends[i].UserId > lastUserId
+	})
+	end := start + limit
+	if end > len(friends) {
+		end = len(friends)
+	}
+	friendList.Friends = friends[start:end]
+	friendList.Paging = &model.Paging{
+		Total:   len(friends),
+		Limit:   limit,
+		HasNext: end < len(friends),
+	}
+
+	return friendList, nil
+}
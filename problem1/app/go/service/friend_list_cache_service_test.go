@@ -0,0 +1,407 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/mock/mock_repository"
+	"problem1/model"
+	"problem1/pkg/cache"
+	"problem1/pkg/testutil"
+	"problem1/repository"
+)
+
+type cachedFriendListServiceTest struct {
+	mock  sqlmock.Sqlmock
+	tx    repository.Transaction
+	flr   *mock_repository.MockFriendListRepository
+	frr   *mock_repository.MockFriendRequestRepository
+	cache *cache.LRU[FriendListCacheKey, *model.FriendList]
+	fls   FriendListService
+	ctx   context.Context
+}
+
+func newCachedFriendListServiceTest(t *testing.T) *cachedFriendListServiceTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	db, mock := testutil.NewSQLMock(t)
+	flr := mock_repository.NewMockFriendListRepository(ctrl)
+	frr := mock_repository.NewMockFriendRequestRepository(ctrl)
+	c := cache.NewLRU[FriendListCacheKey, *model.FriendList](100, time.Minute)
+
+	return &cachedFriendListServiceTest{
+		mock:  mock,
+		tx:    repository.NewTransaction(db),
+		flr:   flr,
+		frr:   frr,
+		cache: c,
+		fls:   NewCachedFriendListService(NewFriendListService(flr, frr, BlockPolicyOneWay), flr, NewFriendListCache(c, time.Minute)),
+		ctx:   context.Background(),
+	}
+}
+
+func Test_cachedFriendListService_GetFriendListByUserId(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	st := newCachedFriendListServiceTest(t)
+	st.flr.EXPECT().GetFriendListByUserIdExcludingBlockUsers(gomock.Any(), userId, false, model.FriendListSortName).Return(newFriendList(), nil).Times(1)
+
+	got, err := st.fls.GetFriendListByUserId(st.ctx, userId, model.FriendListSortName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, newFriendList(), got)
+	got.Paging = model.NewPaging(2, 1, 0)
+
+	got, err = st.fls.GetFriendListByUserId(st.ctx, userId, model.FriendListSortName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, newFriendList(), got, "the page set on a returned list is not cached")
+}
+
+func Test_cachedFriendListService_GetFriendListByUserIdWithPaging(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	st := newCachedFriendListServiceTest(t)
+	st.flr.EXPECT().GetFriendListByUserIdExcludingBlockUsers(gomock.Any(), userId, false, model.FriendListSortUserId).Return(newFriendList(), nil).Times(1)
+
+	for i, friend := range newFriendList().Friends {
+		got, err := st.fls.GetFriendListByUserIdWithPaging(st.ctx, userId, model.FriendListSortUserId, 1, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, &model.FriendList{
+			Friends: []*model.Friend{friend},
+			Paging:  model.NewPaging(2, 1, i),
+		}, got)
+	}
+
+	got, err := st.fls.GetFriendListByUserId(st.ctx, userId, model.FriendListSortUserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, newFriendList(), got, "the page cut out of a list is not cached")
+}
+
+func Test_cachedFriendListService_GetFriendListOfFriendsByUserId(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	st := newCachedFriendListServiceTest(t)
+	gomock.InOrder(
+		st.flr.EXPECT().GetFriendListOfFriendsByUserId(gomock.Any(), userId, false, model.FriendListSortUserId).Return(nil, testutil.ErrTest),
+		st.flr.EXPECT().GetFriendListOfFriendsByUserId(gomock.Any(), userId, false, model.FriendListSortUserId).Return(newFriendList(), nil),
+	)
+
+	_, err := st.fls.GetFriendListOfFriendsByUserId(st.ctx, userId, model.FriendListSortUserId)
+	assert.ErrorIs(t, err, testutil.ErrTest)
+
+	for i := 0; i < 2; i++ {
+		got, err := st.fls.GetFriendListOfFriendsByUserId(st.ctx, userId, model.FriendListSortUserId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, newFriendList(), got)
+	}
+}
+
+func Test_cachedFriendListService_GetFriendListOfFriendsByUserIdWithPaging(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	st := newCachedFriendListServiceTest(t)
+	st.flr.EXPECT().GetFriendListOfFriendsByUserId(gomock.Any(), userId, false, model.FriendListSortName).Return(newFriendList(), nil).Times(1)
+
+	for i, friend := range newFriendList().Friends {
+		got, err := st.fls.GetFriendListOfFriendsByUserIdWithPaging(st.ctx, userId, model.FriendListSortName, 1, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, &model.FriendList{
+			Friends: []*model.Friend{friend},
+			Paging:  model.NewPaging(2, 1, i),
+		}, got)
+	}
+
+	got, err := st.fls.GetFriendListOfFriendsByUserId(st.ctx, userId, model.FriendListSortName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, newFriendList(), got, "the page cut out of a list is not cached")
+}
+
+func Test_cachedFriendListService_GetFriendListOfFriendsByUserIdWithCursor(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	friends := newFriendList().Friends
+	tests := []struct {
+		name       string
+		lastUserId int
+		limit      int
+		want       *model.FriendList
+	}{
+		{
+			name:       "ok: first page",
+			lastUserId: 0,
+			limit:      1,
+			want: &model.FriendList{
+				Friends: friends[:1],
+				Paging:  &model.Paging{Total: 2, Limit: 1, HasNext: true},
+			},
+		},
+		{
+			name:       "ok: last page",
+			lastUserId: 111111,
+			limit:      1,
+			want: &model.FriendList{
+				Friends: friends[1:],
+				Paging:  &model.Paging{Total: 2, Limit: 1, HasNext: false},
+			},
+		},
+		{
+			name:       "ok: between the user ids",
+			lastUserId: 111112,
+			limit:      10,
+			want: &model.FriendList{
+				Friends: friends[1:],
+				Paging:  &model.Paging{Total: 2, Limit: 10, HasNext: false},
+			},
+		},
+		{
+			name:       "ok: past the end",
+			lastUserId: 222222,
+			limit:      10,
+			want: &model.FriendList{
+				Friends: []*model.Friend{},
+				Paging:  &model.Paging{Total: 2, Limit: 10, HasNext: false},
+			},
+		},
+	}
+
+	st := newCachedFriendListServiceTest(t)
+	st.flr.EXPECT().GetFriendListOfFriendsByUserId(gomock.Any(), userId, false, model.FriendListSortUserId).Return(newFriendList(), nil).Times(1)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := st.fls.GetFriendListOfFriendsByUserIdWithCursor(st.ctx, userId, tt.lastUserId, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_cachedFriendListService_evictsOnWrite(t *testing.T) {
+	const (
+		user1Id   = 111111
+		user2Id   = 222222
+		friendOf  = 333333
+		unrelated = 444444
+	)
+	ulfr := &model.UserLinkForRequest{User1Id: user1Id, User2Id: user2Id, Table: "friend_link"}
+
+	tests := []struct {
+		name  string
+		write func(st *cachedFriendListServiceTest) error
+	}{
+		{
+			name: "InsertUserLink",
+			write: func(st *cachedFriendListServiceTest) error {
+				st.flr.EXPECT().CheckUserLink(st.ctx, user1Id, user2Id, "friend_link").Return(sql.ErrNoRows)
+				st.flr.EXPECT().InsertUserLink(st.ctx, user1Id, user2Id, "friend_link").Return(nil)
+				return st.fls.InsertUserLink(st.ctx, ulfr)
+			},
+		},
+		{
+			name: "InsertUserLinks",
+			write: func(st *cachedFriendListServiceTest) error {
+				st.flr.EXPECT().GetExistingUserLinks(st.ctx, "friend_link", [][2]int{{user1Id, user2Id}}).Return(nil, nil)
+				st.flr.EXPECT().InsertUserLinks(st.ctx, "friend_link", [][2]int{{user1Id, user2Id}}).Return(nil)
+				_, err := st.fls.InsertUserLinks(st.ctx, []*model.UserLinkForRequest{ulfr})
+				return err
+			},
+		},
+		{
+			name: "DeleteUserLink",
+			write: func(st *cachedFriendListServiceTest) error {
+				st.flr.EXPECT().DeleteUserLink(st.ctx, user1Id, user2Id, "friend_link").Return(nil)
+				return st.fls.DeleteUserLink(st.ctx, ulfr)
+			},
+		},
+		{
+			name: "BlockUser",
+			write: func(st *cachedFriendListServiceTest) error {
+				st.flr.EXPECT().DeleteFriendLinksBetween(st.ctx, user1Id, user2Id).Return(nil)
+				st.frr.EXPECT().CancelPendingFriendRequestsBetween(st.ctx, user1Id, user2Id).Return(nil)
+				st.flr.EXPECT().CheckUserLink(st.ctx, user1Id, user2Id, "block_list").Return(sql.ErrNoRows)
+				st.flr.EXPECT().InsertUserLink(st.ctx, user1Id, user2Id, "block_list").Return(nil)
+				return st.fls.BlockUser(st.ctx, user1Id, user2Id)
+			},
+		},
+		{
+			name: "AddFriendship",
+			write: func(st *cachedFriendListServiceTest) error {
+				st.flr.EXPECT().InsertUserLink(st.ctx, user1Id, user2Id, "friend_link").Return(nil)
+				st.flr.EXPECT().InsertUserLink(st.ctx, user2Id, user1Id, "friend_link").Return(nil)
+				return st.fls.AddFriendship(st.ctx, user1Id, user2Id)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newCachedFriendListServiceTest(t)
+			for _, userId := range []int{user1Id, user2Id, friendOf, unrelated} {
+				for _, ofFriends := range []bool{false, true} {
+					st.cache.Add(FriendListCacheKey{OfFriends: ofFriends, UserId: userId, Sort: model.FriendListSortName}, newFriendList())
+				}
+			}
+			st.flr.EXPECT().GetFriendOfUserIdsByUserIds(st.ctx, []int{user1Id, user2Id}).Return(map[int][]int{user1Id: {friendOf}}, nil)
+
+			if err := tt.write(st); err != nil {
+				t.Fatal(err)
+			}
+
+			cached := func(ofFriends bool, userId int) bool {
+				_, ok := st.cache.Get(FriendListCacheKey{OfFriends: ofFriends, UserId: userId, Sort: model.FriendListSortName})
+				return ok
+			}
+			assert.False(t, cached(false, user1Id))
+			assert.False(t, cached(true, user1Id))
+			assert.False(t, cached(false, user2Id))
+			assert.False(t, cached(true, user2Id))
+			assert.True(t, cached(false, friendOf), "a friend of the user keeps the same friends")
+			assert.False(t, cached(true, friendOf))
+			assert.True(t, cached(false, unrelated))
+			assert.True(t, cached(true, unrelated))
+		})
+	}
+}
+
+func Test_cachedFriendListService_evictsAfterCommit(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	key := FriendListCacheKey{UserId: userId, Sort: model.FriendListSortUserId}
+
+	tests := []struct {
+		name       string
+		expects    func(st *cachedFriendListServiceTest)
+		fnErr      error
+		wantCached bool
+	}{
+		{
+			name: "ok: evicted when committed",
+			expects: func(st *cachedFriendListServiceTest) {
+				st.mock.ExpectBegin()
+				st.mock.ExpectCommit()
+			},
+			fnErr:      nil,
+			wantCached: false,
+		},
+		{
+			name: "ok: kept when rolled back",
+			expects: func(st *cachedFriendListServiceTest) {
+				st.mock.ExpectBegin()
+				st.mock.ExpectRollback()
+			},
+			fnErr:      testutil.ErrTest,
+			wantCached: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newCachedFriendListServiceTest(t)
+			tt.expects(st)
+			st.cache.Add(key, newFriendList())
+			st.flr.EXPECT().InsertUserLink(gomock.Any(), userId, 111111, "friend_link").Return(nil)
+			st.flr.EXPECT().InsertUserLink(gomock.Any(), 111111, userId, "friend_link").Return(nil)
+			st.flr.EXPECT().GetFriendOfUserIdsByUserIds(gomock.Any(), []int{userId, 111111}).Return(map[int][]int{}, nil)
+
+			err := st.tx.DoInTx(st.ctx, func(ctx context.Context) error {
+				if err := st.fls.AddFriendship(ctx, userId, 111111); err != nil {
+					return err
+				}
+
+				_, ok := st.cache.Get(key)
+				assert.True(t, ok, "not evicted before the commit")
+
+				return tt.fnErr
+			})
+			assert.ErrorIs(t, err, tt.fnErr)
+
+			_, ok := st.cache.Get(key)
+			assert.Equal(t, tt.wantCached, ok)
+		})
+	}
+}
+
+func Test_cachedFriendListService_writeError(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	key := FriendListCacheKey{UserId: userId, Sort: model.FriendListSortUserId}
+	st := newCachedFriendListServiceTest(t)
+	st.cache.Add(key, newFriendList())
+	st.flr.EXPECT().DeleteFriendLinksBetween(st.ctx, userId, 111111).Return(testutil.ErrTest)
+
+	err := st.fls.BlockUser(st.ctx, userId, 111111)
+	assert.ErrorIs(t, err, testutil.ErrTest)
+
+	_, ok := st.cache.Get(key)
+	assert.True(t, ok)
+}
+
+func Test_cachedFriendListService_notCachedWhenEvictedWhileLoading(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	st := newCachedFriendListServiceTest(t)
+	st.flr.EXPECT().GetFriendListByUserIdExcludingBlockUsers(gomock.Any(), userId, false, model.FriendListSortUserId).DoAndReturn(
+		func(ctx context.Context, userId int, excludeBlockedBy bool, sort model.FriendListSort) (*model.FriendList, error) {
+			// a friendship committed after the list was read
+			if err := st.fls.AddFriendship(ctx, userId, 333333); err != nil {
+				return nil, err
+			}
+
+			return newFriendList(), nil
+		})
+	st.flr.EXPECT().InsertUserLink(gomock.Any(), userId, 333333, "friend_link").Return(nil)
+	st.flr.EXPECT().InsertUserLink(gomock.Any(), 333333, userId, "friend_link").Return(nil)
+	st.flr.EXPECT().GetFriendOfUserIdsByUserIds(gomock.Any(), []int{userId, 333333}).Return(map[int][]int{}, nil)
+
+	if _, err := st.fls.GetFriendListByUserId(st.ctx, userId, model.FriendListSortUserId); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, st.cache.Len())
+
+	st.flr.EXPECT().GetFriendListByUserIdExcludingBlockUsers(gomock.Any(), userId, false, model.FriendListSortUserId).Return(newFriendList(), nil)
+	if _, err := st.fls.GetFriendListByUserId(st.ctx, userId, model.FriendListSortUserId); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, st.cache.Len())
+}
+
+func Test_cachedFriendListService_loadsDetachedFromCaller(t *testing.T) {
+	type ctxKey struct{}
+	userId := testutil.UserIDForDebug
+	st := newCachedFriendListServiceTest(t)
+	ctx, cancel := context.WithCancel(context.WithValue(st.ctx, ctxKey{}, "value"))
+	st.flr.EXPECT().GetFriendListByUserIdExcludingBlockUsers(gomock.Any(), userId, false, model.FriendListSortUserId).DoAndReturn(
+		func(ctx context.Context, userId int, excludeBlockedBy bool, sort model.FriendListSort) (*model.FriendList, error) {
+			// the caller that started the load goes away, while others may be waiting on it
+			cancel()
+
+			assert.NoError(t, ctx.Err())
+			assert.Equal(t, "value", ctx.Value(ctxKey{}))
+			deadline, ok := ctx.Deadline()
+			assert.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+
+			return newFriendList(), nil
+		})
+
+	got, err := st.fls.GetFriendListByUserId(ctx, userId, model.FriendListSortUserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, newFriendList(), got)
+	assert.Equal(t, 1, st.cache.Len())
+}
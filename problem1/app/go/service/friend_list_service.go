@@ -1,10 +1,11 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"errors"
-
-	"github.com/labstack/echo/v4"
+	"fmt"
+	"sort"
 
 	"problem1/model"
 	"problem1/repository"
@@ -13,93 +14,417 @@ import (
 //go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
 
 type FriendListService interface {
-	CheckUserExist(userId int) (bool, error)
-	InsertUserLink(ulfr *model.UserLinkForRequest) error
-	GetFriendListByUserId(c echo.Context) (*model.FriendList, error)
-	GetFriendListOfFriendsByUserId(c echo.Context) (*model.FriendList, error)
-	GetFriendListOfFriendsByUserIdWithPaging(c echo.Context) (*model.FriendList, error)
+	CheckUserExist(ctx context.Context, userId int) (bool, error)
+	CheckUsersExist(ctx context.Context, userIds []int) (map[int]bool, error)
+	InsertUserLink(ctx context.Context, ulfr *model.UserLinkForRequest) error
+	InsertUserLinks(ctx context.Context, ulfrs []*model.UserLinkForRequest) ([]model.UserLinkStatus, error)
+	DeleteUserLink(ctx context.Context, ulfr *model.UserLinkForRequest) error
+	BlockUser(ctx context.Context, userId, blockUserId int) error
+	AddFriendship(ctx context.Context, user1Id, user2Id int) error
+	GetFriendListByUserId(ctx context.Context, userId int, sort model.FriendListSort) (*model.FriendList, error)
+	GetFriendListByUserIdWithPaging(ctx context.Context, userId int, sort model.FriendListSort, limit, offset int) (*model.FriendList, error)
+	GetFriendListOfFriendsByUserId(ctx context.Context, userId int, sort model.FriendListSort) (*model.FriendList, error)
+	GetFriendListOfFriendsByUserIdWithPaging(ctx context.Context, userId int, sort model.FriendListSort, limit, offset int) (*model.FriendList, error)
+	GetFriendListOfFriendsByUserIdWithCursor(ctx context.Context, userId, lastUserId, limit int) (*model.FriendList, error)
+	GetBlockListByUserId(ctx context.Context, userId int) (*model.BlockList, error)
+	GetBlockListByUserIdWithPaging(ctx context.Context, userId, limit, offset int) (*model.BlockList, error)
+	GetNeighbourhood(ctx context.Context, userId, minDepth, maxDepth, limit, offset int) (*model.NeighbourList, error)
+}
+
+// MaxNeighbourhoodDepth is the deepest hop GetNeighbourhood reaches.
+const MaxNeighbourhoodDepth = 4
+
+// BlockPolicy decides whose blocks hide users from a user on reads.
+type BlockPolicy string
+
+const (
+	// BlockPolicyOneWay hides only the users the user blocked.
+	BlockPolicyOneWay BlockPolicy = "one_way"
+	// BlockPolicyMutual also hides the users who blocked the user, so neither sees the other.
+	BlockPolicyMutual BlockPolicy = "mutual"
+)
+
+var ErrBlockPolicyInvalid = errors.New("block policy invalid")
+
+// ParseBlockPolicy returns the BlockPolicy named s.
+func ParseBlockPolicy(s string) (BlockPolicy, error) {
+	switch policy := BlockPolicy(s); policy {
+	case BlockPolicyOneWay, BlockPolicyMutual:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrBlockPolicyInvalid, s)
+	}
+}
+
+// hidesBlockedBy reports whether the users who blocked a user are hidden from them as well.
+func (p BlockPolicy) hidesBlockedBy() bool {
+	return p == BlockPolicyMutual
+}
+
+// hiddenUsers returns the users hidden from userId under policy.
+func hiddenUsers(ctx context.Context, flr repository.FriendListRepository, policy BlockPolicy, userId int) ([]int, error) {
+	blockUsers, err := flr.GetBlockUsersIdList(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.hidesBlockedBy() {
+		return blockUsers, nil
+	}
+
+	blockedBy, err := flr.GetBlockedByUsersIdList(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(blockUsers, blockedBy...), nil
+}
+
+// paginate cuts the page starting at offset out of items computed in memory.
+func paginate[T any](items []T, limit, offset int) ([]T, *model.Paging) {
+	paging := model.NewPaging(len(items), limit, offset)
+	if offset >= len(items) {
+		return nil, paging
+	}
+	if offset+limit < len(items) {
+		return items[offset : offset+limit], paging
+	}
+
+	return items[offset:], paging
 }
 
 type friendListService struct {
-	flr repository.FriendListRepository
+	flr         repository.FriendListRepository
+	frr         repository.FriendRequestRepository
+	blockPolicy BlockPolicy
 }
 
-func NewFriendListService(flr repository.FriendListRepository) FriendListService {
+func NewFriendListService(flr repository.FriendListRepository, frr repository.FriendRequestRepository, blockPolicy BlockPolicy) FriendListService {
 	return &friendListService{
-		flr: flr,
+		flr:         flr,
+		frr:         frr,
+		blockPolicy: blockPolicy,
+	}
+}
+
+func (s *friendListService) CheckUserExist(ctx context.Context, userId int) (bool, error) {
+	return s.flr.CheckUserExist(ctx, userId)
+}
+
+// CheckUsersExist reports which of userIds are users with one query.
+func (s *friendListService) CheckUsersExist(ctx context.Context, userIds []int) (map[int]bool, error) {
+	exist := make(map[int]bool, len(userIds))
+	if len(userIds) == 0 {
+		return exist, nil
+	}
+
+	users, err := s.flr.GetUserListByUserIds(ctx, userIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users.Friends {
+		exist[user.UserId] = true
+	}
+
+	return exist, nil
+}
+
+// userLinkTables are the tables InsertUserLinks writes in order, so that a block given with
+// a friend link between the same users severs it as BlockUser does.
+var userLinkTables = []string{"friend_link", "block_list"}
+
+// InsertUserLinks inserts ulfrs with one statement per table and reports whether each of them
+// was created or already there. The links to block_list sever the friendships and pending
+// requests between the users like BlockUser, so a link to friend_link between users blocked in
+// ulfrs is not inserted and reported superseded. Run it in a transaction.
+func (s *friendListService) InsertUserLinks(ctx context.Context, ulfrs []*model.UserLinkForRequest) ([]model.UserLinkStatus, error) {
+	blocked := make(map[[2]int]bool)
+	for _, ulfr := range ulfrs {
+		if ulfr.Table == "block_list" {
+			blocked[userPair(ulfr.User1Id, ulfr.User2Id)] = true
+		}
+	}
+
+	statuses := make([]model.UserLinkStatus, len(ulfrs))
+	for _, table := range userLinkTables {
+		var (
+			userLinks [][2]int
+			indexes   []int
+		)
+		for i, ulfr := range ulfrs {
+			if ulfr.Table != table {
+				continue
+			}
+			if table == "friend_link" && blocked[userPair(ulfr.User1Id, ulfr.User2Id)] {
+				statuses[i] = model.UserLinkStatusSuperseded
+				continue
+			}
+
+			userLinks = append(userLinks, [2]int{ulfr.User1Id, ulfr.User2Id})
+			indexes = append(indexes, i)
+		}
+		if len(userLinks) == 0 {
+			continue
+		}
+
+		if table == "block_list" {
+			for _, ul := range userLinks {
+				if err := s.flr.DeleteFriendLinksBetween(ctx, ul[0], ul[1]); err != nil {
+					return nil, err
+				}
+				if err := s.frr.CancelPendingFriendRequestsBetween(ctx, ul[0], ul[1]); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		existing, err := s.flr.GetExistingUserLinks(ctx, table, userLinks)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.flr.InsertUserLinks(ctx, table, userLinks); err != nil {
+			return nil, err
+		}
+
+		// the same link given twice is created by the first one
+		seen := make(map[[2]int]bool, len(userLinks))
+		for j, ul := range userLinks {
+			if existing[ul] || seen[ul] {
+				statuses[indexes[j]] = model.UserLinkStatusExists
+			} else {
+				statuses[indexes[j]] = model.UserLinkStatusCreated
+			}
+			seen[ul] = true
+		}
+	}
+
+	return statuses, nil
+}
+
+// userPair returns the users of a link in the same order whichever of them is first.
+func userPair(user1Id, user2Id int) [2]int {
+	if user2Id < user1Id {
+		return [2]int{user2Id, user1Id}
 	}
+
+	return [2]int{user1Id, user2Id}
 }
 
-func (s *friendListService) CheckUserExist(userId int) (bool, error) {
-	return s.flr.CheckUserExist(userId)
+func (s *friendListService) InsertUserLink(ctx context.Context, ulfr *model.UserLinkForRequest) error {
+	if ulfr.Table == "block_list" {
+		return s.BlockUser(ctx, ulfr.User1Id, ulfr.User2Id)
+	}
+
+	return s.insertUserLink(ctx, ulfr)
 }
 
-func (s *friendListService) InsertUserLink(ulfr *model.UserLinkForRequest) error {
-	if err := s.flr.CheckUserLink(ulfr.User1Id, ulfr.User2Id, ulfr.Table); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return s.flr.InsertUserLink(ulfr.User1Id, ulfr.User2Id, ulfr.Table)
+func (s *friendListService) insertUserLink(ctx context.Context, ulfr *model.UserLinkForRequest) error {
+	if err := s.flr.CheckUserLink(ctx, ulfr.User1Id, ulfr.User2Id, ulfr.Table); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		// a concurrent request may have inserted the same link after the check above
+		if err := s.flr.InsertUserLink(ctx, ulfr.User1Id, ulfr.User2Id, ulfr.Table); err != nil && !errors.Is(err, repository.ErrUserLinkDuplicated) {
+			return err
 		}
+	}
 
+	return nil
+}
+
+func (s *friendListService) DeleteUserLink(ctx context.Context, ulfr *model.UserLinkForRequest) error {
+	return s.flr.DeleteUserLink(ctx, ulfr.User1Id, ulfr.User2Id, ulfr.Table)
+}
+
+// BlockUser severs the friendship in both directions, cancels the pending friend requests
+// between the users and records the block. Run it in a transaction so that none of them is
+// applied alone. Unblocking only deletes the block, so the friendship is not restored.
+func (s *friendListService) BlockUser(ctx context.Context, userId, blockUserId int) error {
+	if err := s.flr.DeleteFriendLinksBetween(ctx, userId, blockUserId); err != nil {
 		return err
 	}
+	if err := s.frr.CancelPendingFriendRequestsBetween(ctx, userId, blockUserId); err != nil {
+		return err
+	}
+
+	return s.insertUserLink(ctx, &model.UserLinkForRequest{
+		User1Id: userId,
+		User2Id: blockUserId,
+		Table:   "block_list",
+	})
+}
+
+// AddFriendship links the users as friends in both directions. A direction already linked is kept.
+func (s *friendListService) AddFriendship(ctx context.Context, user1Id, user2Id int) error {
+	for _, ul := range [][2]int{{user1Id, user2Id}, {user2Id, user1Id}} {
+		if err := s.flr.InsertUserLink(ctx, ul[0], ul[1], "friend_link"); err != nil && !errors.Is(err, repository.ErrUserLinkDuplicated) {
+			return err
+		}
+	}
 
 	return nil
 }
 
-func (s *friendListService) GetFriendListByUserId(c echo.Context) (*model.FriendList, error) {
-	userId := c.Get("userId").(int)
+func (s *friendListService) GetFriendListByUserId(ctx context.Context, userId int, sort model.FriendListSort) (*model.FriendList, error) {
+	return s.flr.GetFriendListByUserIdExcludingBlockUsers(ctx, userId, s.blockPolicy.hidesBlockedBy(), sort)
+}
+
+func (s *friendListService) GetFriendListByUserIdWithPaging(ctx context.Context, userId int, sort model.FriendListSort, limit, offset int) (*model.FriendList, error) {
+	excludeBlockedBy := s.blockPolicy.hidesBlockedBy()
 
-	blockUsers, err := s.flr.GetBlockUsersIdList(userId)
+	friendList, err := s.flr.GetFriendListByUserIdWithPaging(ctx, userId, excludeBlockedBy, sort, limit, offset)
 	if err != nil {
 		return nil, err
 	}
-	if len(blockUsers) == 0 {
-		return s.flr.GetFriendListByUserId(userId)
+	total, err := s.flr.CountFriendListByUserId(ctx, userId, excludeBlockedBy)
+	if err != nil {
+		return nil, err
 	}
+	friendList.Paging = model.NewPaging(total, limit, offset)
 
-	return s.flr.GetFriendListByUserIdExcludingBlockUsers(userId, blockUsers)
+	return friendList, nil
 }
 
-func (s *friendListService) GetFriendListOfFriendsByUserId(c echo.Context) (*model.FriendList, error) {
-	userId := c.Get("userId").(int)
+func (s *friendListService) GetFriendListOfFriendsByUserId(ctx context.Context, userId int, sort model.FriendListSort) (*model.FriendList, error) {
+	return s.flr.GetFriendListOfFriendsByUserId(ctx, userId, s.blockPolicy.hidesBlockedBy(), sort)
+}
 
-	oneHopFriends, err := s.flr.GetOneHopFriendsUserIdList(userId)
+func (s *friendListService) GetFriendListOfFriendsByUserIdWithPaging(ctx context.Context, userId int, sort model.FriendListSort, limit, offset int) (*model.FriendList, error) {
+	excludeBlockedBy := s.blockPolicy.hidesBlockedBy()
+
+	friendList, err := s.flr.GetFriendListOfFriendsByUserIdWithPaging(ctx, userId, excludeBlockedBy, sort, limit, offset)
 	if err != nil {
 		return nil, err
 	}
-	if len(oneHopFriends) == 0 {
-		return &model.FriendList{Friends: nil}, nil
+	total, err := s.flr.CountFriendListOfFriendsByUserId(ctx, userId, excludeBlockedBy)
+	if err != nil {
+		return nil, err
 	}
+	friendList.Paging = model.NewPaging(total, limit, offset)
 
-	blockUsers, err := s.flr.GetBlockUsersIdList(userId)
+	return friendList, nil
+}
+
+func (s *friendListService) GetFriendListOfFriendsByUserIdWithCursor(ctx context.Context, userId, lastUserId, limit int) (*model.FriendList, error) {
+	excludeBlockedBy := s.blockPolicy.hidesBlockedBy()
+
+	// one extra row tells whether a next page exists
+	friendList, err := s.flr.GetFriendListOfFriendsByUserIdWithCursor(ctx, userId, excludeBlockedBy, lastUserId, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.flr.CountFriendListOfFriendsByUserId(ctx, userId, excludeBlockedBy)
 	if err != nil {
 		return nil, err
 	}
 
-	excludeUsers := append(oneHopFriends, blockUsers...)
+	hasNext := len(friendList.Friends) > limit
+	if hasNext {
+		friendList.Friends = friendList.Friends[:limit]
+	}
+	friendList.Paging = &model.Paging{
+		Total:   total,
+		Limit:   limit,
+		HasNext: hasNext,
+	}
 
-	return s.flr.GetFriendListOfFriendsByUserId(userId, excludeUsers)
+	return friendList, nil
 }
 
-func (s *friendListService) GetFriendListOfFriendsByUserIdWithPaging(c echo.Context) (*model.FriendList, error) {
-	userId := c.Get("userId").(int)
-	limit := c.Get("limit").(int)
-	offset := c.Get("offset").(int)
+func (s *friendListService) GetBlockListByUserId(ctx context.Context, userId int) (*model.BlockList, error) {
+	return s.flr.GetBlockListByUserId(ctx, userId)
+}
 
-	oneHopFriends, err := s.flr.GetOneHopFriendsUserIdList(userId)
+func (s *friendListService) GetBlockListByUserIdWithPaging(ctx context.Context, userId, limit, offset int) (*model.BlockList, error) {
+	blockList, err := s.flr.GetBlockListByUserIdWithPaging(ctx, userId, limit, offset)
 	if err != nil {
 		return nil, err
 	}
-	if len(oneHopFriends) == 0 {
-		return &model.FriendList{Friends: nil}, nil
+	total, err := s.flr.CountBlockListByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
 	}
+	blockList.Paging = model.NewPaging(total, limit, offset)
 
-	blockUsers, err := s.flr.GetBlockUsersIdList(userId)
+	return blockList, nil
+}
+
+// GetNeighbourhood returns the users minDepth to maxDepth hops away ordered by distance and user_id.
+// Each user appears once at the shortest distance. Blocked users are neither returned nor
+// followed, and the user never appears. The walk needs every user to order them, so the page
+// is cut out of the user ids and only its names are read.
+func (s *friendListService) GetNeighbourhood(ctx context.Context, userId, minDepth, maxDepth, limit, offset int) (*model.NeighbourList, error) {
+	blockUsers, err := hiddenUsers(ctx, s.flr, s.blockPolicy, userId)
 	if err != nil {
 		return nil, err
 	}
 
-	excludeUsers := append(oneHopFriends, blockUsers...)
+	visited := map[int]struct{}{userId: {}}
+	for _, blockUser := range blockUsers {
+		visited[blockUser] = struct{}{}
+	}
+
+	var (
+		userIds   []int
+		distances = make(map[int]int)
+		frontier  = []int{userId}
+	)
+	for depth := 1; depth <= maxDepth && len(frontier) > 0; depth++ {
+		friends, err := s.flr.GetFriendUserIdsByUserIds(ctx, frontier)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []int
+		for _, u := range frontier {
+			for _, friend := range friends[u] {
+				if _, ok := visited[friend]; ok {
+					continue
+				}
+
+				visited[friend] = struct{}{}
+				next = append(next, friend)
+			}
+		}
+		sort.Ints(next)
+
+		if depth >= minDepth {
+			for _, id := range next {
+				distances[id] = depth
+			}
+			userIds = append(userIds, next...)
+		}
+		frontier = next
+	}
+
+	userIds, paging := paginate(userIds, limit, offset)
+	if len(userIds) == 0 {
+		return &model.NeighbourList{Neighbours: nil, Paging: paging}, nil
+	}
+
+	userList, err := s.flr.GetUserListByUserIds(ctx, userIds)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int]string, len(userList.Friends))
+	for _, user := range userList.Friends {
+		names[user.UserId] = user.Name
+	}
+
+	neighbourList := &model.NeighbourList{Paging: paging}
+	for _, id := range userIds {
+		name, ok := names[id]
+		// skip links whose user row is gone, as the INNER JOIN on users does for the other lists
+		if !ok {
+			continue
+		}
+
+		neighbourList.Neighbours = append(neighbourList.Neighbours, &model.Neighbour{
+			UserId:   id,
+			Name:     name,
+			Distance: distances[id],
+		})
+	}
 
-	return s.flr.GetFriendListOfFriendsByUserIdWithPaging(userId, excludeUsers, limit, offset)
+	return neighbourList, nil
 }
@@ -1,25 +1,27 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/golang/mock/gomock"
-	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 
 	"problem1/mock/mock_repository"
 	"problem1/model"
 	"problem1/pkg/testutil"
+	"problem1/repository"
 )
 
 type friendListServiceTest struct {
 	db   *sql.DB
 	mock sqlmock.Sqlmock
 	flr  *mock_repository.MockFriendListRepository
+	frr  *mock_repository.MockFriendRequestRepository
 	fls  FriendListService
-	c    echo.Context
+	ctx  context.Context
 }
 
 func newFriendListServiceTest(t *testing.T) *friendListServiceTest {
@@ -28,13 +30,15 @@ func newFriendListServiceTest(t *testing.T) *friendListServiceTest {
 	ctrl := gomock.NewController(t)
 	db, mock := testutil.NewSQLMock(t)
 	flr := mock_repository.NewMockFriendListRepository(ctrl)
+	frr := mock_repository.NewMockFriendRequestRepository(ctrl)
 
 	return &friendListServiceTest{
 		db:   db,
 		mock: mock,
 		flr:  flr,
-		fls:  NewFriendListService(flr),
-		c:    testutil.SetUpContextWithDefault(),
+		frr:  frr,
+		fls:  NewFriendListService(flr, frr, BlockPolicyOneWay),
+		ctx:  context.Background(),
 	}
 }
 
@@ -64,7 +68,7 @@ func Test_friendListService_CheckUserExist(t *testing.T) {
 		{
 			name: "ok: user exist",
 			expects: func(st *friendListServiceTest) {
-				st.flr.EXPECT().CheckUserExist(userId).Return(true, nil)
+				st.flr.EXPECT().CheckUserExist(st.ctx, userId).Return(true, nil)
 			},
 			want:    true,
 			wantErr: false,
@@ -72,7 +76,7 @@ func Test_friendListService_CheckUserExist(t *testing.T) {
 		{
 			name: "ok: user not exist",
 			expects: func(st *friendListServiceTest) {
-				st.flr.EXPECT().CheckUserExist(userId).Return(false, nil)
+				st.flr.EXPECT().CheckUserExist(st.ctx, userId).Return(false, nil)
 			},
 			want:    false,
 			wantErr: false,
@@ -80,7 +84,7 @@ func Test_friendListService_CheckUserExist(t *testing.T) {
 		{
 			name: "ng: error at CheckUserExist()",
 			expects: func(st *friendListServiceTest) {
-				st.flr.EXPECT().CheckUserExist(userId).Return(false, testutil.ErrTest)
+				st.flr.EXPECT().CheckUserExist(st.ctx, userId).Return(false, testutil.ErrTest)
 			},
 			want:    false,
 			wantErr: true,
@@ -92,7 +96,7 @@ func Test_friendListService_CheckUserExist(t *testing.T) {
 			st := newFriendListServiceTest(t)
 			tt.expects(st)
 
-			got, err := st.fls.CheckUserExist(userId)
+			got, err := st.fls.CheckUserExist(st.ctx, userId)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("CheckUserExist() error = %v, wantErr = %v", err, tt.wantErr)
 			}
@@ -116,8 +120,8 @@ func Test_friendListService_InsertUserLink(t *testing.T) {
 			name: "ok: friend_link insert",
 			expects: func(st *friendListServiceTest) {
 				req.Table = "friend_link"
-				st.flr.EXPECT().CheckUserLink(req.User1Id, req.User2Id, req.Table).Return(sql.ErrNoRows)
-				st.flr.EXPECT().InsertUserLink(req.User1Id, req.User2Id, req.Table).Return(nil)
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.User1Id, req.User2Id, req.Table).Return(sql.ErrNoRows)
+				st.flr.EXPECT().InsertUserLink(st.ctx, req.User1Id, req.User2Id, req.Table).Return(nil)
 			},
 			want:    nil,
 			wantErr: false,
@@ -126,8 +130,10 @@ func Test_friendListService_InsertUserLink(t *testing.T) {
 			name: "ok: block_list insert",
 			expects: func(st *friendListServiceTest) {
 				req.Table = "block_list"
-				st.flr.EXPECT().CheckUserLink(req.User1Id, req.User2Id, req.Table).Return(sql.ErrNoRows)
-				st.flr.EXPECT().InsertUserLink(req.User1Id, req.User2Id, req.Table).Return(nil)
+				st.flr.EXPECT().DeleteFriendLinksBetween(st.ctx, req.User1Id, req.User2Id).Return(nil)
+				st.frr.EXPECT().CancelPendingFriendRequestsBetween(st.ctx, req.User1Id, req.User2Id).Return(nil)
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.User1Id, req.User2Id, req.Table).Return(sql.ErrNoRows)
+				st.flr.EXPECT().InsertUserLink(st.ctx, req.User1Id, req.User2Id, req.Table).Return(nil)
 			},
 			want:    nil,
 			wantErr: false,
@@ -136,16 +142,28 @@ func Test_friendListService_InsertUserLink(t *testing.T) {
 			name: "ok: friend_link already exist",
 			expects: func(st *friendListServiceTest) {
 				req.Table = "friend_link"
-				st.flr.EXPECT().CheckUserLink(req.User1Id, req.User2Id, req.Table).Return(nil)
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.User1Id, req.User2Id, req.Table).Return(nil)
 			},
 			want:    nil,
 			wantErr: false,
 		},
 		{
-			name: "ok: block_list insert",
+			name: "ok: block_list already exist",
 			expects: func(st *friendListServiceTest) {
 				req.Table = "block_list"
-				st.flr.EXPECT().CheckUserLink(req.User1Id, req.User2Id, req.Table).Return(nil)
+				st.flr.EXPECT().DeleteFriendLinksBetween(st.ctx, req.User1Id, req.User2Id).Return(nil)
+				st.frr.EXPECT().CancelPendingFriendRequestsBetween(st.ctx, req.User1Id, req.User2Id).Return(nil)
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.User1Id, req.User2Id, req.Table).Return(nil)
+			},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "ok: friend_link inserted concurrently",
+			expects: func(st *friendListServiceTest) {
+				req.Table = "friend_link"
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.User1Id, req.User2Id, req.Table).Return(sql.ErrNoRows)
+				st.flr.EXPECT().InsertUserLink(st.ctx, req.User1Id, req.User2Id, req.Table).Return(repository.ErrUserLinkDuplicated)
 			},
 			want:    nil,
 			wantErr: false,
@@ -154,7 +172,7 @@ func Test_friendListService_InsertUserLink(t *testing.T) {
 			name: "ng: error at CheckUserLink()",
 			expects: func(st *friendListServiceTest) {
 				req.Table = "friend_link"
-				st.flr.EXPECT().CheckUserLink(req.User1Id, req.User2Id, req.Table).Return(testutil.ErrTest)
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.User1Id, req.User2Id, req.Table).Return(testutil.ErrTest)
 			},
 			want:    nil,
 			wantErr: true,
@@ -163,8 +181,10 @@ func Test_friendListService_InsertUserLink(t *testing.T) {
 			name: "ng: error at InsertUserLink()",
 			expects: func(st *friendListServiceTest) {
 				req.Table = "block_list"
-				st.flr.EXPECT().CheckUserLink(req.User1Id, req.User2Id, req.Table).Return(sql.ErrNoRows)
-				st.flr.EXPECT().InsertUserLink(req.User1Id, req.User2Id, req.Table).Return(testutil.ErrTest)
+				st.flr.EXPECT().DeleteFriendLinksBetween(st.ctx, req.User1Id, req.User2Id).Return(nil)
+				st.frr.EXPECT().CancelPendingFriendRequestsBetween(st.ctx, req.User1Id, req.User2Id).Return(nil)
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.User1Id, req.User2Id, req.Table).Return(sql.ErrNoRows)
+				st.flr.EXPECT().InsertUserLink(st.ctx, req.User1Id, req.User2Id, req.Table).Return(testutil.ErrTest)
 			},
 			want:    nil,
 			wantErr: true,
@@ -176,7 +196,7 @@ func Test_friendListService_InsertUserLink(t *testing.T) {
 			st := newFriendListServiceTest(t)
 			tt.expects(st)
 
-			err := st.fls.InsertUserLink(req)
+			err := st.fls.InsertUserLink(st.ctx, req)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("InsertUserLink() error = %v, wantErr = %v", err, tt.wantErr)
 			}
@@ -184,58 +204,202 @@ func Test_friendListService_InsertUserLink(t *testing.T) {
 	}
 }
 
-func Test_friendListService_GetFriendListByUserId(t *testing.T) {
+func Test_friendListService_BlockUser(t *testing.T) {
 	userId := testutil.UserIDForDebug
-	blockUsers := []int{0}
-	want := newFriendList()
-
+	blockUserId := 111111
 	tests := []struct {
 		name    string
 		expects func(test *friendListServiceTest)
-		want    *model.FriendList
 		wantErr bool
 	}{
 		{
-			name: "ok: no block user",
+			name: "ok: friendship and pending requests are severed before the block",
 			expects: func(st *friendListServiceTest) {
-				st.flr.EXPECT().GetBlockUsersIdList(userId).Return(nil, nil)
-				st.flr.EXPECT().GetFriendListByUserId(userId).Return(want, nil)
+				gomock.InOrder(
+					st.flr.EXPECT().DeleteFriendLinksBetween(st.ctx, userId, blockUserId).Return(nil),
+					st.frr.EXPECT().CancelPendingFriendRequestsBetween(st.ctx, userId, blockUserId).Return(nil),
+					st.flr.EXPECT().CheckUserLink(st.ctx, userId, blockUserId, "block_list").Return(sql.ErrNoRows),
+					st.flr.EXPECT().InsertUserLink(st.ctx, userId, blockUserId, "block_list").Return(nil),
+				)
 			},
-			want:    want,
 			wantErr: false,
 		},
 		{
-			name: "ok: block some users",
+			name: "ng: error at DeleteFriendLinksBetween()",
 			expects: func(st *friendListServiceTest) {
-				st.flr.EXPECT().GetBlockUsersIdList(userId).Return(blockUsers, nil)
-				st.flr.EXPECT().GetFriendListByUserIdExcludingBlockUsers(userId, blockUsers).Return(want, nil)
+				st.flr.EXPECT().DeleteFriendLinksBetween(st.ctx, userId, blockUserId).Return(testutil.ErrTest)
 			},
-			want:    want,
+			wantErr: true,
+		},
+		{
+			name: "ng: error at CancelPendingFriendRequestsBetween()",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().DeleteFriendLinksBetween(st.ctx, userId, blockUserId).Return(nil)
+				st.frr.EXPECT().CancelPendingFriendRequestsBetween(st.ctx, userId, blockUserId).Return(testutil.ErrTest)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newFriendListServiceTest(t)
+			tt.expects(st)
+
+			err := st.fls.BlockUser(st.ctx, userId, blockUserId)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BlockUser() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_friendListService_CheckUsersExist(t *testing.T) {
+	userIds := []int{testutil.UserIDForDebug, 111111, 111111, 999999}
+	tests := []struct {
+		name    string
+		expects func(test *friendListServiceTest)
+		userIds []int
+		want    map[int]bool
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetUserListByUserIds(st.ctx, userIds).Return(&model.FriendList{
+					Friends: []*model.Friend{
+						{UserId: 111111, Name: "hoge"},
+						{UserId: testutil.UserIDForDebug, Name: testutil.UserNameForDebug},
+					},
+				}, nil)
+			},
+			userIds: userIds,
+			want:    map[int]bool{testutil.UserIDForDebug: true, 111111: true},
 			wantErr: false,
 		},
 		{
-			name: "ng: error at GetBlockUsersIdList()",
+			name:    "ok: no user id",
+			expects: func(st *friendListServiceTest) {},
+			userIds: nil,
+			want:    map[int]bool{},
+			wantErr: false,
+		},
+		{
+			name: "ng: error at GetUserListByUserIds()",
 			expects: func(st *friendListServiceTest) {
-				st.flr.EXPECT().GetBlockUsersIdList(userId).Return(nil, testutil.ErrTest)
+				st.flr.EXPECT().GetUserListByUserIds(st.ctx, userIds).Return(nil, testutil.ErrTest)
 			},
+			userIds: userIds,
 			want:    nil,
 			wantErr: true,
 		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newFriendListServiceTest(t)
+			tt.expects(st)
+
+			got, err := st.fls.CheckUsersExist(st.ctx, tt.userIds)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckUsersExist() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListService_InsertUserLinks(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	ulfrs := []*model.UserLinkForRequest{
+		{User1Id: userId, User2Id: 111111, Table: "block_list"},
+		{User1Id: userId, User2Id: 222222, Table: "friend_link"},
+		{User1Id: userId, User2Id: 333333, Table: "friend_link"},
+		{User1Id: userId, User2Id: 222222, Table: "friend_link"},
+	}
+	friendLinks := [][2]int{{userId, 222222}, {userId, 333333}, {userId, 222222}}
+	blockLinks := [][2]int{{userId, 111111}}
+
+	tests := []struct {
+		name    string
+		expects func(test *friendListServiceTest)
+		ulfrs   []*model.UserLinkForRequest
+		want    []model.UserLinkStatus
+		wantErr bool
+	}{
+		{
+			name: "ok: friend links first, then blocks severing friendships",
+			expects: func(st *friendListServiceTest) {
+				gomock.InOrder(
+					st.flr.EXPECT().GetExistingUserLinks(st.ctx, "friend_link", friendLinks).Return(map[[2]int]bool{{userId, 333333}: true}, nil),
+					st.flr.EXPECT().InsertUserLinks(st.ctx, "friend_link", friendLinks).Return(nil),
+					st.flr.EXPECT().DeleteFriendLinksBetween(st.ctx, userId, 111111).Return(nil),
+					st.frr.EXPECT().CancelPendingFriendRequestsBetween(st.ctx, userId, 111111).Return(nil),
+					st.flr.EXPECT().GetExistingUserLinks(st.ctx, "block_list", blockLinks).Return(nil, nil),
+					st.flr.EXPECT().InsertUserLinks(st.ctx, "block_list", blockLinks).Return(nil),
+				)
+			},
+			ulfrs: ulfrs,
+			want: []model.UserLinkStatus{
+				model.UserLinkStatusCreated,
+				model.UserLinkStatusCreated,
+				model.UserLinkStatusExists,
+				model.UserLinkStatusExists,
+			},
+			wantErr: false,
+		},
+		{
+			name: "ok: friend link between users blocked in the batch is superseded",
+			expects: func(st *friendListServiceTest) {
+				gomock.InOrder(
+					st.flr.EXPECT().GetExistingUserLinks(st.ctx, "friend_link", [][2]int{{userId, 222222}}).Return(nil, nil),
+					st.flr.EXPECT().InsertUserLinks(st.ctx, "friend_link", [][2]int{{userId, 222222}}).Return(nil),
+					st.flr.EXPECT().DeleteFriendLinksBetween(st.ctx, userId, 111111).Return(nil),
+					st.frr.EXPECT().CancelPendingFriendRequestsBetween(st.ctx, userId, 111111).Return(nil),
+					st.flr.EXPECT().GetExistingUserLinks(st.ctx, "block_list", blockLinks).Return(nil, nil),
+					st.flr.EXPECT().InsertUserLinks(st.ctx, "block_list", blockLinks).Return(nil),
+				)
+			},
+			ulfrs: []*model.UserLinkForRequest{
+				{User1Id: userId, User2Id: 111111, Table: "friend_link"},
+				{User1Id: 111111, User2Id: userId, Table: "friend_link"},
+				{User1Id: userId, User2Id: 222222, Table: "friend_link"},
+				{User1Id: userId, User2Id: 111111, Table: "block_list"},
+			},
+			want: []model.UserLinkStatus{
+				model.UserLinkStatusSuperseded,
+				model.UserLinkStatusSuperseded,
+				model.UserLinkStatusCreated,
+				model.UserLinkStatusCreated,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "ok: no link",
+			expects: func(st *friendListServiceTest) {},
+			ulfrs:   nil,
+			want:    []model.UserLinkStatus{},
+			wantErr: false,
+		},
 		{
-			name: "ng: error at GetFriendListByUserId()",
+			name: "ng: error at InsertUserLinks()",
 			expects: func(st *friendListServiceTest) {
-				st.flr.EXPECT().GetBlockUsersIdList(userId).Return(nil, nil)
-				st.flr.EXPECT().GetFriendListByUserId(userId).Return(nil, testutil.ErrTest)
+				st.flr.EXPECT().GetExistingUserLinks(st.ctx, "friend_link", friendLinks).Return(nil, nil)
+				st.flr.EXPECT().InsertUserLinks(st.ctx, "friend_link", friendLinks).Return(testutil.ErrTest)
 			},
+			ulfrs:   ulfrs,
 			want:    nil,
 			wantErr: true,
 		},
 		{
-			name: "ng: error at GetFriendListByUserIdExcludingBlockUsers()",
+			name: "ng: error at CancelPendingFriendRequestsBetween()",
 			expects: func(st *friendListServiceTest) {
-				st.flr.EXPECT().GetBlockUsersIdList(userId).Return(blockUsers, nil)
-				st.flr.EXPECT().GetFriendListByUserIdExcludingBlockUsers(userId, blockUsers).Return(nil, testutil.ErrTest)
+				st.flr.EXPECT().GetExistingUserLinks(st.ctx, "friend_link", friendLinks).Return(nil, nil)
+				st.flr.EXPECT().InsertUserLinks(st.ctx, "friend_link", friendLinks).Return(nil)
+				st.flr.EXPECT().DeleteFriendLinksBetween(st.ctx, userId, 111111).Return(nil)
+				st.frr.EXPECT().CancelPendingFriendRequestsBetween(st.ctx, userId, 111111).Return(testutil.ErrTest)
 			},
+			ulfrs:   ulfrs,
 			want:    nil,
 			wantErr: true,
 		},
@@ -246,70 +410,195 @@ func Test_friendListService_GetFriendListByUserId(t *testing.T) {
 			st := newFriendListServiceTest(t)
 			tt.expects(st)
 
-			got, err := st.fls.GetFriendListByUserId(st.c)
+			got, err := st.fls.InsertUserLinks(st.ctx, tt.ulfrs)
 			if (err != nil) != tt.wantErr {
-				t.Fatalf("GetFriendListByUserId() error = %v, wantErr = %v", err, tt.wantErr)
+				t.Fatalf("InsertUserLinks() error = %v, wantErr = %v", err, tt.wantErr)
 			}
 			assert.Equal(t, tt.want, got)
 		})
 	}
 }
 
-func Test_friendListService_GetFriendListOfFriendsByUserId(t *testing.T) {
+func Test_friendListService_AddFriendship(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	friendId := 111111
+	tests := []struct {
+		name    string
+		expects func(test *friendListServiceTest)
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().InsertUserLink(st.ctx, userId, friendId, "friend_link").Return(nil)
+				st.flr.EXPECT().InsertUserLink(st.ctx, friendId, userId, "friend_link").Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "ok: one direction already linked",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().InsertUserLink(st.ctx, userId, friendId, "friend_link").Return(nil)
+				st.flr.EXPECT().InsertUserLink(st.ctx, friendId, userId, "friend_link").Return(repository.ErrUserLinkDuplicated)
+			},
+			wantErr: false,
+		},
+		{
+			name: "ng: error at InsertUserLink()",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().InsertUserLink(st.ctx, userId, friendId, "friend_link").Return(testutil.ErrTest)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newFriendListServiceTest(t)
+			tt.expects(st)
+
+			err := st.fls.AddFriendship(st.ctx, userId, friendId)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddFriendship() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_friendListService_DeleteUserLink(t *testing.T) {
+	req := &model.UserLinkForRequest{
+		User1Id: testutil.UserIDForDebug,
+		User2Id: 111111,
+		Table:   "friend_link",
+	}
+	tests := []struct {
+		name    string
+		expects func(test *friendListServiceTest)
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().DeleteUserLink(st.ctx, req.User1Id, req.User2Id, req.Table).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "ng: error at DeleteUserLink()",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().DeleteUserLink(st.ctx, req.User1Id, req.User2Id, req.Table).Return(repository.ErrUserLinkNotFound)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newFriendListServiceTest(t)
+			tt.expects(st)
+
+			err := st.fls.DeleteUserLink(st.ctx, req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeleteUserLink() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_friendListService_GetFriendListByUserId(t *testing.T) {
 	userId := testutil.UserIDForDebug
-	userList := []int{0}
-	userLists := append(userList, userList...)
 	want := newFriendList()
 
 	tests := []struct {
 		name    string
+		policy  BlockPolicy
 		expects func(test *friendListServiceTest)
 		want    *model.FriendList
 		wantErr bool
 	}{
 		{
-			name: "ok",
+			name:   "ok: one way",
+			policy: BlockPolicyOneWay,
 			expects: func(st *friendListServiceTest) {
-				st.flr.EXPECT().GetOneHopFriendsUserIdList(userId).Return(userList, nil)
-				st.flr.EXPECT().GetBlockUsersIdList(userId).Return(userList, nil)
-				st.flr.EXPECT().GetFriendListOfFriendsByUserId(userId, userLists).Return(want, nil)
+				st.flr.EXPECT().GetFriendListByUserIdExcludingBlockUsers(st.ctx, userId, false, model.FriendListSortUserId).Return(want, nil)
 			},
 			want:    want,
 			wantErr: false,
 		},
 		{
-			name: "ok: no 1hop friend",
+			name:   "ok: mutual excludes users who blocked me",
+			policy: BlockPolicyMutual,
 			expects: func(st *friendListServiceTest) {
-				st.flr.EXPECT().GetOneHopFriendsUserIdList(userId).Return(nil, nil)
-			},
-			want: &model.FriendList{
-				Friends: []*model.Friend(nil),
+				st.flr.EXPECT().GetFriendListByUserIdExcludingBlockUsers(st.ctx, userId, true, model.FriendListSortUserId).Return(want, nil)
 			},
+			want:    want,
 			wantErr: false,
 		},
 		{
-			name: "ng: error at GetOneHopFriendsUserIdList()",
+			name:   "ng: error at GetFriendListByUserIdExcludingBlockUsers()",
+			policy: BlockPolicyOneWay,
 			expects: func(st *friendListServiceTest) {
-				st.flr.EXPECT().GetOneHopFriendsUserIdList(userId).Return(nil, testutil.ErrTest)
+				st.flr.EXPECT().GetFriendListByUserIdExcludingBlockUsers(st.ctx, userId, false, model.FriendListSortUserId).Return(nil, testutil.ErrTest)
 			},
 			want:    nil,
 			wantErr: true,
 		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newFriendListServiceTest(t)
+			st.fls = NewFriendListService(st.flr, st.frr, tt.policy)
+			tt.expects(st)
+
+			got, err := st.fls.GetFriendListByUserId(st.ctx, userId, model.FriendListSortUserId)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetFriendListByUserId() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListService_GetFriendListByUserIdWithPaging(t *testing.T) {
+	userId := testutil.UserIDForDebug
+
+	tests := []struct {
+		name    string
+		expects func(test *friendListServiceTest)
+		want    *model.FriendList
+		wantErr bool
+	}{
 		{
-			name: "ng: error at GetBlockUsersIdList()",
+			name: "ok",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetFriendListByUserIdWithPaging(st.ctx, userId, false, model.FriendListSortUserId, 2, 0).Return(newFriendList(), nil)
+				st.flr.EXPECT().CountFriendListByUserId(st.ctx, userId, false).Return(3, nil)
+			},
+			want: &model.FriendList{
+				Friends: newFriendList().Friends,
+				Paging: &model.Paging{
+					Total:   3,
+					Page:    1,
+					Limit:   2,
+					HasNext: true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ng: error at GetFriendListByUserIdWithPaging()",
 			expects: func(st *friendListServiceTest) {
-				st.flr.EXPECT().GetOneHopFriendsUserIdList(userId).Return(userList, nil)
-				st.flr.EXPECT().GetBlockUsersIdList(userId).Return(nil, testutil.ErrTest)
+				st.flr.EXPECT().GetFriendListByUserIdWithPaging(st.ctx, userId, false, model.FriendListSortUserId, 2, 0).Return(nil, testutil.ErrTest)
 			},
 			want:    nil,
 			wantErr: true,
 		},
 		{
-			name: "ng: error at GetFriendListOfFriendsByUserId()",
+			name: "ng: error at CountFriendListByUserId()",
 			expects: func(st *friendListServiceTest) {
-				st.flr.EXPECT().GetOneHopFriendsUserIdList(userId).Return(userList, nil)
-				st.flr.EXPECT().GetBlockUsersIdList(userId).Return(userList, nil)
-				st.flr.EXPECT().GetFriendListOfFriendsByUserId(userId, userLists).Return(nil, testutil.ErrTest)
+				st.flr.EXPECT().GetFriendListByUserIdWithPaging(st.ctx, userId, false, model.FriendListSortUserId, 2, 0).Return(newFriendList(), nil)
+				st.flr.EXPECT().CountFriendListByUserId(st.ctx, userId, false).Return(0, testutil.ErrTest)
 			},
 			want:    nil,
 			wantErr: true,
@@ -321,80 +610,126 @@ func Test_friendListService_GetFriendListOfFriendsByUserId(t *testing.T) {
 			st := newFriendListServiceTest(t)
 			tt.expects(st)
 
-			got, err := st.fls.GetFriendListOfFriendsByUserId(st.c)
+			got, err := st.fls.GetFriendListByUserIdWithPaging(st.ctx, userId, model.FriendListSortUserId, 2, 0)
 			if (err != nil) != tt.wantErr {
-				t.Fatalf("GetFriendListOfFriendsByUserId() error = %v, wantErr = %v", err, tt.wantErr)
+				t.Fatalf("GetFriendListByUserIdWithPaging() error = %v, wantErr = %v", err, tt.wantErr)
 			}
 			assert.Equal(t, tt.want, got)
 		})
 	}
 }
 
-func Test_friendListService_GetFriendListOfFriendsByUserIdWithPaging(t *testing.T) {
+func Test_friendListService_GetFriendListOfFriendsByUserId(t *testing.T) {
 	userId := testutil.UserIDForDebug
-	userList := []int{0}
-	userLists := append(userList, userList...)
 	want := newFriendList()
 
 	tests := []struct {
 		name    string
+		policy  BlockPolicy
 		expects func(test *friendListServiceTest)
 		want    *model.FriendList
 		wantErr bool
 	}{
 		{
-			name: "ok",
+			name:   "ok: one way",
+			policy: BlockPolicyOneWay,
 			expects: func(st *friendListServiceTest) {
-				st.c.Set("limit", 0)
-				st.c.Set("offset", 0)
-				st.flr.EXPECT().GetOneHopFriendsUserIdList(userId).Return(userList, nil)
-				st.flr.EXPECT().GetBlockUsersIdList(userId).Return(userList, nil)
-				st.flr.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(userId, userLists, 0, 0).Return(want, nil)
+				st.flr.EXPECT().GetFriendListOfFriendsByUserId(st.ctx, userId, false, model.FriendListSortUserId).Return(want, nil)
 			},
 			want:    want,
 			wantErr: false,
 		},
 		{
-			name: "ok: no 1hop friend",
+			name:   "ok: mutual excludes users who blocked me",
+			policy: BlockPolicyMutual,
 			expects: func(st *friendListServiceTest) {
-				st.c.Set("limit", 0)
-				st.c.Set("offset", 0)
-				st.flr.EXPECT().GetOneHopFriendsUserIdList(userId).Return(nil, nil)
-			},
-			want: &model.FriendList{
-				Friends: []*model.Friend(nil),
+				st.flr.EXPECT().GetFriendListOfFriendsByUserId(st.ctx, userId, true, model.FriendListSortUserId).Return(want, nil)
 			},
+			want:    want,
 			wantErr: false,
 		},
 		{
-			name: "ng: error at GetOneHopFriendsUserIdList()",
+			name:   "ng: error at GetFriendListOfFriendsByUserId()",
+			policy: BlockPolicyOneWay,
 			expects: func(st *friendListServiceTest) {
-				st.c.Set("limit", 0)
-				st.c.Set("offset", 0)
-				st.flr.EXPECT().GetOneHopFriendsUserIdList(userId).Return(nil, testutil.ErrTest)
+				st.flr.EXPECT().GetFriendListOfFriendsByUserId(st.ctx, userId, false, model.FriendListSortUserId).Return(nil, testutil.ErrTest)
 			},
 			want:    nil,
 			wantErr: true,
 		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newFriendListServiceTest(t)
+			st.fls = NewFriendListService(st.flr, st.frr, tt.policy)
+			tt.expects(st)
+
+			got, err := st.fls.GetFriendListOfFriendsByUserId(st.ctx, userId, model.FriendListSortUserId)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetFriendListOfFriendsByUserId() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListService_GetFriendListOfFriendsByUserIdWithPaging(t *testing.T) {
+	userId := testutil.UserIDForDebug
+
+	tests := []struct {
+		name    string
+		expects func(test *friendListServiceTest)
+		want    *model.FriendList
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(st.ctx, userId, false, model.FriendListSortUserId, 2, 0).Return(newFriendList(), nil)
+				st.flr.EXPECT().CountFriendListOfFriendsByUserId(st.ctx, userId, false).Return(3, nil)
+			},
+			want: &model.FriendList{
+				Friends: newFriendList().Friends,
+				Paging: &model.Paging{
+					Total:   3,
+					Page:    1,
+					Limit:   2,
+					HasNext: true,
+				},
+			},
+			wantErr: false,
+		},
 		{
-			name: "ng: error at GetBlockUsersIdList()",
+			name: "ok: no friend of friend",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(st.ctx, userId, false, model.FriendListSortUserId, 2, 0).Return(&model.FriendList{}, nil)
+				st.flr.EXPECT().CountFriendListOfFriendsByUserId(st.ctx, userId, false).Return(0, nil)
+			},
+			want: &model.FriendList{
+				Friends: []*model.Friend(nil),
+				Paging: &model.Paging{
+					Total:   0,
+					Page:    1,
+					Limit:   2,
+					HasNext: false,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ng: error at GetFriendListOfFriendsByUserIdWithPaging()",
 			expects: func(st *friendListServiceTest) {
-				st.c.Set("limit", 0)
-				st.c.Set("offset", 0)
-				st.flr.EXPECT().GetOneHopFriendsUserIdList(userId).Return(userList, nil)
-				st.flr.EXPECT().GetBlockUsersIdList(userId).Return(nil, testutil.ErrTest)
+				st.flr.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(st.ctx, userId, false, model.FriendListSortUserId, 2, 0).Return(nil, testutil.ErrTest)
 			},
 			want:    nil,
 			wantErr: true,
 		},
 		{
-			name: "ng: error at GetFriendListOfFriendsByUserId()",
+			name: "ng: error at CountFriendListOfFriendsByUserId()",
 			expects: func(st *friendListServiceTest) {
-				st.c.Set("limit", 0)
-				st.c.Set("offset", 0)
-				st.flr.EXPECT().GetOneHopFriendsUserIdList(userId).Return(userList, nil)
-				st.flr.EXPECT().GetBlockUsersIdList(userId).Return(userList, nil)
-				st.flr.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(userId, userLists, 0, 0).Return(nil, testutil.ErrTest)
+				st.flr.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(st.ctx, userId, false, model.FriendListSortUserId, 2, 0).Return(newFriendList(), nil)
+				st.flr.EXPECT().CountFriendListOfFriendsByUserId(st.ctx, userId, false).Return(0, testutil.ErrTest)
 			},
 			want:    nil,
 			wantErr: true,
@@ -406,7 +741,7 @@ func Test_friendListService_GetFriendListOfFriendsByUserIdWithPaging(t *testing.
 			st := newFriendListServiceTest(t)
 			tt.expects(st)
 
-			got, err := st.fls.GetFriendListOfFriendsByUserIdWithPaging(st.c)
+			got, err := st.fls.GetFriendListOfFriendsByUserIdWithPaging(st.ctx, userId, model.FriendListSortUserId, 2, 0)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("GetFriendListOfFriendsByUserIdWithPaging() error = %v, wantErr = %v", err, tt.wantErr)
 			}
@@ -414,3 +749,531 @@ func Test_friendListService_GetFriendListOfFriendsByUserIdWithPaging(t *testing.
 		})
 	}
 }
+
+func Test_friendListService_GetFriendListOfFriendsByUserIdWithCursor(t *testing.T) {
+	userId := testutil.UserIDForDebug
+
+	tests := []struct {
+		name    string
+		expects func(test *friendListServiceTest)
+		limit   int
+		want    *model.FriendList
+		wantErr bool
+	}{
+		{
+			name: "ok: has next",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetFriendListOfFriendsByUserIdWithCursor(st.ctx, userId, false, 111111, 2).Return(newFriendList(), nil)
+				st.flr.EXPECT().CountFriendListOfFriendsByUserId(st.ctx, userId, false).Return(5, nil)
+			},
+			limit: 1,
+			want: &model.FriendList{
+				Friends: newFriendList().Friends[:1],
+				Paging: &model.Paging{
+					Total:   5,
+					Limit:   1,
+					HasNext: true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ok: last page",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetFriendListOfFriendsByUserIdWithCursor(st.ctx, userId, false, 111111, 3).Return(newFriendList(), nil)
+				st.flr.EXPECT().CountFriendListOfFriendsByUserId(st.ctx, userId, false).Return(5, nil)
+			},
+			limit: 2,
+			want: &model.FriendList{
+				Friends: newFriendList().Friends,
+				Paging: &model.Paging{
+					Total:   5,
+					Limit:   2,
+					HasNext: false,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ok: no friend of friend",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetFriendListOfFriendsByUserIdWithCursor(st.ctx, userId, false, 111111, 3).Return(&model.FriendList{}, nil)
+				st.flr.EXPECT().CountFriendListOfFriendsByUserId(st.ctx, userId, false).Return(0, nil)
+			},
+			limit: 2,
+			want: &model.FriendList{
+				Friends: []*model.Friend(nil),
+				Paging: &model.Paging{
+					Limit: 2,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ng: error at GetFriendListOfFriendsByUserIdWithCursor()",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetFriendListOfFriendsByUserIdWithCursor(st.ctx, userId, false, 111111, 3).Return(nil, testutil.ErrTest)
+			},
+			limit:   2,
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newFriendListServiceTest(t)
+			tt.expects(st)
+
+			got, err := st.fls.GetFriendListOfFriendsByUserIdWithCursor(st.ctx, userId, 111111, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetFriendListOfFriendsByUserIdWithCursor() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListService_GetBlockListByUserId(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	want := &model.BlockList{BlockUsers: newFriendList().Friends}
+
+	tests := []struct {
+		name    string
+		expects func(test *friendListServiceTest)
+		want    *model.BlockList
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetBlockListByUserId(st.ctx, userId).Return(want, nil)
+			},
+			want:    want,
+			wantErr: false,
+		},
+		{
+			name: "ng: error at GetBlockListByUserId()",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetBlockListByUserId(st.ctx, userId).Return(nil, testutil.ErrTest)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newFriendListServiceTest(t)
+			tt.expects(st)
+
+			got, err := st.fls.GetBlockListByUserId(st.ctx, userId)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetBlockListByUserId() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListService_GetBlockListByUserIdWithPaging(t *testing.T) {
+	userId := testutil.UserIDForDebug
+
+	tests := []struct {
+		name    string
+		expects func(test *friendListServiceTest)
+		want    *model.BlockList
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetBlockListByUserIdWithPaging(st.ctx, userId, 2, 2).Return(&model.BlockList{BlockUsers: newFriendList().Friends[:1]}, nil)
+				st.flr.EXPECT().CountBlockListByUserId(st.ctx, userId).Return(3, nil)
+			},
+			want: &model.BlockList{
+				BlockUsers: newFriendList().Friends[:1],
+				Paging: &model.Paging{
+					Total:   3,
+					Page:    2,
+					Limit:   2,
+					HasNext: false,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ng: error at GetBlockListByUserIdWithPaging()",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetBlockListByUserIdWithPaging(st.ctx, userId, 2, 2).Return(nil, testutil.ErrTest)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "ng: error at CountBlockListByUserId()",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetBlockListByUserIdWithPaging(st.ctx, userId, 2, 2).Return(&model.BlockList{}, nil)
+				st.flr.EXPECT().CountBlockListByUserId(st.ctx, userId).Return(0, testutil.ErrTest)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newFriendListServiceTest(t)
+			tt.expects(st)
+
+			got, err := st.fls.GetBlockListByUserIdWithPaging(st.ctx, userId, 2, 2)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetBlockListByUserIdWithPaging() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_paginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		name       string
+		limit      int
+		offset     int
+		want       []int
+		wantPaging *model.Paging
+	}{
+		{
+			name:       "ok: first page",
+			limit:      2,
+			offset:     0,
+			want:       []int{1, 2},
+			wantPaging: &model.Paging{Total: 5, Page: 1, Limit: 2, HasNext: true},
+		},
+		{
+			name:       "ok: last page",
+			limit:      2,
+			offset:     4,
+			want:       []int{5},
+			wantPaging: &model.Paging{Total: 5, Page: 3, Limit: 2, HasNext: false},
+		},
+		{
+			name:       "ok: exactly filled",
+			limit:      5,
+			offset:     0,
+			want:       items,
+			wantPaging: &model.Paging{Total: 5, Page: 1, Limit: 5, HasNext: false},
+		},
+		{
+			name:       "ok: out of range",
+			limit:      2,
+			offset:     10,
+			want:       nil,
+			wantPaging: &model.Paging{Total: 5, Page: 6, Limit: 2, HasNext: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotPaging := paginate(items, tt.limit, tt.offset)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantPaging, gotPaging)
+		})
+	}
+}
+
+func Test_friendListService_GetNeighbourhood(t *testing.T) {
+	userId := 1
+	// 1 - 2 - 3 - 4 - 5 - 6 with 1 - 7 - 4 and the blocked 8 bridging 1 to 9
+	links := map[int][]int{
+		1: {2, 7, 8},
+		2: {1, 3},
+		3: {2, 4},
+		4: {3, 5, 7},
+		5: {4, 6},
+		7: {1, 4},
+		8: {9},
+	}
+	neighbours := func(total int, ns ...[2]int) *model.NeighbourList {
+		list := &model.NeighbourList{Paging: model.NewPaging(total, 20, 0)}
+		for _, n := range ns {
+			list.Neighbours = append(list.Neighbours, &model.Neighbour{UserId: n[0], Name: "user", Distance: n[1]})
+		}
+
+		return list
+	}
+
+	tests := []struct {
+		name               string
+		minDepth, maxDepth int
+		limit, offset      int
+		want               *model.NeighbourList
+	}{
+		{
+			name:     "ok: at most 1 hop",
+			minDepth: 1,
+			maxDepth: 1,
+			limit:    20,
+			want:     neighbours(2, [2]int{2, 1}, [2]int{7, 1}),
+		},
+		{
+			name:     "ok: at most 3 hops",
+			minDepth: 1,
+			maxDepth: 3,
+			limit:    20,
+			want:     neighbours(5, [2]int{2, 1}, [2]int{7, 1}, [2]int{3, 2}, [2]int{4, 2}, [2]int{5, 3}),
+		},
+		{
+			name:     "ok: exactly 2 hops excludes users already closer",
+			minDepth: 2,
+			maxDepth: 2,
+			limit:    20,
+			want:     neighbours(2, [2]int{3, 2}, [2]int{4, 2}),
+		},
+		{
+			name:     "ok: exactly 4 hops",
+			minDepth: 4,
+			maxDepth: 4,
+			limit:    20,
+			want:     neighbours(1, [2]int{6, 4}),
+		},
+		{
+			name:     "ok: at most 4 hops never passes the blocked user",
+			minDepth: 1,
+			maxDepth: 4,
+			limit:    20,
+			want:     neighbours(6, [2]int{2, 1}, [2]int{7, 1}, [2]int{3, 2}, [2]int{4, 2}, [2]int{5, 3}, [2]int{6, 4}),
+		},
+		{
+			name:     "ok: second page",
+			minDepth: 1,
+			maxDepth: 4,
+			limit:    2,
+			offset:   2,
+			want: &model.NeighbourList{
+				Neighbours: []*model.Neighbour{{UserId: 3, Name: "user", Distance: 2}, {UserId: 4, Name: "user", Distance: 2}},
+				Paging:     &model.Paging{Total: 6, Page: 2, Limit: 2, HasNext: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newFriendListServiceTest(t)
+			st.flr.EXPECT().GetBlockUsersIdList(st.ctx, userId).Return([]int{8}, nil)
+			st.flr.EXPECT().GetFriendUserIdsByUserIds(st.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, userIds []int) (map[int][]int, error) {
+				got := make(map[int][]int)
+				for _, id := range userIds {
+					got[id] = links[id]
+				}
+
+				return got, nil
+			}).AnyTimes()
+			st.flr.EXPECT().GetUserListByUserIds(st.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, userIds []int) (*model.FriendList, error) {
+				// only the names of the page are read
+				assert.LessOrEqual(t, len(userIds), tt.limit)
+
+				friendList := &model.FriendList{}
+				for _, id := range userIds {
+					friendList.Friends = append(friendList.Friends, &model.Friend{UserId: id, Name: "user"})
+				}
+
+				return friendList, nil
+			}).AnyTimes()
+
+			got, err := st.fls.GetNeighbourhood(st.ctx, userId, tt.minDepth, tt.maxDepth, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListService_GetNeighbourhood_Error(t *testing.T) {
+	userId := testutil.UserIDForDebug
+
+	tests := []struct {
+		name    string
+		expects func(*friendListServiceTest)
+		want    *model.NeighbourList
+		wantErr bool
+	}{
+		{
+			name: "ok: no friend",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetBlockUsersIdList(st.ctx, userId).Return(nil, nil)
+				st.flr.EXPECT().GetFriendUserIdsByUserIds(st.ctx, []int{userId}).Return(map[int][]int{}, nil)
+			},
+			want:    &model.NeighbourList{Neighbours: nil, Paging: model.NewPaging(0, 20, 0)},
+			wantErr: false,
+		},
+		{
+			name: "ok: user row gone",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetBlockUsersIdList(st.ctx, userId).Return(nil, nil)
+				st.flr.EXPECT().GetFriendUserIdsByUserIds(st.ctx, []int{userId}).Return(map[int][]int{userId: {111111, 222222}}, nil)
+				st.flr.EXPECT().GetUserListByUserIds(st.ctx, []int{111111, 222222}).Return(&model.FriendList{
+					Friends: []*model.Friend{{UserId: 222222, Name: "fuga"}},
+				}, nil)
+			},
+			want: &model.NeighbourList{
+				Neighbours: []*model.Neighbour{{UserId: 222222, Name: "fuga", Distance: 1}},
+				Paging:     model.NewPaging(2, 20, 0),
+			},
+			wantErr: false,
+		},
+		{
+			name: "ng: error at GetBlockUsersIdList()",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetBlockUsersIdList(st.ctx, userId).Return(nil, testutil.ErrTest)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "ng: error at GetFriendUserIdsByUserIds()",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetBlockUsersIdList(st.ctx, userId).Return(nil, nil)
+				st.flr.EXPECT().GetFriendUserIdsByUserIds(st.ctx, []int{userId}).Return(nil, testutil.ErrTest)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "ng: error at GetUserListByUserIds()",
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetBlockUsersIdList(st.ctx, userId).Return(nil, nil)
+				st.flr.EXPECT().GetFriendUserIdsByUserIds(st.ctx, []int{userId}).Return(map[int][]int{userId: {111111}}, nil)
+				st.flr.EXPECT().GetUserListByUserIds(st.ctx, []int{111111}).Return(nil, testutil.ErrTest)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newFriendListServiceTest(t)
+			tt.expects(st)
+
+			got, err := st.fls.GetNeighbourhood(st.ctx, userId, 1, 1, 20, 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetNeighbourhood() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_ParseBlockPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    BlockPolicy
+		wantErr error
+	}{
+		{
+			name:    "ok: one way",
+			s:       "one_way",
+			want:    BlockPolicyOneWay,
+			wantErr: nil,
+		},
+		{
+			name:    "ok: mutual",
+			s:       "mutual",
+			want:    BlockPolicyMutual,
+			wantErr: nil,
+		},
+		{
+			name:    "ng: unknown",
+			s:       "both",
+			want:    "",
+			wantErr: ErrBlockPolicyInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBlockPolicy(tt.s)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_hiddenUsers(t *testing.T) {
+	userId := testutil.UserIDForDebug
+
+	tests := []struct {
+		name    string
+		policy  BlockPolicy
+		expects func(*friendListServiceTest)
+		want    []int
+		wantErr bool
+	}{
+		{
+			name:   "ok: one way hides users I blocked",
+			policy: BlockPolicyOneWay,
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetBlockUsersIdList(st.ctx, userId).Return([]int{111111}, nil)
+			},
+			want:    []int{111111},
+			wantErr: false,
+		},
+		{
+			name:   "ok: mutual also hides users who blocked me",
+			policy: BlockPolicyMutual,
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetBlockUsersIdList(st.ctx, userId).Return([]int{111111}, nil)
+				st.flr.EXPECT().GetBlockedByUsersIdList(st.ctx, userId).Return([]int{222222}, nil)
+			},
+			want:    []int{111111, 222222},
+			wantErr: false,
+		},
+		{
+			name:   "ng: error at GetBlockedByUsersIdList()",
+			policy: BlockPolicyMutual,
+			expects: func(st *friendListServiceTest) {
+				st.flr.EXPECT().GetBlockUsersIdList(st.ctx, userId).Return(nil, nil)
+				st.flr.EXPECT().GetBlockedByUsersIdList(st.ctx, userId).Return(nil, testutil.ErrTest)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newFriendListServiceTest(t)
+			tt.expects(st)
+
+			got, err := hiddenUsers(st.ctx, st.flr, tt.policy, userId)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("hiddenUsers() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListService_MutualBlockPolicy(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	// users who blocked me must not be seen even though I never blocked them
+	want := newFriendList()
+
+	st := newFriendListServiceTest(t)
+	st.fls = NewFriendListService(st.flr, st.frr, BlockPolicyMutual)
+	st.flr.EXPECT().GetFriendListByUserIdExcludingBlockUsers(st.ctx, userId, true, model.FriendListSortUserId).Return(want, nil)
+	st.flr.EXPECT().GetFriendListOfFriendsByUserId(st.ctx, userId, true, model.FriendListSortUserId).Return(want, nil)
+
+	got, err := st.fls.GetFriendListByUserId(st.ctx, userId, model.FriendListSortUserId)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	got, err = st.fls.GetFriendListOfFriendsByUserId(st.ctx, userId, model.FriendListSortUserId)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+}
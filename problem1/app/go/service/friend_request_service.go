@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"problem1/model"
+	"problem1/repository"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+type FriendRequestService interface {
+	SendFriendRequest(ctx context.Context, frfr *model.FriendRequestForRequest) (*model.FriendRequest, error)
+	GetIncomingFriendRequests(ctx context.Context, userId int) (*model.FriendRequestList, error)
+	GetOutgoingFriendRequests(ctx context.Context, userId int) (*model.FriendRequestList, error)
+	AcceptFriendRequest(ctx context.Context, requestId, userId int) (*model.FriendRequest, error)
+	RejectFriendRequest(ctx context.Context, requestId, userId int) error
+	CancelFriendRequest(ctx context.Context, requestId, userId int) error
+}
+
+var (
+	ErrFriendRequestBlocked       = errors.New("user is blocked")
+	ErrAlreadyFriends             = errors.New("users are already friends")
+	ErrFriendRequestAlreadyExists = errors.New("friend request already exists")
+	ErrFriendRequestNotPending    = errors.New("friend request is not pending")
+	ErrFriendRequestForbidden     = errors.New("user can not operate the friend request")
+)
+
+type friendRequestService struct {
+	frr repository.FriendRequestRepository
+	flr repository.FriendListRepository
+}
+
+func NewFriendRequestService(frr repository.FriendRequestRepository, flr repository.FriendListRepository) FriendRequestService {
+	return &friendRequestService{
+		frr: frr,
+		flr: flr,
+	}
+}
+
+func (s *friendRequestService) existUserLink(ctx context.Context, user1Id, user2Id int, table string) (bool, error) {
+	if err := s.flr.CheckUserLink(ctx, user1Id, user2Id, table); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *friendRequestService) checkNotBlocked(ctx context.Context, user1Id, user2Id int) error {
+	for _, ul := range [][2]int{{user1Id, user2Id}, {user2Id, user1Id}} {
+		blocked, err := s.existUserLink(ctx, ul[0], ul[1], "block_list")
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return ErrFriendRequestBlocked
+		}
+	}
+
+	return nil
+}
+
+func (s *friendRequestService) SendFriendRequest(ctx context.Context, frfr *model.FriendRequestForRequest) (*model.FriendRequest, error) {
+	if err := s.checkNotBlocked(ctx, frfr.FromUserId, frfr.ToUserId); err != nil {
+		return nil, err
+	}
+
+	friends, err := s.existUserLink(ctx, frfr.FromUserId, frfr.ToUserId, "friend_link")
+	if err != nil {
+		return nil, err
+	}
+	if friends {
+		return nil, ErrAlreadyFriends
+	}
+
+	pending, err := s.frr.ExistsPendingFriendRequest(ctx, frfr.FromUserId, frfr.ToUserId)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, ErrFriendRequestAlreadyExists
+	}
+
+	// the check above does not lock, so a concurrent request is caught by the unique key
+	requestId, err := s.frr.InsertFriendRequest(ctx, frfr.FromUserId, frfr.ToUserId)
+	if err != nil {
+		if errors.Is(err, repository.ErrFriendRequestDuplicated) {
+			return nil, ErrFriendRequestAlreadyExists
+		}
+
+		return nil, err
+	}
+
+	return s.frr.GetFriendRequestForUpdate(ctx, requestId)
+}
+
+func (s *friendRequestService) GetIncomingFriendRequests(ctx context.Context, userId int) (*model.FriendRequestList, error) {
+	return s.frr.GetIncomingFriendRequests(ctx, userId)
+}
+
+func (s *friendRequestService) GetOutgoingFriendRequests(ctx context.Context, userId int) (*model.FriendRequestList, error) {
+	return s.frr.GetOutgoingFriendRequests(ctx, userId)
+}
+
+// getPendingFriendRequest returns the locked request after checking that userId is allowed to operate it.
+func (s *friendRequestService) getPendingFriendRequest(ctx context.Context, requestId, userId int, operatorOf func(*model.FriendRequest) int) (*model.FriendRequest, error) {
+	fr, err := s.frr.GetFriendRequestForUpdate(ctx, requestId)
+	if err != nil {
+		return nil, err
+	}
+	if operatorOf(fr) != userId {
+		return nil, ErrFriendRequestForbidden
+	}
+	if fr.Status != model.FriendRequestStatusPending {
+		return nil, ErrFriendRequestNotPending
+	}
+
+	return fr, nil
+}
+
+func toUser(fr *model.FriendRequest) int {
+	return fr.ToUserId
+}
+
+func fromUser(fr *model.FriendRequest) int {
+	return fr.FromUserId
+}
+
+// AcceptFriendRequest marks the request accepted and returns it. The caller makes the users
+// friends with FriendListService.AddFriendship in the same transaction.
+func (s *friendRequestService) AcceptFriendRequest(ctx context.Context, requestId, userId int) (*model.FriendRequest, error) {
+	fr, err := s.getPendingFriendRequest(ctx, requestId, userId, toUser)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNotBlocked(ctx, fr.FromUserId, fr.ToUserId); err != nil {
+		return nil, err
+	}
+
+	if err := s.frr.UpdatePendingFriendRequestStatus(ctx, requestId, model.FriendRequestStatusAccepted); err != nil {
+		return nil, err
+	}
+	fr.Status = model.FriendRequestStatusAccepted
+
+	return fr, nil
+}
+
+func (s *friendRequestService) RejectFriendRequest(ctx context.Context, requestId, userId int) error {
+	if _, err := s.getPendingFriendRequest(ctx, requestId, userId, toUser); err != nil {
+		return err
+	}
+
+	return s.frr.UpdatePendingFriendRequestStatus(ctx, requestId, model.FriendRequestStatusRejected)
+}
+
+func (s *friendRequestService) CancelFriendRequest(ctx context.Context, requestId, userId int) error {
+	if _, err := s.getPendingFriendRequest(ctx, requestId, userId, fromUser); err != nil {
+		return err
+	}
+
+	return s.frr.UpdatePendingFriendRequestStatus(ctx, requestId, model.FriendRequestStatusCanceled)
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/mock/mock_repository"
+	"problem1/model"
+	"problem1/pkg/testutil"
+	"problem1/repository"
+)
+
+type friendRequestServiceTest struct {
+	frr *mock_repository.MockFriendRequestRepository
+	flr *mock_repository.MockFriendListRepository
+	frs FriendRequestService
+	ctx context.Context
+}
+
+func newFriendRequestServiceTest(t *testing.T) *friendRequestServiceTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	frr := mock_repository.NewMockFriendRequestRepository(ctrl)
+	flr := mock_repository.NewMockFriendListRepository(ctrl)
+
+	return &friendRequestServiceTest{
+		frr: frr,
+		flr: flr,
+		frs: NewFriendRequestService(frr, flr),
+		ctx: context.Background(),
+	}
+}
+
+func newPendingFriendRequest() *model.FriendRequest {
+	return &model.FriendRequest{
+		RequestId:  1,
+		FromUserId: testutil.UserIDForDebug,
+		ToUserId:   111111,
+		Status:     model.FriendRequestStatusPending,
+	}
+}
+
+func Test_friendRequestService_SendFriendRequest(t *testing.T) {
+	req := &model.FriendRequestForRequest{
+		FromUserId: testutil.UserIDForDebug,
+		ToUserId:   111111,
+	}
+	want := newPendingFriendRequest()
+
+	tests := []struct {
+		name    string
+		expects func(*friendRequestServiceTest)
+		want    *model.FriendRequest
+		wantErr error
+	}{
+		{
+			name: "ok",
+			expects: func(st *friendRequestServiceTest) {
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.FromUserId, req.ToUserId, "block_list").Return(sql.ErrNoRows)
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.ToUserId, req.FromUserId, "block_list").Return(sql.ErrNoRows)
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.FromUserId, req.ToUserId, "friend_link").Return(sql.ErrNoRows)
+				st.frr.EXPECT().ExistsPendingFriendRequest(st.ctx, req.FromUserId, req.ToUserId).Return(false, nil)
+				st.frr.EXPECT().InsertFriendRequest(st.ctx, req.FromUserId, req.ToUserId).Return(1, nil)
+				st.frr.EXPECT().GetFriendRequestForUpdate(st.ctx, 1).Return(want, nil)
+			},
+			want:    want,
+			wantErr: nil,
+		},
+		{
+			name: "ng: blocked by sender",
+			expects: func(st *friendRequestServiceTest) {
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.FromUserId, req.ToUserId, "block_list").Return(nil)
+			},
+			want:    nil,
+			wantErr: ErrFriendRequestBlocked,
+		},
+		{
+			name: "ng: blocked by receiver",
+			expects: func(st *friendRequestServiceTest) {
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.FromUserId, req.ToUserId, "block_list").Return(sql.ErrNoRows)
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.ToUserId, req.FromUserId, "block_list").Return(nil)
+			},
+			want:    nil,
+			wantErr: ErrFriendRequestBlocked,
+		},
+		{
+			name: "ng: already friends",
+			expects: func(st *friendRequestServiceTest) {
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.FromUserId, req.ToUserId, "block_list").Return(sql.ErrNoRows)
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.ToUserId, req.FromUserId, "block_list").Return(sql.ErrNoRows)
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.FromUserId, req.ToUserId, "friend_link").Return(nil)
+			},
+			want:    nil,
+			wantErr: ErrAlreadyFriends,
+		},
+		{
+			name: "ng: pending request exists",
+			expects: func(st *friendRequestServiceTest) {
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.FromUserId, req.ToUserId, "block_list").Return(sql.ErrNoRows)
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.ToUserId, req.FromUserId, "block_list").Return(sql.ErrNoRows)
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.FromUserId, req.ToUserId, "friend_link").Return(sql.ErrNoRows)
+				st.frr.EXPECT().ExistsPendingFriendRequest(st.ctx, req.FromUserId, req.ToUserId).Return(true, nil)
+			},
+			want:    nil,
+			wantErr: ErrFriendRequestAlreadyExists,
+		},
+		{
+			name: "ng: error at CheckUserLink()",
+			expects: func(st *friendRequestServiceTest) {
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.FromUserId, req.ToUserId, "block_list").Return(testutil.ErrTest)
+			},
+			want:    nil,
+			wantErr: testutil.ErrTest,
+		},
+		{
+			name: "ng: pending request inserted concurrently",
+			expects: func(st *friendRequestServiceTest) {
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.FromUserId, req.ToUserId, "block_list").Return(sql.ErrNoRows)
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.ToUserId, req.FromUserId, "block_list").Return(sql.ErrNoRows)
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.FromUserId, req.ToUserId, "friend_link").Return(sql.ErrNoRows)
+				st.frr.EXPECT().ExistsPendingFriendRequest(st.ctx, req.FromUserId, req.ToUserId).Return(false, nil)
+				st.frr.EXPECT().InsertFriendRequest(st.ctx, req.FromUserId, req.ToUserId).Return(0, repository.ErrFriendRequestDuplicated)
+			},
+			want:    nil,
+			wantErr: ErrFriendRequestAlreadyExists,
+		},
+		{
+			name: "ng: error at InsertFriendRequest()",
+			expects: func(st *friendRequestServiceTest) {
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.FromUserId, req.ToUserId, "block_list").Return(sql.ErrNoRows)
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.ToUserId, req.FromUserId, "block_list").Return(sql.ErrNoRows)
+				st.flr.EXPECT().CheckUserLink(st.ctx, req.FromUserId, req.ToUserId, "friend_link").Return(sql.ErrNoRows)
+				st.frr.EXPECT().ExistsPendingFriendRequest(st.ctx, req.FromUserId, req.ToUserId).Return(false, nil)
+				st.frr.EXPECT().InsertFriendRequest(st.ctx, req.FromUserId, req.ToUserId).Return(0, testutil.ErrTest)
+			},
+			want:    nil,
+			wantErr: testutil.ErrTest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newFriendRequestServiceTest(t)
+			tt.expects(st)
+
+			got, err := st.frs.SendFriendRequest(st.ctx, req)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendRequestService_AcceptFriendRequest(t *testing.T) {
+	fr := newPendingFriendRequest()
+	accepted := newPendingFriendRequest()
+	accepted.Status = model.FriendRequestStatusAccepted
+
+	tests := []struct {
+		name    string
+		expects func(*friendRequestServiceTest)
+		userId  int
+		want    *model.FriendRequest
+		wantErr error
+	}{
+		{
+			name: "ok",
+			expects: func(st *friendRequestServiceTest) {
+				st.frr.EXPECT().GetFriendRequestForUpdate(st.ctx, fr.RequestId).Return(newPendingFriendRequest(), nil)
+				st.flr.EXPECT().CheckUserLink(st.ctx, fr.FromUserId, fr.ToUserId, "block_list").Return(sql.ErrNoRows)
+				st.flr.EXPECT().CheckUserLink(st.ctx, fr.ToUserId, fr.FromUserId, "block_list").Return(sql.ErrNoRows)
+				st.frr.EXPECT().UpdatePendingFriendRequestStatus(st.ctx, fr.RequestId, model.FriendRequestStatusAccepted).Return(nil)
+			},
+			userId:  fr.ToUserId,
+			want:    accepted,
+			wantErr: nil,
+		},
+		{
+			name: "ng: sender can not accept",
+			expects: func(st *friendRequestServiceTest) {
+				st.frr.EXPECT().GetFriendRequestForUpdate(st.ctx, fr.RequestId).Return(newPendingFriendRequest(), nil)
+			},
+			userId:  fr.FromUserId,
+			want:    nil,
+			wantErr: ErrFriendRequestForbidden,
+		},
+		{
+			name: "ng: not pending",
+			expects: func(st *friendRequestServiceTest) {
+				rejected := newPendingFriendRequest()
+				rejected.Status = model.FriendRequestStatusRejected
+				st.frr.EXPECT().GetFriendRequestForUpdate(st.ctx, fr.RequestId).Return(rejected, nil)
+			},
+			userId:  fr.ToUserId,
+			want:    nil,
+			wantErr: ErrFriendRequestNotPending,
+		},
+		{
+			name: "ng: blocked after request",
+			expects: func(st *friendRequestServiceTest) {
+				st.frr.EXPECT().GetFriendRequestForUpdate(st.ctx, fr.RequestId).Return(newPendingFriendRequest(), nil)
+				st.flr.EXPECT().CheckUserLink(st.ctx, fr.FromUserId, fr.ToUserId, "block_list").Return(sql.ErrNoRows)
+				st.flr.EXPECT().CheckUserLink(st.ctx, fr.ToUserId, fr.FromUserId, "block_list").Return(nil)
+			},
+			userId:  fr.ToUserId,
+			want:    nil,
+			wantErr: ErrFriendRequestBlocked,
+		},
+		{
+			name: "ng: request not exist",
+			expects: func(st *friendRequestServiceTest) {
+				st.frr.EXPECT().GetFriendRequestForUpdate(st.ctx, fr.RequestId).Return(nil, repository.ErrFriendRequestNotFound)
+			},
+			userId:  fr.ToUserId,
+			want:    nil,
+			wantErr: repository.ErrFriendRequestNotFound,
+		},
+		{
+			name: "ng: error at UpdatePendingFriendRequestStatus()",
+			expects: func(st *friendRequestServiceTest) {
+				st.frr.EXPECT().GetFriendRequestForUpdate(st.ctx, fr.RequestId).Return(newPendingFriendRequest(), nil)
+				st.flr.EXPECT().CheckUserLink(st.ctx, fr.FromUserId, fr.ToUserId, "block_list").Return(sql.ErrNoRows)
+				st.flr.EXPECT().CheckUserLink(st.ctx, fr.ToUserId, fr.FromUserId, "block_list").Return(sql.ErrNoRows)
+				st.frr.EXPECT().UpdatePendingFriendRequestStatus(st.ctx, fr.RequestId, model.FriendRequestStatusAccepted).Return(testutil.ErrTest)
+			},
+			userId:  fr.ToUserId,
+			want:    nil,
+			wantErr: testutil.ErrTest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newFriendRequestServiceTest(t)
+			tt.expects(st)
+
+			got, err := st.frs.AcceptFriendRequest(st.ctx, fr.RequestId, tt.userId)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendRequestService_RejectFriendRequest(t *testing.T) {
+	fr := newPendingFriendRequest()
+
+	tests := []struct {
+		name    string
+		expects func(*friendRequestServiceTest)
+		userId  int
+		wantErr error
+	}{
+		{
+			name: "ok",
+			expects: func(st *friendRequestServiceTest) {
+				st.frr.EXPECT().GetFriendRequestForUpdate(st.ctx, fr.RequestId).Return(newPendingFriendRequest(), nil)
+				st.frr.EXPECT().UpdatePendingFriendRequestStatus(st.ctx, fr.RequestId, model.FriendRequestStatusRejected).Return(nil)
+			},
+			userId:  fr.ToUserId,
+			wantErr: nil,
+		},
+		{
+			name: "ng: sender can not reject",
+			expects: func(st *friendRequestServiceTest) {
+				st.frr.EXPECT().GetFriendRequestForUpdate(st.ctx, fr.RequestId).Return(newPendingFriendRequest(), nil)
+			},
+			userId:  fr.FromUserId,
+			wantErr: ErrFriendRequestForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newFriendRequestServiceTest(t)
+			tt.expects(st)
+
+			err := st.frs.RejectFriendRequest(st.ctx, fr.RequestId, tt.userId)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_friendRequestService_CancelFriendRequest(t *testing.T) {
+	fr := newPendingFriendRequest()
+
+	tests := []struct {
+		name    string
+		expects func(*friendRequestServiceTest)
+		userId  int
+		wantErr error
+	}{
+		{
+			name: "ok",
+			expects: func(st *friendRequestServiceTest) {
+				st.frr.EXPECT().GetFriendRequestForUpdate(st.ctx, fr.RequestId).Return(newPendingFriendRequest(), nil)
+				st.frr.EXPECT().UpdatePendingFriendRequestStatus(st.ctx, fr.RequestId, model.FriendRequestStatusCanceled).Return(nil)
+			},
+			userId:  fr.FromUserId,
+			wantErr: nil,
+		},
+		{
+			name: "ng: receiver can not cancel",
+			expects: func(st *friendRequestServiceTest) {
+				st.frr.EXPECT().GetFriendRequestForUpdate(st.ctx, fr.RequestId).Return(newPendingFriendRequest(), nil)
+			},
+			userId:  fr.ToUserId,
+			wantErr: ErrFriendRequestForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newFriendRequestServiceTest(t)
+			tt.expects(st)
+
+			err := st.frs.CancelFriendRequest(st.ctx, fr.RequestId, tt.userId)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_friendRequestService_GetIncomingAndOutgoingFriendRequests(t *testing.T) {
+	want := &model.FriendRequestList{FriendRequests: []*model.FriendRequest{newPendingFriendRequest()}}
+
+	st := newFriendRequestServiceTest(t)
+	st.frr.EXPECT().GetIncomingFriendRequests(st.ctx, 111111).Return(want, nil)
+	st.frr.EXPECT().GetOutgoingFriendRequests(st.ctx, testutil.UserIDForDebug).Return(want, nil)
+
+	got, err := st.frs.GetIncomingFriendRequests(st.ctx, 111111)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	got, err = st.frs.GetOutgoingFriendRequests(st.ctx, testutil.UserIDForDebug)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"problem1/pkg/graphexport"
+	"problem1/repository"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+type GraphExportService interface {
+	Export(ctx context.Context, enc graphexport.Encoder, userId, depth int) error
+}
+
+type graphExportService struct {
+	flr repository.FriendListRepository
+	ger repository.GraphExportRepository
+}
+
+func NewGraphExportService(flr repository.FriendListRepository, ger repository.GraphExportRepository) GraphExportService {
+	return &graphExportService{
+		flr: flr,
+		ger: ger,
+	}
+}
+
+// edgeTables are the tables exported as edges, in the order they are written.
+var edgeTables = []struct {
+	table    string
+	edgeType graphexport.EdgeType
+}{
+	{table: "friend_link", edgeType: graphexport.EdgeTypeFriend},
+	{table: "block_list", edgeType: graphexport.EdgeTypeBlock},
+}
+
+// Export writes the users, the friend links and the blocks to enc as they are read. With a
+// depth of 1 or more, it writes the ego network of userId instead: the users up to depth
+// hops away following friend_link as GetNeighbourhood does, and the links between them.
+// The blocks are exported as they are and hide no one.
+func (s *graphExportService) Export(ctx context.Context, enc graphexport.Encoder, userId, depth int) error {
+	var userIds []int
+	if depth > 0 {
+		var err error
+		if userIds, err = s.egoNetwork(ctx, userId, depth); err != nil {
+			return err
+		}
+	}
+
+	if err := enc.Begin(); err != nil {
+		return err
+	}
+	if err := s.ger.EachUser(ctx, userIds, enc.User); err != nil {
+		return err
+	}
+	for _, et := range edgeTables {
+		err := s.ger.EachUserLink(ctx, et.table, userIds, func(user1Id, user2Id int) error {
+			return enc.Edge(graphexport.Edge{Type: et.edgeType, From: user1Id, To: user2Id})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return enc.End()
+}
+
+// egoNetwork returns userId and the users up to depth hops away from it ordered by user_id.
+func (s *graphExportService) egoNetwork(ctx context.Context, userId, depth int) ([]int, error) {
+	visited := map[int]struct{}{userId: {}}
+	userIds := []int{userId}
+	frontier := []int{userId}
+	for d := 1; d <= depth && len(frontier) > 0; d++ {
+		friends, err := s.flr.GetFriendUserIdsByUserIds(ctx, frontier)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []int
+		for _, u := range frontier {
+			for _, friend := range friends[u] {
+				if _, ok := visited[friend]; ok {
+					continue
+				}
+
+				visited[friend] = struct{}{}
+				next = append(next, friend)
+			}
+		}
+		userIds = append(userIds, next...)
+		frontier = next
+	}
+	sort.Ints(userIds)
+
+	return userIds, nil
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/mock/mock_repository"
+	"problem1/model"
+	"problem1/pkg/graphexport"
+	"problem1/pkg/testutil"
+)
+
+type graphExportServiceTest struct {
+	flr *mock_repository.MockFriendListRepository
+	ger *mock_repository.MockGraphExportRepository
+	ges GraphExportService
+	ctx context.Context
+}
+
+func newGraphExportServiceTest(t *testing.T) *graphExportServiceTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	flr := mock_repository.NewMockFriendListRepository(ctrl)
+	ger := mock_repository.NewMockGraphExportRepository(ctrl)
+
+	return &graphExportServiceTest{
+		flr: flr,
+		ger: ger,
+		ges: NewGraphExportService(flr, ger),
+		ctx: context.Background(),
+	}
+}
+
+// serveRows answers the reads of the graph from users and links, filtered by userIds as the repository does.
+func (st *graphExportServiceTest) serveRows(userIds []int, users []*model.User, links map[string][][2]int) {
+	st.ger.EXPECT().EachUser(st.ctx, userIds, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ []int, fn func(*model.User) error) error {
+			for _, u := range users {
+				if err := fn(u); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	for _, table := range []string{"friend_link", "block_list"} {
+		rows := links[table]
+		st.ger.EXPECT().EachUserLink(st.ctx, table, userIds, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, _ []int, fn func(int, int) error) error {
+				for _, l := range rows {
+					if err := fn(l[0], l[1]); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+	}
+}
+
+func Test_graphExportService_Export(t *testing.T) {
+	users := []*model.User{{UserId: 1, Name: "hoge"}, {UserId: 2, Name: "fuga"}}
+
+	tests := []struct {
+		name    string
+		expects func(*graphExportServiceTest)
+		userId  int
+		depth   int
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "ok: whole graph",
+			expects: func(st *graphExportServiceTest) {
+				st.serveRows(nil, users, map[string][][2]int{
+					"friend_link": {{1, 2}, {2, 1}},
+					"block_list":  {{2, 3}},
+				})
+			},
+			userId: 0,
+			depth:  0,
+			want: `{"users":[{"userId":1,"name":"hoge"},{"userId":2,"name":"fuga"}],"edges":[
+				{"type":"friend","from":1,"to":2},{"type":"friend","from":2,"to":1},{"type":"block","from":2,"to":3}]}`,
+			wantErr: false,
+		},
+		{
+			name: "ok: ego network",
+			expects: func(st *graphExportServiceTest) {
+				gomock.InOrder(
+					st.flr.EXPECT().GetFriendUserIdsByUserIds(st.ctx, []int{5}).Return(map[int][]int{5: {4, 1}}, nil),
+					st.flr.EXPECT().GetFriendUserIdsByUserIds(st.ctx, []int{4, 1}).Return(map[int][]int{4: {5, 9}, 1: {2}}, nil),
+				)
+				st.serveRows([]int{1, 2, 4, 5, 9}, users, map[string][][2]int{"friend_link": {{1, 2}}})
+			},
+			userId:  5,
+			depth:   2,
+			want:    `{"users":[{"userId":1,"name":"hoge"},{"userId":2,"name":"fuga"}],"edges":[{"type":"friend","from":1,"to":2}]}`,
+			wantErr: false,
+		},
+		{
+			name: "ok: ego network of a user without friends",
+			expects: func(st *graphExportServiceTest) {
+				st.flr.EXPECT().GetFriendUserIdsByUserIds(st.ctx, []int{5}).Return(map[int][]int{}, nil)
+				st.serveRows([]int{5}, nil, nil)
+			},
+			userId:  5,
+			depth:   3,
+			want:    `{"users":[],"edges":[]}`,
+			wantErr: false,
+		},
+		{
+			name: "ng: error at GetFriendUserIdsByUserIds",
+			expects: func(st *graphExportServiceTest) {
+				st.flr.EXPECT().GetFriendUserIdsByUserIds(st.ctx, []int{5}).Return(nil, testutil.ErrTest)
+			},
+			userId:  5,
+			depth:   1,
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "ng: error at EachUser",
+			expects: func(st *graphExportServiceTest) {
+				st.ger.EXPECT().EachUser(st.ctx, nil, gomock.Any()).Return(testutil.ErrTest)
+			},
+			userId:  0,
+			depth:   0,
+			want:    `{"users":[`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newGraphExportServiceTest(t)
+			tt.expects(st)
+
+			var b bytes.Buffer
+			enc, err := graphexport.NewEncoder(&b, graphexport.FormatJSON)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = st.ges.Export(st.ctx, enc, tt.userId, tt.depth)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Export() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if err != nil {
+				assert.Equal(t, tt.want, b.String())
+				return
+			}
+			assert.JSONEq(t, tt.want, b.String())
+		})
+	}
+}
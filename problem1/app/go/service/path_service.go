@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"problem1/model"
+	"problem1/repository"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+type PathService interface {
+	GetShortestPath(ctx context.Context, fromUserId, toUserId, maxDepth int) (*model.FriendPath, error)
+}
+
+var (
+	ErrPathNotFound             = errors.New("path not exist within max depth")
+	ErrPathSearchBudgetExceeded = errors.New("path search visited too many users")
+)
+
+type pathService struct {
+	flr             repository.FriendListRepository
+	blockPolicy     BlockPolicy
+	maxVisitedUsers int
+}
+
+// NewPathService returns a PathService which gives up once maxVisitedUsers users are visited.
+func NewPathService(flr repository.FriendListRepository, blockPolicy BlockPolicy, maxVisitedUsers int) PathService {
+	return &pathService{
+		flr:             flr,
+		blockPolicy:     blockPolicy,
+		maxVisitedUsers: maxVisitedUsers,
+	}
+}
+
+// searchSide is one end of a bidirectional BFS.
+type searchSide struct {
+	// parents maps each visited user to the user it was reached from
+	parents  map[int]int
+	frontier []int
+	// neighbors follows friend_link forward from the start and backward from the goal
+	neighbors func(ctx context.Context, userIds []int) (map[int][]int, error)
+}
+
+// expand visits the next layer of s and returns a user also visited by other if any.
+func (s *searchSide) expand(ctx context.Context, other *searchSide, blocked map[int]struct{}) (int, bool, error) {
+	neighbors, err := s.neighbors(ctx, s.frontier)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var next []int
+	for _, userId := range s.frontier {
+		for _, neighbor := range neighbors[userId] {
+			if _, ok := blocked[neighbor]; ok {
+				continue
+			}
+			if _, ok := s.parents[neighbor]; ok {
+				continue
+			}
+
+			s.parents[neighbor] = userId
+			if _, ok := other.parents[neighbor]; ok {
+				return neighbor, true, nil
+			}
+			next = append(next, neighbor)
+		}
+	}
+	s.frontier = next
+
+	return 0, false, nil
+}
+
+// GetShortestPath searches the friend links from both users at once, expanding the smaller
+// frontier first, and skips users hidden from either of them by the block policy.
+func (s *pathService) GetShortestPath(ctx context.Context, fromUserId, toUserId, maxDepth int) (*model.FriendPath, error) {
+	if fromUserId == toUserId {
+		return s.newFriendPath(ctx, []int{fromUserId})
+	}
+
+	blocked := make(map[int]struct{})
+	for _, userId := range []int{fromUserId, toUserId} {
+		blockUsers, err := hiddenUsers(ctx, s.flr, s.blockPolicy, userId)
+		if err != nil {
+			return nil, err
+		}
+		for _, blockUser := range blockUsers {
+			blocked[blockUser] = struct{}{}
+		}
+	}
+	_, fromBlocked := blocked[fromUserId]
+	_, toBlocked := blocked[toUserId]
+	if fromBlocked || toBlocked {
+		return nil, ErrPathNotFound
+	}
+
+	forward := &searchSide{
+		parents:   map[int]int{fromUserId: fromUserId},
+		frontier:  []int{fromUserId},
+		neighbors: s.flr.GetFriendUserIdsByUserIds,
+	}
+	backward := &searchSide{
+		parents:   map[int]int{toUserId: toUserId},
+		frontier:  []int{toUserId},
+		neighbors: s.flr.GetFriendOfUserIdsByUserIds,
+	}
+
+	for depth := 0; depth < maxDepth; depth++ {
+		side, other := forward, backward
+		if len(backward.frontier) < len(forward.frontier) {
+			side, other = backward, forward
+		}
+
+		meet, ok, err := side.expand(ctx, other, blocked)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return s.newFriendPath(ctx, joinPath(forward.parents, backward.parents, fromUserId, toUserId, meet))
+		}
+		if len(side.frontier) == 0 {
+			return nil, ErrPathNotFound
+		}
+		if len(forward.parents)+len(backward.parents) > s.maxVisitedUsers {
+			return nil, ErrPathSearchBudgetExceeded
+		}
+	}
+
+	return nil, ErrPathNotFound
+}
+
+// joinPath walks back from meet to both ends.
+func joinPath(forwardParents, backwardParents map[int]int, fromUserId, toUserId, meet int) []int {
+	var path []int
+	for userId := meet; userId != fromUserId; userId = forwardParents[userId] {
+		path = append(path, userId)
+	}
+	path = append(path, fromUserId)
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	for userId := meet; userId != toUserId; {
+		userId = backwardParents[userId]
+		path = append(path, userId)
+	}
+
+	return path
+}
+
+func (s *pathService) newFriendPath(ctx context.Context, path []int) (*model.FriendPath, error) {
+	userList, err := s.flr.GetUserListByUserIds(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[int]*model.Friend, len(userList.Friends))
+	for _, user := range userList.Friends {
+		users[user.UserId] = user
+	}
+
+	friendPath := &model.FriendPath{Degree: len(path) - 1}
+	for _, userId := range path {
+		user, ok := users[userId]
+		if !ok {
+			// the link remains but the user itself does not
+			user = &model.Friend{UserId: userId}
+		}
+		friendPath.Users = append(friendPath.Users, user)
+	}
+
+	return friendPath, nil
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/mock/mock_repository"
+	"problem1/model"
+	"problem1/pkg/testutil"
+)
+
+type pathServiceTest struct {
+	flr *mock_repository.MockFriendListRepository
+	ps  PathService
+	ctx context.Context
+}
+
+func newPathServiceTest(t *testing.T, maxVisitedUsers int) *pathServiceTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	flr := mock_repository.NewMockFriendListRepository(ctrl)
+
+	return &pathServiceTest{
+		flr: flr,
+		ps:  NewPathService(flr, BlockPolicyOneWay, maxVisitedUsers),
+		ctx: context.Background(),
+	}
+}
+
+// serveGraph answers the neighbor and user lookups from links, where links[u] are the friends of u.
+func (pt *pathServiceTest) serveGraph(links map[int][]int, blockUsers map[int][]int) {
+	lookup := func(m map[int][]int) func(context.Context, []int) (map[int][]int, error) {
+		return func(_ context.Context, userIds []int) (map[int][]int, error) {
+			got := make(map[int][]int)
+			for _, userId := range userIds {
+				if v, ok := m[userId]; ok {
+					got[userId] = v
+				}
+			}
+
+			return got, nil
+		}
+	}
+	reverse := make(map[int][]int)
+	for u, vs := range links {
+		for _, v := range vs {
+			reverse[v] = append(reverse[v], u)
+		}
+	}
+
+	pt.flr.EXPECT().GetFriendUserIdsByUserIds(gomock.Any(), gomock.Any()).DoAndReturn(lookup(links)).AnyTimes()
+	pt.flr.EXPECT().GetFriendOfUserIdsByUserIds(gomock.Any(), gomock.Any()).DoAndReturn(lookup(reverse)).AnyTimes()
+	pt.flr.EXPECT().GetBlockUsersIdList(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, userId int) ([]int, error) {
+		return blockUsers[userId], nil
+	}).AnyTimes()
+	pt.flr.EXPECT().GetUserListByUserIds(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, userIds []int) (*model.FriendList, error) {
+		friendList := &model.FriendList{}
+		for _, userId := range userIds {
+			friendList.Friends = append(friendList.Friends, &model.Friend{UserId: userId, Name: "user"})
+		}
+
+		return friendList, nil
+	}).AnyTimes()
+}
+
+func pathOf(userIds ...int) *model.FriendPath {
+	friendPath := &model.FriendPath{Degree: len(userIds) - 1}
+	for _, userId := range userIds {
+		friendPath.Users = append(friendPath.Users, &model.Friend{UserId: userId, Name: "user"})
+	}
+
+	return friendPath
+}
+
+func Test_pathService_GetShortestPath(t *testing.T) {
+	// 1 - 2 - 3 - 4 - 5 and the shortcut 1 - 6 - 5, linked both ways
+	links := map[int][]int{
+		1: {2, 6},
+		2: {1, 3},
+		3: {2, 4},
+		4: {3, 5},
+		5: {4, 6},
+		6: {1, 5},
+	}
+
+	tests := []struct {
+		name            string
+		links           map[int][]int
+		blockUsers      map[int][]int
+		from, to        int
+		maxDepth        int
+		maxVisitedUsers int
+		want            *model.FriendPath
+		wantErr         error
+	}{
+		{
+			name:            "ok: shortcut",
+			links:           links,
+			from:            1,
+			to:              5,
+			maxDepth:        6,
+			maxVisitedUsers: 100,
+			want:            pathOf(1, 6, 5),
+		},
+		{
+			name:            "ok: direct friend",
+			links:           links,
+			from:            1,
+			to:              2,
+			maxDepth:        6,
+			maxVisitedUsers: 100,
+			want:            pathOf(1, 2),
+		},
+		{
+			name:            "ok: same user",
+			links:           links,
+			from:            3,
+			to:              3,
+			maxDepth:        6,
+			maxVisitedUsers: 100,
+			want:            pathOf(3),
+		},
+		{
+			name:            "ok: detour around user blocked by the start",
+			links:           links,
+			blockUsers:      map[int][]int{1: {6}},
+			from:            1,
+			to:              5,
+			maxDepth:        6,
+			maxVisitedUsers: 100,
+			want:            pathOf(1, 2, 3, 4, 5),
+		},
+		{
+			name:            "ok: detour around user blocked by the goal",
+			links:           links,
+			blockUsers:      map[int][]int{5: {6}},
+			from:            1,
+			to:              5,
+			maxDepth:        6,
+			maxVisitedUsers: 100,
+			want:            pathOf(1, 2, 3, 4, 5),
+		},
+		{
+			name:            "ok: one way link is followed only forward",
+			links:           map[int][]int{1: {2}, 2: {3}},
+			from:            1,
+			to:              3,
+			maxDepth:        6,
+			maxVisitedUsers: 100,
+			want:            pathOf(1, 2, 3),
+		},
+		{
+			name:            "ng: one way link against the direction",
+			links:           map[int][]int{1: {2}, 2: {3}},
+			from:            3,
+			to:              1,
+			maxDepth:        6,
+			maxVisitedUsers: 100,
+			wantErr:         ErrPathNotFound,
+		},
+		{
+			name:            "ng: goal blocked",
+			links:           links,
+			blockUsers:      map[int][]int{5: {1}},
+			from:            1,
+			to:              5,
+			maxDepth:        6,
+			maxVisitedUsers: 100,
+			wantErr:         ErrPathNotFound,
+		},
+		{
+			name:            "ng: deeper than max depth",
+			links:           links,
+			blockUsers:      map[int][]int{1: {6}},
+			from:            1,
+			to:              5,
+			maxDepth:        3,
+			maxVisitedUsers: 100,
+			wantErr:         ErrPathNotFound,
+		},
+		{
+			name:            "ng: visited users over budget",
+			links:           links,
+			blockUsers:      map[int][]int{1: {6}},
+			from:            1,
+			to:              5,
+			maxDepth:        6,
+			maxVisitedUsers: 3,
+			wantErr:         ErrPathSearchBudgetExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := newPathServiceTest(t, tt.maxVisitedUsers)
+			pt.serveGraph(tt.links, tt.blockUsers)
+
+			got, err := pt.ps.GetShortestPath(pt.ctx, tt.from, tt.to, tt.maxDepth)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_pathService_GetShortestPath_Error(t *testing.T) {
+	pt := newPathServiceTest(t, 100)
+	pt.flr.EXPECT().GetBlockUsersIdList(pt.ctx, 1).Return(nil, nil)
+	pt.flr.EXPECT().GetBlockUsersIdList(pt.ctx, 2).Return(nil, nil)
+	pt.flr.EXPECT().GetFriendUserIdsByUserIds(pt.ctx, []int{1}).Return(nil, testutil.ErrTest)
+
+	got, err := pt.ps.GetShortestPath(pt.ctx, 1, 2, 6)
+	assert.ErrorIs(t, err, testutil.ErrTest)
+	assert.Nil(t, got)
+}
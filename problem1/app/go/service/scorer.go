@@ -0,0 +1,82 @@
+package service
+
+import (
+	"errors"
+	"math"
+)
+
+// ScoreInput is what a Scorer sees of a friend of friend.
+type ScoreInput struct {
+	// UserFriendCount is the number of friends of the requester.
+	UserFriendCount int
+	// CandidateFriendCount is the number of friends of the friend of friend.
+	CandidateFriendCount int
+	// MutualFriendCounts is the number of friends of each mutual friend.
+	MutualFriendCounts []int
+}
+
+// Scorer ranks friend of friends for suggestions. Higher is better.
+type Scorer interface {
+	Score(in *ScoreInput) float64
+}
+
+var ErrScorerNotFound = errors.New("scorer not exist")
+
+const (
+	ScorerMutual     = "mutual"
+	ScorerJaccard    = "jaccard"
+	ScorerAdamicAdar = "adamic_adar"
+)
+
+var scorers = map[string]Scorer{
+	ScorerMutual:     MutualScorer{},
+	ScorerJaccard:    JaccardScorer{},
+	ScorerAdamicAdar: AdamicAdarScorer{},
+}
+
+// GetScorer returns the Scorer registered as name.
+func GetScorer(name string) (Scorer, error) {
+	scorer, ok := scorers[name]
+	if !ok {
+		return nil, ErrScorerNotFound
+	}
+
+	return scorer, nil
+}
+
+// MutualScorer scores by the number of mutual friends.
+type MutualScorer struct{}
+
+func (MutualScorer) Score(in *ScoreInput) float64 {
+	return float64(len(in.MutualFriendCounts))
+}
+
+// JaccardScorer scores by the mutual friends over the union of both friends.
+type JaccardScorer struct{}
+
+func (JaccardScorer) Score(in *ScoreInput) float64 {
+	mutual := len(in.MutualFriendCounts)
+	union := in.UserFriendCount + in.CandidateFriendCount - mutual
+	if union <= 0 {
+		return 0
+	}
+
+	return float64(mutual) / float64(union)
+}
+
+// AdamicAdarScorer weights each mutual friend by 1 / log(friend count), so that
+// sharing a friend who has few friends counts more than sharing a popular one.
+type AdamicAdarScorer struct{}
+
+func (AdamicAdarScorer) Score(in *ScoreInput) float64 {
+	var score float64
+	for _, count := range in.MutualFriendCounts {
+		// a mutual friend has at least the two users as friends
+		if count < 2 {
+			count = 2
+		}
+		score += 1 / math.Log(float64(count))
+	}
+
+	return score
+}
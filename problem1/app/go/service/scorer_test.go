@@ -0,0 +1,102 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetScorer(t *testing.T) {
+	tests := []struct {
+		name    string
+		scorer  string
+		want    Scorer
+		wantErr error
+	}{
+		{
+			name:    "ok: mutual",
+			scorer:  ScorerMutual,
+			want:    MutualScorer{},
+			wantErr: nil,
+		},
+		{
+			name:    "ok: jaccard",
+			scorer:  ScorerJaccard,
+			want:    JaccardScorer{},
+			wantErr: nil,
+		},
+		{
+			name:    "ok: adamic adar",
+			scorer:  ScorerAdamicAdar,
+			want:    AdamicAdarScorer{},
+			wantErr: nil,
+		},
+		{
+			name:    "ng: unknown",
+			scorer:  "unknown",
+			want:    nil,
+			wantErr: ErrScorerNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetScorer(tt.scorer)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_Scorer_Score(t *testing.T) {
+	in := &ScoreInput{
+		UserFriendCount:      4,
+		CandidateFriendCount: 3,
+		MutualFriendCounts:   []int{2, 10},
+	}
+
+	tests := []struct {
+		name   string
+		scorer Scorer
+		in     *ScoreInput
+		want   float64
+	}{
+		{
+			name:   "ok: mutual",
+			scorer: MutualScorer{},
+			in:     in,
+			want:   2,
+		},
+		{
+			name:   "ok: jaccard",
+			scorer: JaccardScorer{},
+			in:     in,
+			want:   2.0 / 5.0,
+		},
+		{
+			name:   "ok: jaccard without friends",
+			scorer: JaccardScorer{},
+			in:     &ScoreInput{},
+			want:   0,
+		},
+		{
+			name:   "ok: adamic adar",
+			scorer: AdamicAdarScorer{},
+			in:     in,
+			want:   1/math.Log(2) + 1/math.Log(10),
+		},
+		{
+			name:   "ok: adamic adar with one way link",
+			scorer: AdamicAdarScorer{},
+			in:     &ScoreInput{MutualFriendCounts: []int{1}},
+			want:   1 / math.Log(2),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, tt.scorer.Score(tt.in), 1e-9)
+		})
+	}
+}
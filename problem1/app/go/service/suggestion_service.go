@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"problem1/model"
+	"problem1/repository"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+type SuggestionService interface {
+	GetSuggestions(ctx context.Context, userId int, scorer Scorer, limit, offset int) (*model.SuggestionList, error)
+}
+
+// mutualFriendsLimit is the number of mutual friends shown with a suggestion as the reason.
+const mutualFriendsLimit = 3
+
+type suggestionService struct {
+	flr         repository.FriendListRepository
+	blockPolicy BlockPolicy
+}
+
+func NewSuggestionService(flr repository.FriendListRepository, blockPolicy BlockPolicy) SuggestionService {
+	return &suggestionService{
+		flr:         flr,
+		blockPolicy: blockPolicy,
+	}
+}
+
+// GetSuggestions ranks friends of friends who are neither friends nor blocked by scorer.
+// A scorer may weigh anything it is given, so every candidate is scored and the page is cut
+// out of the ranking.
+func (s *suggestionService) GetSuggestions(ctx context.Context, userId int, scorer Scorer, limit, offset int) (*model.SuggestionList, error) {
+	// the friends, the hidden users and the blocked friends linking them are left out by the query
+	mutualFriends, err := s.flr.GetMutualFriendsByUserId(ctx, userId, s.blockPolicy.hidesBlockedBy())
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		suggestions []*model.Suggestion
+		mutualIds   = make(map[*model.Suggestion][]int)
+		userIds     = []int{userId}
+	)
+	for _, mf := range mutualFriends {
+		if len(suggestions) == 0 || suggestions[len(suggestions)-1].UserId != mf.Candidate.UserId {
+			suggestions = append(suggestions, &model.Suggestion{
+				UserId: mf.Candidate.UserId,
+				Name:   mf.Candidate.Name,
+			})
+			userIds = append(userIds, mf.Candidate.UserId)
+		}
+		suggestion := suggestions[len(suggestions)-1]
+
+		suggestion.MutualFriendCount++
+		if len(suggestion.MutualFriends) < mutualFriendsLimit {
+			suggestion.MutualFriends = append(suggestion.MutualFriends, mf.Friend)
+		}
+		mutualIds[suggestion] = append(mutualIds[suggestion], mf.Friend.UserId)
+		userIds = append(userIds, mf.Friend.UserId)
+	}
+	if len(suggestions) == 0 {
+		return &model.SuggestionList{Suggestions: nil, Paging: model.NewPaging(0, limit, offset)}, nil
+	}
+
+	friendCounts, err := s.flr.GetFriendCountByUserIds(ctx, userIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, suggestion := range suggestions {
+		in := &ScoreInput{
+			UserFriendCount:      friendCounts[userId],
+			CandidateFriendCount: friendCounts[suggestion.UserId],
+		}
+		for _, id := range mutualIds[suggestion] {
+			in.MutualFriendCounts = append(in.MutualFriendCounts, friendCounts[id])
+		}
+		suggestion.Score = scorer.Score(in)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+
+		return suggestions[i].MutualFriendCount > suggestions[j].MutualFriendCount
+	})
+
+	suggestionList := &model.SuggestionList{}
+	suggestionList.Suggestions, suggestionList.Paging = paginate(suggestions, limit, offset)
+
+	return suggestionList, nil
+}
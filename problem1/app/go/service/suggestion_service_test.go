@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/mock/mock_repository"
+	"problem1/model"
+	"problem1/pkg/testutil"
+)
+
+type suggestionServiceTest struct {
+	flr *mock_repository.MockFriendListRepository
+	ss  SuggestionService
+	ctx context.Context
+}
+
+func newSuggestionServiceTest(t *testing.T) *suggestionServiceTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	flr := mock_repository.NewMockFriendListRepository(ctrl)
+
+	return &suggestionServiceTest{
+		flr: flr,
+		ss:  NewSuggestionService(flr, BlockPolicyOneWay),
+		ctx: context.Background(),
+	}
+}
+
+func Test_suggestionService_GetSuggestions(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	hoge := &model.Friend{UserId: 111111, Name: "hoge"}
+	fuga := &model.Friend{UserId: 222222, Name: "fuga"}
+	bar := &model.Friend{UserId: 333333, Name: "bar"}
+	piyo := &model.Friend{UserId: 444444, Name: "piyo"}
+
+	// piyo has one mutual friend and bar has two
+	mutualFriends := []*model.MutualFriend{
+		{Candidate: bar, Friend: hoge},
+		{Candidate: bar, Friend: fuga},
+		{Candidate: piyo, Friend: fuga},
+	}
+	countUserIds := []int{userId, 333333, 111111, 222222, 444444, 222222}
+	friendCounts := map[int]int{userId: 3, 111111: 2, 222222: 10, 333333: 30, 444444: 1}
+
+	tests := []struct {
+		name          string
+		expects       func(*suggestionServiceTest)
+		scorer        Scorer
+		limit, offset int
+		want          *model.SuggestionList
+		wantErr       bool
+	}{
+		{
+			name: "ok: mutual",
+			expects: func(st *suggestionServiceTest) {
+				st.flr.EXPECT().GetMutualFriendsByUserId(st.ctx, userId, false).Return(mutualFriends, nil)
+				st.flr.EXPECT().GetFriendCountByUserIds(st.ctx, countUserIds).Return(friendCounts, nil)
+			},
+			scorer: MutualScorer{},
+			limit:  20,
+			want: &model.SuggestionList{
+				Suggestions: []*model.Suggestion{
+					{UserId: 333333, Name: "bar", Score: 2, MutualFriendCount: 2, MutualFriends: []*model.Friend{hoge, fuga}},
+					{UserId: 444444, Name: "piyo", Score: 1, MutualFriendCount: 1, MutualFriends: []*model.Friend{fuga}},
+				},
+				Paging: model.NewPaging(2, 20, 0),
+			},
+			wantErr: false,
+		},
+		{
+			name: "ok: the page is cut out of the ranking",
+			expects: func(st *suggestionServiceTest) {
+				st.flr.EXPECT().GetMutualFriendsByUserId(st.ctx, userId, false).Return(mutualFriends, nil)
+				st.flr.EXPECT().GetFriendCountByUserIds(st.ctx, countUserIds).Return(friendCounts, nil)
+			},
+			scorer: MutualScorer{},
+			limit:  1,
+			offset: 1,
+			want: &model.SuggestionList{
+				Suggestions: []*model.Suggestion{
+					{UserId: 444444, Name: "piyo", Score: 1, MutualFriendCount: 1, MutualFriends: []*model.Friend{fuga}},
+				},
+				Paging: &model.Paging{Total: 2, Page: 2, Limit: 1, HasNext: false},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ok: jaccard ranks the candidate with fewer friends first",
+			expects: func(st *suggestionServiceTest) {
+				st.flr.EXPECT().GetMutualFriendsByUserId(st.ctx, userId, false).Return(mutualFriends, nil)
+				st.flr.EXPECT().GetFriendCountByUserIds(st.ctx, countUserIds).Return(friendCounts, nil)
+			},
+			scorer: JaccardScorer{},
+			limit:  20,
+			want: &model.SuggestionList{
+				Suggestions: []*model.Suggestion{
+					{UserId: 444444, Name: "piyo", Score: 1.0 / 3.0, MutualFriendCount: 1, MutualFriends: []*model.Friend{fuga}},
+					{UserId: 333333, Name: "bar", Score: 2.0 / 31.0, MutualFriendCount: 2, MutualFriends: []*model.Friend{hoge, fuga}},
+				},
+				Paging: model.NewPaging(2, 20, 0),
+			},
+			wantErr: false,
+		},
+		{
+			name: "ok: no friend of friend",
+			expects: func(st *suggestionServiceTest) {
+				st.flr.EXPECT().GetMutualFriendsByUserId(st.ctx, userId, false).Return(nil, nil)
+			},
+			scorer:  MutualScorer{},
+			limit:   20,
+			want:    &model.SuggestionList{Suggestions: nil, Paging: model.NewPaging(0, 20, 0)},
+			wantErr: false,
+		},
+		{
+			name: "ng: error at GetMutualFriendsByUserId()",
+			expects: func(st *suggestionServiceTest) {
+				st.flr.EXPECT().GetMutualFriendsByUserId(st.ctx, userId, false).Return(nil, testutil.ErrTest)
+			},
+			scorer:  MutualScorer{},
+			limit:   20,
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "ng: error at GetFriendCountByUserIds()",
+			expects: func(st *suggestionServiceTest) {
+				st.flr.EXPECT().GetMutualFriendsByUserId(st.ctx, userId, false).Return(mutualFriends, nil)
+				st.flr.EXPECT().GetFriendCountByUserIds(st.ctx, countUserIds).Return(nil, testutil.ErrTest)
+			},
+			scorer:  MutualScorer{},
+			limit:   20,
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newSuggestionServiceTest(t)
+			tt.expects(st)
+
+			got, err := st.ss.GetSuggestions(st.ctx, userId, tt.scorer, tt.limit, tt.offset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetSuggestions() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_suggestionService_GetSuggestions_MutualFriendsLimit(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	candidate := &model.Friend{UserId: 999999, Name: "candidate"}
+
+	var mutualFriends []*model.MutualFriend
+	for i := 1; i <= mutualFriendsLimit+1; i++ {
+		mutualFriends = append(mutualFriends, &model.MutualFriend{Candidate: candidate, Friend: &model.Friend{UserId: i}})
+	}
+
+	st := newSuggestionServiceTest(t)
+	st.flr.EXPECT().GetMutualFriendsByUserId(st.ctx, userId, false).Return(mutualFriends, nil)
+	st.flr.EXPECT().GetFriendCountByUserIds(st.ctx, gomock.Any()).Return(map[int]int{}, nil)
+
+	got, err := st.ss.GetSuggestions(st.ctx, userId, MutualScorer{}, 20, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, got.Suggestions, 1) {
+		assert.Equal(t, mutualFriendsLimit+1, got.Suggestions[0].MutualFriendCount)
+		assert.Len(t, got.Suggestions[0].MutualFriends, mutualFriendsLimit)
+	}
+}
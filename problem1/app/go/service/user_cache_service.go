@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+
+	"problem1/model"
+	"problem1/repository"
+)
+
+// cachedUserService drops the cached lists once a user written through it is committed. The
+// name of a user is in the lists of the users up to two links away, and a deleted user leaves
+// all of them, so every list is dropped rather than looking for them.
+type cachedUserService struct {
+	UserService
+	fc *FriendListCache
+}
+
+func NewCachedUserService(us UserService, fc *FriendListCache) UserService {
+	return &cachedUserService{
+		UserService: us,
+		fc:          fc,
+	}
+}
+
+func (s *cachedUserService) UpdateUserName(ctx context.Context, userId int, name string) (*model.User, error) {
+	user, err := s.UserService.UpdateUserName(ctx, userId, name)
+	if err != nil {
+		return nil, err
+	}
+	repository.AfterCommit(ctx, s.fc.purge)
+
+	return user, nil
+}
+
+func (s *cachedUserService) DeleteUser(ctx context.Context, userId int) error {
+	if err := s.UserService.DeleteUser(ctx, userId); err != nil {
+		return err
+	}
+	repository.AfterCommit(ctx, s.fc.purge)
+
+	return nil
+}
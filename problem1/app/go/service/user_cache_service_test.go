@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"problem1/model"
+	"problem1/pkg/cache"
+	"problem1/pkg/testutil"
+	"problem1/repository"
+)
+
+func Test_cachedUserService_purgesOnWrite(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	key := FriendListCacheKey{OfFriends: true, UserId: 111111, Sort: model.FriendListSortName}
+
+	tests := []struct {
+		name       string
+		write      func(st *userServiceTest, us UserService) error
+		wantCached bool
+	}{
+		{
+			name: "ok: UpdateUserName",
+			write: func(st *userServiceTest, us UserService) error {
+				st.ur.EXPECT().UpdateUserName(st.ctx, userId, "hoge").Return(nil)
+				_, err := us.UpdateUserName(st.ctx, userId, "hoge")
+				return err
+			},
+			wantCached: false,
+		},
+		{
+			name: "ok: DeleteUser",
+			write: func(st *userServiceTest, us UserService) error {
+				st.ur.EXPECT().GetUser(st.ctx, userId).Return(&model.User{UserId: userId, Name: "hoge"}, nil)
+				st.flr.EXPECT().DeleteUserLinksOfUser(st.ctx, userId).Return(nil)
+				st.frr.EXPECT().CancelPendingFriendRequestsOfUser(st.ctx, userId).Return(nil)
+				st.ur.EXPECT().DeleteUser(st.ctx, userId).Return(nil)
+				return us.DeleteUser(st.ctx, userId)
+			},
+			wantCached: false,
+		},
+		{
+			name: "ok: kept on error",
+			write: func(st *userServiceTest, us UserService) error {
+				st.ur.EXPECT().UpdateUserName(st.ctx, userId, "hoge").Return(repository.ErrUserNotFound)
+				_, err := us.UpdateUserName(st.ctx, userId, "hoge")
+				assert.ErrorIs(t, err, repository.ErrUserNotFound)
+				return nil
+			},
+			wantCached: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newUserServiceTest(t)
+			c := cache.NewLRU[FriendListCacheKey, *model.FriendList](100, time.Minute)
+			c.Add(key, newFriendList())
+			us := NewCachedUserService(st.us, NewFriendListCache(c, time.Minute))
+
+			if err := tt.write(st, us); err != nil {
+				t.Fatal(err)
+			}
+
+			_, ok := c.Get(key)
+			assert.Equal(t, tt.wantCached, ok)
+		})
+	}
+}
+
+func Test_cachedUserService_purgesAfterCommit(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	key := FriendListCacheKey{UserId: 111111, Sort: model.FriendListSortUserId}
+	st := newUserServiceTest(t)
+	db, mock := testutil.NewSQLMock(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	c := cache.NewLRU[FriendListCacheKey, *model.FriendList](100, time.Minute)
+	c.Add(key, newFriendList())
+	us := NewCachedUserService(st.us, NewFriendListCache(c, time.Minute))
+
+	err := repository.NewTransaction(db).DoInTx(st.ctx, func(ctx context.Context) error {
+		st.ur.EXPECT().UpdateUserName(ctx, userId, "hoge").Return(nil)
+		if _, err := us.UpdateUserName(ctx, userId, "hoge"); err != nil {
+			return err
+		}
+
+		_, ok := c.Get(key)
+		assert.True(t, ok, "not purged before the commit")
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, ok := c.Get(key)
+	assert.False(t, ok)
+}
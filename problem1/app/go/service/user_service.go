@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+
+	"problem1/model"
+	"problem1/repository"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+type UserService interface {
+	CreateUser(ctx context.Context, ufr *model.UserForRequest) (*model.User, error)
+	GetUser(ctx context.Context, userId int) (*model.User, error)
+	UpdateUserName(ctx context.Context, userId int, name string) (*model.User, error)
+	DeleteUser(ctx context.Context, userId int) error
+	SearchUsers(ctx context.Context, searcherId int, query string, limit, offset int) (*model.UserList, error)
+}
+
+type userService struct {
+	ur  repository.UserRepository
+	flr repository.FriendListRepository
+	frr repository.FriendRequestRepository
+}
+
+func NewUserService(ur repository.UserRepository, flr repository.FriendListRepository, frr repository.FriendRequestRepository) UserService {
+	return &userService{
+		ur:  ur,
+		flr: flr,
+		frr: frr,
+	}
+}
+
+func (s *userService) CreateUser(ctx context.Context, ufr *model.UserForRequest) (*model.User, error) {
+	user := &model.User{UserId: ufr.UserId, Name: ufr.Name}
+	if err := s.ur.InsertUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *userService) GetUser(ctx context.Context, userId int) (*model.User, error) {
+	return s.ur.GetUser(ctx, userId)
+}
+
+func (s *userService) UpdateUserName(ctx context.Context, userId int, name string) (*model.User, error) {
+	if err := s.ur.UpdateUserName(ctx, userId, name); err != nil {
+		return nil, err
+	}
+
+	return &model.User{UserId: userId, Name: name}, nil
+}
+
+// DeleteUser deletes the user with the friend links and the blocks from and to the user,
+// and cancels the pending friend requests of the user. The answered requests are kept.
+func (s *userService) DeleteUser(ctx context.Context, userId int) error {
+	if _, err := s.ur.GetUser(ctx, userId); err != nil {
+		return err
+	}
+	if err := s.flr.DeleteUserLinksOfUser(ctx, userId); err != nil {
+		return err
+	}
+	if err := s.frr.CancelPendingFriendRequestsOfUser(ctx, userId); err != nil {
+		return err
+	}
+
+	return s.ur.DeleteUser(ctx, userId)
+}
+
+// SearchUsers returns the page of the users whose name contains query, hiding the searcher
+// and the users blocked by or blocking the searcher whatever the block policy.
+func (s *userService) SearchUsers(ctx context.Context, searcherId int, query string, limit, offset int) (*model.UserList, error) {
+	total, err := s.ur.CountSearchUsers(ctx, searcherId, query)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.ur.SearchUsers(ctx, searcherId, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.UserList{Users: users, Paging: model.NewPaging(total, limit, offset)}, nil
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/mock/mock_repository"
+	"problem1/model"
+	"problem1/pkg/testutil"
+	"problem1/repository"
+)
+
+type userServiceTest struct {
+	ur  *mock_repository.MockUserRepository
+	flr *mock_repository.MockFriendListRepository
+	frr *mock_repository.MockFriendRequestRepository
+	us  UserService
+	ctx context.Context
+}
+
+func newUserServiceTest(t *testing.T) *userServiceTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	ur := mock_repository.NewMockUserRepository(ctrl)
+	flr := mock_repository.NewMockFriendListRepository(ctrl)
+	frr := mock_repository.NewMockFriendRequestRepository(ctrl)
+
+	return &userServiceTest{
+		ur:  ur,
+		flr: flr,
+		frr: frr,
+		us:  NewUserService(ur, flr, frr),
+		ctx: context.Background(),
+	}
+}
+
+func Test_userService_CreateUser(t *testing.T) {
+	user := &model.User{UserId: testutil.UserIDForDebug, Name: "藤井 太郎"}
+
+	tests := []struct {
+		name    string
+		expects func(*userServiceTest)
+		want    *model.User
+		wantErr error
+	}{
+		{
+			name: "ok",
+			expects: func(st *userServiceTest) {
+				st.ur.EXPECT().InsertUser(st.ctx, user).Return(nil)
+			},
+			want:    user,
+			wantErr: nil,
+		},
+		{
+			name: "ng: duplicated",
+			expects: func(st *userServiceTest) {
+				st.ur.EXPECT().InsertUser(st.ctx, user).Return(repository.ErrUserDuplicated)
+			},
+			want:    nil,
+			wantErr: repository.ErrUserDuplicated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newUserServiceTest(t)
+			tt.expects(st)
+
+			got, err := st.us.CreateUser(st.ctx, &model.UserForRequest{UserId: user.UserId, Name: user.Name})
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_userService_UpdateUserName(t *testing.T) {
+	userId := testutil.UserIDForDebug
+
+	tests := []struct {
+		name    string
+		expects func(*userServiceTest)
+		want    *model.User
+		wantErr error
+	}{
+		{
+			name: "ok",
+			expects: func(st *userServiceTest) {
+				st.ur.EXPECT().UpdateUserName(st.ctx, userId, "hoge").Return(nil)
+			},
+			want:    &model.User{UserId: userId, Name: "hoge"},
+			wantErr: nil,
+		},
+		{
+			name: "ng: user not exist",
+			expects: func(st *userServiceTest) {
+				st.ur.EXPECT().UpdateUserName(st.ctx, userId, "hoge").Return(repository.ErrUserNotFound)
+			},
+			want:    nil,
+			wantErr: repository.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newUserServiceTest(t)
+			tt.expects(st)
+
+			got, err := st.us.UpdateUserName(st.ctx, userId, "hoge")
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_userService_DeleteUser(t *testing.T) {
+	userId := testutil.UserIDForDebug
+
+	tests := []struct {
+		name    string
+		expects func(*userServiceTest)
+		wantErr error
+	}{
+		{
+			name: "ok",
+			expects: func(st *userServiceTest) {
+				gomock.InOrder(
+					st.ur.EXPECT().GetUser(st.ctx, userId).Return(&model.User{UserId: userId, Name: "hoge"}, nil),
+					st.flr.EXPECT().DeleteUserLinksOfUser(st.ctx, userId).Return(nil),
+					st.frr.EXPECT().CancelPendingFriendRequestsOfUser(st.ctx, userId).Return(nil),
+					st.ur.EXPECT().DeleteUser(st.ctx, userId).Return(nil),
+				)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "ng: user not exist",
+			expects: func(st *userServiceTest) {
+				st.ur.EXPECT().GetUser(st.ctx, userId).Return(nil, repository.ErrUserNotFound)
+			},
+			wantErr: repository.ErrUserNotFound,
+		},
+		{
+			name: "ng: error at DeleteUserLinksOfUser()",
+			expects: func(st *userServiceTest) {
+				st.ur.EXPECT().GetUser(st.ctx, userId).Return(&model.User{UserId: userId, Name: "hoge"}, nil)
+				st.flr.EXPECT().DeleteUserLinksOfUser(st.ctx, userId).Return(testutil.ErrTest)
+			},
+			wantErr: testutil.ErrTest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newUserServiceTest(t)
+			tt.expects(st)
+
+			err := st.us.DeleteUser(st.ctx, userId)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_userService_SearchUsers(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	users := []*model.User{{UserId: 111111, Name: "スズキ"}}
+
+	tests := []struct {
+		name    string
+		expects func(*userServiceTest)
+		want    *model.UserList
+		wantErr error
+	}{
+		{
+			name: "ok",
+			expects: func(st *userServiceTest) {
+				st.ur.EXPECT().CountSearchUsers(st.ctx, userId, "すずき").Return(3, nil)
+				st.ur.EXPECT().SearchUsers(st.ctx, userId, "すずき", 1, 1).Return(users, nil)
+			},
+			want:    &model.UserList{Users: users, Paging: &model.Paging{Total: 3, Page: 2, Limit: 1, HasNext: true}},
+			wantErr: nil,
+		},
+		{
+			name: "ng: error at CountSearchUsers()",
+			expects: func(st *userServiceTest) {
+				st.ur.EXPECT().CountSearchUsers(st.ctx, userId, "すずき").Return(0, testutil.ErrTest)
+			},
+			want:    nil,
+			wantErr: testutil.ErrTest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newUserServiceTest(t)
+			tt.expects(st)
+
+			got, err := st.us.SearchUsers(st.ctx, userId, "すずき", 1, 1)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
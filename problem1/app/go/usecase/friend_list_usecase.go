@@ -1,39 +1,44 @@
 package usecase
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"net/http"
 
-	"github.com/labstack/echo/v4"
-
 	"problem1/model"
 	"problem1/pkg/httputil"
+	"problem1/repository"
 	"problem1/service"
 )
 
 //go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
 
 type FriendListUseCase interface {
-	PostUserLink(ulfr *model.UserLinkForRequest) error
-	GetFriendListByUserId(c echo.Context) (*model.FriendList, error)
-	GetFriendListOfFriendsByUserId(c echo.Context) (*model.FriendList, error)
-	GetFriendListOfFriendsByUserIdWithPaging(c echo.Context) (*model.FriendList, error)
+	PostUserLink(ctx context.Context, ulfr *model.UserLinkForRequest) error
+	PostUserLinks(ctx context.Context, ulfrs []*model.UserLinkForRequest, mode model.UserLinksMode) ([]model.UserLinkStatus, error)
+	DeleteUserLink(ctx context.Context, ulfr *model.UserLinkForRequest) error
+	GetFriendListByUserId(ctx context.Context, userId int, sort model.FriendListSort, limit, offset int) (*model.FriendList, error)
+	GetFriendListOfFriendsByUserId(ctx context.Context, userId int, sort model.FriendListSort, limit, offset int) (*model.FriendList, error)
+	GetFriendListOfFriendsByUserIdWithPaging(ctx context.Context, userId int, sort model.FriendListSort, limit, offset int) (*model.FriendList, error)
+	GetFriendListOfFriendsByUserIdWithCursor(ctx context.Context, userId, lastUserId, limit int) (*model.FriendList, error)
+	GetBlockListByUserId(ctx context.Context, userId, limit, offset int) (*model.BlockList, error)
+	GetNeighbourhood(ctx context.Context, userId, minDepth, maxDepth, limit, offset int) (*model.NeighbourList, error)
 }
 
 type friendListUseCase struct {
-	db  *sql.DB
+	tx  repository.Transaction
 	fls service.FriendListService
 }
 
-func NewFriendListUseCase(db *sql.DB, fls service.FriendListService) FriendListUseCase {
+func NewFriendListUseCase(tx repository.Transaction, fls service.FriendListService) FriendListUseCase {
 	return &friendListUseCase{
-		db:  db,
+		tx:  tx,
 		fls: fls,
 	}
 }
 
-func (u *friendListUseCase) checkUserExist(userId int) error {
-	exist, err := u.fls.CheckUserExist(userId)
+func ensureUserExist(ctx context.Context, fls service.FriendListService, userId int) error {
+	exist, err := fls.CheckUserExist(ctx, userId)
 	if err != nil {
 		return err
 	}
@@ -41,40 +46,156 @@ func (u *friendListUseCase) checkUserExist(userId int) error {
 		return nil
 	}
 
-	return httputil.NewHTTPError(err, http.StatusBadRequest, "user not exist")
+	return httputil.NewHTTPError(err, http.StatusBadRequest, httputil.ErrorCodeUserNotFound, "user not exist")
 }
 
-func (u *friendListUseCase) PostUserLink(ulfr *model.UserLinkForRequest) error {
-	if err := u.checkUserExist(ulfr.User1Id); err != nil {
-		return err
+func (u *friendListUseCase) checkUserExist(ctx context.Context, userId int) error {
+	return ensureUserExist(ctx, u.fls, userId)
+}
+
+func (u *friendListUseCase) PostUserLink(ctx context.Context, ulfr *model.UserLinkForRequest) error {
+	return u.tx.DoInTx(ctx, func(ctx context.Context) error {
+		if err := u.checkUserExist(ctx, ulfr.User1Id); err != nil {
+			return err
+		}
+		if err := u.checkUserExist(ctx, ulfr.User2Id); err != nil {
+			return err
+		}
+
+		return u.fls.InsertUserLink(ctx, ulfr)
+	})
+}
+
+// PostUserLinks inserts ulfrs in one transaction and returns the status of each of them.
+// The users of all the links are checked with one query. In all-or-nothing mode none of the
+// links is inserted when a user does not exist.
+func (u *friendListUseCase) PostUserLinks(ctx context.Context, ulfrs []*model.UserLinkForRequest, mode model.UserLinksMode) ([]model.UserLinkStatus, error) {
+	statuses := make([]model.UserLinkStatus, len(ulfrs))
+	err := u.tx.DoInTx(ctx, func(ctx context.Context) error {
+		userIds := make([]int, 0, len(ulfrs)*2)
+		for _, ulfr := range ulfrs {
+			userIds = append(userIds, ulfr.User1Id, ulfr.User2Id)
+		}
+		exist, err := u.fls.CheckUsersExist(ctx, userIds)
+		if err != nil {
+			return err
+		}
+
+		var (
+			inserted []*model.UserLinkForRequest
+			indexes  []int
+		)
+		for i, ulfr := range ulfrs {
+			if !exist[ulfr.User1Id] || !exist[ulfr.User2Id] {
+				statuses[i] = model.UserLinkStatusUserNotExist
+				continue
+			}
+
+			inserted = append(inserted, ulfr)
+			indexes = append(indexes, i)
+		}
+		if len(inserted) < len(ulfrs) && mode == model.UserLinksModeAllOrNothing {
+			for _, i := range indexes {
+				statuses[i] = model.UserLinkStatusSkipped
+			}
+
+			return nil
+		}
+
+		insertedStatuses, err := u.fls.InsertUserLinks(ctx, inserted)
+		if err != nil {
+			return err
+		}
+		for j, i := range indexes {
+			statuses[i] = insertedStatuses[j]
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	if err := u.checkUserExist(ulfr.User2Id); err != nil {
-		return err
+
+	return statuses, nil
+}
+
+func (u *friendListUseCase) DeleteUserLink(ctx context.Context, ulfr *model.UserLinkForRequest) error {
+	return u.tx.DoInTx(ctx, func(ctx context.Context) error {
+		if err := u.checkUserExist(ctx, ulfr.User1Id); err != nil {
+			return err
+		}
+		if err := u.checkUserExist(ctx, ulfr.User2Id); err != nil {
+			return err
+		}
+
+		if err := u.fls.DeleteUserLink(ctx, ulfr); err != nil {
+			if errors.Is(err, repository.ErrUserLinkNotFound) {
+				return httputil.NewHTTPError(err, http.StatusNotFound, httputil.ErrorCodeUserLinkNotFound, "")
+			}
+
+			return err
+		}
+
+		return nil
+	})
+}
+
+// GetFriendListByUserId returns the whole list without paging when limit is 0.
+func (u *friendListUseCase) GetFriendListByUserId(ctx context.Context, userId int, sort model.FriendListSort, limit, offset int) (*model.FriendList, error) {
+	if err := u.checkUserExist(ctx, userId); err != nil {
+		return nil, err
+	}
+	if limit == 0 {
+		return u.fls.GetFriendListByUserId(ctx, userId, sort)
 	}
 
-	return u.fls.InsertUserLink(ulfr)
+	return u.fls.GetFriendListByUserIdWithPaging(ctx, userId, sort, limit, offset)
 }
 
-func (u *friendListUseCase) GetFriendListByUserId(c echo.Context) (*model.FriendList, error) {
-	if err := u.checkUserExist(c.Get("userId").(int)); err != nil {
+// GetFriendListOfFriendsByUserId returns the whole list without paging when limit is 0.
+func (u *friendListUseCase) GetFriendListOfFriendsByUserId(ctx context.Context, userId int, sort model.FriendListSort, limit, offset int) (*model.FriendList, error) {
+	if err := u.checkUserExist(ctx, userId); err != nil {
 		return nil, err
 	}
+	if limit == 0 {
+		return u.fls.GetFriendListOfFriendsByUserId(ctx, userId, sort)
+	}
 
-	return u.fls.GetFriendListByUserId(c)
+	return u.fls.GetFriendListOfFriendsByUserIdWithPaging(ctx, userId, sort, limit, offset)
 }
 
-func (u *friendListUseCase) GetFriendListOfFriendsByUserId(c echo.Context) (*model.FriendList, error) {
-	if err := u.checkUserExist(c.Get("userId").(int)); err != nil {
+func (u *friendListUseCase) GetFriendListOfFriendsByUserIdWithPaging(ctx context.Context, userId int, sort model.FriendListSort, limit, offset int) (*model.FriendList, error) {
+	if err := u.checkUserExist(ctx, userId); err != nil {
 		return nil, err
 	}
 
-	return u.fls.GetFriendListOfFriendsByUserId(c)
+	return u.fls.GetFriendListOfFriendsByUserIdWithPaging(ctx, userId, sort, limit, offset)
+}
+
+func (u *friendListUseCase) GetFriendListOfFriendsByUserIdWithCursor(ctx context.Context, userId, lastUserId, limit int) (*model.FriendList, error) {
+	if err := u.checkUserExist(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	return u.fls.GetFriendListOfFriendsByUserIdWithCursor(ctx, userId, lastUserId, limit)
+}
+
+// GetBlockListByUserId returns the whole list without paging when limit is 0.
+func (u *friendListUseCase) GetBlockListByUserId(ctx context.Context, userId, limit, offset int) (*model.BlockList, error) {
+	if err := u.checkUserExist(ctx, userId); err != nil {
+		return nil, err
+	}
+	if limit == 0 {
+		return u.fls.GetBlockListByUserId(ctx, userId)
+	}
+
+	return u.fls.GetBlockListByUserIdWithPaging(ctx, userId, limit, offset)
 }
 
-func (u *friendListUseCase) GetFriendListOfFriendsByUserIdWithPaging(c echo.Context) (*model.FriendList, error) {
-	if err := u.checkUserExist(c.Get("userId").(int)); err != nil {
+func (u *friendListUseCase) GetNeighbourhood(ctx context.Context, userId, minDepth, maxDepth, limit, offset int) (*model.NeighbourList, error) {
+	if err := u.checkUserExist(ctx, userId); err != nil {
 		return nil, err
 	}
 
-	return u.fls.GetFriendListOfFriendsByUserIdWithPaging(c)
+	return u.fls.GetNeighbourhood(ctx, userId, minDepth, maxDepth, limit, offset)
 }
@@ -1,19 +1,22 @@
 package usecase
 
 import (
+	"context"
 	"database/sql"
 	"net/http"
+	"sync"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/golang/mock/gomock"
-	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 
 	"problem1/mock/mock_service"
 	"problem1/model"
 	"problem1/pkg/httputil"
 	"problem1/pkg/testutil"
+	"problem1/repository"
+	"problem1/service"
 )
 
 type friendListUseCaseTest struct {
@@ -22,7 +25,7 @@ type friendListUseCaseTest struct {
 	fls       *mock_service.MockFriendListService
 	flu       FriendListUseCase
 	fluStruct *friendListUseCase
-	c         echo.Context
+	ctx       context.Context
 }
 
 func newFriendListUseCaseTest(t *testing.T) *friendListUseCaseTest {
@@ -31,7 +34,7 @@ func newFriendListUseCaseTest(t *testing.T) *friendListUseCaseTest {
 	ctrl := gomock.NewController(t)
 	db, mock := testutil.NewSQLMock(t)
 	fls := mock_service.NewMockFriendListService(ctrl)
-	flu := NewFriendListUseCase(db, fls)
+	flu := NewFriendListUseCase(repository.NewTransaction(db), fls)
 
 	return &friendListUseCaseTest{
 		db:        db,
@@ -39,7 +42,7 @@ func newFriendListUseCaseTest(t *testing.T) *friendListUseCaseTest {
 		fls:       fls,
 		flu:       flu,
 		fluStruct: flu.(*friendListUseCase),
-		c:         testutil.SetUpContextWithDefault(),
+		ctx:       context.Background(),
 	}
 }
 
@@ -68,21 +71,21 @@ func Test_friendListUseCase_checkUserExist(t *testing.T) {
 		{
 			name: "ok",
 			expects: func(ut *friendListUseCaseTest) {
-				ut.fls.EXPECT().CheckUserExist(testutil.UserIDForDebug).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
 			},
 			wantErr: false,
 		},
 		{
-			name: "ng: error at CheckUserExist()",
+			name: "ng: error at CheckUserExist(ut.ctx, )",
 			expects: func(ut *friendListUseCaseTest) {
-				ut.fls.EXPECT().CheckUserExist(testutil.UserIDForDebug).Return(false, testutil.ErrTest)
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(false, testutil.ErrTest)
 			},
 			wantErr: true,
 		},
 		{
 			name: "ng: user not exist",
 			expects: func(ut *friendListUseCaseTest) {
-				ut.fls.EXPECT().CheckUserExist(testutil.UserIDForDebug).Return(false, nil)
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(false, nil)
 			},
 			wantErr:     true,
 			wantErrCode: http.StatusBadRequest,
@@ -94,7 +97,7 @@ func Test_friendListUseCase_checkUserExist(t *testing.T) {
 			ut := newFriendListUseCaseTest(t)
 			tt.expects(ut)
 
-			err := ut.fluStruct.checkUserExist(testutil.UserIDForDebug)
+			err := ut.fluStruct.checkUserExist(ut.ctx, testutil.UserIDForDebug)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("checkUserExist() error = %v, wantErr = %v", err, tt.wantErr)
 			}
@@ -121,9 +124,11 @@ func Test_friendListUseCase_PostUserLink(t *testing.T) {
 		{
 			name: "ok",
 			expects: func(ut *friendListUseCaseTest) {
-				ut.fls.EXPECT().CheckUserExist(req.User1Id).Return(true, nil)
-				ut.fls.EXPECT().CheckUserExist(req.User2Id).Return(true, nil)
-				ut.fls.EXPECT().InsertUserLink(req).Return(nil)
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User1Id).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User2Id).Return(true, nil)
+				ut.fls.EXPECT().InsertUserLink(gomock.Any(), req).Return(nil)
+				ut.mock.ExpectCommit()
 			},
 			want:    nil,
 			wantErr: false,
@@ -131,7 +136,9 @@ func Test_friendListUseCase_PostUserLink(t *testing.T) {
 		{
 			name: "ng: user1 not exist",
 			expects: func(ut *friendListUseCaseTest) {
-				ut.fls.EXPECT().CheckUserExist(req.User1Id).Return(false, nil)
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User1Id).Return(false, nil)
+				ut.mock.ExpectRollback()
 			},
 			want:    nil,
 			wantErr: true,
@@ -139,8 +146,10 @@ func Test_friendListUseCase_PostUserLink(t *testing.T) {
 		{
 			name: "ng: user2 not exist",
 			expects: func(ut *friendListUseCaseTest) {
-				ut.fls.EXPECT().CheckUserExist(req.User1Id).Return(true, nil)
-				ut.fls.EXPECT().CheckUserExist(req.User2Id).Return(false, nil)
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User1Id).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User2Id).Return(false, nil)
+				ut.mock.ExpectRollback()
 			},
 			want:    nil,
 			wantErr: true,
@@ -148,7 +157,9 @@ func Test_friendListUseCase_PostUserLink(t *testing.T) {
 		{
 			name: "ng: error at check user1 exist",
 			expects: func(ut *friendListUseCaseTest) {
-				ut.fls.EXPECT().CheckUserExist(req.User1Id).Return(false, testutil.ErrTest)
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User1Id).Return(false, testutil.ErrTest)
+				ut.mock.ExpectRollback()
 			},
 			want:    nil,
 			wantErr: true,
@@ -156,8 +167,10 @@ func Test_friendListUseCase_PostUserLink(t *testing.T) {
 		{
 			name: "ng: error at check user2 exist",
 			expects: func(ut *friendListUseCaseTest) {
-				ut.fls.EXPECT().CheckUserExist(req.User1Id).Return(true, nil)
-				ut.fls.EXPECT().CheckUserExist(req.User2Id).Return(false, testutil.ErrTest)
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User1Id).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User2Id).Return(false, testutil.ErrTest)
+				ut.mock.ExpectRollback()
 			},
 			want:    nil,
 			wantErr: true,
@@ -165,9 +178,11 @@ func Test_friendListUseCase_PostUserLink(t *testing.T) {
 		{
 			name: "ng: error at InsertUserLink",
 			expects: func(ut *friendListUseCaseTest) {
-				ut.fls.EXPECT().CheckUserExist(req.User1Id).Return(true, nil)
-				ut.fls.EXPECT().CheckUserExist(req.User2Id).Return(true, nil)
-				ut.fls.EXPECT().InsertUserLink(req).Return(testutil.ErrTest)
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User1Id).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User2Id).Return(true, nil)
+				ut.fls.EXPECT().InsertUserLink(gomock.Any(), req).Return(testutil.ErrTest)
+				ut.mock.ExpectRollback()
 			},
 			want:    nil,
 			wantErr: true,
@@ -179,44 +194,257 @@ func Test_friendListUseCase_PostUserLink(t *testing.T) {
 			ut := newFriendListUseCaseTest(t)
 			tt.expects(ut)
 
-			if err := ut.flu.PostUserLink(req); (err != nil) != tt.wantErr {
+			if err := ut.flu.PostUserLink(ut.ctx, req); (err != nil) != tt.wantErr {
 				t.Fatalf("PostUserLink() error = %v, wantErr = %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
-func Test_friendListUseCase_GetFriendListByUserId(t *testing.T) {
-	want := newFriendList()
+func Test_friendListUseCase_PostUserLinks(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	reqs := []*model.UserLinkForRequest{
+		{User1Id: userId, User2Id: 111111, Table: "friend_link"},
+		{User1Id: userId, User2Id: 999999, Table: "friend_link"},
+		{User1Id: userId, User2Id: 222222, Table: "block_list"},
+	}
+	userIds := []int{userId, 111111, userId, 999999, userId, 222222}
+	exist := map[int]bool{userId: true, 111111: true, 222222: true}
 
 	tests := []struct {
 		name    string
 		expects func(*friendListUseCaseTest)
-		want    *model.FriendList
+		reqs    []*model.UserLinkForRequest
+		mode    model.UserLinksMode
+		want    []model.UserLinkStatus
 		wantErr bool
+	}{
+		{
+			name: "ok: all-or-nothing",
+			expects: func(ut *friendListUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUsersExist(gomock.Any(), []int{userId, 111111, userId, 222222}).Return(exist, nil)
+				ut.fls.EXPECT().InsertUserLinks(gomock.Any(), []*model.UserLinkForRequest{reqs[0], reqs[2]}).Return([]model.UserLinkStatus{model.UserLinkStatusCreated, model.UserLinkStatusExists}, nil)
+				ut.mock.ExpectCommit()
+			},
+			reqs:    []*model.UserLinkForRequest{reqs[0], reqs[2]},
+			mode:    model.UserLinksModeAllOrNothing,
+			want:    []model.UserLinkStatus{model.UserLinkStatusCreated, model.UserLinkStatusExists},
+			wantErr: false,
+		},
+		{
+			name: "ok: all-or-nothing skips all when a user not exist",
+			expects: func(ut *friendListUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUsersExist(gomock.Any(), userIds).Return(exist, nil)
+				ut.mock.ExpectCommit()
+			},
+			reqs:    reqs,
+			mode:    model.UserLinksModeAllOrNothing,
+			want:    []model.UserLinkStatus{model.UserLinkStatusSkipped, model.UserLinkStatusUserNotExist, model.UserLinkStatusSkipped},
+			wantErr: false,
+		},
+		{
+			name: "ok: best-effort inserts the others",
+			expects: func(ut *friendListUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUsersExist(gomock.Any(), userIds).Return(exist, nil)
+				ut.fls.EXPECT().InsertUserLinks(gomock.Any(), []*model.UserLinkForRequest{reqs[0], reqs[2]}).Return([]model.UserLinkStatus{model.UserLinkStatusCreated, model.UserLinkStatusCreated}, nil)
+				ut.mock.ExpectCommit()
+			},
+			reqs:    reqs,
+			mode:    model.UserLinksModeBestEffort,
+			want:    []model.UserLinkStatus{model.UserLinkStatusCreated, model.UserLinkStatusUserNotExist, model.UserLinkStatusCreated},
+			wantErr: false,
+		},
+		{
+			name: "ng: error at CheckUsersExist",
+			expects: func(ut *friendListUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUsersExist(gomock.Any(), userIds).Return(nil, testutil.ErrTest)
+				ut.mock.ExpectRollback()
+			},
+			reqs:    reqs,
+			mode:    model.UserLinksModeBestEffort,
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "ng: error at InsertUserLinks rolls all back",
+			expects: func(ut *friendListUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUsersExist(gomock.Any(), userIds).Return(exist, nil)
+				ut.fls.EXPECT().InsertUserLinks(gomock.Any(), []*model.UserLinkForRequest{reqs[0], reqs[2]}).Return(nil, testutil.ErrTest)
+				ut.mock.ExpectRollback()
+			},
+			reqs:    reqs,
+			mode:    model.UserLinksModeBestEffort,
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ut := newFriendListUseCaseTest(t)
+			tt.expects(ut)
+
+			got, err := ut.flu.PostUserLinks(ut.ctx, tt.reqs, tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PostUserLinks() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+			if err := ut.mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func Test_friendListUseCase_DeleteUserLink(t *testing.T) {
+	req := &model.UserLinkForRequest{
+		User1Id: testutil.UserIDForDebug,
+		User2Id: 111111,
+		Table:   "friend_link",
+	}
+	tests := []struct {
+		name        string
+		expects     func(*friendListUseCaseTest)
+		wantErr     bool
+		wantErrCode int
 	}{
 		{
 			name: "ok",
 			expects: func(ut *friendListUseCaseTest) {
-				ut.fls.EXPECT().CheckUserExist(testutil.UserIDForDebug).Return(true, nil)
-				ut.fls.EXPECT().GetFriendListByUserId(ut.c).Return(want, nil)
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User1Id).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User2Id).Return(true, nil)
+				ut.fls.EXPECT().DeleteUserLink(gomock.Any(), req).Return(nil)
+				ut.mock.ExpectCommit()
 			},
-			want:    want,
 			wantErr: false,
 		},
 		{
-			name: "ng: user not exist",
+			name: "ng: user1 not exist",
+			expects: func(ut *friendListUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User1Id).Return(false, nil)
+				ut.mock.ExpectRollback()
+			},
+			wantErr:     true,
+			wantErrCode: http.StatusBadRequest,
+		},
+		{
+			name: "ng: user2 not exist",
+			expects: func(ut *friendListUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User1Id).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User2Id).Return(false, nil)
+				ut.mock.ExpectRollback()
+			},
+			wantErr:     true,
+			wantErrCode: http.StatusBadRequest,
+		},
+		{
+			name: "ng: user link not exist",
 			expects: func(ut *friendListUseCaseTest) {
-				ut.fls.EXPECT().CheckUserExist(testutil.UserIDForDebug).Return(false, testutil.ErrTest)
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User1Id).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User2Id).Return(true, nil)
+				ut.fls.EXPECT().DeleteUserLink(gomock.Any(), req).Return(repository.ErrUserLinkNotFound)
+				ut.mock.ExpectRollback()
+			},
+			wantErr:     true,
+			wantErrCode: http.StatusNotFound,
+		},
+		{
+			name: "ng: error at DeleteUserLink()",
+			expects: func(ut *friendListUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User1Id).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.User2Id).Return(true, nil)
+				ut.fls.EXPECT().DeleteUserLink(gomock.Any(), req).Return(testutil.ErrTest)
+				ut.mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ut := newFriendListUseCaseTest(t)
+			tt.expects(ut)
+
+			err := ut.flu.DeleteUserLink(ut.ctx, req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeleteUserLink() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if err != nil && tt.wantErrCode != 0 {
+				if !httputil.As(err, tt.wantErrCode) {
+					t.Fatalf("DeleteUserLink() error = %v, wantErrCode= %v", err, tt.wantErrCode)
+				}
+			}
+		})
+	}
+}
+
+func Test_friendListUseCase_GetFriendListByUserId(t *testing.T) {
+	paged := newFriendList()
+
+	tests := []struct {
+		name          string
+		limit, offset int
+		expects       func(*friendListUseCaseTest)
+		want          *model.FriendList
+		wantErr       bool
+	}{
+		{
+			name:  "ok: whole list without limit",
+			limit: 0,
+			expects: func(ut *friendListUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.fls.EXPECT().GetFriendListByUserId(ut.ctx, testutil.UserIDForDebug, model.FriendListSortUserId).Return(paged, nil)
+			},
+			want:    paged,
+			wantErr: false,
+		},
+		{
+			name:   "ok: page",
+			limit:  1,
+			offset: 1,
+			expects: func(ut *friendListUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.fls.EXPECT().GetFriendListByUserIdWithPaging(ut.ctx, testutil.UserIDForDebug, model.FriendListSortUserId, 1, 1).Return(paged, nil)
+			},
+			want:    paged,
+			wantErr: false,
+		},
+		{
+			name:  "ng: user not exist",
+			limit: 1,
+			expects: func(ut *friendListUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(false, nil)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:  "ng: error at GetFriendListByUserId()",
+			limit: 0,
+			expects: func(ut *friendListUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.fls.EXPECT().GetFriendListByUserId(ut.ctx, testutil.UserIDForDebug, model.FriendListSortUserId).Return(nil, testutil.ErrTest)
 			},
 			want:    nil,
 			wantErr: true,
 		},
 		{
-			name: "ng: error at GetFriendListByUserId()",
+			name:   "ng: error at GetFriendListByUserIdWithPaging()",
+			limit:  1,
+			offset: 1,
 			expects: func(ut *friendListUseCaseTest) {
-				ut.fls.EXPECT().CheckUserExist(testutil.UserIDForDebug).Return(true, nil)
-				ut.fls.EXPECT().GetFriendListByUserId(ut.c).Return(nil, testutil.ErrTest)
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.fls.EXPECT().GetFriendListByUserIdWithPaging(ut.ctx, testutil.UserIDForDebug, model.FriendListSortUserId, 1, 1).Return(nil, testutil.ErrTest)
 			},
 			want:    nil,
 			wantErr: true,
@@ -228,7 +456,7 @@ func Test_friendListUseCase_GetFriendListByUserId(t *testing.T) {
 			ut := newFriendListUseCaseTest(t)
 			tt.expects(ut)
 
-			got, err := ut.flu.GetFriendListByUserId(ut.c)
+			got, err := ut.flu.GetFriendListByUserId(ut.ctx, testutil.UserIDForDebug, model.FriendListSortUserId, tt.limit, tt.offset)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("GetFriendListByUserId() error = %v, wantErr = %v", err, tt.wantErr)
 			}
@@ -238,6 +466,83 @@ func Test_friendListUseCase_GetFriendListByUserId(t *testing.T) {
 }
 
 func Test_friendListUseCase_GetFriendListOfFriendsByUserId(t *testing.T) {
+	paged := newFriendList()
+
+	tests := []struct {
+		name          string
+		limit, offset int
+		expects       func(*friendListUseCaseTest)
+		want          *model.FriendList
+		wantErr       bool
+	}{
+		{
+			name:  "ok: whole list without limit",
+			limit: 0,
+			expects: func(ut *friendListUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.fls.EXPECT().GetFriendListOfFriendsByUserId(ut.ctx, testutil.UserIDForDebug, model.FriendListSortUserId).Return(paged, nil)
+			},
+			want:    paged,
+			wantErr: false,
+		},
+		{
+			name:   "ok: page",
+			limit:  1,
+			offset: 1,
+			expects: func(ut *friendListUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.fls.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(ut.ctx, testutil.UserIDForDebug, model.FriendListSortUserId, 1, 1).Return(paged, nil)
+			},
+			want:    paged,
+			wantErr: false,
+		},
+		{
+			name:  "ng: user not exist",
+			limit: 1,
+			expects: func(ut *friendListUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(false, nil)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:  "ng: error at GetFriendListOfFriendsByUserId()",
+			limit: 0,
+			expects: func(ut *friendListUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.fls.EXPECT().GetFriendListOfFriendsByUserId(ut.ctx, testutil.UserIDForDebug, model.FriendListSortUserId).Return(nil, testutil.ErrTest)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:   "ng: error at GetFriendListOfFriendsByUserIdWithPaging()",
+			limit:  1,
+			offset: 1,
+			expects: func(ut *friendListUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.fls.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(ut.ctx, testutil.UserIDForDebug, model.FriendListSortUserId, 1, 1).Return(nil, testutil.ErrTest)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ut := newFriendListUseCaseTest(t)
+			tt.expects(ut)
+
+			got, err := ut.flu.GetFriendListOfFriendsByUserId(ut.ctx, testutil.UserIDForDebug, model.FriendListSortUserId, tt.limit, tt.offset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetFriendListOfFriendsByUserId() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListUseCase_GetFriendListOfFriendsByUserIdWithPaging(t *testing.T) {
 	want := newFriendList()
 
 	tests := []struct {
@@ -249,8 +554,8 @@ func Test_friendListUseCase_GetFriendListOfFriendsByUserId(t *testing.T) {
 		{
 			name: "ok",
 			expects: func(ut *friendListUseCaseTest) {
-				ut.fls.EXPECT().CheckUserExist(testutil.UserIDForDebug).Return(true, nil)
-				ut.fls.EXPECT().GetFriendListOfFriendsByUserId(ut.c).Return(want, nil)
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.fls.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(ut.ctx, testutil.UserIDForDebug, model.FriendListSortUserId, 20, 0).Return(want, nil)
 			},
 			want:    want,
 			wantErr: false,
@@ -258,7 +563,7 @@ func Test_friendListUseCase_GetFriendListOfFriendsByUserId(t *testing.T) {
 		{
 			name: "ng: user not exist",
 			expects: func(ut *friendListUseCaseTest) {
-				ut.fls.EXPECT().CheckUserExist(testutil.UserIDForDebug).Return(false, testutil.ErrTest)
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(false, testutil.ErrTest)
 			},
 			want:    nil,
 			wantErr: true,
@@ -266,8 +571,8 @@ func Test_friendListUseCase_GetFriendListOfFriendsByUserId(t *testing.T) {
 		{
 			name: "ng: error at GetFriendListOfFriendsByUserId()",
 			expects: func(ut *friendListUseCaseTest) {
-				ut.fls.EXPECT().CheckUserExist(testutil.UserIDForDebug).Return(true, nil)
-				ut.fls.EXPECT().GetFriendListOfFriendsByUserId(ut.c).Return(nil, testutil.ErrTest)
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.fls.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(ut.ctx, testutil.UserIDForDebug, model.FriendListSortUserId, 20, 0).Return(nil, testutil.ErrTest)
 			},
 			want:    nil,
 			wantErr: true,
@@ -279,16 +584,16 @@ func Test_friendListUseCase_GetFriendListOfFriendsByUserId(t *testing.T) {
 			ut := newFriendListUseCaseTest(t)
 			tt.expects(ut)
 
-			got, err := ut.flu.GetFriendListOfFriendsByUserId(ut.c)
+			got, err := ut.flu.GetFriendListOfFriendsByUserIdWithPaging(ut.ctx, testutil.UserIDForDebug, model.FriendListSortUserId, 20, 0)
 			if (err != nil) != tt.wantErr {
-				t.Fatalf("GetFriendListOfFriendsByUserId() error = %v, wantErr = %v", err, tt.wantErr)
+				t.Fatalf("GetFriendListOfFriendsByUserIdWithPaging() error = %v, wantErr = %v", err, tt.wantErr)
 			}
 			assert.Equal(t, tt.want, got)
 		})
 	}
 }
 
-func Test_friendListUseCase_GetFriendListOfFriendsByUserIdWithPaging(t *testing.T) {
+func Test_friendListUseCase_GetFriendListOfFriendsByUserIdWithCursor(t *testing.T) {
 	want := newFriendList()
 
 	tests := []struct {
@@ -300,8 +605,8 @@ func Test_friendListUseCase_GetFriendListOfFriendsByUserIdWithPaging(t *testing.
 		{
 			name: "ok",
 			expects: func(ut *friendListUseCaseTest) {
-				ut.fls.EXPECT().CheckUserExist(testutil.UserIDForDebug).Return(true, nil)
-				ut.fls.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(ut.c).Return(want, nil)
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.fls.EXPECT().GetFriendListOfFriendsByUserIdWithCursor(ut.ctx, testutil.UserIDForDebug, 111111, 20).Return(want, nil)
 			},
 			want:    want,
 			wantErr: false,
@@ -309,16 +614,84 @@ func Test_friendListUseCase_GetFriendListOfFriendsByUserIdWithPaging(t *testing.
 		{
 			name: "ng: user not exist",
 			expects: func(ut *friendListUseCaseTest) {
-				ut.fls.EXPECT().CheckUserExist(testutil.UserIDForDebug).Return(false, testutil.ErrTest)
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(false, nil)
 			},
 			want:    nil,
 			wantErr: true,
 		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ut := newFriendListUseCaseTest(t)
+			tt.expects(ut)
+
+			got, err := ut.flu.GetFriendListOfFriendsByUserIdWithCursor(ut.ctx, testutil.UserIDForDebug, 111111, 20)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetFriendListOfFriendsByUserIdWithCursor() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListUseCase_GetBlockListByUserId(t *testing.T) {
+	paged := &model.BlockList{BlockUsers: newFriendList().Friends}
+
+	tests := []struct {
+		name          string
+		limit, offset int
+		expects       func(*friendListUseCaseTest)
+		want          *model.BlockList
+		wantErr       bool
+	}{
 		{
-			name: "ng: error at GetFriendListOfFriendsByUserId()",
+			name:  "ok: whole list without limit",
+			limit: 0,
+			expects: func(ut *friendListUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.fls.EXPECT().GetBlockListByUserId(ut.ctx, testutil.UserIDForDebug).Return(paged, nil)
+			},
+			want:    paged,
+			wantErr: false,
+		},
+		{
+			name:   "ok: page",
+			limit:  1,
+			offset: 1,
+			expects: func(ut *friendListUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.fls.EXPECT().GetBlockListByUserIdWithPaging(ut.ctx, testutil.UserIDForDebug, 1, 1).Return(paged, nil)
+			},
+			want:    paged,
+			wantErr: false,
+		},
+		{
+			name:  "ng: user not exist",
+			limit: 1,
 			expects: func(ut *friendListUseCaseTest) {
-				ut.fls.EXPECT().CheckUserExist(testutil.UserIDForDebug).Return(true, nil)
-				ut.fls.EXPECT().GetFriendListOfFriendsByUserIdWithPaging(ut.c).Return(nil, testutil.ErrTest)
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(false, nil)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:  "ng: error at GetBlockListByUserId()",
+			limit: 0,
+			expects: func(ut *friendListUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.fls.EXPECT().GetBlockListByUserId(ut.ctx, testutil.UserIDForDebug).Return(nil, testutil.ErrTest)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:   "ng: error at GetBlockListByUserIdWithPaging()",
+			limit:  1,
+			offset: 1,
+			expects: func(ut *friendListUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.fls.EXPECT().GetBlockListByUserIdWithPaging(ut.ctx, testutil.UserIDForDebug, 1, 1).Return(nil, testutil.ErrTest)
 			},
 			want:    nil,
 			wantErr: true,
@@ -330,11 +703,169 @@ func Test_friendListUseCase_GetFriendListOfFriendsByUserIdWithPaging(t *testing.
 			ut := newFriendListUseCaseTest(t)
 			tt.expects(ut)
 
-			got, err := ut.flu.GetFriendListOfFriendsByUserIdWithPaging(ut.c)
+			got, err := ut.flu.GetBlockListByUserId(ut.ctx, testutil.UserIDForDebug, tt.limit, tt.offset)
 			if (err != nil) != tt.wantErr {
-				t.Fatalf("GetFriendListOfFriendsByUserIdWithPaging() error = %v, wantErr = %v", err, tt.wantErr)
+				t.Fatalf("GetBlockListByUserId() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendListUseCase_GetNeighbourhood(t *testing.T) {
+	neighbourList := func() *model.NeighbourList {
+		return &model.NeighbourList{
+			Neighbours: []*model.Neighbour{
+				{UserId: 111111, Name: "hoge", Distance: 1},
+				{UserId: 222222, Name: "fuga", Distance: 2},
+			},
+			Paging: &model.Paging{Total: 2, Page: 2, Limit: 1, HasNext: false},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		expects func(*friendListUseCaseTest)
+		want    *model.NeighbourList
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			expects: func(ut *friendListUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.fls.EXPECT().GetNeighbourhood(ut.ctx, testutil.UserIDForDebug, 1, 2, 1, 1).Return(neighbourList(), nil)
+			},
+			want:    neighbourList(),
+			wantErr: false,
+		},
+		{
+			name: "ng: user not exist",
+			expects: func(ut *friendListUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(false, nil)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "ng: error at GetNeighbourhood()",
+			expects: func(ut *friendListUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.fls.EXPECT().GetNeighbourhood(ut.ctx, testutil.UserIDForDebug, 1, 2, 1, 1).Return(nil, testutil.ErrTest)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ut := newFriendListUseCaseTest(t)
+			tt.expects(ut)
+
+			got, err := ut.flu.GetNeighbourhood(ut.ctx, testutil.UserIDForDebug, 1, 2, 1, 1)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetNeighbourhood() error = %v, wantErr = %v", err, tt.wantErr)
 			}
 			assert.Equal(t, tt.want, got)
 		})
 	}
 }
+
+func Test_friendListUseCase_PostUserLink_Concurrent(t *testing.T) {
+	const parallel = 10
+
+	db := testutil.PrepareMySQL(t)
+	// txdb shares one connection per DSN, so the pool is limited to keep each transaction's savepoints nested.
+	// It runs the requests one at a time, so this only checks that repeating a link keeps one row; the
+	// duplicate entry of a link inserted after the check is forced in Test_friendListController_PostUserLink_Race.
+	db.SetMaxOpenConns(1)
+
+	for _, userId := range []int{testutil.UserIDForDebug, 111111} {
+		testutil.ExecSQL(t, db, `INSERT INTO users (id, user_id, name) VALUES (0, ?, ?)`, userId, "hoge")
+	}
+
+	flr := repository.NewFriendListRepository(db)
+	flu := NewFriendListUseCase(repository.NewTransaction(db), service.NewFriendListService(flr, repository.NewFriendRequestRepository(db), service.BlockPolicyMutual))
+
+	for _, table := range []string{"friend_link", "block_list"} {
+		t.Run(table, func(t *testing.T) {
+			req := &model.UserLinkForRequest{
+				User1Id: testutil.UserIDForDebug,
+				User2Id: 111111,
+				Table:   table,
+			}
+
+			var wg sync.WaitGroup
+			errs := make(chan error, parallel)
+			for i := 0; i < parallel; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs <- flu.PostUserLink(context.Background(), req)
+				}()
+			}
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				assert.NoError(t, err)
+			}
+
+			var count int
+			q := "SELECT COUNT(*) FROM " + table + " WHERE user1_id = ? AND user2_id = ?"
+			if err := db.QueryRow(q, req.User1Id, req.User2Id).Scan(&count); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, 1, count)
+		})
+	}
+}
+
+func Test_friendListUseCase_PostUserLink_BlockSeversFriendship(t *testing.T) {
+	db := testutil.PrepareMySQL(t)
+
+	for _, userId := range []int{testutil.UserIDForDebug, 111111} {
+		testutil.ExecSQL(t, db, `INSERT INTO users (id, user_id, name) VALUES (0, ?, ?)`, userId, "hoge")
+	}
+	for _, ul := range [][2]int{{testutil.UserIDForDebug, 111111}, {111111, testutil.UserIDForDebug}} {
+		testutil.ExecSQL(t, db, `INSERT INTO friend_link (id, user1_id, user2_id) VALUES (0, ?, ?)`, ul[0], ul[1])
+	}
+	testutil.ExecSQL(t, db, `INSERT INTO friend_request (id, from_user_id, to_user_id, status) VALUES (0, ?, ?, ?)`,
+		111111, testutil.UserIDForDebug, model.FriendRequestStatusPending)
+
+	flr := repository.NewFriendListRepository(db)
+	frr := repository.NewFriendRequestRepository(db)
+	flu := NewFriendListUseCase(repository.NewTransaction(db), service.NewFriendListService(flr, frr, service.BlockPolicyMutual))
+
+	req := &model.UserLinkForRequest{
+		User1Id: testutil.UserIDForDebug,
+		User2Id: 111111,
+		Table:   "block_list",
+	}
+	if err := flu.PostUserLink(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	countFriendLinks := func() int {
+		var count int
+		const q = `SELECT COUNT(*) FROM friend_link WHERE user1_id IN (?, ?) AND user2_id IN (?, ?)`
+		if err := db.QueryRow(q, req.User1Id, req.User2Id, req.User1Id, req.User2Id).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+
+		return count
+	}
+	assert.Equal(t, 0, countFriendLinks())
+
+	pending, err := frr.ExistsPendingFriendRequest(context.Background(), req.User1Id, req.User2Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, pending)
+
+	// unblocking does not restore the friendship
+	if err := flu.DeleteUserLink(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, countFriendLinks())
+}
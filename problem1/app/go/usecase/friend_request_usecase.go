@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"problem1/model"
+	"problem1/pkg/httputil"
+	"problem1/repository"
+	"problem1/service"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+type FriendRequestUseCase interface {
+	SendFriendRequest(ctx context.Context, frfr *model.FriendRequestForRequest) (*model.FriendRequest, error)
+	GetIncomingFriendRequests(ctx context.Context, userId int) (*model.FriendRequestList, error)
+	GetOutgoingFriendRequests(ctx context.Context, userId int) (*model.FriendRequestList, error)
+	AcceptFriendRequest(ctx context.Context, frafr *model.FriendRequestActionForRequest) error
+	RejectFriendRequest(ctx context.Context, frafr *model.FriendRequestActionForRequest) error
+	CancelFriendRequest(ctx context.Context, frafr *model.FriendRequestActionForRequest) error
+}
+
+type friendRequestUseCase struct {
+	tx  repository.Transaction
+	fls service.FriendListService
+	frs service.FriendRequestService
+}
+
+func NewFriendRequestUseCase(tx repository.Transaction, fls service.FriendListService, frs service.FriendRequestService) FriendRequestUseCase {
+	return &friendRequestUseCase{
+		tx:  tx,
+		fls: fls,
+		frs: frs,
+	}
+}
+
+func convertFriendRequestError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrFriendRequestNotFound):
+		return httputil.NewHTTPError(err, http.StatusNotFound, httputil.ErrorCodeFriendRequestNotFound, "")
+	case errors.Is(err, service.ErrFriendRequestBlocked):
+		return httputil.NewHTTPError(err, http.StatusForbidden, httputil.ErrorCodeFriendRequestBlocked, "")
+	case errors.Is(err, service.ErrFriendRequestForbidden):
+		return httputil.NewHTTPError(err, http.StatusForbidden, httputil.ErrorCodeFriendRequestForbidden, "")
+	case errors.Is(err, service.ErrAlreadyFriends):
+		return httputil.NewHTTPError(err, http.StatusConflict, httputil.ErrorCodeAlreadyFriends, "")
+	case errors.Is(err, service.ErrFriendRequestAlreadyExists):
+		return httputil.NewHTTPError(err, http.StatusConflict, httputil.ErrorCodeFriendRequestDuplicated, "")
+	case errors.Is(err, service.ErrFriendRequestNotPending):
+		return httputil.NewHTTPError(err, http.StatusConflict, httputil.ErrorCodeFriendRequestNotPending, "")
+	default:
+		return err
+	}
+}
+
+func (u *friendRequestUseCase) SendFriendRequest(ctx context.Context, frfr *model.FriendRequestForRequest) (*model.FriendRequest, error) {
+	var fr *model.FriendRequest
+	err := u.tx.DoInTx(ctx, func(ctx context.Context) error {
+		if err := ensureUserExist(ctx, u.fls, frfr.FromUserId); err != nil {
+			return err
+		}
+		if err := ensureUserExist(ctx, u.fls, frfr.ToUserId); err != nil {
+			return err
+		}
+
+		var err error
+		fr, err = u.frs.SendFriendRequest(ctx, frfr)
+
+		return err
+	})
+	if err != nil {
+		return nil, convertFriendRequestError(err)
+	}
+
+	return fr, nil
+}
+
+func (u *friendRequestUseCase) GetIncomingFriendRequests(ctx context.Context, userId int) (*model.FriendRequestList, error) {
+	if err := ensureUserExist(ctx, u.fls, userId); err != nil {
+		return nil, err
+	}
+
+	return u.frs.GetIncomingFriendRequests(ctx, userId)
+}
+
+func (u *friendRequestUseCase) GetOutgoingFriendRequests(ctx context.Context, userId int) (*model.FriendRequestList, error) {
+	if err := ensureUserExist(ctx, u.fls, userId); err != nil {
+		return nil, err
+	}
+
+	return u.frs.GetOutgoingFriendRequests(ctx, userId)
+}
+
+func (u *friendRequestUseCase) AcceptFriendRequest(ctx context.Context, frafr *model.FriendRequestActionForRequest) error {
+	return u.doAction(ctx, frafr, func(ctx context.Context, requestId, userId int) error {
+		fr, err := u.frs.AcceptFriendRequest(ctx, requestId, userId)
+		if err != nil {
+			return err
+		}
+
+		return u.fls.AddFriendship(ctx, fr.FromUserId, fr.ToUserId)
+	})
+}
+
+func (u *friendRequestUseCase) RejectFriendRequest(ctx context.Context, frafr *model.FriendRequestActionForRequest) error {
+	return u.doAction(ctx, frafr, u.frs.RejectFriendRequest)
+}
+
+func (u *friendRequestUseCase) CancelFriendRequest(ctx context.Context, frafr *model.FriendRequestActionForRequest) error {
+	return u.doAction(ctx, frafr, u.frs.CancelFriendRequest)
+}
+
+func (u *friendRequestUseCase) doAction(ctx context.Context, frafr *model.FriendRequestActionForRequest, action func(ctx context.Context, requestId, userId int) error) error {
+	err := u.tx.DoInTx(ctx, func(ctx context.Context) error {
+		if err := ensureUserExist(ctx, u.fls, frafr.UserId); err != nil {
+			return err
+		}
+
+		return action(ctx, frafr.RequestId, frafr.UserId)
+	})
+
+	return convertFriendRequestError(err)
+}
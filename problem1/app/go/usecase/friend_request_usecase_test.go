@@ -0,0 +1,312 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/mock/mock_service"
+	"problem1/model"
+	"problem1/pkg/httputil"
+	"problem1/pkg/testutil"
+	"problem1/repository"
+	"problem1/service"
+)
+
+type friendRequestUseCaseTest struct {
+	mock sqlmock.Sqlmock
+	fls  *mock_service.MockFriendListService
+	frs  *mock_service.MockFriendRequestService
+	fru  FriendRequestUseCase
+	ctx  context.Context
+}
+
+func newFriendRequestUseCaseTest(t *testing.T) *friendRequestUseCaseTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	db, mock := testutil.NewSQLMock(t)
+	fls := mock_service.NewMockFriendListService(ctrl)
+	frs := mock_service.NewMockFriendRequestService(ctrl)
+
+	return &friendRequestUseCaseTest{
+		mock: mock,
+		fls:  fls,
+		frs:  frs,
+		fru:  NewFriendRequestUseCase(repository.NewTransaction(db), fls, frs),
+		ctx:  context.Background(),
+	}
+}
+
+func Test_friendRequestUseCase_SendFriendRequest(t *testing.T) {
+	req := &model.FriendRequestForRequest{
+		FromUserId: testutil.UserIDForDebug,
+		ToUserId:   111111,
+	}
+	want := &model.FriendRequest{
+		RequestId:  1,
+		FromUserId: req.FromUserId,
+		ToUserId:   req.ToUserId,
+		Status:     model.FriendRequestStatusPending,
+	}
+
+	tests := []struct {
+		name        string
+		expects     func(*friendRequestUseCaseTest)
+		want        *model.FriendRequest
+		wantErr     bool
+		wantErrCode int
+	}{
+		{
+			name: "ok",
+			expects: func(ut *friendRequestUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.FromUserId).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.ToUserId).Return(true, nil)
+				ut.frs.EXPECT().SendFriendRequest(gomock.Any(), req).Return(want, nil)
+				ut.mock.ExpectCommit()
+			},
+			want:    want,
+			wantErr: false,
+		},
+		{
+			name: "ng: receiver not exist",
+			expects: func(ut *friendRequestUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.FromUserId).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.ToUserId).Return(false, nil)
+				ut.mock.ExpectRollback()
+			},
+			want:        nil,
+			wantErr:     true,
+			wantErrCode: http.StatusBadRequest,
+		},
+		{
+			name: "ng: blocked",
+			expects: func(ut *friendRequestUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.FromUserId).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.ToUserId).Return(true, nil)
+				ut.frs.EXPECT().SendFriendRequest(gomock.Any(), req).Return(nil, service.ErrFriendRequestBlocked)
+				ut.mock.ExpectRollback()
+			},
+			want:        nil,
+			wantErr:     true,
+			wantErrCode: http.StatusForbidden,
+		},
+		{
+			name: "ng: already exists",
+			expects: func(ut *friendRequestUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.FromUserId).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.ToUserId).Return(true, nil)
+				ut.frs.EXPECT().SendFriendRequest(gomock.Any(), req).Return(nil, service.ErrFriendRequestAlreadyExists)
+				ut.mock.ExpectRollback()
+			},
+			want:        nil,
+			wantErr:     true,
+			wantErrCode: http.StatusConflict,
+		},
+		{
+			name: "ng: error at SendFriendRequest()",
+			expects: func(ut *friendRequestUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.FromUserId).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.ToUserId).Return(true, nil)
+				ut.frs.EXPECT().SendFriendRequest(gomock.Any(), req).Return(nil, testutil.ErrTest)
+				ut.mock.ExpectRollback()
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ut := newFriendRequestUseCaseTest(t)
+			tt.expects(ut)
+
+			got, err := ut.fru.SendFriendRequest(ut.ctx, req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SendFriendRequest() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if err != nil && tt.wantErrCode != 0 {
+				if !httputil.As(err, tt.wantErrCode) {
+					t.Fatalf("SendFriendRequest() error = %v, wantErrCode= %v", err, tt.wantErrCode)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendRequestUseCase_AcceptFriendRequest(t *testing.T) {
+	req := &model.FriendRequestActionForRequest{
+		RequestId: 1,
+		UserId:    111111,
+	}
+	accepted := &model.FriendRequest{
+		RequestId:  req.RequestId,
+		FromUserId: testutil.UserIDForDebug,
+		ToUserId:   req.UserId,
+		Status:     model.FriendRequestStatusAccepted,
+	}
+
+	tests := []struct {
+		name        string
+		expects     func(*friendRequestUseCaseTest)
+		wantErr     bool
+		wantErrCode int
+	}{
+		{
+			name: "ok",
+			expects: func(ut *friendRequestUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.UserId).Return(true, nil)
+				ut.frs.EXPECT().AcceptFriendRequest(gomock.Any(), req.RequestId, req.UserId).Return(accepted, nil)
+				ut.fls.EXPECT().AddFriendship(gomock.Any(), accepted.FromUserId, accepted.ToUserId).Return(nil)
+				ut.mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "ng: error at AddFriendship()",
+			expects: func(ut *friendRequestUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.UserId).Return(true, nil)
+				ut.frs.EXPECT().AcceptFriendRequest(gomock.Any(), req.RequestId, req.UserId).Return(accepted, nil)
+				ut.fls.EXPECT().AddFriendship(gomock.Any(), accepted.FromUserId, accepted.ToUserId).Return(testutil.ErrTest)
+				ut.mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "ng: request not exist",
+			expects: func(ut *friendRequestUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.UserId).Return(true, nil)
+				ut.frs.EXPECT().AcceptFriendRequest(gomock.Any(), req.RequestId, req.UserId).Return(nil, repository.ErrFriendRequestNotFound)
+				ut.mock.ExpectRollback()
+			},
+			wantErr:     true,
+			wantErrCode: http.StatusNotFound,
+		},
+		{
+			name: "ng: forbidden",
+			expects: func(ut *friendRequestUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.UserId).Return(true, nil)
+				ut.frs.EXPECT().AcceptFriendRequest(gomock.Any(), req.RequestId, req.UserId).Return(nil, service.ErrFriendRequestForbidden)
+				ut.mock.ExpectRollback()
+			},
+			wantErr:     true,
+			wantErrCode: http.StatusForbidden,
+		},
+		{
+			name: "ng: not pending",
+			expects: func(ut *friendRequestUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.UserId).Return(true, nil)
+				ut.frs.EXPECT().AcceptFriendRequest(gomock.Any(), req.RequestId, req.UserId).Return(nil, service.ErrFriendRequestNotPending)
+				ut.mock.ExpectRollback()
+			},
+			wantErr:     true,
+			wantErrCode: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ut := newFriendRequestUseCaseTest(t)
+			tt.expects(ut)
+
+			err := ut.fru.AcceptFriendRequest(ut.ctx, req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AcceptFriendRequest() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if err != nil && tt.wantErrCode != 0 {
+				if !httputil.As(err, tt.wantErrCode) {
+					t.Fatalf("AcceptFriendRequest() error = %v, wantErrCode= %v", err, tt.wantErrCode)
+				}
+			}
+		})
+	}
+}
+
+func Test_friendRequestUseCase_RejectAndCancelFriendRequest(t *testing.T) {
+	req := &model.FriendRequestActionForRequest{
+		RequestId: 1,
+		UserId:    111111,
+	}
+
+	ut := newFriendRequestUseCaseTest(t)
+	ut.mock.ExpectBegin()
+	ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.UserId).Return(true, nil)
+	ut.frs.EXPECT().RejectFriendRequest(gomock.Any(), req.RequestId, req.UserId).Return(nil)
+	ut.mock.ExpectCommit()
+	ut.mock.ExpectBegin()
+	ut.fls.EXPECT().CheckUserExist(gomock.Any(), req.UserId).Return(true, nil)
+	ut.frs.EXPECT().CancelFriendRequest(gomock.Any(), req.RequestId, req.UserId).Return(service.ErrFriendRequestForbidden)
+	ut.mock.ExpectRollback()
+
+	assert.NoError(t, ut.fru.RejectFriendRequest(ut.ctx, req))
+	assert.True(t, httputil.As(ut.fru.CancelFriendRequest(ut.ctx, req), http.StatusForbidden))
+}
+
+func Test_friendRequestUseCase_GetIncomingFriendRequests(t *testing.T) {
+	want := &model.FriendRequestList{}
+
+	tests := []struct {
+		name    string
+		expects func(*friendRequestUseCaseTest)
+		want    *model.FriendRequestList
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			expects: func(ut *friendRequestUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.frs.EXPECT().GetIncomingFriendRequests(ut.ctx, testutil.UserIDForDebug).Return(want, nil)
+			},
+			want:    want,
+			wantErr: false,
+		},
+		{
+			name: "ng: user not exist",
+			expects: func(ut *friendRequestUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(false, nil)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ut := newFriendRequestUseCaseTest(t)
+			tt.expects(ut)
+
+			got, err := ut.fru.GetIncomingFriendRequests(ut.ctx, testutil.UserIDForDebug)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetIncomingFriendRequests() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_friendRequestUseCase_GetOutgoingFriendRequests(t *testing.T) {
+	want := &model.FriendRequestList{}
+
+	ut := newFriendRequestUseCaseTest(t)
+	ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+	ut.frs.EXPECT().GetOutgoingFriendRequests(ut.ctx, testutil.UserIDForDebug).Return(want, nil)
+
+	got, err := ut.fru.GetOutgoingFriendRequests(ut.ctx, testutil.UserIDForDebug)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+}
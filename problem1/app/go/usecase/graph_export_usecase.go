@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+
+	"problem1/pkg/graphexport"
+	"problem1/repository"
+	"problem1/service"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+type GraphExportUseCase interface {
+	Export(ctx context.Context, enc graphexport.Encoder, userId, depth int) error
+}
+
+type graphExportUseCase struct {
+	tx  repository.Transaction
+	fls service.FriendListService
+	ges service.GraphExportService
+}
+
+func NewGraphExportUseCase(tx repository.Transaction, fls service.FriendListService, ges service.GraphExportService) GraphExportUseCase {
+	return &graphExportUseCase{
+		tx:  tx,
+		fls: fls,
+		ges: ges,
+	}
+}
+
+// Export writes the graph, or the ego network of userId with a depth of 1 or more, to enc.
+// It reads in one transaction so that the links written meanwhile do not tear the graph.
+func (u *graphExportUseCase) Export(ctx context.Context, enc graphexport.Encoder, userId, depth int) error {
+	return u.tx.DoInTx(ctx, func(ctx context.Context) error {
+		if depth > 0 {
+			if err := ensureUserExist(ctx, u.fls, userId); err != nil {
+				return err
+			}
+		}
+
+		return u.ges.Export(ctx, enc, userId, depth)
+	})
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+
+	"problem1/mock/mock_service"
+	"problem1/pkg/graphexport"
+	"problem1/pkg/httputil"
+	"problem1/pkg/testutil"
+	"problem1/repository"
+)
+
+type graphExportUseCaseTest struct {
+	mock sqlmock.Sqlmock
+	fls  *mock_service.MockFriendListService
+	ges  *mock_service.MockGraphExportService
+	geu  GraphExportUseCase
+	ctx  context.Context
+}
+
+func newGraphExportUseCaseTest(t *testing.T) *graphExportUseCaseTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	db, mock := testutil.NewSQLMock(t)
+	fls := mock_service.NewMockFriendListService(ctrl)
+	ges := mock_service.NewMockGraphExportService(ctrl)
+
+	return &graphExportUseCaseTest{
+		mock: mock,
+		fls:  fls,
+		ges:  ges,
+		geu:  NewGraphExportUseCase(repository.NewTransaction(db), fls, ges),
+		ctx:  context.Background(),
+	}
+}
+
+func Test_graphExportUseCase_Export(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	enc, err := graphexport.NewEncoder(io.Discard, graphexport.FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		expects     func(*graphExportUseCaseTest)
+		depth       int
+		wantErr     bool
+		wantErrCode int
+	}{
+		{
+			name: "ok: whole graph",
+			expects: func(ut *graphExportUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.ges.EXPECT().Export(gomock.Any(), enc, userId, 0).Return(nil)
+				ut.mock.ExpectCommit()
+			},
+			depth:   0,
+			wantErr: false,
+		},
+		{
+			name: "ok: ego network",
+			expects: func(ut *graphExportUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), userId).Return(true, nil)
+				ut.ges.EXPECT().Export(gomock.Any(), enc, userId, 2).Return(nil)
+				ut.mock.ExpectCommit()
+			},
+			depth:   2,
+			wantErr: false,
+		},
+		{
+			name: "ng: user not exist",
+			expects: func(ut *graphExportUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.fls.EXPECT().CheckUserExist(gomock.Any(), userId).Return(false, nil)
+				ut.mock.ExpectRollback()
+			},
+			depth:       2,
+			wantErr:     true,
+			wantErrCode: http.StatusBadRequest,
+		},
+		{
+			name: "ng: error at Export",
+			expects: func(ut *graphExportUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.ges.EXPECT().Export(gomock.Any(), enc, userId, 0).Return(testutil.ErrTest)
+				ut.mock.ExpectRollback()
+			},
+			depth:   0,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ut := newGraphExportUseCaseTest(t)
+			tt.expects(ut)
+
+			err := ut.geu.Export(ut.ctx, enc, userId, tt.depth)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Export() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if err != nil && tt.wantErrCode != 0 && !httputil.As(err, tt.wantErrCode) {
+				t.Fatalf("Export() error = %v, wantErrCode = %v", err, tt.wantErrCode)
+			}
+			if err := ut.mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
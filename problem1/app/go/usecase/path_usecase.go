@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"problem1/model"
+	"problem1/pkg/httputil"
+	"problem1/service"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+type PathUseCase interface {
+	GetShortestPath(ctx context.Context, fromUserId, toUserId, maxDepth int) (*model.FriendPath, error)
+}
+
+type pathUseCase struct {
+	fls service.FriendListService
+	ps  service.PathService
+}
+
+func NewPathUseCase(fls service.FriendListService, ps service.PathService) PathUseCase {
+	return &pathUseCase{
+		fls: fls,
+		ps:  ps,
+	}
+}
+
+func (u *pathUseCase) GetShortestPath(ctx context.Context, fromUserId, toUserId, maxDepth int) (*model.FriendPath, error) {
+	if err := ensureUserExist(ctx, u.fls, fromUserId); err != nil {
+		return nil, err
+	}
+	if err := ensureUserExist(ctx, u.fls, toUserId); err != nil {
+		return nil, err
+	}
+
+	friendPath, err := u.ps.GetShortestPath(ctx, fromUserId, toUserId, maxDepth)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPathNotFound):
+			return nil, httputil.NewHTTPError(err, http.StatusNotFound, httputil.ErrorCodePathNotFound, "")
+		case errors.Is(err, service.ErrPathSearchBudgetExceeded):
+			return nil, httputil.NewHTTPError(err, http.StatusUnprocessableEntity, httputil.ErrorCodePathSearchBudgetExceeded, "")
+		default:
+			return nil, err
+		}
+	}
+
+	return friendPath, nil
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/mock/mock_service"
+	"problem1/model"
+	"problem1/pkg/httputil"
+	"problem1/pkg/testutil"
+	"problem1/service"
+)
+
+type pathUseCaseTest struct {
+	fls *mock_service.MockFriendListService
+	ps  *mock_service.MockPathService
+	pu  PathUseCase
+	ctx context.Context
+}
+
+func newPathUseCaseTest(t *testing.T) *pathUseCaseTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	fls := mock_service.NewMockFriendListService(ctrl)
+	ps := mock_service.NewMockPathService(ctrl)
+
+	return &pathUseCaseTest{
+		fls: fls,
+		ps:  ps,
+		pu:  NewPathUseCase(fls, ps),
+		ctx: context.Background(),
+	}
+}
+
+func Test_pathUseCase_GetShortestPath(t *testing.T) {
+	from, to := testutil.UserIDForDebug, 222222
+	want := &model.FriendPath{
+		Users: []*model.Friend{
+			{UserId: from, Name: testutil.UserNameForDebug},
+			{UserId: 111111, Name: "hoge"},
+			{UserId: to, Name: "fuga"},
+		},
+		Degree: 2,
+	}
+
+	tests := []struct {
+		name        string
+		expects     func(*pathUseCaseTest)
+		want        *model.FriendPath
+		wantErr     bool
+		wantErrCode int
+	}{
+		{
+			name: "ok",
+			expects: func(ut *pathUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, from).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, to).Return(true, nil)
+				ut.ps.EXPECT().GetShortestPath(ut.ctx, from, to, 6).Return(want, nil)
+			},
+			want:    want,
+			wantErr: false,
+		},
+		{
+			name: "ng: user not exist",
+			expects: func(ut *pathUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, from).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, to).Return(false, nil)
+			},
+			want:        nil,
+			wantErr:     true,
+			wantErrCode: http.StatusBadRequest,
+		},
+		{
+			name: "ng: path not exist",
+			expects: func(ut *pathUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, from).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, to).Return(true, nil)
+				ut.ps.EXPECT().GetShortestPath(ut.ctx, from, to, 6).Return(nil, service.ErrPathNotFound)
+			},
+			want:        nil,
+			wantErr:     true,
+			wantErrCode: http.StatusNotFound,
+		},
+		{
+			name: "ng: budget exceeded",
+			expects: func(ut *pathUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, from).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, to).Return(true, nil)
+				ut.ps.EXPECT().GetShortestPath(ut.ctx, from, to, 6).Return(nil, service.ErrPathSearchBudgetExceeded)
+			},
+			want:        nil,
+			wantErr:     true,
+			wantErrCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "ng: error at GetShortestPath()",
+			expects: func(ut *pathUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, from).Return(true, nil)
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, to).Return(true, nil)
+				ut.ps.EXPECT().GetShortestPath(ut.ctx, from, to, 6).Return(nil, testutil.ErrTest)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ut := newPathUseCaseTest(t)
+			tt.expects(ut)
+
+			got, err := ut.pu.GetShortestPath(ut.ctx, from, to, 6)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetShortestPath() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if err != nil && tt.wantErrCode != 0 {
+				if !httputil.As(err, tt.wantErrCode) {
+					t.Fatalf("GetShortestPath() error = %v, wantErrCode= %v", err, tt.wantErrCode)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
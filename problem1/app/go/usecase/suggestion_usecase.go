@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+
+	"problem1/model"
+	"problem1/pkg/httputil"
+	"problem1/service"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+type SuggestionUseCase interface {
+	GetSuggestions(ctx context.Context, userId int, scorerName string, limit, offset int) (*model.SuggestionList, error)
+}
+
+type suggestionUseCase struct {
+	fls service.FriendListService
+	ss  service.SuggestionService
+}
+
+func NewSuggestionUseCase(fls service.FriendListService, ss service.SuggestionService) SuggestionUseCase {
+	return &suggestionUseCase{
+		fls: fls,
+		ss:  ss,
+	}
+}
+
+func (u *suggestionUseCase) GetSuggestions(ctx context.Context, userId int, scorerName string, limit, offset int) (*model.SuggestionList, error) {
+	scorer, err := service.GetScorer(scorerName)
+	if err != nil {
+		return nil, httputil.NewHTTPError(err, http.StatusBadRequest, httputil.ErrorCodeInvalidParameter, "")
+	}
+	if err := ensureUserExist(ctx, u.fls, userId); err != nil {
+		return nil, err
+	}
+
+	return u.ss.GetSuggestions(ctx, userId, scorer, limit, offset)
+}
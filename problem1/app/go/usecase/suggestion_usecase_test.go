@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/mock/mock_service"
+	"problem1/model"
+	"problem1/pkg/httputil"
+	"problem1/pkg/testutil"
+	"problem1/service"
+)
+
+type suggestionUseCaseTest struct {
+	fls *mock_service.MockFriendListService
+	ss  *mock_service.MockSuggestionService
+	su  SuggestionUseCase
+	ctx context.Context
+}
+
+func newSuggestionUseCaseTest(t *testing.T) *suggestionUseCaseTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	fls := mock_service.NewMockFriendListService(ctrl)
+	ss := mock_service.NewMockSuggestionService(ctrl)
+
+	return &suggestionUseCaseTest{
+		fls: fls,
+		ss:  ss,
+		su:  NewSuggestionUseCase(fls, ss),
+		ctx: context.Background(),
+	}
+}
+
+func newSuggestionList() *model.SuggestionList {
+	return &model.SuggestionList{
+		Suggestions: []*model.Suggestion{
+			{UserId: 333333, Name: "bar", Score: 2, MutualFriendCount: 2},
+			{UserId: 444444, Name: "piyo", Score: 1, MutualFriendCount: 1},
+		},
+		Paging: &model.Paging{Total: 2, Page: 1, Limit: 20, HasNext: false},
+	}
+}
+
+func Test_suggestionUseCase_GetSuggestions(t *testing.T) {
+	tests := []struct {
+		name        string
+		expects     func(*suggestionUseCaseTest)
+		scorer      string
+		want        *model.SuggestionList
+		wantErr     bool
+		wantErrCode int
+	}{
+		{
+			name: "ok",
+			expects: func(ut *suggestionUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.ss.EXPECT().GetSuggestions(ut.ctx, testutil.UserIDForDebug, service.JaccardScorer{}, 20, 0).Return(newSuggestionList(), nil)
+			},
+			scorer:  service.ScorerJaccard,
+			want:    newSuggestionList(),
+			wantErr: false,
+		},
+		{
+			name:        "ng: scorer not exist",
+			expects:     func(ut *suggestionUseCaseTest) {},
+			scorer:      "unknown",
+			want:        nil,
+			wantErr:     true,
+			wantErrCode: http.StatusBadRequest,
+		},
+		{
+			name: "ng: user not exist",
+			expects: func(ut *suggestionUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(false, nil)
+			},
+			scorer:      service.ScorerMutual,
+			want:        nil,
+			wantErr:     true,
+			wantErrCode: http.StatusBadRequest,
+		},
+		{
+			name: "ng: error at GetSuggestions()",
+			expects: func(ut *suggestionUseCaseTest) {
+				ut.fls.EXPECT().CheckUserExist(ut.ctx, testutil.UserIDForDebug).Return(true, nil)
+				ut.ss.EXPECT().GetSuggestions(ut.ctx, testutil.UserIDForDebug, service.MutualScorer{}, 20, 0).Return(nil, testutil.ErrTest)
+			},
+			scorer:  service.ScorerMutual,
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ut := newSuggestionUseCaseTest(t)
+			tt.expects(ut)
+
+			got, err := ut.su.GetSuggestions(ut.ctx, testutil.UserIDForDebug, tt.scorer, 20, 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetSuggestions() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if err != nil && tt.wantErrCode != 0 {
+				if !httputil.As(err, tt.wantErrCode) {
+					t.Fatalf("GetSuggestions() error = %v, wantErrCode= %v", err, tt.wantErrCode)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"problem1/model"
+	"problem1/pkg/httputil"
+	"problem1/repository"
+	"problem1/service"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=../mock/mock_$GOPACKAGE/mock_$GOFILE
+
+type UserUseCase interface {
+	CreateUser(ctx context.Context, ufr *model.UserForRequest) (*model.User, error)
+	GetUser(ctx context.Context, userId int) (*model.User, error)
+	UpdateUser(ctx context.Context, userId int, upfr *model.UserPatchForRequest) (*model.User, error)
+	DeleteUser(ctx context.Context, userId int) error
+	SearchUsers(ctx context.Context, searcherId int, query string, limit, offset int) (*model.UserList, error)
+}
+
+type userUseCase struct {
+	tx repository.Transaction
+	us service.UserService
+}
+
+func NewUserUseCase(tx repository.Transaction, us service.UserService) UserUseCase {
+	return &userUseCase{
+		tx: tx,
+		us: us,
+	}
+}
+
+func convertUserError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound):
+		return httputil.NewHTTPError(err, http.StatusNotFound, httputil.ErrorCodeUserNotFound, "")
+	case errors.Is(err, repository.ErrUserDuplicated):
+		return httputil.NewHTTPError(err, http.StatusConflict, httputil.ErrorCodeUserDuplicated, "")
+	default:
+		return err
+	}
+}
+
+func (u *userUseCase) CreateUser(ctx context.Context, ufr *model.UserForRequest) (*model.User, error) {
+	var user *model.User
+	err := u.tx.DoInTx(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = u.us.CreateUser(ctx, ufr)
+
+		return err
+	})
+	if err != nil {
+		return nil, convertUserError(err)
+	}
+
+	return user, nil
+}
+
+func (u *userUseCase) GetUser(ctx context.Context, userId int) (*model.User, error) {
+	user, err := u.us.GetUser(ctx, userId)
+	if err != nil {
+		return nil, convertUserError(err)
+	}
+
+	return user, nil
+}
+
+// UpdateUser changes the fields given in upfr, and returns the user as it is when none is given.
+func (u *userUseCase) UpdateUser(ctx context.Context, userId int, upfr *model.UserPatchForRequest) (*model.User, error) {
+	if upfr.Name == nil {
+		return u.GetUser(ctx, userId)
+	}
+
+	var user *model.User
+	err := u.tx.DoInTx(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = u.us.UpdateUserName(ctx, userId, *upfr.Name)
+
+		return err
+	})
+	if err != nil {
+		return nil, convertUserError(err)
+	}
+
+	return user, nil
+}
+
+func (u *userUseCase) DeleteUser(ctx context.Context, userId int) error {
+	err := u.tx.DoInTx(ctx, func(ctx context.Context) error {
+		return u.us.DeleteUser(ctx, userId)
+	})
+	if err != nil {
+		return convertUserError(err)
+	}
+
+	return nil
+}
+
+func (u *userUseCase) SearchUsers(ctx context.Context, searcherId int, query string, limit, offset int) (*model.UserList, error) {
+	if _, err := u.us.GetUser(ctx, searcherId); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, httputil.NewHTTPError(err, http.StatusBadRequest, httputil.ErrorCodeUserNotFound, "user not exist")
+		}
+
+		return nil, err
+	}
+
+	return u.us.SearchUsers(ctx, searcherId, query, limit, offset)
+}
@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"problem1/mock/mock_service"
+	"problem1/model"
+	"problem1/pkg/httputil"
+	"problem1/pkg/testutil"
+	"problem1/repository"
+)
+
+type userUseCaseTest struct {
+	mock sqlmock.Sqlmock
+	us   *mock_service.MockUserService
+	uu   UserUseCase
+	ctx  context.Context
+}
+
+func newUserUseCaseTest(t *testing.T) *userUseCaseTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	db, mock := testutil.NewSQLMock(t)
+	us := mock_service.NewMockUserService(ctrl)
+
+	return &userUseCaseTest{
+		mock: mock,
+		us:   us,
+		uu:   NewUserUseCase(repository.NewTransaction(db), us),
+		ctx:  context.Background(),
+	}
+}
+
+func Test_userUseCase_CreateUser(t *testing.T) {
+	req := &model.UserForRequest{UserId: testutil.UserIDForDebug, Name: "藤井 太郎"}
+	want := &model.User{UserId: req.UserId, Name: req.Name}
+
+	tests := []struct {
+		name        string
+		expects     func(*userUseCaseTest)
+		want        *model.User
+		wantErr     bool
+		wantErrCode int
+	}{
+		{
+			name: "ok",
+			expects: func(ut *userUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.us.EXPECT().CreateUser(gomock.Any(), req).Return(want, nil)
+				ut.mock.ExpectCommit()
+			},
+			want:    want,
+			wantErr: false,
+		},
+		{
+			name: "ng: duplicated",
+			expects: func(ut *userUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.us.EXPECT().CreateUser(gomock.Any(), req).Return(nil, repository.ErrUserDuplicated)
+				ut.mock.ExpectRollback()
+			},
+			want:        nil,
+			wantErr:     true,
+			wantErrCode: http.StatusConflict,
+		},
+		{
+			name: "ng: error at CreateUser()",
+			expects: func(ut *userUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.us.EXPECT().CreateUser(gomock.Any(), req).Return(nil, testutil.ErrTest)
+				ut.mock.ExpectRollback()
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ut := newUserUseCaseTest(t)
+			tt.expects(ut)
+
+			got, err := ut.uu.CreateUser(ut.ctx, req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateUser() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if err != nil && tt.wantErrCode != 0 {
+				if !httputil.As(err, tt.wantErrCode) {
+					t.Fatalf("CreateUser() error = %v, wantErrCode= %v", err, tt.wantErrCode)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_userUseCase_UpdateUser(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	name := "hoge"
+	want := &model.User{UserId: userId, Name: name}
+
+	tests := []struct {
+		name        string
+		req         *model.UserPatchForRequest
+		expects     func(*userUseCaseTest)
+		want        *model.User
+		wantErr     bool
+		wantErrCode int
+	}{
+		{
+			name: "ok",
+			req:  &model.UserPatchForRequest{Name: &name},
+			expects: func(ut *userUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.us.EXPECT().UpdateUserName(gomock.Any(), userId, name).Return(want, nil)
+				ut.mock.ExpectCommit()
+			},
+			want:    want,
+			wantErr: false,
+		},
+		{
+			name: "ok: nothing to change",
+			req:  &model.UserPatchForRequest{},
+			expects: func(ut *userUseCaseTest) {
+				ut.us.EXPECT().GetUser(ut.ctx, userId).Return(want, nil)
+			},
+			want:    want,
+			wantErr: false,
+		},
+		{
+			name: "ng: user not exist",
+			req:  &model.UserPatchForRequest{Name: &name},
+			expects: func(ut *userUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.us.EXPECT().UpdateUserName(gomock.Any(), userId, name).Return(nil, repository.ErrUserNotFound)
+				ut.mock.ExpectRollback()
+			},
+			want:        nil,
+			wantErr:     true,
+			wantErrCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ut := newUserUseCaseTest(t)
+			tt.expects(ut)
+
+			got, err := ut.uu.UpdateUser(ut.ctx, userId, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateUser() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if err != nil && tt.wantErrCode != 0 {
+				if !httputil.As(err, tt.wantErrCode) {
+					t.Fatalf("UpdateUser() error = %v, wantErrCode= %v", err, tt.wantErrCode)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_userUseCase_DeleteUser(t *testing.T) {
+	userId := testutil.UserIDForDebug
+
+	tests := []struct {
+		name        string
+		expects     func(*userUseCaseTest)
+		wantErr     bool
+		wantErrCode int
+	}{
+		{
+			name: "ok",
+			expects: func(ut *userUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.us.EXPECT().DeleteUser(gomock.Any(), userId).Return(nil)
+				ut.mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "ng: user not exist",
+			expects: func(ut *userUseCaseTest) {
+				ut.mock.ExpectBegin()
+				ut.us.EXPECT().DeleteUser(gomock.Any(), userId).Return(repository.ErrUserNotFound)
+				ut.mock.ExpectRollback()
+			},
+			wantErr:     true,
+			wantErrCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ut := newUserUseCaseTest(t)
+			tt.expects(ut)
+
+			err := ut.uu.DeleteUser(ut.ctx, userId)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeleteUser() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if err != nil && tt.wantErrCode != 0 {
+				if !httputil.As(err, tt.wantErrCode) {
+					t.Fatalf("DeleteUser() error = %v, wantErrCode= %v", err, tt.wantErrCode)
+				}
+			}
+			if err := ut.mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func Test_userUseCase_SearchUsers(t *testing.T) {
+	userId := testutil.UserIDForDebug
+	want := &model.UserList{Users: []*model.User{{UserId: 111111, Name: "スズキ"}}, Paging: model.NewPaging(1, 20, 0)}
+
+	tests := []struct {
+		name        string
+		expects     func(*userUseCaseTest)
+		want        *model.UserList
+		wantErr     bool
+		wantErrCode int
+	}{
+		{
+			name: "ok",
+			expects: func(ut *userUseCaseTest) {
+				ut.us.EXPECT().GetUser(ut.ctx, userId).Return(&model.User{UserId: userId}, nil)
+				ut.us.EXPECT().SearchUsers(ut.ctx, userId, "すずき", 20, 0).Return(want, nil)
+			},
+			want:    want,
+			wantErr: false,
+		},
+		{
+			name: "ng: searcher not exist",
+			expects: func(ut *userUseCaseTest) {
+				ut.us.EXPECT().GetUser(ut.ctx, userId).Return(nil, repository.ErrUserNotFound)
+			},
+			want:        nil,
+			wantErr:     true,
+			wantErrCode: http.StatusBadRequest,
+		},
+		{
+			name: "ng: error at SearchUsers()",
+			expects: func(ut *userUseCaseTest) {
+				ut.us.EXPECT().GetUser(ut.ctx, userId).Return(&model.User{UserId: userId}, nil)
+				ut.us.EXPECT().SearchUsers(ut.ctx, userId, "すずき", 20, 0).Return(nil, testutil.ErrTest)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ut := newUserUseCaseTest(t)
+			tt.expects(ut)
+
+			got, err := ut.uu.SearchUsers(ut.ctx, userId, "すずき", 20, 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SearchUsers() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if err != nil && tt.wantErrCode != 0 {
+				if !httputil.As(err, tt.wantErrCode) {
+					t.Fatalf("SearchUsers() error = %v, wantErrCode= %v", err, tt.wantErrCode)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
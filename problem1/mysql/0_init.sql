@@ -1,19 +1,25 @@
 DROP TABLE IF EXISTS `users`;
 CREATE TABLE `users`
 (
-    `id`      bigint(20) unsigned    NOT NULL AUTO_INCREMENT,
-    `user_id` int(11) unsigned       NOT NULL UNIQUE,
-    `name`    varchar(64) DEFAULT '' NOT NULL,
+    `id`      bigint(20) unsigned                                                       NOT NULL AUTO_INCREMENT,
+    `user_id` int(11) unsigned                                                          NOT NULL UNIQUE,
+    -- utf8mb4 so that names such as 藤井 太郎 can be sorted with the Japanese collation
+    `name`    varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_ja_0900_as_cs DEFAULT '' NOT NULL,
+    -- name folded by textnorm.Normalize for GET /users/search, compared byte by byte
+    `search_name` varchar(256) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin DEFAULT '' NOT NULL,
     PRIMARY KEY (`id`)
 );
--- user1 user2
+-- user1 user2 added_at
 DROP TABLE IF EXISTS `friend_link`;
 CREATE TABLE `friend_link`
 (
-    `id`       bigint(20) unsigned NOT NULL AUTO_INCREMENT,
-    `user1_id` int(11) unsigned    NOT NULL,
-    `user2_id` int(11) unsigned    NOT NULL,
-    PRIMARY KEY (`id`)
+    `id`         bigint(20) unsigned                NOT NULL AUTO_INCREMENT,
+    `user1_id`   int(11) unsigned                   NOT NULL,
+    `user2_id`   int(11) unsigned                   NOT NULL,
+    `created_at` datetime DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_friend_link_user1_id_user2_id` (`user1_id`, `user2_id`),
+    KEY `idx_friend_link_user2_id_user1_id` (`user2_id`, `user1_id`)
 );
 -- user1 user2 block
 DROP TABLE IF EXISTS `block_list`;
@@ -22,5 +28,26 @@ CREATE TABLE `block_list`
     `id`       bigint(20) unsigned NOT NULL AUTO_INCREMENT,
     `user1_id` int(11) unsigned    NOT NULL,
     `user2_id` int(11) unsigned    NOT NULL,
-    PRIMARY KEY (`id`)
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_block_list_user1_id_user2_id` (`user1_id`, `user2_id`),
+    KEY `idx_block_list_user2_id_user1_id` (`user2_id`, `user1_id`)
+);
+-- from_user to_user status
+DROP TABLE IF EXISTS `friend_request`;
+CREATE TABLE `friend_request`
+(
+    `id`           bigint(20) unsigned                    NOT NULL AUTO_INCREMENT,
+    `from_user_id` int(11) unsigned                       NOT NULL,
+    `to_user_id`   int(11) unsigned                       NOT NULL,
+    `status`       varchar(16) DEFAULT 'pending'          NOT NULL,
+    `created_at`   datetime    DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    `updated_at`   datetime    DEFAULT CURRENT_TIMESTAMP  NOT NULL ON UPDATE CURRENT_TIMESTAMP,
+    -- the users of a pending request in either direction, NULL once it is answered,
+    -- so that only one request is pending between two users
+    `pending_user1_id` int(11) unsigned GENERATED ALWAYS AS (IF(`status` = 'pending', LEAST(`from_user_id`, `to_user_id`), NULL)) STORED,
+    `pending_user2_id` int(11) unsigned GENERATED ALWAYS AS (IF(`status` = 'pending', GREATEST(`from_user_id`, `to_user_id`), NULL)) STORED,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_friend_request_pending_user1_id_pending_user2_id` (`pending_user1_id`, `pending_user2_id`),
+    KEY `idx_friend_request_from_user_id_status` (`from_user_id`, `status`),
+    KEY `idx_friend_request_to_user_id_status` (`to_user_id`, `status`)
 );
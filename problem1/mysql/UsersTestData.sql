@@ -1,31 +1,31 @@
 USE app;
-INSERT INTO users (id, user_id, name) VALUES (0, '0', '藤井 太郎');
-INSERT INTO users (id, user_id, name) VALUES (0, '1', '石川 篤司');
-INSERT INTO users (id, user_id, name) VALUES (0, '2', '清水 聡太郎');
-INSERT INTO users (id, user_id, name) VALUES (0, '3', '斉藤 康弘');
-INSERT INTO users (id, user_id, name) VALUES (0, '4', '村上 加奈');
-INSERT INTO users (id, user_id, name) VALUES (0, '5', '小林 涼平');
-INSERT INTO users (id, user_id, name) VALUES (0, '6', '遠藤 舞');
-INSERT INTO users (id, user_id, name) VALUES (0, '7', '藤田 加奈');
-INSERT INTO users (id, user_id, name) VALUES (0, '8', '藤田 英樹');
-INSERT INTO users (id, user_id, name) VALUES (0, '9', '岡本 くみ子');
-INSERT INTO users (id, user_id, name) VALUES (0, '10', '吉田 さゆり');
-INSERT INTO users (id, user_id, name) VALUES (0, '11', '加藤 京助');
-INSERT INTO users (id, user_id, name) VALUES (0, '12', '佐藤 千代');
-INSERT INTO users (id, user_id, name) VALUES (0, '13', '福田 充');
-INSERT INTO users (id, user_id, name) VALUES (0, '14', '岡田 桃子');
-INSERT INTO users (id, user_id, name) VALUES (0, '15', '斎藤 京助');
-INSERT INTO users (id, user_id, name) VALUES (0, '16', '森 陽子');
-INSERT INTO users (id, user_id, name) VALUES (0, '17', '山下 直子');
-INSERT INTO users (id, user_id, name) VALUES (0, '18', '木村 充');
-INSERT INTO users (id, user_id, name) VALUES (0, '19', '清水 加奈');
-INSERT INTO users (id, user_id, name) VALUES (0, '20', '加藤 零');
-INSERT INTO users (id, user_id, name) VALUES (0, '21', '岡本 美加子');
-INSERT INTO users (id, user_id, name) VALUES (0, '22', '遠藤 修平');
-INSERT INTO users (id, user_id, name) VALUES (0, '23', '斉藤 亮介');
-INSERT INTO users (id, user_id, name) VALUES (0, '24', '林 香織');
-INSERT INTO users (id, user_id, name) VALUES (0, '25', '森 洋介');
-INSERT INTO users (id, user_id, name) VALUES (0, '26', '鈴木 裕美子');
-INSERT INTO users (id, user_id, name) VALUES (0, '27', '山崎 翼');
-INSERT INTO users (id, user_id, name) VALUES (0, '28', '林 真綾');
-INSERT INTO users (id, user_id, name) VALUES (0, '29', '太田 あすか');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '0', '藤井 太郎', '藤井太郎');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '1', '石川 篤司', '石川篤司');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '2', '清水 聡太郎', '清水聡太郎');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '3', '斉藤 康弘', '斉藤康弘');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '4', '村上 加奈', '村上加奈');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '5', '小林 涼平', '小林涼平');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '6', '遠藤 舞', '遠藤舞');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '7', '藤田 加奈', '藤田加奈');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '8', '藤田 英樹', '藤田英樹');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '9', '岡本 くみ子', '岡本くみ子');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '10', '吉田 さゆり', '吉田さゆり');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '11', '加藤 京助', '加藤京助');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '12', '佐藤 千代', '佐藤千代');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '13', '福田 充', '福田充');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '14', '岡田 桃子', '岡田桃子');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '15', '斎藤 京助', '斎藤京助');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '16', '森 陽子', '森陽子');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '17', '山下 直子', '山下直子');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '18', '木村 充', '木村充');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '19', '清水 加奈', '清水加奈');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '20', '加藤 零', '加藤零');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '21', '岡本 美加子', '岡本美加子');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '22', '遠藤 修平', '遠藤修平');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '23', '斉藤 亮介', '斉藤亮介');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '24', '林 香織', '林香織');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '25', '森 洋介', '森洋介');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '26', '鈴木 裕美子', '鈴木裕美子');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '27', '山崎 翼', '山崎翼');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '28', '林 真綾', '林真綾');
+INSERT INTO users (id, user_id, name, search_name) VALUES (0, '29', '太田 あすか', '太田あすか');
@@ -1,4 +1,6 @@
 # coding:utf-8
+import unicodedata
+
 from faker import Factory
 
 # 苗字と名前のリストから名前を生成
@@ -6,6 +8,20 @@ def randomName():
     f = Factory.create('ja_JP')
     return f.name()
 
+# 検索用に名前を正規化する。app/go/pkg/textnorm の Normalize と同じ変換をする
+def searchName(name):
+    name = unicodedata.normalize('NFKC', name)
+    folded = ""
+    for c in name:
+        if c.isspace():
+            continue
+        if 'ァ' <= c <= 'ヶ' or 'ヽ' <= c <= 'ヾ':
+            c = chr(ord(c) - (ord('ァ') - ord('ぁ')))
+        else:
+            c = c.lower()
+        folded += c
+    return folded
+
 # 出力するファイル名
 OUTPUT_FILE = "../UsersTestData.sql"
 
@@ -27,9 +43,9 @@ for i in range(RECORD_COUNT):
 
     # ランダムなデータからInsert文を生成
     sqlCommands += "INSERT INTO users " \
-                   "(id, user_id, name) " \
-                   "VALUES (0, '{}', '{}');\n"\
-                   .format(id, name)
+                   "(id, user_id, name, search_name) " \
+                   "VALUES (0, '{}', '{}', '{}');\n"\
+                   .format(id, name, searchName(name))
 
 # 生成したSQLコマンドをファイルに書き出す
 f = open(OUTPUT_FILE, 'w')